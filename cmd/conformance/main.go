@@ -0,0 +1,206 @@
+// Command conformance runs the OrderEngine wallet-grouping/nonce vectors,
+// the CopyEngine position-sizing vectors, the CopyEngine/RiskManager
+// decision vectors, and the PermissionlessCopyEngine copy-decision vectors
+// in internal/conformance and reports pass/fail per vector. The same four
+// corpora also run under `go test ./internal/conformance` via
+// TestConformanceVectors/TestSizingVectors/TestDecideVectors/
+// TestPermissionlessVectors, so CI catches a mismatch without anyone having
+// to remember to invoke this binary separately; this command remains as a
+// thin CLI wrapper for ad hoc local runs and recording new vectors. Set
+// SKIP_CONFORMANCE to skip the run entirely, for local dev once the corpus
+// gets heavy. Set HYPERLIT_VECTORS_BRANCH to load decide vectors from a
+// sibling hyperlit-vectors checkout instead of the embedded corpus. Pass
+// -run or -skip a substring to only run (or skip) vectors whose name
+// contains it, across all four corpora. Pass -record path/to/input.json to
+// turn a hand-built engine.DecisionInput into a new vectors/decide/*.json
+// case instead of running the corpus.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"hyperliquid-copy-trading/internal/conformance"
+	"hyperliquid-copy-trading/internal/engine"
+)
+
+func main() {
+	record := flag.String("record", "", "path to a JSON engine.DecisionInput to record as a new decide vector, instead of running the corpus")
+	name := flag.String("name", "", "name for the vector written by -record")
+	description := flag.String("description", "", "description for the vector written by -record")
+	run := flag.String("run", "", "only run vectors whose name contains this substring")
+	skip := flag.String("skip", "", "skip vectors whose name contains this substring")
+	flag.Parse()
+
+	if *record != "" {
+		if err := recordDecideVector(*record, *name, *description); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to record decide vector: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if conformance.SkipEnabled() {
+		fmt.Println("SKIP_CONFORMANCE set, skipping conformance run")
+		return
+	}
+
+	filter := func(vectorName string) bool {
+		if *run != "" && !strings.Contains(vectorName, *run) {
+			return false
+		}
+		if *skip != "" && strings.Contains(vectorName, *skip) {
+			return false
+		}
+		return true
+	}
+
+	failed := 0
+
+	vectors, err := conformance.LoadVectors()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load conformance vectors: %v\n", err)
+		os.Exit(1)
+	}
+	ran := 0
+	results := conformance.Run(vectors)
+	for _, r := range results {
+		if !filter(r.Vector.Name) {
+			continue
+		}
+		ran++
+		if r.Passed() {
+			fmt.Printf("PASS  %s\n", r.Vector.Name)
+			continue
+		}
+
+		failed++
+		fmt.Printf("FAIL  %s\n", r.Vector.Name)
+		for _, f := range r.Failures {
+			fmt.Printf("      - %s\n", f)
+		}
+	}
+	fmt.Printf("\n%d/%d order engine vectors passed\n", ran-failed, ran)
+
+	sizingVectors, err := conformance.LoadSizingVectors()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load sizing vectors: %v\n", err)
+		os.Exit(1)
+	}
+	sizingRan := 0
+	sizingResults := conformance.RunSizing(sizingVectors)
+	sizingFailed := 0
+	for _, r := range sizingResults {
+		if !filter(r.Vector.Name) {
+			continue
+		}
+		sizingRan++
+		if r.Passed() {
+			fmt.Printf("PASS  %s\n", r.Vector.Name)
+			continue
+		}
+
+		sizingFailed++
+		fmt.Printf("FAIL  %s\n", r.Vector.Name)
+		for _, f := range r.Failures {
+			fmt.Printf("      - %s\n", f)
+		}
+	}
+	fmt.Printf("%d/%d sizing vectors passed\n", sizingRan-sizingFailed, sizingRan)
+
+	decideVectors, err := conformance.LoadDecideVectors()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load decide vectors: %v\n", err)
+		os.Exit(1)
+	}
+	decideRan := 0
+	decideResults := conformance.RunDecide(decideVectors)
+	decideFailed := 0
+	for _, r := range decideResults {
+		if !filter(r.Vector.Name) {
+			continue
+		}
+		decideRan++
+		if r.Passed() {
+			fmt.Printf("PASS  %s\n", r.Vector.Name)
+			continue
+		}
+
+		decideFailed++
+		fmt.Printf("FAIL  %s\n", r.Vector.Name)
+		for _, f := range r.Failures {
+			fmt.Printf("      - %s\n", f)
+		}
+	}
+	fmt.Printf("%d/%d decide vectors passed\n", decideRan-decideFailed, decideRan)
+
+	permissionlessVectors, err := conformance.LoadPermissionlessVectors()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load permissionless vectors: %v\n", err)
+		os.Exit(1)
+	}
+	permissionlessRan := 0
+	permissionlessResults := conformance.RunPermissionless(permissionlessVectors)
+	permissionlessFailed := 0
+	for _, r := range permissionlessResults {
+		if !filter(r.Vector.Name) {
+			continue
+		}
+		permissionlessRan++
+		if r.Passed() {
+			fmt.Printf("PASS  %s\n", r.Vector.Name)
+			continue
+		}
+
+		permissionlessFailed++
+		fmt.Printf("FAIL  %s\n", r.Vector.Name)
+		for _, f := range r.Failures {
+			fmt.Printf("      - %s\n", f)
+		}
+	}
+	fmt.Printf("%d/%d permissionless vectors passed\n", permissionlessRan-permissionlessFailed, permissionlessRan)
+
+	if failed+sizingFailed+decideFailed+permissionlessFailed > 0 {
+		os.Exit(1)
+	}
+}
+
+// recordDecideVector reads a hand-built engine.DecisionInput from
+// inputPath, runs it through engine.Decide via
+// conformance.RecordDecideVector, and writes the resulting vector to
+// internal/conformance/vectors/decide/<name>.json, so a contributor can add
+// a decide case without hand-writing its expected_* fields.
+func recordDecideVector(inputPath, name, description string) error {
+	if name == "" {
+		return fmt.Errorf("-name is required with -record")
+	}
+
+	data, err := os.ReadFile(inputPath)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", inputPath, err)
+	}
+
+	var input engine.DecisionInput
+	if err := json.Unmarshal(data, &input); err != nil {
+		return fmt.Errorf("parsing %s as engine.DecisionInput: %w", inputPath, err)
+	}
+
+	vector := conformance.RecordDecideVector(name, description, input)
+
+	out, err := json.MarshalIndent(vector, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding recorded vector: %w", err)
+	}
+
+	destPath := filepath.Join("internal", "conformance", "vectors", "decide", name+".json")
+	if err := os.WriteFile(destPath, out, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", destPath, err)
+	}
+
+	fmt.Printf("wrote %s\n", destPath)
+	return nil
+}
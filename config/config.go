@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -29,6 +30,19 @@ type Config struct {
 	OrderBatchInterval    time.Duration
 	MaxPositionSize       float64
 
+	// FollowerSchedulerWorkers bounds how many WalletBatches OrderEngine
+	// dispatches to the exchange concurrently. A smaller pool makes the
+	// (risk_score, follower_tier, arrival_time) fairness ordering actually
+	// matter under load; an unbounded one would just fire every wallet at
+	// once like before the scheduler existed.
+	FollowerSchedulerWorkers int
+
+	// MaxCopySlippageBps caps how far a copy's marketable-limit price may
+	// cross the live book's top-of-book before it's submitted, in basis
+	// points. Only applied when a live order-book connection is available;
+	// copies fall back to a plain market order otherwise.
+	MaxCopySlippageBps float64
+
 	// Hyperliquid-specific settings
 	MaxWebSocketSubscriptions int
 	ReconnectAttempts         int
@@ -42,6 +56,98 @@ type Config struct {
 	// Security
 	APIWalletPrivateKeys map[string]string
 	SignatureChainID     int64 // 42161 for Arbitrum
+
+	// Liquidation risk guard
+	MaxMarginRiskRate float64 // RiskRate above which new copy trades are blocked
+
+	// MinMarginLevel is the minimum equity/required-margin ratio a trade may
+	// leave a follower's account at; trades that would push the account
+	// below this are rejected.
+	MinMarginLevel float64
+
+	// DBRetryMaxElapsed bounds how long dbretry will keep retrying a
+	// transient Postgres error (connection reset, serialization failure,
+	// deadlock, restart) before giving up and returning it to the caller.
+	DBRetryMaxElapsed time.Duration
+
+	// LotMatchingMethod selects how pnl.Matcher pairs closing fills against
+	// open lots when computing realized PnL: "fifo" (default), "lifo", or
+	// "weighted_average".
+	LotMatchingMethod string
+
+	// BlacklistRefreshInterval is how often BlacklistStore reloads its
+	// in-memory blocked-address set from Postgres and BlacklistSourceURLs/
+	// BlacklistSourceFiles.
+	BlacklistRefreshInterval time.Duration
+
+	// BlacklistSourceURLs are OFAC-style endpoints BlacklistStore polls for
+	// a JSON array of addresses to block, in addition to blocked_addresses.
+	BlacklistSourceURLs []string
+
+	// BlacklistSourceFiles are local JSON files (same array-of-addresses
+	// shape as BlacklistSourceURLs) BlacklistStore reloads on every refresh.
+	BlacklistSourceFiles []string
+
+	// DatabaseReplicaURLs are read-replica connection strings PostgresDB
+	// routes read-heavy queries to. Empty means every query goes to
+	// DatabaseURL.
+	DatabaseReplicaURLs []string
+
+	// ReplicaLagWindow is how long, after a write, PostgresDB keeps reading
+	// from the primary instead of a replica on the same request context, to
+	// avoid a replica that hasn't caught up yet serving a stale read right
+	// after the write that just landed.
+	ReplicaLagWindow time.Duration
+
+	// AgentKeyEncryptionKey seeds the AES-256-GCM key AgentWalletManager
+	// uses to encrypt ephemeral agent wallet private keys at rest. Hashed
+	// with SHA-256 before use, so any non-empty passphrase is accepted.
+	AgentKeyEncryptionKey string
+
+	// AgentWalletTTL is how long a generated AgentWallet is usable for
+	// before AgentWalletManager refuses to resolve it as a signer, even if
+	// it was never explicitly revoked.
+	AgentWalletTTL time.Duration
+
+	// SessionOrdersPerMinute caps how many orders a single permissionless
+	// follower's engine.Session may submit per minute, independent of the
+	// shared HyperliquidAPI rate limiter -- so one runaway leader can't
+	// exhaust a follower's own exchange-level limits on its own.
+	SessionOrdersPerMinute int
+
+	// DiscoveryCandidateCacheSize bounds how many addresses the auto-discovery
+	// scoring pipeline tracks fills for at once, evicting the
+	// least-recently-updated address once exceeded.
+	DiscoveryCandidateCacheSize int
+
+	// DiscoveryShortWindow and DiscoveryLongWindow are the two rolling
+	// windows a discovery candidate is scored on -- long for the
+	// composite score itself, short to tell a candidate that's gone quiet
+	// apart from one that's still active.
+	DiscoveryShortWindow time.Duration
+	DiscoveryLongWindow  time.Duration
+
+	// DiscoveryMinTradeCount is the fewest fills a candidate must have in
+	// DiscoveryLongWindow before it's scored at all -- below this, Sharpe
+	// and drawdown are too noisy to act on.
+	DiscoveryMinTradeCount int
+
+	// DiscoveryTopK is how many of the highest-scoring candidates
+	// discoverActiveTraders promotes into discoveredTraders each tick.
+	DiscoveryTopK int
+
+	// DiscoveryScoreWeights weights the composite auto-discovery score:
+	// w1*sharpe - w2*maxDD + w3*log(1+profitFactor) - w4*herfindahl.
+	DiscoveryScoreWeights DiscoveryWeights
+}
+
+// DiscoveryWeights are the composite-score coefficients
+// engine.compositeScore applies to a candidate's rolling metrics.
+type DiscoveryWeights struct {
+	Sharpe       float64
+	Drawdown     float64
+	ProfitFactor float64
+	Herfindahl   float64
 }
 
 func Load() *Config {
@@ -63,6 +169,22 @@ func Load() *Config {
 		MaxOrderBatchSize:     getEnvInt("MAX_ORDER_BATCH_SIZE", 50),
 		OrderBatchInterval:    time.Duration(getEnvInt("ORDER_BATCH_INTERVAL_MS", 100)) * time.Millisecond,
 		MaxPositionSize:       getEnvFloat("MAX_POSITION_SIZE", 100000.0),
+		MaxCopySlippageBps:    getEnvFloat("MAX_COPY_SLIPPAGE_BPS", 15.0),
+
+		FollowerSchedulerWorkers: getEnvInt("FOLLOWER_SCHEDULER_WORKERS", 8),
+		SessionOrdersPerMinute:   getEnvInt("SESSION_ORDERS_PER_MINUTE", 60),
+
+		DiscoveryCandidateCacheSize: getEnvInt("DISCOVERY_CANDIDATE_CACHE_SIZE", 10000),
+		DiscoveryShortWindow:        time.Duration(getEnvInt("DISCOVERY_SHORT_WINDOW_HOURS", 7*24)) * time.Hour,
+		DiscoveryLongWindow:         time.Duration(getEnvInt("DISCOVERY_LONG_WINDOW_HOURS", 30*24)) * time.Hour,
+		DiscoveryMinTradeCount:      getEnvInt("DISCOVERY_MIN_TRADE_COUNT", 10),
+		DiscoveryTopK:               getEnvInt("DISCOVERY_TOP_K", 50),
+		DiscoveryScoreWeights: DiscoveryWeights{
+			Sharpe:       getEnvFloat("DISCOVERY_WEIGHT_SHARPE", 1.0),
+			Drawdown:     getEnvFloat("DISCOVERY_WEIGHT_DRAWDOWN", 1.0),
+			ProfitFactor: getEnvFloat("DISCOVERY_WEIGHT_PROFIT_FACTOR", 0.5),
+			Herfindahl:   getEnvFloat("DISCOVERY_WEIGHT_HERFINDAHL", 0.5),
+		},
 
 		// Hyperliquid API limits
 		MaxWebSocketSubscriptions: getEnvInt("MAX_WEBSOCKET_SUBSCRIPTIONS", 1000), // Hyperliquid limit
@@ -78,6 +200,23 @@ func Load() *Config {
 			"default": getEnv("API_WALLET_PRIVATE_KEY", ""),
 		},
 		SignatureChainID: getEnvInt64("SIGNATURE_CHAIN_ID", 42161), // Arbitrum
+
+		MaxMarginRiskRate: getEnvFloat("MAX_MARGIN_RISK_RATE", 0.8),
+		MinMarginLevel:    getEnvFloat("MIN_MARGIN_LEVEL", 1.2),
+
+		DBRetryMaxElapsed: time.Duration(getEnvInt("DB_RETRY_MAX_ELAPSED_MS", 5000)) * time.Millisecond,
+
+		LotMatchingMethod: getEnv("LOT_MATCHING_METHOD", "fifo"),
+
+		BlacklistRefreshInterval: time.Duration(getEnvInt("BLACKLIST_REFRESH_INTERVAL_MS", 300000)) * time.Millisecond,
+		BlacklistSourceURLs:      getEnvList("BLACKLIST_SOURCE_URLS"),
+		BlacklistSourceFiles:     getEnvList("BLACKLIST_SOURCE_FILES"),
+
+		DatabaseReplicaURLs: getEnvList("DATABASE_REPLICA_URLS"),
+		ReplicaLagWindow:    time.Duration(getEnvInt("REPLICA_LAG_WINDOW_MS", 2000)) * time.Millisecond,
+
+		AgentKeyEncryptionKey: getEnv("AGENT_KEY_ENCRYPTION_KEY", ""),
+		AgentWalletTTL:        time.Duration(getEnvInt("AGENT_WALLET_TTL_HOURS", 24*30)) * time.Hour,
 	}
 }
 
@@ -114,3 +253,20 @@ func getEnvFloat(key string, defaultValue float64) float64 {
 	}
 	return defaultValue
 }
+
+// getEnvList reads a comma-separated env var into a slice, trimming
+// whitespace and dropping empty elements. Returns nil if key is unset.
+func getEnvList(key string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	parts := strings.Split(value, ",")
+	list := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			list = append(list, p)
+		}
+	}
+	return list
+}
@@ -0,0 +1,352 @@
+// Package analytics maintains leader performance metrics incrementally from
+// a live userFills stream instead of re-running GetLeaderPerformance's
+// window-function scan over every historical fill on each read.
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"hyperliquid-copy-trading/internal/api"
+	"hyperliquid-copy-trading/internal/database"
+	"hyperliquid-copy-trading/internal/models"
+
+	"github.com/rs/zerolog/log"
+)
+
+// warmUpLookbackDays bounds the existing SQL query AnalyticsAggregator seeds
+// new leader state from on TrackLeader, matching GetLeaderPerformance's own
+// default window.
+const warmUpLookbackDays = 30
+
+// snapshotInterval is how often each tracked leader's current metrics are
+// persisted to leader_performance_snapshots.
+const snapshotInterval = 30 * time.Second
+
+// dailyBucket accumulates one UTC day's realized PnL for a leader, the same
+// granularity GetLeaderPerformance's daily_pnl CTE materializes in SQL.
+type dailyBucket struct {
+	pnl float64
+}
+
+// leaderState is the full incremental state backing one tracked leader's
+// LeaderPerformanceSnapshot: daily buckets feed the Sharpe ratio's
+// mean/stddev, and a running equity/peak pair feeds max drawdown, so
+// neither needs a full re-scan as more fills arrive.
+type leaderState struct {
+	mu sync.Mutex
+
+	totalPnL         float64
+	totalTrades      int
+	profitableTrades int
+
+	buckets map[string]*dailyBucket // "2006-01-02" (UTC) -> bucket
+
+	runningEquity float64
+	peakEquity    float64
+	maxDrawdown   float64
+
+	subs []chan models.LeaderPerformanceSnapshot
+}
+
+// snapshotLocked builds leaderAddress's current LeaderPerformanceSnapshot.
+// Callers must hold s.mu.
+func (s *leaderState) snapshotLocked(leaderAddress string) models.LeaderPerformanceSnapshot {
+	winRate := 0.0
+	if s.totalTrades > 0 {
+		winRate = float64(s.profitableTrades) / float64(s.totalTrades)
+	}
+
+	return models.LeaderPerformanceSnapshot{
+		LeaderAddress:    leaderAddress,
+		TotalPnL:         s.totalPnL,
+		TotalTrades:      s.totalTrades,
+		ProfitableTrades: s.profitableTrades,
+		WinRate:          winRate,
+		SharpeRatio:      s.sharpeLocked(),
+		MaxDrawdown:      s.maxDrawdown,
+		UpdatedAt:        time.Now(),
+	}
+}
+
+// sharpeLocked computes mean/stddev of daily_pnl across s.buckets, the same
+// ratio GetLeaderPerformance derives from its daily_pnl array. Callers must
+// hold s.mu.
+func (s *leaderState) sharpeLocked() float64 {
+	if len(s.buckets) == 0 {
+		return 0
+	}
+
+	mean := 0.0
+	for _, b := range s.buckets {
+		mean += b.pnl
+	}
+	mean /= float64(len(s.buckets))
+
+	var variance float64
+	for _, b := range s.buckets {
+		diff := b.pnl - mean
+		variance += diff * diff
+	}
+	variance /= float64(len(s.buckets))
+
+	stddev := math.Sqrt(variance)
+	if stddev == 0 {
+		return 0
+	}
+	return mean / stddev
+}
+
+// AnalyticsAggregator subscribes to userFills for every tracked leader and
+// maintains each one's PnL/win-rate/Sharpe/max-drawdown incrementally in
+// memory, persisting a snapshot every snapshotInterval so a read is an O(1)
+// row lookup rather than a full re-scan.
+type AnalyticsAggregator struct {
+	source api.MarketDataSource
+	db     *database.PostgresDB // nil: warm-up/persistence become no-ops
+
+	mu      sync.RWMutex
+	leaders map[string]*leaderState
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewAnalyticsAggregator returns an aggregator reading fills from source,
+// warming up and persisting snapshots through db. db may be nil for
+// offline/test use, in which case warm-up and persistence are skipped.
+func NewAnalyticsAggregator(source api.MarketDataSource, db *database.PostgresDB) *AnalyticsAggregator {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &AnalyticsAggregator{
+		source:  source,
+		db:      db,
+		leaders: make(map[string]*leaderState),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// TrackLeader starts following leaderAddress: it warms up from the existing
+// GetLeaderPerformance query (if db is set), subscribes to its live
+// userFills, and begins persisting a snapshot every snapshotInterval.
+func (a *AnalyticsAggregator) TrackLeader(leaderAddress string) error {
+	a.mu.Lock()
+	if _, exists := a.leaders[leaderAddress]; exists {
+		a.mu.Unlock()
+		return fmt.Errorf("analytics: leader %s is already tracked", leaderAddress)
+	}
+	state := &leaderState{buckets: make(map[string]*dailyBucket)}
+	a.leaders[leaderAddress] = state
+	a.mu.Unlock()
+
+	a.warmUp(leaderAddress, state)
+
+	fills, err := a.source.SubscribeUserFills(leaderAddress)
+	if err != nil {
+		return fmt.Errorf("analytics: subscribing to fills for %s: %w", leaderAddress, err)
+	}
+
+	a.wg.Add(2)
+	go func() { defer a.wg.Done(); a.consumeFills(leaderAddress, state, fills) }()
+	go func() { defer a.wg.Done(); a.persistPeriodically(leaderAddress, state) }()
+
+	return nil
+}
+
+// warmUp seeds state's running totals from the existing GetLeaderPerformance
+// query, so a fresh restart doesn't report zeros until enough live fills
+// arrive to rebuild them. Per-day dates aren't preserved by that query's
+// aggregate array, so the warm-up days are bucketed under synthetic keys
+// that still contribute to the Sharpe ratio's daily-return distribution.
+func (a *AnalyticsAggregator) warmUp(leaderAddress string, state *leaderState) {
+	if a.db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(a.ctx, 30*time.Second)
+	defer cancel()
+
+	seed, err := a.db.GetLeaderPerformance(ctx, leaderAddress, warmUpLookbackDays)
+	if err != nil {
+		log.Warn().Err(err).Str("leader", leaderAddress).Msg("Analytics aggregator: failed to warm up from SQL, starting cold")
+		return
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	state.totalPnL = seed.TotalPnL
+	state.totalTrades = seed.TotalTrades
+	state.profitableTrades = seed.ProfitableTrades
+	state.maxDrawdown = seed.MaxDrawdown
+	state.runningEquity = seed.TotalPnL
+	state.peakEquity = seed.TotalPnL
+	for i, pnl := range seed.DailyPnL {
+		state.buckets[fmt.Sprintf("warmup-%d", i)] = &dailyBucket{pnl: pnl}
+	}
+}
+
+// consumeFills applies every fill delivered on fills to state until ctx is
+// done or fills is closed (e.g. the underlying source was closed).
+func (a *AnalyticsAggregator) consumeFills(leaderAddress string, state *leaderState, fills <-chan models.EnhancedTradeEvent) {
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case event, ok := <-fills:
+			if !ok {
+				return
+			}
+			a.applyFill(leaderAddress, state, event)
+		}
+	}
+}
+
+// applyFill folds one fill into state's running totals, daily bucket and
+// drawdown tracking, then fans the resulting snapshot out to every
+// SubscribeLeaderMetrics subscriber.
+func (a *AnalyticsAggregator) applyFill(leaderAddress string, state *leaderState, event models.EnhancedTradeEvent) {
+	pnl := fillPnL(event)
+
+	state.mu.Lock()
+	state.totalTrades++
+	if pnl > 0 {
+		state.profitableTrades++
+	}
+	state.totalPnL += pnl
+
+	state.runningEquity += pnl
+	if state.runningEquity > state.peakEquity {
+		state.peakEquity = state.runningEquity
+	}
+	if drawdown := state.runningEquity - state.peakEquity; drawdown < state.maxDrawdown {
+		state.maxDrawdown = drawdown
+	}
+
+	day := time.UnixMilli(event.Time).UTC().Format("2006-01-02")
+	bucket, ok := state.buckets[day]
+	if !ok {
+		bucket = &dailyBucket{}
+		state.buckets[day] = bucket
+	}
+	bucket.pnl += pnl
+
+	snapshot := state.snapshotLocked(leaderAddress)
+	subs := append([]chan models.LeaderPerformanceSnapshot(nil), state.subs...)
+	state.mu.Unlock()
+
+	for _, ch := range subs {
+		select {
+		case ch <- snapshot:
+		default:
+			// A slow subscriber shouldn't stall fill processing; it'll pick
+			// up a fresher snapshot on the next fill or persisted read.
+		}
+	}
+}
+
+// fillPnL returns one fill's realized PnL contribution: Hyperliquid's
+// exchange-reported closedPnl when present, falling back to the same
+// sign-based size*price heuristic GetLeaderPerformance's SQL uses for
+// fills that don't carry one.
+func fillPnL(event models.EnhancedTradeEvent) float64 {
+	if event.ClosedPnl != "" {
+		if v, err := strconv.ParseFloat(event.ClosedPnl, 64); err == nil {
+			return v
+		}
+	}
+
+	sz, szErr := strconv.ParseFloat(event.Sz, 64)
+	px, pxErr := strconv.ParseFloat(event.Px, 64)
+	if szErr != nil || pxErr != nil {
+		return 0
+	}
+	if event.Side == "sell" {
+		return sz * px
+	}
+	return -sz * px
+}
+
+// persistPeriodically saves state's current snapshot to
+// leader_performance_snapshots every snapshotInterval until the aggregator
+// is closed. A no-op if no db was configured.
+func (a *AnalyticsAggregator) persistPeriodically(leaderAddress string, state *leaderState) {
+	if a.db == nil {
+		return
+	}
+
+	ticker := time.NewTicker(snapshotInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+			state.mu.Lock()
+			snapshot := state.snapshotLocked(leaderAddress)
+			state.mu.Unlock()
+
+			ctx, cancel := context.WithTimeout(a.ctx, 10*time.Second)
+			err := a.db.SaveLeaderPerformanceSnapshot(ctx, snapshot)
+			cancel()
+			if err != nil {
+				log.Warn().Err(err).Str("leader", leaderAddress).Msg("Failed to persist leader performance snapshot")
+			}
+		}
+	}
+}
+
+// LeaderMetrics returns leaderAddress's current in-memory snapshot in O(1),
+// with ok false if that leader isn't tracked.
+func (a *AnalyticsAggregator) LeaderMetrics(leaderAddress string) (models.LeaderPerformanceSnapshot, bool) {
+	a.mu.RLock()
+	state, ok := a.leaders[leaderAddress]
+	a.mu.RUnlock()
+	if !ok {
+		return models.LeaderPerformanceSnapshot{}, false
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	return state.snapshotLocked(leaderAddress), true
+}
+
+// SubscribeLeaderMetrics returns a channel that receives leaderAddress's
+// current snapshot immediately, then a fresh one on every subsequent fill,
+// so the HTTP layer can push live metric deltas to the frontend.
+func (a *AnalyticsAggregator) SubscribeLeaderMetrics(leaderAddress string) (<-chan models.LeaderPerformanceSnapshot, error) {
+	a.mu.RLock()
+	state, ok := a.leaders[leaderAddress]
+	a.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("analytics: leader %s is not tracked", leaderAddress)
+	}
+
+	ch := make(chan models.LeaderPerformanceSnapshot, 16)
+
+	state.mu.Lock()
+	state.subs = append(state.subs, ch)
+	initial := state.snapshotLocked(leaderAddress)
+	state.mu.Unlock()
+
+	select {
+	case ch <- initial:
+	default:
+	}
+
+	return ch, nil
+}
+
+// Close stops every TrackLeader goroutine. It does not close the
+// MarketDataSource passed to NewAnalyticsAggregator; the caller owns that.
+func (a *AnalyticsAggregator) Close() error {
+	a.cancel()
+	a.wg.Wait()
+	return nil
+}
@@ -0,0 +1,308 @@
+package api
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"hyperliquid-copy-trading/internal/models"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rs/zerolog/log"
+)
+
+// AgentKeyCipher encrypts an agent wallet's private key before
+// AgentWalletManager hands it to an AgentWalletStore, and decrypts it
+// when resolving a signer. Swappable for a KMS-backed implementation in
+// production; AESGCMKeyCipher is a local-key default for development and
+// single-instance deployments, mirroring NonceWAL/FileNonceWAL's
+// pluggable-interface-with-default-impl split.
+type AgentKeyCipher interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// AESGCMKeyCipher is an AgentKeyCipher backed by a single AES-256-GCM key
+// held in process memory. It has no external dependency, at the cost of
+// every encrypted key becoming unrecoverable if that key is lost.
+type AESGCMKeyCipher struct {
+	gcm cipher.AEAD
+}
+
+// NewAESGCMKeyCipher derives an AES-256 key from passphrase (via SHA-256,
+// so any non-empty passphrase is accepted) and returns an AESGCMKeyCipher
+// using it.
+func NewAESGCMKeyCipher(passphrase string) (*AESGCMKeyCipher, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("agent key cipher: passphrase is required")
+	}
+	key := sha256.Sum256([]byte(passphrase))
+
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("agent key cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("agent key cipher: %w", err)
+	}
+	return &AESGCMKeyCipher{gcm: gcm}, nil
+}
+
+func (c *AESGCMKeyCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, c.gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("agent key cipher: generating nonce: %w", err)
+	}
+	return c.gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (c *AESGCMKeyCipher) Decrypt(ciphertext []byte) ([]byte, error) {
+	nonceSize := c.gcm.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, fmt.Errorf("agent key cipher: ciphertext shorter than nonce")
+	}
+	nonce, sealed := ciphertext[:nonceSize], ciphertext[nonceSize:]
+	return c.gcm.Open(nil, nonce, sealed, nil)
+}
+
+// AgentWalletStore persists AgentWallet rows so AgentWalletManager can
+// resolve a follower's signer after a restart without re-running
+// approval. Implemented by database.PostgresDB. Generation and approval
+// are keyed by address rather than follower id, since both happen before
+// CreateFollower has assigned the follower a real id; BindAgentWallet
+// attaches that id once it exists.
+type AgentWalletStore interface {
+	SaveAgentWallet(ctx context.Context, wallet *models.AgentWallet) error
+	GetAgentWalletByAddress(ctx context.Context, address string) (*models.AgentWallet, error)
+	GetAgentWallet(ctx context.Context, followerID int) (*models.AgentWallet, error)
+	ListApprovedAgentWallets(ctx context.Context) ([]*models.AgentWallet, error)
+	MarkAgentWalletApproved(ctx context.Context, address string) error
+	BindAgentWallet(ctx context.Context, address string, followerID int) error
+	RevokeAgentWallet(ctx context.Context, followerID int) error
+}
+
+// GenerateAgentKey creates a fresh secp256k1 keypair for a new agent
+// wallet, returning its private key (hex, no 0x prefix) and address. The
+// private key is returned exactly once, to be handed straight to an
+// AgentKeyCipher.Encrypt call -- callers must not log or persist it
+// unencrypted.
+func GenerateAgentKey() (privateKeyHex string, address string, err error) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return "", "", fmt.Errorf("generating agent key: %w", err)
+	}
+	publicKey, ok := key.Public().(*ecdsa.PublicKey)
+	if !ok {
+		return "", "", fmt.Errorf("generating agent key: unexpected public key type")
+	}
+	return hex.EncodeToString(crypto.FromECDSA(key)), crypto.PubkeyToAddress(*publicKey).Hex(), nil
+}
+
+// resolvedSigner pairs a decrypted Signer with the ExpiresAt its
+// AgentWallet row was generated with, so resolve can enforce the TTL
+// without a store round-trip on every signing call.
+type resolvedSigner struct {
+	signer    *Signer
+	expiresAt time.Time
+}
+
+// AgentWalletManager generates, approves, resolves and revokes the
+// per-follower ephemeral agent wallets that replace CopyEngine's former
+// single shared signer. It installs itself into api via
+// SetAgentSignerResolver, so every existing SignL1Action call site starts
+// resolving the right signer without itself knowing agent wallets exist.
+type AgentWalletManager struct {
+	store  AgentWalletStore
+	cipher AgentKeyCipher
+	api    *HyperliquidAPI
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	signers map[string]*resolvedSigner // agent address (lowercase) -> decrypted signer + expiry
+}
+
+// NewAgentWalletManager returns an AgentWalletManager backed by store and
+// cipher, installing it as api's agent signer resolver and loading every
+// already-approved agent wallet store has persisted, so resolve can serve
+// a follower's signer immediately after a process restart. ttl bounds how
+// long a newly generated agent wallet is usable for before it must be
+// rotated.
+func NewAgentWalletManager(store AgentWalletStore, cipher AgentKeyCipher, api *HyperliquidAPI, ttl time.Duration) *AgentWalletManager {
+	m := &AgentWalletManager{
+		store:   store,
+		cipher:  cipher,
+		api:     api,
+		ttl:     ttl,
+		signers: make(map[string]*resolvedSigner),
+	}
+	api.SetAgentSignerResolver(m.resolve)
+	m.loadApprovedWallets(context.Background())
+	return m
+}
+
+// loadApprovedWallets decrypts every approved, unrevoked agent wallet
+// store has persisted and repopulates m.signers with it, skipping any
+// that have already passed their ExpiresAt. Failures are logged, not
+// fatal -- a wallet that can't be loaded just isn't usable until its
+// follower is re-approved, the same as if it had never been cached.
+func (m *AgentWalletManager) loadApprovedWallets(ctx context.Context) {
+	wallets, err := m.store.ListApprovedAgentWallets(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load persisted agent wallets")
+		return
+	}
+
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, wallet := range wallets {
+		if wallet.ExpiresAt.Before(now) {
+			continue
+		}
+		plaintext, err := m.cipher.Decrypt(wallet.EncryptedKey)
+		if err != nil {
+			log.Error().Err(err).Str("address", wallet.Address).Msg("Failed to decrypt persisted agent wallet")
+			continue
+		}
+		signer, err := NewSigner(string(plaintext))
+		if err != nil {
+			log.Error().Err(err).Str("address", wallet.Address).Msg("Failed to construct signer for persisted agent wallet")
+			continue
+		}
+		m.signers[strings.ToLower(wallet.Address)] = &resolvedSigner{signer: signer, expiresAt: wallet.ExpiresAt}
+	}
+
+	log.Info().Int("loaded", len(m.signers)).Msg("Loaded persisted agent wallets")
+}
+
+// GenerateAgent creates a fresh agent wallet for masterAddress, not yet
+// bound to any follower, and returns its address and the nonce the
+// approval must use. It does not build the typed data masterAddress signs
+// to authorize it; the caller relays agentAddress/nonce to the client for
+// an out-of-band signature, then calls ApproveAgent with the result.
+func (m *AgentWalletManager) GenerateAgent(ctx context.Context, masterAddress, agentName string) (agentAddress string, nonce int64, err error) {
+	privateKeyHex, address, err := GenerateAgentKey()
+	if err != nil {
+		return "", 0, err
+	}
+
+	encryptedKey, err := m.cipher.Encrypt([]byte(privateKeyHex))
+	if err != nil {
+		return "", 0, fmt.Errorf("encrypting agent key: %w", err)
+	}
+
+	nonce = time.Now().UnixMilli()
+	wallet := &models.AgentWallet{
+		MasterAddress: masterAddress,
+		Address:       address,
+		EncryptedKey:  encryptedKey,
+		ExpiresAt:     time.Now().Add(m.ttl),
+	}
+	if err := m.store.SaveAgentWallet(ctx, wallet); err != nil {
+		return "", 0, fmt.Errorf("saving agent wallet: %w", err)
+	}
+
+	return address, nonce, nil
+}
+
+// ApproveAgent submits the master-signed approveAgent action for
+// agentAddress's pending wallet and, once Hyperliquid accepts it, makes
+// the wallet available to signerFor. The wallet is still unbound to any
+// follower at this point; AddFollower calls BindFollower once it has a
+// real follower id.
+func (m *AgentWalletManager) ApproveAgent(ctx context.Context, agentAddress string, approval models.AgentApproval) error {
+	wallet, err := m.store.GetAgentWalletByAddress(ctx, agentAddress)
+	if err != nil {
+		return fmt.Errorf("loading agent wallet: %w", err)
+	}
+	if wallet == nil {
+		return fmt.Errorf("no agent wallet generated for address %s", agentAddress)
+	}
+
+	account := models.TradingAccount{APIWallet: wallet.Address}
+	if err := m.api.SubmitApproveAgent(ctx, account, wallet.Address, approval.AgentName, approval.Nonce, approval.Signature); err != nil {
+		return fmt.Errorf("submitting approveAgent: %w", err)
+	}
+
+	plaintext, err := m.cipher.Decrypt(wallet.EncryptedKey)
+	if err != nil {
+		return fmt.Errorf("decrypting agent key: %w", err)
+	}
+	signer, err := NewSigner(string(plaintext))
+	if err != nil {
+		return fmt.Errorf("constructing agent signer: %w", err)
+	}
+
+	if err := m.store.MarkAgentWalletApproved(ctx, wallet.Address); err != nil {
+		return fmt.Errorf("marking agent wallet approved: %w", err)
+	}
+
+	m.mu.Lock()
+	m.signers[strings.ToLower(wallet.Address)] = &resolvedSigner{signer: signer, expiresAt: wallet.ExpiresAt}
+	m.mu.Unlock()
+
+	return nil
+}
+
+// BindFollower attaches agentAddress's approved wallet to followerID,
+// once CreateFollower has assigned it a real id.
+func (m *AgentWalletManager) BindFollower(ctx context.Context, agentAddress string, followerID int) error {
+	if err := m.store.BindAgentWallet(ctx, agentAddress, followerID); err != nil {
+		return fmt.Errorf("binding agent wallet to follower %d: %w", followerID, err)
+	}
+	return nil
+}
+
+// RevokeAgent marks followerID's agent wallet revoked and drops its
+// cached signer, so signerFor falls back to the config-level signer (and
+// Hyperliquid itself rejects any order still in flight once the revoked
+// agent address is no longer an approved signer for the account).
+func (m *AgentWalletManager) RevokeAgent(ctx context.Context, followerID int) error {
+	wallet, err := m.store.GetAgentWallet(ctx, followerID)
+	if err != nil {
+		return fmt.Errorf("loading agent wallet: %w", err)
+	}
+	if wallet == nil {
+		return nil
+	}
+	if err := m.store.RevokeAgentWallet(ctx, followerID); err != nil {
+		return fmt.Errorf("revoking agent wallet: %w", err)
+	}
+
+	m.mu.Lock()
+	delete(m.signers, strings.ToLower(wallet.Address))
+	m.mu.Unlock()
+	return nil
+}
+
+// resolve is the AgentSignerResolver AgentWalletManager installs on
+// HyperliquidAPI. It serves signers cached by a prior ApproveAgent call or
+// loaded by loadApprovedWallets at startup, and refuses to serve one past
+// its ExpiresAt -- an expired wallet is dropped from the cache and treated
+// as unresolved, the same as one that was never approved, rather than
+// signing with a key that was supposed to have been rotated out.
+func (m *AgentWalletManager) resolve(account models.TradingAccount) (*Signer, bool) {
+	key := strings.ToLower(account.APIWallet)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	resolved, ok := m.signers[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(resolved.expiresAt) {
+		delete(m.signers, key)
+		return nil, false
+	}
+	return resolved.signer, true
+}
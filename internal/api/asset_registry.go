@@ -0,0 +1,169 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"hyperliquid-copy-trading/internal/models"
+	"hyperliquid-copy-trading/internal/utils"
+)
+
+// minOrderNotionalUSD is Hyperliquid's exchange-wide minimum order value;
+// AssetRegistry.ValidateOrderSize enforces it per asset until the meta
+// endpoint starts returning a per-asset minimum.
+const minOrderNotionalUSD = 10.0
+
+// AssetMeta is one asset's exchange metadata, as cached by AssetRegistry.
+type AssetMeta struct {
+	ID          int
+	SzDecimals  int
+	MaxLeverage int
+	IsDelisted  bool
+}
+
+// AssetRegistry replaces the hardcoded asset-name-to-ID map previously
+// hand-maintained in utils with a cache refreshed from Hyperliquid's meta,
+// spotMeta and perpsAtOpenInterestCap endpoints, so szDecimals, maxLeverage
+// and delisted status track the live exchange instead of a list frozen at
+// whatever point someone last updated it.
+type AssetRegistry struct {
+	fetchPerp   func(ctx context.Context) (*models.MetaInfo, error)
+	fetchSpot   func(ctx context.Context) (*models.SpotMetaInfo, error)
+	fetchCapped func(ctx context.Context) ([]string, error)
+
+	mu     sync.RWMutex
+	assets map[string]AssetMeta
+}
+
+// NewAssetRegistry builds a registry backed by live HyperliquidAPI calls.
+// Refresh must be called at least once before Get/ID/FormatTradeSize return
+// useful data.
+func NewAssetRegistry(
+	fetchPerp func(ctx context.Context) (*models.MetaInfo, error),
+	fetchSpot func(ctx context.Context) (*models.SpotMetaInfo, error),
+	fetchCapped func(ctx context.Context) ([]string, error),
+) *AssetRegistry {
+	return &AssetRegistry{
+		fetchPerp:   fetchPerp,
+		fetchSpot:   fetchSpot,
+		fetchCapped: fetchCapped,
+		assets:      make(map[string]AssetMeta),
+	}
+}
+
+// NewStaticAssetRegistry builds a registry pre-populated with assets and no
+// live fetch source, for backtests/sweeps and other offline callers that
+// need asset metadata without a network round trip.
+func NewStaticAssetRegistry(assets map[string]AssetMeta) *AssetRegistry {
+	return &AssetRegistry{assets: assets}
+}
+
+// Refresh re-fetches perp metadata, spot metadata and the open-interest-cap
+// list, and rebuilds the cache. Perp asset IDs are their universe index;
+// spot pairs are offset by 10000, matching Hyperliquid's order/cancel
+// payload convention.
+func (r *AssetRegistry) Refresh(ctx context.Context) error {
+	if r.fetchPerp == nil {
+		return fmt.Errorf("asset registry: no live fetch source configured")
+	}
+
+	perpMeta, err := r.fetchPerp(ctx)
+	if err != nil {
+		return fmt.Errorf("asset registry: fetching perp meta: %w", err)
+	}
+
+	var delisted map[string]bool
+	if r.fetchCapped != nil {
+		if capped, err := r.fetchCapped(ctx); err == nil {
+			delisted = make(map[string]bool, len(capped))
+			for _, asset := range capped {
+				delisted[asset] = true
+			}
+		}
+	}
+
+	assets := make(map[string]AssetMeta, len(perpMeta.Universe))
+	for i, info := range perpMeta.Universe {
+		assets[info.Name] = AssetMeta{
+			ID:          i,
+			SzDecimals:  info.SzDecimals,
+			MaxLeverage: info.MaxLeverage,
+			IsDelisted:  delisted[info.Name],
+		}
+	}
+
+	if r.fetchSpot != nil {
+		if spotMeta, err := r.fetchSpot(ctx); err == nil {
+			for _, pair := range spotMeta.Universe {
+				assets[pair.Name] = AssetMeta{ID: 10000 + pair.Index}
+			}
+		}
+	}
+
+	r.mu.Lock()
+	r.assets = assets
+	r.mu.Unlock()
+	return nil
+}
+
+// Get returns the cached metadata for asset, and whether it is known.
+func (r *AssetRegistry) Get(asset string) (AssetMeta, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	meta, ok := r.assets[asset]
+	return meta, ok
+}
+
+// All returns a snapshot of every cached asset's metadata, keyed by name,
+// for callers (e.g. the exchange/meta instrument cache) that need to
+// iterate the whole universe rather than look up one asset at a time.
+func (r *AssetRegistry) All() map[string]AssetMeta {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make(map[string]AssetMeta, len(r.assets))
+	for k, v := range r.assets {
+		out[k] = v
+	}
+	return out
+}
+
+// ID returns asset's numeric ID for order/cancel payloads, and rejects
+// assets the registry has flagged as delisted.
+func (r *AssetRegistry) ID(asset string) (int, error) {
+	meta, ok := r.Get(asset)
+	if !ok {
+		return 0, fmt.Errorf("asset registry: unknown asset %s", asset)
+	}
+	if meta.IsDelisted {
+		return 0, fmt.Errorf("asset registry: %s is delisted", asset)
+	}
+	return meta.ID, nil
+}
+
+// FormatTradeSize formats size at asset's exchange-defined szDecimals,
+// falling back to 4 decimals for an asset the registry hasn't cached yet.
+func (r *AssetRegistry) FormatTradeSize(asset string, size float64) string {
+	decimals := 4
+	if meta, ok := r.Get(asset); ok {
+		decimals = meta.SzDecimals
+	}
+	return strconv.FormatFloat(size, 'f', decimals, 64)
+}
+
+// ValidateOrderSize checks size against Hyperliquid's minimum order notional
+// at the given price.
+func (r *AssetRegistry) ValidateOrderSize(size, price float64) bool {
+	if price <= 0 {
+		return false
+	}
+	return utils.ValidateOrderSize(size, minOrderNotionalUSD/price)
+}
+
+// MaxLeverage returns asset's exchange-configured max leverage, or 0 if the
+// asset isn't cached yet.
+func (r *AssetRegistry) MaxLeverage(asset string) int {
+	meta, _ := r.Get(asset)
+	return meta.MaxLeverage
+}
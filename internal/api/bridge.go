@@ -0,0 +1,231 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"hyperliquid-copy-trading/internal/models"
+
+	"github.com/rs/zerolog/log"
+)
+
+// arbitrumChainID is where every Bridge implementation's Send ultimately
+// lands funds, ready for HyperliquidAPI's deposit transfer. It's also the
+// real chain a user-signed action's EIP-712 domain names in
+// Signer.SignUserSignedAction, as opposed to SignL1Action's fixed chainId
+// 1337 phantom domain.
+const arbitrumChainID = 42161
+
+// Bridge is one provider's integration for moving a DepositSource to
+// Arbitrum USDC: Quote estimates cost/time without committing funds, Send
+// submits the bridge transaction, and WaitForConfirmation blocks until the
+// funds are observed on Arbitrum.
+type Bridge interface {
+	// Name identifies this provider for BridgeJob.Provider and logging.
+	Name() string
+	// Quote estimates the cost and time of bridging source, without
+	// submitting anything.
+	Quote(ctx context.Context, source models.DepositSource) (models.BridgeQuote, error)
+	// Send submits the bridge transaction for source and returns the
+	// source-chain transaction hash.
+	Send(ctx context.Context, source models.DepositSource, quote models.BridgeQuote) (txHash string, err error)
+	// WaitForConfirmation blocks until srcTxHash's funds have arrived on
+	// Arbitrum, returning the Arbitrum-side transaction hash.
+	WaitForConfirmation(ctx context.Context, srcTxHash string) (arbitrumTxHash string, err error)
+}
+
+// BridgeJobStore persists BridgeJob state so BridgeManager can resume a job
+// that was interrupted mid-flight by a restart, instead of losing track of
+// funds already in transit. Implemented by database.PostgresDB.
+type BridgeJobStore interface {
+	SaveBridgeJob(ctx context.Context, job *models.BridgeJob) error
+	GetPendingBridgeJobs(ctx context.Context) ([]*models.BridgeJob, error)
+}
+
+// Router picks the cheapest route among a set of Bridges by comparing
+// their quotes for the same DepositSource.
+type Router struct {
+	bridges []Bridge
+}
+
+// NewRouter returns a Router that chooses among bridges, in the order
+// given, for every EnsureCollateral call.
+func NewRouter(bridges ...Bridge) *Router {
+	return &Router{bridges: bridges}
+}
+
+// BestQuote asks every configured Bridge for a quote on source and returns
+// the one with the highest AmountOut (i.e. lowest net fee), along with the
+// Bridge that produced it. A provider whose Quote call errors is logged and
+// skipped rather than failing the whole comparison.
+func (r *Router) BestQuote(ctx context.Context, source models.DepositSource) (Bridge, models.BridgeQuote, error) {
+	type candidate struct {
+		bridge Bridge
+		quote  models.BridgeQuote
+	}
+	var candidates []candidate
+
+	for _, b := range r.bridges {
+		quote, err := b.Quote(ctx, source)
+		if err != nil {
+			log.Warn().Err(err).Str("provider", b.Name()).Msg("Bridge quote failed, skipping provider")
+			continue
+		}
+		candidates = append(candidates, candidate{bridge: b, quote: quote})
+	}
+	if len(candidates) == 0 {
+		return nil, models.BridgeQuote{}, fmt.Errorf("bridge: no provider returned a quote for chain %d", source.ChainID)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].quote.AmountOut > candidates[j].quote.AmountOut
+	})
+	best := candidates[0]
+	return best.bridge, best.quote, nil
+}
+
+// BridgeManager drives a DepositSource through quote -> send -> confirm ->
+// Hyperliquid deposit, persisting its BridgeJob at every status transition
+// via store so a restart resumes in-flight jobs instead of losing track of
+// them.
+type BridgeManager struct {
+	router *Router
+	store  BridgeJobStore
+	api    *HyperliquidAPI
+}
+
+// NewBridgeManager returns a BridgeManager that routes through router,
+// persists jobs via store, and performs the final Hyperliquid deposit
+// through api.
+func NewBridgeManager(router *Router, store BridgeJobStore, api *HyperliquidAPI) *BridgeManager {
+	return &BridgeManager{router: router, store: store, api: api}
+}
+
+// Resume reloads every BridgeJob store has left in a non-terminal state and
+// drives each one forward from where it stopped, for BridgeManager to call
+// once at startup so a restart doesn't strand in-flight bridges.
+func (bm *BridgeManager) Resume(ctx context.Context) error {
+	jobs, err := bm.store.GetPendingBridgeJobs(ctx)
+	if err != nil {
+		return fmt.Errorf("loading pending bridge jobs: %w", err)
+	}
+	for _, job := range jobs {
+		bridge := bm.bridgeByName(job.Provider)
+		if bridge == nil {
+			log.Error().Str("provider", job.Provider).Int("job_id", job.ID).Msg("Unknown bridge provider for pending job, leaving it stuck")
+			continue
+		}
+		if err := bm.advance(ctx, bridge, job); err != nil {
+			log.Error().Err(err).Int("job_id", job.ID).Msg("Failed to resume bridge job")
+		}
+	}
+	return nil
+}
+
+func (bm *BridgeManager) bridgeByName(name string) Bridge {
+	for _, b := range bm.router.bridges {
+		if b.Name() == name {
+			return b
+		}
+	}
+	return nil
+}
+
+// EnsureCollateral bridges source.Amount of source's token to
+// Arbitrum and deposits it as Hyperliquid collateral, blocking until the
+// deposit lands. Callers that already have enough collateral should skip
+// calling this; BridgeManager has no visibility into the caller's current
+// balance.
+func (bm *BridgeManager) EnsureCollateral(ctx context.Context, source models.DepositSource) (*models.BridgeJob, error) {
+	bridge, quote, err := bm.router.BestQuote(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	job := &models.BridgeJob{
+		Source:    source,
+		Provider:  bridge.Name(),
+		Quote:     quote,
+		Status:    models.BridgeStatusQuoted,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := bm.store.SaveBridgeJob(ctx, job); err != nil {
+		return nil, fmt.Errorf("persisting bridge job: %w", err)
+	}
+
+	if err := bm.advance(ctx, bridge, job); err != nil {
+		return job, err
+	}
+	return job, nil
+}
+
+// advance drives job forward through whichever of Send/WaitForConfirmation/
+// deposit steps its current Status hasn't completed yet, persisting after
+// every transition so Resume can pick up from exactly this point.
+func (bm *BridgeManager) advance(ctx context.Context, bridge Bridge, job *models.BridgeJob) error {
+	if job.Status == models.BridgeStatusQuoted {
+		txHash, err := bridge.Send(ctx, job.Source, job.Quote)
+		if err != nil {
+			return bm.fail(ctx, job, fmt.Errorf("sending bridge tx: %w", err))
+		}
+		job.SourceTxHash = txHash
+		job.Status = models.BridgeStatusSent
+		job.UpdatedAt = time.Now()
+		if err := bm.store.SaveBridgeJob(ctx, job); err != nil {
+			return fmt.Errorf("persisting bridge job after send: %w", err)
+		}
+	}
+
+	if job.Status == models.BridgeStatusSent {
+		arbTxHash, err := bridge.WaitForConfirmation(ctx, job.SourceTxHash)
+		if err != nil {
+			return bm.fail(ctx, job, fmt.Errorf("waiting for bridge confirmation: %w", err))
+		}
+		job.ArbitrumTxHash = arbTxHash
+		job.Status = models.BridgeStatusArrivedOnArbitrum
+		job.UpdatedAt = time.Now()
+		if err := bm.store.SaveBridgeJob(ctx, job); err != nil {
+			return fmt.Errorf("persisting bridge job after confirmation: %w", err)
+		}
+	}
+
+	if job.Status == models.BridgeStatusArrivedOnArbitrum {
+		if err := bm.api.DepositUSDC(ctx, job.Source.Wallet, job.Quote.AmountOut); err != nil {
+			return bm.fail(ctx, job, fmt.Errorf("depositing to hyperliquid: %w", err))
+		}
+		job.Status = models.BridgeStatusDepositedToHL
+		job.UpdatedAt = time.Now()
+		if err := bm.store.SaveBridgeJob(ctx, job); err != nil {
+			return fmt.Errorf("persisting bridge job after deposit: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (bm *BridgeManager) fail(ctx context.Context, job *models.BridgeJob, cause error) error {
+	job.Status = models.BridgeStatusFailed
+	job.ErrorMessage = cause.Error()
+	job.UpdatedAt = time.Now()
+	if err := bm.store.SaveBridgeJob(ctx, job); err != nil {
+		log.Error().Err(err).Int("job_id", job.ID).Msg("Failed to persist failed bridge job")
+	}
+	return cause
+}
+
+// EnsureCollateralAndPlaceOrder bridges source.Amount of collateral via
+// EnsureCollateral, then places order once the deposit has landed. It
+// always bridges: it does not check the account's current collateral or
+// skip the bridge when the account already holds enough. Order placement never
+// starts until BridgeStatusDepositedToHL, so a caller never signs against
+// collateral that hasn't actually arrived yet.
+func (api *HyperliquidAPI) EnsureCollateralAndPlaceOrder(ctx context.Context, bm *BridgeManager, source models.DepositSource, order *models.EnhancedOrderRequest, account models.TradingAccount) (*models.OrderResponse, error) {
+	if _, err := bm.EnsureCollateral(ctx, source); err != nil {
+		return nil, fmt.Errorf("ensuring collateral: %w", err)
+	}
+	return api.PlaceOrder(ctx, order, account)
+}
@@ -0,0 +1,225 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"hyperliquid-copy-trading/internal/models"
+)
+
+// AcrossBridge quotes and submits deposits through Across Protocol's public
+// relayer network (https://docs.across.to), which is the most liquid
+// general-purpose bridge into Arbitrum and is used as the default/fallback
+// Bridge by Router.
+type AcrossBridge struct {
+	httpClient *http.Client
+	apiURL     string // https://app.across.to/api by default
+}
+
+// NewAcrossBridge returns an AcrossBridge that queries Across' hosted
+// suggested-fees API for quotes. apiURL is configurable for testnet/mock
+// endpoints in tests.
+func NewAcrossBridge(apiURL string) *AcrossBridge {
+	return &AcrossBridge{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		apiURL:     apiURL,
+	}
+}
+
+func (b *AcrossBridge) Name() string { return "across" }
+
+// acrossSuggestedFeesResponse is the subset of Across' /api/suggested-fees
+// response this package needs.
+type acrossSuggestedFeesResponse struct {
+	TotalRelayFee struct {
+		Pct string `json:"pct"`
+	} `json:"totalRelayFee"`
+	EstimatedFillTimeSec int `json:"estimatedFillTimeSec"`
+}
+
+// Quote asks Across for the current relay fee on source's route to
+// Arbitrum and converts it into a BridgeQuote. Real money amounts (AmountOut,
+// Fee) are computed from Across' quoted percentage; EstimatedTime comes
+// straight from their estimatedFillTimeSec.
+func (b *AcrossBridge) Quote(ctx context.Context, source models.DepositSource) (models.BridgeQuote, error) {
+	url := fmt.Sprintf("%s/suggested-fees?originChainId=%d&destinationChainId=%d&token=%s&amount=%d",
+		b.apiURL, source.ChainID, arbitrumChainID, source.Token, int64(source.Amount*1e6))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return models.BridgeQuote{}, err
+	}
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return models.BridgeQuote{}, fmt.Errorf("across: suggested-fees request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return models.BridgeQuote{}, fmt.Errorf("across: suggested-fees returned HTTP %d", resp.StatusCode)
+	}
+
+	var parsed acrossSuggestedFeesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return models.BridgeQuote{}, fmt.Errorf("across: decoding suggested-fees response: %w", err)
+	}
+
+	var feePct float64
+	if _, err := fmt.Sscanf(parsed.TotalRelayFee.Pct, "%f", &feePct); err != nil {
+		return models.BridgeQuote{}, fmt.Errorf("across: parsing relay fee percentage: %w", err)
+	}
+	// Across reports the fee as a WAD-scaled fraction (1e18 == 100%).
+	feeFraction := feePct / 1e18
+	fee := source.Amount * feeFraction
+
+	return models.BridgeQuote{
+		Provider:      b.Name(),
+		AmountOut:     source.Amount - fee,
+		Fee:           fee,
+		EstimatedTime: time.Duration(parsed.EstimatedFillTimeSec) * time.Second,
+	}, nil
+}
+
+// Send submits the deposit to Across' SpokePool contract on source's chain.
+// Doing so requires a wallet holding source.Wallet's private key and an RPC
+// client for source.ChainID, neither of which this package has -- callers
+// running against real funds need to inject a signer/RPC layer here before
+// this can move real money. Until then this returns an error rather than a
+// fabricated transaction hash, so BridgeManager surfaces a clear failure
+// instead of silently treating an unsent deposit as in-flight.
+func (b *AcrossBridge) Send(ctx context.Context, source models.DepositSource, quote models.BridgeQuote) (string, error) {
+	return "", fmt.Errorf("across: Send requires a configured chain %d signer/RPC client, none is wired up", source.ChainID)
+}
+
+// WaitForConfirmation would poll Across' /api/deposit/status endpoint for
+// srcTxHash until it reports "filled" on Arbitrum. Unreachable until Send
+// can produce a real srcTxHash.
+func (b *AcrossBridge) WaitForConfirmation(ctx context.Context, srcTxHash string) (string, error) {
+	return "", fmt.Errorf("across: WaitForConfirmation has no srcTxHash to poll, Send is not implemented")
+}
+
+// HopBridge represents Hop Protocol's AMM-based bridge (https://hop.exchange),
+// Router's alternate route for chains Across doesn't cover. Hop prices
+// routes through its own AMM pools rather than a relay-fee quote, so its
+// Quote implementation differs from AcrossBridge's even though the
+// interface is the same.
+type HopBridge struct {
+	httpClient *http.Client
+	apiURL     string // https://api.hop.exchange by default
+}
+
+// NewHopBridge returns a HopBridge that queries Hop's hosted quote API.
+func NewHopBridge(apiURL string) *HopBridge {
+	return &HopBridge{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		apiURL:     apiURL,
+	}
+}
+
+func (b *HopBridge) Name() string { return "hop" }
+
+type hopQuoteResponse struct {
+	AmountOut      string `json:"amountOut"`
+	EstimatedRecvS int    `json:"estimatedRecvTimeSeconds"`
+}
+
+// Quote asks Hop's AMM for the expected output of bridging source to
+// Arbitrum; the spread between source.Amount and AmountOut already
+// captures Hop's bonder fee plus AMM slippage, so Fee is simply the
+// difference.
+func (b *HopBridge) Quote(ctx context.Context, source models.DepositSource) (models.BridgeQuote, error) {
+	url := fmt.Sprintf("%s/v1/quote?fromChain=%d&toChain=%d&token=%s&amount=%d",
+		b.apiURL, source.ChainID, arbitrumChainID, source.Token, int64(source.Amount*1e6))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return models.BridgeQuote{}, err
+	}
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return models.BridgeQuote{}, fmt.Errorf("hop: quote request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return models.BridgeQuote{}, fmt.Errorf("hop: quote returned HTTP %d", resp.StatusCode)
+	}
+
+	var parsed hopQuoteResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return models.BridgeQuote{}, fmt.Errorf("hop: decoding quote response: %w", err)
+	}
+
+	var amountOutUnits int64
+	if _, err := fmt.Sscanf(parsed.AmountOut, "%d", &amountOutUnits); err != nil {
+		return models.BridgeQuote{}, fmt.Errorf("hop: parsing amountOut: %w", err)
+	}
+	amountOut := float64(amountOutUnits) / 1e6
+
+	return models.BridgeQuote{
+		Provider:      b.Name(),
+		AmountOut:     amountOut,
+		Fee:           source.Amount - amountOut,
+		EstimatedTime: time.Duration(parsed.EstimatedRecvS) * time.Second,
+	}, nil
+}
+
+// Send and WaitForConfirmation have the same on-chain-signer dependency
+// AcrossBridge.Send documents; see that comment.
+func (b *HopBridge) Send(ctx context.Context, source models.DepositSource, quote models.BridgeQuote) (string, error) {
+	return "", fmt.Errorf("hop: Send requires a configured chain %d signer/RPC client, none is wired up", source.ChainID)
+}
+
+func (b *HopBridge) WaitForConfirmation(ctx context.Context, srcTxHash string) (string, error) {
+	return "", fmt.Errorf("hop: WaitForConfirmation has no srcTxHash to poll, Send is not implemented")
+}
+
+// CCTPBridge moves USDC via Circle's native burn-and-mint Cross-Chain
+// Transfer Protocol instead of a liquidity-pool bridge: source chain USDC
+// is burned, Circle's attestation service signs off, and the equivalent
+// amount is minted natively on Arbitrum. No AMM or relay fee applies, so
+// its Quote is a flat estimate rather than a priced API call.
+type CCTPBridge struct {
+	attestationAPIURL string // https://iris-api.circle.com by default
+}
+
+// NewCCTPBridge returns a CCTPBridge pointed at Circle's attestation
+// service for confirming burns.
+func NewCCTPBridge(attestationAPIURL string) *CCTPBridge {
+	return &CCTPBridge{attestationAPIURL: attestationAPIURL}
+}
+
+func (b *CCTPBridge) Name() string { return "cctp" }
+
+// Quote returns source.Amount unchanged: CCTP charges no protocol fee,
+// only the source and destination chains' own gas, which this package has
+// no way to estimate without a configured RPC client. EstimatedTime is
+// Circle's documented "soft finality" attestation latency.
+func (b *CCTPBridge) Quote(ctx context.Context, source models.DepositSource) (models.BridgeQuote, error) {
+	return models.BridgeQuote{
+		Provider:      b.Name(),
+		AmountOut:     source.Amount,
+		Fee:           0,
+		EstimatedTime: 15 * time.Minute,
+	}, nil
+}
+
+// Send would call depositForBurn on source.ChainID's TokenMessenger
+// contract. As with AcrossBridge.Send, that requires a wallet/RPC client
+// this package doesn't have configured, so it fails rather than fabricate
+// a burn transaction hash.
+func (b *CCTPBridge) Send(ctx context.Context, source models.DepositSource, quote models.BridgeQuote) (string, error) {
+	return "", fmt.Errorf("cctp: Send requires a configured chain %d signer/RPC client, none is wired up", source.ChainID)
+}
+
+// WaitForConfirmation would poll Circle's attestation API for srcTxHash's
+// message hash, then submit receiveMessage on Arbitrum once attested.
+// Unreachable until Send can produce a real burn transaction hash.
+func (b *CCTPBridge) WaitForConfirmation(ctx context.Context, srcTxHash string) (string, error) {
+	return "", fmt.Errorf("cctp: WaitForConfirmation has no srcTxHash to poll, Send is not implemented")
+}
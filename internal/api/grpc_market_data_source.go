@@ -0,0 +1,203 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"hyperliquid-copy-trading/internal/models"
+
+	"github.com/rs/zerolog/log"
+)
+
+// logStreamError logs a non-EOF error from a streamKind Recv loop, tagging
+// it with identifier (a coin or user address) when one applies.
+func logStreamError(streamKind, identifier string, err error) {
+	log.Warn().Err(err).Str("stream", streamKind).Str("identifier", identifier).Msg("gRPC market data stream ended with error")
+}
+
+// GRPCFeedClient is the subset of a generated Hyperliquid node gRPC stub
+// that GRPCMarketDataSource needs. It's declared here rather than imported
+// from generated pb code so this package has no hard dependency on a
+// specific .proto layout yet; a self-hosted node's client plugs in by
+// implementing this interface, the same way AssetRegistry takes its
+// fetch funcs rather than a concrete HTTP client.
+type GRPCFeedClient interface {
+	StreamUserFills(ctx context.Context, userAddress string) (FillStream, error)
+	StreamL2Book(ctx context.Context, coin string) (L2BookStream, error)
+	StreamAllMids(ctx context.Context) (AllMidsStream, error)
+	StreamCandles(ctx context.Context, coin, interval string) (CandleStream, error)
+	StreamBBO(ctx context.Context, coin string) (BBOStream, error)
+	Close() error
+}
+
+// FillStream, L2BookStream, AllMidsStream, CandleStream and BBOStream mirror
+// the Recv-loop shape grpc-go generates for a server-streaming RPC: Recv
+// blocks for the next message and returns io.EOF (or the stream's context
+// error) once the server or caller ends the stream.
+type FillStream interface {
+	Recv() (models.EnhancedTradeEvent, error)
+}
+
+type L2BookStream interface {
+	Recv() (models.L2Book, error)
+}
+
+type AllMidsStream interface {
+	Recv() (map[string]string, error)
+}
+
+type CandleStream interface {
+	Recv() (models.Kline, error)
+}
+
+type BBOStream interface {
+	Recv() (models.BBO, error)
+}
+
+// GRPCMarketDataSource is a MarketDataSource backed by a self-hosted
+// Hyperliquid node's gRPC feed instead of the public websocket gateway,
+// for operators who want lower-latency fills without rewriting anything
+// downstream of MarketDataSource.
+type GRPCMarketDataSource struct {
+	client GRPCFeedClient
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewGRPCMarketDataSource wraps client as a MarketDataSource. Every
+// subscription made through the returned source is torn down when Close is
+// called.
+func NewGRPCMarketDataSource(client GRPCFeedClient) *GRPCMarketDataSource {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &GRPCMarketDataSource{client: client, ctx: ctx, cancel: cancel}
+}
+
+func (s *GRPCMarketDataSource) SubscribeUserFills(userAddress string) (<-chan models.EnhancedTradeEvent, error) {
+	stream, err := s.client.StreamUserFills(s.ctx, userAddress)
+	if err != nil {
+		return nil, fmt.Errorf("grpc market data: streaming user fills for %s: %w", userAddress, err)
+	}
+
+	ch := make(chan models.EnhancedTradeEvent, 256)
+	go func() {
+		defer close(ch)
+		for {
+			event, err := stream.Recv()
+			if err != nil {
+				if err != io.EOF {
+					logStreamError("userFills", userAddress, err)
+				}
+				return
+			}
+			ch <- event
+		}
+	}()
+	return ch, nil
+}
+
+func (s *GRPCMarketDataSource) SubscribeL2Book(coin string) (<-chan models.L2Book, error) {
+	stream, err := s.client.StreamL2Book(s.ctx, coin)
+	if err != nil {
+		return nil, fmt.Errorf("grpc market data: streaming L2 book for %s: %w", coin, err)
+	}
+
+	ch := make(chan models.L2Book, 64)
+	go func() {
+		defer close(ch)
+		for {
+			book, err := stream.Recv()
+			if err != nil {
+				if err != io.EOF {
+					logStreamError("l2Book", coin, err)
+				}
+				return
+			}
+			ch <- book
+		}
+	}()
+	return ch, nil
+}
+
+func (s *GRPCMarketDataSource) SubscribeAllMids() (<-chan map[string]string, error) {
+	stream, err := s.client.StreamAllMids(s.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("grpc market data: streaming all mids: %w", err)
+	}
+
+	ch := make(chan map[string]string, 16)
+	go func() {
+		defer close(ch)
+		for {
+			mids, err := stream.Recv()
+			if err != nil {
+				if err != io.EOF {
+					logStreamError("allMids", "", err)
+				}
+				return
+			}
+			ch <- mids
+		}
+	}()
+	return ch, nil
+}
+
+func (s *GRPCMarketDataSource) SubscribeCandles(coin, interval string) (<-chan models.Kline, error) {
+	stream, err := s.client.StreamCandles(s.ctx, coin, interval)
+	if err != nil {
+		return nil, fmt.Errorf("grpc market data: streaming candles for %s@%s: %w", coin, interval, err)
+	}
+
+	ch := make(chan models.Kline, 64)
+	go func() {
+		defer close(ch)
+		for {
+			kline, err := stream.Recv()
+			if err != nil {
+				if err != io.EOF {
+					logStreamError("candle", coin, err)
+				}
+				return
+			}
+			ch <- kline
+		}
+	}()
+	return ch, nil
+}
+
+func (s *GRPCMarketDataSource) SubscribeBBO(coin string) (<-chan models.BBO, error) {
+	stream, err := s.client.StreamBBO(s.ctx, coin)
+	if err != nil {
+		return nil, fmt.Errorf("grpc market data: streaming BBO for %s: %w", coin, err)
+	}
+
+	ch := make(chan models.BBO, 64)
+	go func() {
+		defer close(ch)
+		for {
+			bbo, err := stream.Recv()
+			if err != nil {
+				if err != io.EOF {
+					logStreamError("bbo", coin, err)
+				}
+				return
+			}
+			ch <- bbo
+		}
+	}()
+	return ch, nil
+}
+
+// Unsubscribe is a no-op: each Subscribe* call above owns a dedicated
+// server-streaming RPC, so ending a subscription is Close's job, the same
+// way a grpc-go client stream has no standalone "unsubscribe" verb.
+func (s *GRPCMarketDataSource) Unsubscribe(subscriptionType, identifier string) error {
+	return nil
+}
+
+// Close cancels every in-flight stream and closes the underlying gRPC
+// connection.
+func (s *GRPCMarketDataSource) Close() error {
+	s.cancel()
+	return s.client.Close()
+}
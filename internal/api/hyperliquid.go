@@ -1,24 +1,108 @@
 package api
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"hyperliquid-copy-trading/config"
 	"hyperliquid-copy-trading/internal/models"
 	"io"
+	"math"
 	"net/http"
+	"os"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/rs/zerolog/log"
 )
 
+// NonceWAL persists nonce reservations made by NonceManager.ReserveNonce so
+// they survive a process restart within Hyperliquid's nonce window. Without
+// it, a restart would forget a reservation and could hand the same nonce
+// out twice -- once to the original (possibly still air-gapped) signer and
+// once to a fresh GetNextNonce/ReserveNonce call.
+type NonceWAL interface {
+	// Append records that nonce has been reserved for accountKey (see
+	// NonceManager.accountKey).
+	Append(accountKey string, nonce int64) error
+	// Load returns the highest reserved nonce seen per account key, for
+	// NonceManager to seed its in-memory high-water mark from at startup.
+	Load() (map[string]int64, error)
+}
+
+// FileNonceWAL is a NonceWAL backed by a local append-only file of
+// "accountKey,nonce" lines. It's a minimal default suitable for a
+// single-process deployment; a multi-instance deployment should supply its
+// own NonceWAL backed by shared storage instead.
+type FileNonceWAL struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileNonceWAL returns a FileNonceWAL appending to path, creating it if
+// it doesn't already exist.
+func NewFileNonceWAL(path string) *FileNonceWAL {
+	return &FileNonceWAL{path: path}
+}
+
+func (w *FileNonceWAL) Append(accountKey string, nonce int64) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	f, err := os.OpenFile(w.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("nonce wal: opening %s: %w", w.path, err)
+	}
+	defer f.Close()
+
+	if _, err := fmt.Fprintf(f, "%s,%d\n", accountKey, nonce); err != nil {
+		return fmt.Errorf("nonce wal: appending record: %w", err)
+	}
+	return nil
+}
+
+func (w *FileNonceWAL) Load() (map[string]int64, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	f, err := os.Open(w.path)
+	if os.IsNotExist(err) {
+		return map[string]int64{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("nonce wal: opening %s: %w", w.path, err)
+	}
+	defer f.Close()
+
+	highWater := map[string]int64{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		accountKey, nonceStr, found := strings.Cut(scanner.Text(), ",")
+		if !found {
+			continue
+		}
+		nonce, err := strconv.ParseInt(nonceStr, 10, 64)
+		if err != nil {
+			continue
+		}
+		if nonce > highWater[accountKey] {
+			highWater[accountKey] = nonce
+		}
+	}
+	return highWater, scanner.Err()
+}
+
 // NonceManager handles collision-free nonce generation
 type NonceManager struct {
-	nonces map[string]int64 // Map of wallet address to last used nonce
+	nonces map[string]int64 // Map of account key (see accountKey) to last used nonce
+	wal    NonceWAL         // nil means reservations aren't persisted
 	mutex  sync.Mutex
 }
 
@@ -28,21 +112,69 @@ func NewNonceManager() *NonceManager {
 	}
 }
 
-// GetNextNonce generates a unique nonce for the wallet within Hyperliquid's time window
-func (nm *NonceManager) GetNextNonce(walletAddress string) int64 {
+// NewNonceManagerWithWAL returns a NonceManager whose ReserveNonce calls are
+// persisted to wal, seeding its in-memory state from wal.Load() so
+// reservations made before a restart aren't handed out again.
+func NewNonceManagerWithWAL(wal NonceWAL) (*NonceManager, error) {
+	highWater, err := wal.Load()
+	if err != nil {
+		return nil, fmt.Errorf("loading nonce wal: %w", err)
+	}
+	return &NonceManager{nonces: highWater, wal: wal}, nil
+}
+
+// accountKey returns the NonceManager map key for account. Hyperliquid
+// tracks nonce windows per (signer, account) pair, so the same API wallet
+// trading its own account and a vault it controls must not share a
+// high-water mark.
+func accountKey(account models.TradingAccount) string {
+	vault := ""
+	if account.VaultOrSubaccount != nil {
+		vault = *account.VaultOrSubaccount
+	}
+	return account.APIWallet + "|" + vault
+}
+
+// GetNextNonce generates a unique nonce for account within Hyperliquid's time window
+func (nm *NonceManager) GetNextNonce(account models.TradingAccount) int64 {
 	nm.mutex.Lock()
 	defer nm.mutex.Unlock()
+	return nm.next(accountKey(account))
+}
+
+// ReserveNonce is GetNextNonce plus durable persistence: it's for a caller
+// that will sign an envelope now (e.g. SignOrder for later, possibly
+// offline, submission) but won't call GetNextNonce/SubmitSigned again for
+// the same nonce, so a restart must not forget the reservation and hand the
+// same nonce out a second time.
+func (nm *NonceManager) ReserveNonce(account models.TradingAccount) (int64, error) {
+	key := accountKey(account)
+
+	nm.mutex.Lock()
+	nonce := nm.next(key)
+	nm.mutex.Unlock()
+
+	if nm.wal != nil {
+		if err := nm.wal.Append(key, nonce); err != nil {
+			return 0, err
+		}
+	}
+	return nonce, nil
+}
 
+// next computes the next nonce for the account key and records it as the
+// new high-water mark. Callers must hold nm.mutex.
+func (nm *NonceManager) next(key string) int64 {
 	// Use current Unix millisecond timestamp as base
 	currentTime := time.Now().UnixMilli()
-	lastNonce, exists := nm.nonces[walletAddress]
+	lastNonce, exists := nm.nonces[key]
 
 	// Ensure nonce is within valid window (T - 2 days to T + 1 day)
 	minNonce := currentTime - 2*24*60*60*1000
 	maxNonce := currentTime + 24*60*60*1000
 
 	if !exists || lastNonce < currentTime {
-		nm.nonces[walletAddress] = currentTime
+		nm.nonces[key] = currentTime
 		return currentTime
 	}
 
@@ -55,21 +187,69 @@ func (nm *NonceManager) GetNextNonce(walletAddress string) int64 {
 		nextNonce = minNonce
 	}
 
-	nm.nonces[walletAddress] = nextNonce
+	nm.nonces[key] = nextNonce
 	return nextNonce
 }
 
 type HyperliquidAPI struct {
-	config       *config.Config
-	httpClient   *http.Client
-	signer       *Signer
-	nonceManager *NonceManager
-	perpMeta     *models.MetaInfo
-	spotMeta     *models.SpotMetaInfo
-	metaMutex    sync.RWMutex
+	config        *config.Config
+	httpClient    *http.Client
+	signer        *Signer
+	nonceManager  *NonceManager
+	assetRegistry *AssetRegistry
+	rateLimiter   *rateLimitedClient
+
+	// agentSigners, when set, is consulted by signerFor before falling
+	// back to signer -- AgentWalletManager installs it via
+	// SetAgentSignerResolver so a follower trading through an ephemeral
+	// agent wallet gets that wallet's signature instead of the single
+	// config-level signer every other account shares.
+	agentSigners AgentSignerResolver
+}
+
+// AgentSignerResolver looks up the Signer that should sign for account,
+// returning false if account has no agent wallet of its own (the caller
+// falls back to the config-level signer). See
+// HyperliquidAPI.SetAgentSignerResolver.
+type AgentSignerResolver func(account models.TradingAccount) (*Signer, bool)
+
+// SetAgentSignerResolver installs resolver as the seam signerFor consults
+// before falling back to api.signer. There is deliberately no way to
+// unset it once installed other than passing a resolver that always
+// returns false -- AgentWalletManager is expected to be constructed once
+// per process, alongside api itself.
+func (api *HyperliquidAPI) SetAgentSignerResolver(resolver AgentSignerResolver) {
+	api.agentSigners = resolver
+}
+
+// signerFor resolves which Signer should sign an L1 action on account's
+// behalf: agentSigners' entry for account if one is installed and found,
+// otherwise the single config-level signer every account used before
+// per-follower agent wallets existed.
+func (api *HyperliquidAPI) signerFor(account models.TradingAccount) *Signer {
+	if api.agentSigners != nil {
+		if signer, ok := api.agentSigners(account); ok {
+			return signer
+		}
+	}
+	return api.signer
 }
 
 func NewHyperliquidAPI(cfg *config.Config) (*HyperliquidAPI, error) {
+	return NewHyperliquidAPIWithMetrics(cfg, nil)
+}
+
+// isMainnet reports whether api is configured against Hyperliquid mainnet
+// rather than testnet, for SignL1Action's phantomAgent source selection.
+func (api *HyperliquidAPI) isMainnet() bool {
+	return api.config.Environment != "testnet"
+}
+
+// NewHyperliquidAPIWithMetrics is NewHyperliquidAPI with an explicit
+// RequestMetrics sink, for callers (e.g. main) that want the rate-limited
+// client's counters/histograms wired into their own Prometheus registry
+// instead of discarded.
+func NewHyperliquidAPIWithMetrics(cfg *config.Config, metrics RequestMetrics) (*HyperliquidAPI, error) {
 	signer, err := NewSigner(cfg.APIWalletPrivateKeys["default"])
 	if err != nil {
 		return nil, err
@@ -80,7 +260,9 @@ func NewHyperliquidAPI(cfg *config.Config) (*HyperliquidAPI, error) {
 		httpClient:   &http.Client{Timeout: 30 * time.Second},
 		signer:       signer,
 		nonceManager: NewNonceManager(),
+		rateLimiter:  newRateLimitedClient(metrics),
 	}
+	api.assetRegistry = NewAssetRegistry(api.GetMetaInfo, api.GetSpotMetaInfo, api.GetPerpsAtOpenInterestCap)
 
 	// Initialize metadata cache
 	if err := api.refreshMetaData(context.Background()); err != nil {
@@ -90,23 +272,17 @@ func NewHyperliquidAPI(cfg *config.Config) (*HyperliquidAPI, error) {
 	return api, nil
 }
 
-// refreshMetaData fetches and caches perp and spot metadata
+// refreshMetaData refreshes the asset registry's cached perp/spot metadata.
 func (api *HyperliquidAPI) refreshMetaData(ctx context.Context) error {
-	perpMeta, err := api.GetMetaInfo(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to fetch perp metadata: %w", err)
-	}
-
-	spotMeta, err := api.GetSpotMetaInfo(ctx)
-	if err != nil {
-		log.Warn().Err(err).Msg("Failed to fetch spot metadata, continuing with perp only")
-	}
+	return api.assetRegistry.Refresh(ctx)
+}
 
-	api.metaMutex.Lock()
-	defer api.metaMutex.Unlock()
-	api.perpMeta = perpMeta
-	api.spotMeta = spotMeta
-	return nil
+// AssetRegistry returns the API's cached asset metadata, refreshed from
+// Hyperliquid's meta/spotMeta/perpsAtOpenInterestCap endpoints. Callers that
+// need szDecimals, maxLeverage or delisted status (order sizing, risk
+// scoring) should use this rather than re-deriving it themselves.
+func (api *HyperliquidAPI) AssetRegistry() *AssetRegistry {
+	return api.assetRegistry
 }
 
 func (api *HyperliquidAPI) GetMetaInfo(ctx context.Context) (*models.MetaInfo, error) {
@@ -120,7 +296,7 @@ func (api *HyperliquidAPI) GetMetaInfo(ctx context.Context) (*models.MetaInfo, e
 	}
 
 	var metaInfo models.MetaInfo
-	err := api.makeRequest(ctx, apiURL+"/info", reqBody, &metaInfo)
+	err := api.makeRequest(ctx, apiURL+"/info", "/info", "", 1, reqBody, &metaInfo)
 	return &metaInfo, err
 }
 
@@ -136,7 +312,7 @@ func (api *HyperliquidAPI) GetSpotMetaInfo(ctx context.Context) (*models.SpotMet
 	}
 
 	var spotMeta models.SpotMetaInfo
-	err := api.makeRequest(ctx, apiURL+"/info", reqBody, &spotMeta)
+	err := api.makeRequest(ctx, apiURL+"/info", "/info", "", 1, reqBody, &spotMeta)
 	return &spotMeta, err
 }
 
@@ -153,7 +329,7 @@ func (api *HyperliquidAPI) GetSpotClearinghouseState(ctx context.Context, userAd
 	}
 
 	var state models.SpotClearinghouseState
-	err := api.makeRequest(ctx, apiURL+"/info", reqBody, &state)
+	err := api.makeRequest(ctx, apiURL+"/info", "/info", userAddress, 1, reqBody, &state)
 	return &state, err
 }
 
@@ -170,10 +346,65 @@ func (api *HyperliquidAPI) GetL2Book(ctx context.Context, coin string) (*models.
 	}
 
 	var book models.L2Book
-	err := api.makeRequest(ctx, apiURL+"/info", reqBody, &book)
+	err := api.makeRequest(ctx, apiURL+"/info", "/info", "", 1, reqBody, &book)
 	return &book, err
 }
 
+// GetCandleSnapshot fetches up to `limit` recent candles for coin at the
+// given interval (e.g. "15m"), used to drive ATR/stddev volatility
+// estimation.
+func (api *HyperliquidAPI) GetCandleSnapshot(ctx context.Context, coin, interval string, limit int) ([]models.Kline, error) {
+	apiURL := api.config.HyperliquidAPIURL
+	if api.config.Environment == "testnet" {
+		apiURL = api.config.HyperliquidTestnetURL
+	}
+
+	intervalDuration, err := parseIntervalDuration(interval)
+	if err != nil {
+		return nil, err
+	}
+
+	endTime := time.Now()
+	startTime := endTime.Add(-intervalDuration * time.Duration(limit))
+
+	reqBody := map[string]interface{}{
+		"type": "candleSnapshot",
+		"req": map[string]interface{}{
+			"coin":      coin,
+			"interval":  interval,
+			"startTime": startTime.UnixMilli(),
+			"endTime":   endTime.UnixMilli(),
+		},
+	}
+
+	var candles []models.Kline
+	if err := api.makeRequest(ctx, apiURL+"/info", "/info", "", 1, reqBody, &candles); err != nil {
+		return nil, err
+	}
+	return candles, nil
+}
+
+// parseIntervalDuration converts a Hyperliquid candle interval string into a
+// time.Duration.
+func parseIntervalDuration(interval string) (time.Duration, error) {
+	switch interval {
+	case "1m":
+		return time.Minute, nil
+	case "5m":
+		return 5 * time.Minute, nil
+	case "15m":
+		return 15 * time.Minute, nil
+	case "1h":
+		return time.Hour, nil
+	case "4h":
+		return 4 * time.Hour, nil
+	case "1d":
+		return 24 * time.Hour, nil
+	default:
+		return 0, fmt.Errorf("unsupported candle interval: %s", interval)
+	}
+}
+
 // GetActiveAssetData fetches user's active asset data for margin checks
 func (api *HyperliquidAPI) GetActiveAssetData(ctx context.Context, userAddress, coin string) (*models.ActiveAssetData, error) {
 	apiURL := api.config.HyperliquidAPIURL
@@ -188,7 +419,7 @@ func (api *HyperliquidAPI) GetActiveAssetData(ctx context.Context, userAddress,
 	}
 
 	var data models.ActiveAssetData
-	err := api.makeRequest(ctx, apiURL+"/info", reqBody, &data)
+	err := api.makeRequest(ctx, apiURL+"/info", "/info", userAddress, 1, reqBody, &data)
 	return &data, err
 }
 
@@ -205,7 +436,7 @@ func (api *HyperliquidAPI) GetUserFees(ctx context.Context, userAddress string)
 	}
 
 	var fees models.UserFees
-	err := api.makeRequest(ctx, apiURL+"/info", reqBody, &fees)
+	err := api.makeRequest(ctx, apiURL+"/info", "/info", userAddress, 1, reqBody, &fees)
 	return &fees, err
 }
 
@@ -222,7 +453,7 @@ func (api *HyperliquidAPI) GetPortfolio(ctx context.Context, userAddress string)
 	}
 
 	var portfolio models.Portfolio
-	err := api.makeRequest(ctx, apiURL+"/info", reqBody, &portfolio)
+	err := api.makeRequest(ctx, apiURL+"/info", "/info", userAddress, 1, reqBody, &portfolio)
 	return &portfolio, err
 }
 
@@ -239,10 +470,51 @@ func (api *HyperliquidAPI) GetUserFills(ctx context.Context, userAddress string)
 	}
 
 	var fills []models.EnhancedTradeEvent
-	err := api.makeRequest(ctx, apiURL+"/info", reqBody, &fills)
+	err := api.makeRequest(ctx, apiURL+"/info", "/info", userAddress, 1, reqBody, &fills)
 	return fills, err
 }
 
+// GetUserFillsByTime fetches userAddress's fills with Time in
+// [startTimeMs, endTimeMs], both Unix milliseconds. Used by ProfitFixer's
+// TradeBatchQuery to page a user's full fill history in fixed-size windows
+// rather than relying on GetUserFills' unbounded, most-recent-only result.
+func (api *HyperliquidAPI) GetUserFillsByTime(ctx context.Context, userAddress string, startTimeMs, endTimeMs int64) ([]models.EnhancedTradeEvent, error) {
+	apiURL := api.config.HyperliquidAPIURL
+	if api.config.Environment == "testnet" {
+		apiURL = api.config.HyperliquidTestnetURL
+	}
+
+	reqBody := map[string]interface{}{
+		"type":      "userFillsByTime",
+		"user":      userAddress,
+		"startTime": startTimeMs,
+		"endTime":   endTimeMs,
+	}
+
+	var fills []models.EnhancedTradeEvent
+	err := api.makeRequest(ctx, apiURL+"/info", "/info", userAddress, 1, reqBody, &fills)
+	return fills, err
+}
+
+// GetSubaccounts fetches the subaccounts userAddress (a master account)
+// controls, for building a models.TradingAccount{APIWallet: userAddress,
+// VaultOrSubaccount: &sub.SubaccountUser} to trade on one of them.
+func (api *HyperliquidAPI) GetSubaccounts(ctx context.Context, userAddress string) ([]models.Subaccount, error) {
+	apiURL := api.config.HyperliquidAPIURL
+	if api.config.Environment == "testnet" {
+		apiURL = api.config.HyperliquidTestnetURL
+	}
+
+	reqBody := map[string]interface{}{
+		"type": "subAccounts",
+		"user": userAddress,
+	}
+
+	var subaccounts []models.Subaccount
+	err := api.makeRequest(ctx, apiURL+"/info", "/info", userAddress, 1, reqBody, &subaccounts)
+	return subaccounts, err
+}
+
 // GetPerpsAtOpenInterestCap fetches assets at open interest cap
 func (api *HyperliquidAPI) GetPerpsAtOpenInterestCap(ctx context.Context) ([]string, error) {
 	apiURL := api.config.HyperliquidAPIURL
@@ -255,7 +527,7 @@ func (api *HyperliquidAPI) GetPerpsAtOpenInterestCap(ctx context.Context) ([]str
 	}
 
 	var cappedAssets []string
-	err := api.makeRequest(ctx, apiURL+"/info", reqBody, &cappedAssets)
+	err := api.makeRequest(ctx, apiURL+"/info", "/info", "", 1, reqBody, &cappedAssets)
 	return cappedAssets, err
 }
 
@@ -271,10 +543,39 @@ func (api *HyperliquidAPI) GetUserState(ctx context.Context, userAddress string)
 	}
 
 	var userState models.UserState
-	err := api.makeRequest(ctx, apiURL+"/info", reqBody, &userState)
+	err := api.makeRequest(ctx, apiURL+"/info", "/info", userAddress, 1, reqBody, &userState)
 	return &userState, err
 }
 
+// DepositUSDC waits for wallet's collateral on Hyperliquid to reflect a
+// bridge deposit, for BridgeManager to call once a Bridge has confirmed
+// amountUSDC landed on Arbitrum. Unlike PlaceOrder/CancelOrder there is no
+// signed deposit action to submit: Hyperliquid's validators credit a
+// deposit automatically once they observe the USDC transfer to its bridge
+// contract on Arbitrum, so all this does is poll clearinghouseState until
+// wallet's account value is positive or ctx/the timeout expires. It does
+// not compare against the balance before the deposit or check amountUSDC,
+// so a wallet that already holds collateral returns on the first poll.
+func (api *HyperliquidAPI) DepositUSDC(ctx context.Context, wallet string, amountUSDC float64) error {
+	deadline := time.Now().Add(10 * time.Minute)
+	for {
+		state, err := api.GetUserState(ctx, wallet)
+		if err == nil && state != nil {
+			if accountValue, convErr := strconv.ParseFloat(state.MarginSummary.AccountValue, 64); convErr == nil && accountValue > 0 {
+				return nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("deposit of %.2f USDC for %s not observed on Hyperliquid within timeout", amountUSDC, wallet)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(15 * time.Second):
+		}
+	}
+}
+
 func (api *HyperliquidAPI) GetAllMids(ctx context.Context) (map[string]string, error) {
 	apiURL := api.config.HyperliquidAPIURL
 	if api.config.Environment == "testnet" {
@@ -286,12 +587,15 @@ func (api *HyperliquidAPI) GetAllMids(ctx context.Context) (map[string]string, e
 	}
 
 	var mids map[string]string
-	err := api.makeRequest(ctx, apiURL+"/info", reqBody, &mids)
+	err := api.makeRequest(ctx, apiURL+"/info", "/info", "", 1, reqBody, &mids)
 	return mids, err
 }
 
-// ValidateOrder checks liquidity and margin/balance before placing order
-func (api *HyperliquidAPI) ValidateOrder(ctx context.Context, order *models.EnhancedOrderRequest, userAddress string, isPerp bool) error {
+// ValidateOrder checks order against account's own market: liquidity on
+// the book, and margin (perps) or balance (spot). account.EffectiveAddress
+// is read rather than account.APIWallet, since a vault/subaccount's margin
+// and balances are what an order against it actually draws on.
+func (api *HyperliquidAPI) ValidateOrder(ctx context.Context, order *models.EnhancedOrderRequest, account models.TradingAccount, isPerp bool) error {
 	// Check liquidity
 	l2Book, err := api.GetL2Book(ctx, order.Asset)
 	if err != nil {
@@ -320,6 +624,8 @@ func (api *HyperliquidAPI) ValidateOrder(ctx context.Context, order *models.Enha
 		return fmt.Errorf("insufficient liquidity for %s: need %f, available %f", order.Asset, size, availableSize)
 	}
 
+	userAddress := account.EffectiveAddress()
+
 	// Check margin (perps) or balance (spot)
 	if isPerp {
 		assetData, err := api.GetActiveAssetData(ctx, userAddress, order.Asset)
@@ -355,14 +661,14 @@ func (api *HyperliquidAPI) ValidateOrder(ctx context.Context, order *models.Enha
 }
 
 // PlaceOrder with enhanced validation and error handling
-func (api *HyperliquidAPI) PlaceOrder(ctx context.Context, order *models.EnhancedOrderRequest, apiWalletAddress string) (*models.OrderResponse, error) {
+func (api *HyperliquidAPI) PlaceOrder(ctx context.Context, order *models.EnhancedOrderRequest, account models.TradingAccount) (*models.OrderResponse, error) {
 	// Validate order first
-	if err := api.ValidateOrder(ctx, order, apiWalletAddress, true); err != nil {
+	if err := api.ValidateOrder(ctx, order, account, true); err != nil {
 		return nil, fmt.Errorf("order validation failed: %w", err)
 	}
 
 	// Check fees
-	fees, err := api.GetUserFees(ctx, apiWalletAddress)
+	fees, err := api.GetUserFees(ctx, account.APIWallet)
 	if err != nil {
 		log.Warn().Err(err).Msg("Failed to get user fees")
 	} else {
@@ -371,7 +677,7 @@ func (api *HyperliquidAPI) PlaceOrder(ctx context.Context, order *models.Enhance
 		notional := *order.Price * order.Size
 		estimatedFee := notional * feeRate
 
-		userState, err := api.GetUserState(ctx, apiWalletAddress)
+		userState, err := api.GetUserState(ctx, account.EffectiveAddress())
 		if err == nil {
 			available, _ := strconv.ParseFloat(userState.MarginSummary.AccountValue, 64)
 			if available < estimatedFee {
@@ -380,28 +686,45 @@ func (api *HyperliquidAPI) PlaceOrder(ctx context.Context, order *models.Enhance
 		}
 	}
 
-	apiURL := api.config.HyperliquidAPIURL
-	if api.config.Environment == "testnet" {
-		apiURL = api.config.HyperliquidTestnetURL
+	// Generate nonce automatically
+	nonce := api.nonceManager.GetNextNonce(account)
+
+	envelope, err := api.SignOrder(order, account, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := api.SubmitSigned(ctx, envelope)
+	if err != nil && IsNonceExpired(err) {
+		// Hyperliquid rejected our nonce (e.g. a slow request landed
+		// outside the +/- window); re-sign with a fresh one and try once
+		// more rather than surfacing a rejection the caller can't act on.
+		nonce = api.nonceManager.GetNextNonce(account)
+		envelope, err = api.SignOrder(order, account, nonce)
+		if err != nil {
+			return nil, err
+		}
+		response, err = api.SubmitSigned(ctx, envelope)
 	}
+	return response, err
+}
 
-	// Convert asset to proper format
+// buildOrderAction converts order into Hyperliquid's wire order-action
+// shape, shared by SignOrder (and so PlaceOrder) and ModifyOrder;
+// batchOrders builds its per-order maps inline instead of calling it.
+func (api *HyperliquidAPI) buildOrderAction(order *models.EnhancedOrderRequest) (map[string]interface{}, error) {
 	assetID, err := api.getAssetID(order.Asset)
 	if err != nil {
 		return nil, err
 	}
 
-	// Generate nonce automatically
-	nonce := api.nonceManager.GetNextNonce(apiWalletAddress)
-
-	// Prepare order data with enhanced options
 	orderData := map[string]interface{}{
 		"a": assetID,
 		"b": order.IsBuy,
 		"p": api.formatPrice(order.Price),
 		"s": api.formatSize(order.Size),
-		"r": false, // reduceOnly
-		"t": api.getOrderTypeCode(order.OrderType, order.Tif),
+		"r": order.ReduceOnly,
+		"t": api.getOrderTypeCode(order),
 	}
 
 	if order.ClOid != nil {
@@ -410,29 +733,84 @@ func (api *HyperliquidAPI) PlaceOrder(ctx context.Context, order *models.Enhance
 		orderData["c"] = nil
 	}
 
-	orderAction := map[string]interface{}{
+	return map[string]interface{}{
 		"type":     "order",
 		"orders":   []map[string]interface{}{orderData},
 		"grouping": "na",
+	}, nil
+}
+
+// hashAction returns a canonical sha256 of action+nonce, for
+// SignedOrderEnvelope.ActionHash. Map key order from encoding/json is
+// already deterministic (sorted), so this is stable across calls with
+// equal inputs.
+func hashAction(action map[string]interface{}, nonce int64) (string, error) {
+	encoded, err := json.Marshal(struct {
+		Action map[string]interface{} `json:"action"`
+		Nonce  int64                  `json:"nonce"`
+	}{action, nonce})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// SignOrder builds and signs order's action without submitting it,
+// producing a SignedOrderEnvelope that SubmitSigned can dispatch later,
+// possibly from a different process or machine. This is the offline half
+// of PlaceOrder's validate-then-sign-then-submit flow: an air-gapped
+// signer calls SignOrder and hands the envelope to a hot node (or a
+// third-party relayer) that never touches the private key, or a caller
+// pre-signs a batch of TWAP slices ahead of their scheduled dispatch time.
+func (api *HyperliquidAPI) SignOrder(order *models.EnhancedOrderRequest, account models.TradingAccount, nonce int64) (*models.SignedOrderEnvelope, error) {
+	orderAction, err := api.buildOrderAction(order)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := api.signerFor(account).SignL1Action(orderAction, account.VaultOrSubaccount, nonce, api.isMainnet())
+	if err != nil {
+		return nil, err
 	}
 
-	// Sign the action
-	signature, err := api.signer.SignAction(orderAction, apiWalletAddress, nonce)
+	actionHash, err := hashAction(orderAction, nonce)
 	if err != nil {
 		return nil, err
 	}
 
-	// Prepare request body
+	return &models.SignedOrderEnvelope{
+		Action:           orderAction,
+		Nonce:            nonce,
+		Signature:        signature,
+		VaultAddress:     account.VaultOrSubaccount,
+		APIWalletAddress: account.APIWallet,
+		ActionHash:       actionHash,
+	}, nil
+}
+
+// SubmitSigned dispatches a SignedOrderEnvelope produced by an earlier
+// SignOrder call, without touching the signer. The envelope may have been
+// produced in a previous process, or even on a different machine. If
+// Hyperliquid rejects it because the nonce has expired, the returned error
+// satisfies IsNonceExpired; SubmitSigned itself can't resign (it no longer
+// has the original order), so PlaceOrder is the one that retries with a
+// fresh nonce.
+func (api *HyperliquidAPI) SubmitSigned(ctx context.Context, envelope *models.SignedOrderEnvelope) (*models.OrderResponse, error) {
+	apiURL := api.config.HyperliquidAPIURL
+	if api.config.Environment == "testnet" {
+		apiURL = api.config.HyperliquidTestnetURL
+	}
+
 	reqBody := map[string]interface{}{
-		"action":       orderAction,
-		"nonce":        nonce,
-		"signature":    signature,
-		"vaultAddress": nil,
+		"action":       envelope.Action,
+		"nonce":        envelope.Nonce,
+		"signature":    envelope.Signature,
+		"vaultAddress": envelope.VaultAddress,
 	}
 
 	var response models.OrderResponse
-	err = api.makeRequest(ctx, apiURL+"/exchange", reqBody, &response)
-	if err != nil {
+	if err := api.makeRequest(ctx, apiURL+"/exchange", "/exchange", envelope.APIWalletAddress, 1, reqBody, &response); err != nil {
 		return nil, err
 	}
 
@@ -450,7 +828,7 @@ func (api *HyperliquidAPI) PlaceOrder(ctx context.Context, order *models.Enhance
 }
 
 
-func (api *HyperliquidAPI) CancelOrder(ctx context.Context, asset string, oid int64, apiWalletAddress string, nonce int64) (*models.HyperliquidAPIResponse, error) {
+func (api *HyperliquidAPI) CancelOrder(ctx context.Context, asset string, oid int64, account models.TradingAccount, nonce int64) (*models.HyperliquidAPIResponse, error) {
 	apiURL := api.config.HyperliquidAPIURL
 	if api.config.Environment == "testnet" {
 		apiURL = api.config.HyperliquidTestnetURL
@@ -471,7 +849,7 @@ func (api *HyperliquidAPI) CancelOrder(ctx context.Context, asset string, oid in
 		},
 	}
 
-	signature, err := api.signer.SignAction(cancelAction, apiWalletAddress, nonce)
+	signature, err := api.signerFor(account).SignL1Action(cancelAction, account.VaultOrSubaccount, nonce, api.isMainnet())
 	if err != nil {
 		return nil, err
 	}
@@ -480,16 +858,16 @@ func (api *HyperliquidAPI) CancelOrder(ctx context.Context, asset string, oid in
 		"action":       cancelAction,
 		"nonce":        nonce,
 		"signature":    signature,
-		"vaultAddress": nil,
+		"vaultAddress": account.VaultOrSubaccount,
 	}
 
 	var response models.HyperliquidAPIResponse
-	err = api.makeRequest(ctx, apiURL+"/exchange", reqBody, &response)
+	err = api.makeRequest(ctx, apiURL+"/exchange", "/exchange", account.APIWallet, 1, reqBody, &response)
 	return &response, err
 }
 
 // BatchOrders with IOC/GTC and ALO separation
-func (api *HyperliquidAPI) BatchOrders(ctx context.Context, orders []*models.EnhancedOrderRequest, apiWalletAddress string) (*models.OrderResponse, error) {
+func (api *HyperliquidAPI) BatchOrders(ctx context.Context, orders []*models.EnhancedOrderRequest, account models.TradingAccount) (*models.OrderResponse, error) {
 	// Separate IOC/GTC and ALO orders
 	var iocOrders, aloOrders []*models.EnhancedOrderRequest
 	for _, order := range orders {
@@ -502,8 +880,8 @@ func (api *HyperliquidAPI) BatchOrders(ctx context.Context, orders []*models.Enh
 
 	// Process IOC/GTC batch first
 	if len(iocOrders) > 0 {
-		nonce := api.nonceManager.GetNextNonce(apiWalletAddress)
-		response, err := api.batchOrders(ctx, iocOrders, apiWalletAddress, nonce, "na")
+		nonce := api.nonceManager.GetNextNonce(account)
+		response, err := api.batchOrders(ctx, iocOrders, account, nonce, "na")
 		if err != nil {
 			return nil, fmt.Errorf("IOC/GTC batch failed: %w", err)
 		}
@@ -514,8 +892,8 @@ func (api *HyperliquidAPI) BatchOrders(ctx context.Context, orders []*models.Enh
 
 	// Process ALO batch
 	if len(aloOrders) > 0 {
-		nonce := api.nonceManager.GetNextNonce(apiWalletAddress)
-		response, err := api.batchOrders(ctx, aloOrders, apiWalletAddress, nonce, "alo")
+		nonce := api.nonceManager.GetNextNonce(account)
+		response, err := api.batchOrders(ctx, aloOrders, account, nonce, "alo")
 		if err != nil {
 			return nil, fmt.Errorf("ALO batch failed: %w", err)
 		}
@@ -527,7 +905,7 @@ func (api *HyperliquidAPI) BatchOrders(ctx context.Context, orders []*models.Enh
 	return &models.OrderResponse{Status: "success"}, nil
 }
 
-func (api *HyperliquidAPI) batchOrders(ctx context.Context, orders []*models.EnhancedOrderRequest, apiWalletAddress string, nonce int64, grouping string) (*models.OrderResponse, error) {
+func (api *HyperliquidAPI) batchOrders(ctx context.Context, orders []*models.EnhancedOrderRequest, account models.TradingAccount, nonce int64, grouping string) (*models.OrderResponse, error) {
 	apiURL := api.config.HyperliquidAPIURL
 	if api.config.Environment == "testnet" {
 		apiURL = api.config.HyperliquidTestnetURL
@@ -545,8 +923,8 @@ func (api *HyperliquidAPI) batchOrders(ctx context.Context, orders []*models.Enh
 			"b": order.IsBuy,
 			"p": api.formatPrice(order.Price),
 			"s": api.formatSize(order.Size),
-			"r": false,
-			"t": api.getOrderTypeCode(order.OrderType, order.Tif),
+			"r": order.ReduceOnly,
+			"t": api.getOrderTypeCode(order),
 		}
 
 		if order.ClOid != nil {
@@ -564,7 +942,7 @@ func (api *HyperliquidAPI) batchOrders(ctx context.Context, orders []*models.Enh
 		"grouping": grouping,
 	}
 
-	signature, err := api.signer.SignAction(batchAction, apiWalletAddress, nonce)
+	signature, err := api.signerFor(account).SignL1Action(batchAction, account.VaultOrSubaccount, nonce, api.isMainnet())
 	if err != nil {
 		return nil, err
 	}
@@ -573,14 +951,300 @@ func (api *HyperliquidAPI) batchOrders(ctx context.Context, orders []*models.Enh
 		"action":       batchAction,
 		"nonce":        nonce,
 		"signature":    signature,
-		"vaultAddress": nil,
+		"vaultAddress": account.VaultOrSubaccount,
 	}
 
 	var response models.OrderResponse
-	err = api.makeRequest(ctx, apiURL+"/exchange", reqBody, &response)
+	err = api.makeRequest(ctx, apiURL+"/exchange", "/exchange", account.APIWallet, weightFor("/exchange", len(orders)), reqBody, &response)
 	return &response, err
 }
 
+// PlaceBracketBatch submits parent together with its TP/SL Children in a
+// single exchange batch, using Hyperliquid's native "normalTpsl"/
+// "positionTpsl" grouping instead of OrderEngine.ExecuteBracketOrder's
+// sequential placement. Use this when the entry and its TP/SL must be
+// registered atomically in one round trip; use ExecuteBracketOrder when
+// children should only go live after the entry actually fills.
+func (api *HyperliquidAPI) PlaceBracketBatch(ctx context.Context, parent *models.EnhancedOrderRequest, account models.TradingAccount) (*models.OrderResponse, error) {
+	if len(parent.Children) == 0 {
+		return nil, fmt.Errorf("bracket batch requires at least one child TP/SL order")
+	}
+
+	// positionTpsl ties the TP/SL to the resulting position as a whole
+	// rather than to this specific entry order; Hyperliquid infers that
+	// intent from the children being reduce-only against the full position.
+	grouping := "normalTpsl"
+	if parent.Contingency == models.ContingencyOTOCO && parent.ReduceOnly {
+		grouping = "positionTpsl"
+	}
+
+	orders := make([]*models.EnhancedOrderRequest, 0, len(parent.Children)+1)
+	orders = append(orders, parent)
+	for i := range parent.Children {
+		orders = append(orders, &parent.Children[i])
+	}
+
+	nonce := api.nonceManager.GetNextNonce(account)
+	return api.batchOrders(ctx, orders, account, nonce, grouping)
+}
+
+// PlaceTwapOrder decomposes parent into twap.SliceCount equal child orders
+// spread evenly across twap.Duration, reserving each slice's nonce via
+// NonceManager.ReserveNonce (rather than GetNextNonce) so a paused or
+// delayed schedule can't collide with some unrelated concurrent call using
+// the same wallet. It blocks for the full Duration, submitting one slice
+// per tick; a caller that wants this to run without blocking should invoke
+// it from its own goroutine.
+func (api *HyperliquidAPI) PlaceTwapOrder(ctx context.Context, parent *models.EnhancedOrderRequest, twap models.TwapParams, account models.TradingAccount) ([]*models.OrderResponse, error) {
+	if twap.SliceCount <= 0 {
+		return nil, fmt.Errorf("twap: slice count must be positive")
+	}
+
+	sliceSize := parent.Size / float64(twap.SliceCount)
+	interval := twap.Duration / time.Duration(twap.SliceCount)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	responses := make([]*models.OrderResponse, 0, twap.SliceCount)
+	for i := 0; i < twap.SliceCount; i++ {
+		slice := *parent
+		slice.Size = sliceSize
+
+		nonce, err := api.nonceManager.ReserveNonce(account)
+		if err != nil {
+			return responses, fmt.Errorf("twap: reserving nonce for slice %d: %w", i, err)
+		}
+		envelope, err := api.SignOrder(&slice, account, nonce)
+		if err != nil {
+			return responses, fmt.Errorf("twap: signing slice %d: %w", i, err)
+		}
+		response, err := api.SubmitSigned(ctx, envelope)
+		if err != nil {
+			return responses, fmt.Errorf("twap: submitting slice %d: %w", i, err)
+		}
+		responses = append(responses, response)
+
+		if i < twap.SliceCount-1 {
+			select {
+			case <-ctx.Done():
+				return responses, ctx.Err()
+			case <-ticker.C:
+			}
+		}
+	}
+	return responses, nil
+}
+
+// PlaceScaleOrder decomposes parent into scale.LevelCount limit orders
+// with prices spanning [scale.StartPrice, scale.EndPrice] -- linearly
+// spaced, or geometrically if scale.Geometric is set -- each sized
+// parent.Size/LevelCount, and submits the whole ladder as a single
+// exchange batch via BatchOrders.
+func (api *HyperliquidAPI) PlaceScaleOrder(ctx context.Context, parent *models.EnhancedOrderRequest, scale models.ScaleParams, account models.TradingAccount) (*models.OrderResponse, error) {
+	if scale.LevelCount <= 0 {
+		return nil, fmt.Errorf("scale order: level count must be positive")
+	}
+
+	levelSize := parent.Size / float64(scale.LevelCount)
+	orders := make([]*models.EnhancedOrderRequest, 0, scale.LevelCount)
+	for i := 0; i < scale.LevelCount; i++ {
+		level := *parent
+		level.Size = levelSize
+		price := scalePrice(scale, i)
+		level.Price = &price
+		orders = append(orders, &level)
+	}
+
+	return api.BatchOrders(ctx, orders, account)
+}
+
+// scalePrice returns the i-th of scale.LevelCount prices between
+// StartPrice and EndPrice, linearly spaced by default or geometrically if
+// Geometric is set (falling back to linear if either bound is non-positive,
+// since a geometric ratio isn't meaningful across zero).
+func scalePrice(scale models.ScaleParams, i int) float64 {
+	if scale.LevelCount == 1 {
+		return scale.StartPrice
+	}
+	t := float64(i) / float64(scale.LevelCount-1)
+	if scale.Geometric && scale.StartPrice > 0 && scale.EndPrice > 0 {
+		ratio := scale.EndPrice / scale.StartPrice
+		return scale.StartPrice * math.Pow(ratio, t)
+	}
+	return scale.StartPrice + t*(scale.EndPrice-scale.StartPrice)
+}
+
+// ModifyOrder submits Hyperliquid's "modify" action to change a resting
+// order's price/size/type in place, for laddering strategies that shift a
+// level without a cancel-replace round trip that would briefly leave the
+// level unprotected.
+func (api *HyperliquidAPI) ModifyOrder(ctx context.Context, oid int64, order *models.EnhancedOrderRequest, account models.TradingAccount, nonce int64) (*models.OrderResponse, error) {
+	apiURL := api.config.HyperliquidAPIURL
+	if api.config.Environment == "testnet" {
+		apiURL = api.config.HyperliquidTestnetURL
+	}
+
+	orderAction, err := api.buildOrderAction(order)
+	if err != nil {
+		return nil, err
+	}
+	orderData := orderAction["orders"].([]map[string]interface{})[0]
+
+	modifyAction := map[string]interface{}{
+		"type":  "modify",
+		"oid":   oid,
+		"order": orderData,
+	}
+
+	signature, err := api.signerFor(account).SignL1Action(modifyAction, account.VaultOrSubaccount, nonce, api.isMainnet())
+	if err != nil {
+		return nil, err
+	}
+
+	reqBody := map[string]interface{}{
+		"action":       modifyAction,
+		"nonce":        nonce,
+		"signature":    signature,
+		"vaultAddress": account.VaultOrSubaccount,
+	}
+
+	var response models.OrderResponse
+	err = api.makeRequest(ctx, apiURL+"/exchange", "/exchange", account.APIWallet, 1, reqBody, &response)
+	return &response, err
+}
+
+// CreateSubaccount submits Hyperliquid's "createSubAccount" action, naming
+// a new subaccount under account.APIWallet's master account. The returned
+// address is that subaccount's, suitable for a later
+// models.TradingAccount{APIWallet: account.APIWallet, VaultOrSubaccount: &addr}.
+func (api *HyperliquidAPI) CreateSubaccount(ctx context.Context, account models.TradingAccount, name string, nonce int64) (string, error) {
+	apiURL := api.config.HyperliquidAPIURL
+	if api.config.Environment == "testnet" {
+		apiURL = api.config.HyperliquidTestnetURL
+	}
+
+	action := map[string]interface{}{
+		"type": "createSubAccount",
+		"name": name,
+	}
+
+	signature, err := api.signerFor(account).SignL1Action(action, nil, nonce, api.isMainnet())
+	if err != nil {
+		return "", err
+	}
+
+	reqBody := map[string]interface{}{
+		"action":       action,
+		"nonce":        nonce,
+		"signature":    signature,
+		"vaultAddress": nil,
+	}
+
+	var response struct {
+		Status   string `json:"status"`
+		Response struct {
+			Type string `json:"type"`
+			Data string `json:"data"`
+		} `json:"response"`
+	}
+	if err := api.makeRequest(ctx, apiURL+"/exchange", "/exchange", account.APIWallet, 1, reqBody, &response); err != nil {
+		return "", err
+	}
+	if response.Status != "success" {
+		return "", fmt.Errorf("create subaccount failed with status: %s", response.Status)
+	}
+	return response.Response.Data, nil
+}
+
+// TransferBetweenSubaccounts submits Hyperliquid's "subAccountTransfer"
+// action, moving amountUSDC of USDC perp collateral between account's
+// master account and subaccountAddress (one of its own subaccounts, from
+// GetSubaccounts). isDeposit true moves funds master -> subaccount; false
+// moves them subaccount -> master.
+func (api *HyperliquidAPI) TransferBetweenSubaccounts(ctx context.Context, account models.TradingAccount, subaccountAddress string, amountUSDC float64, isDeposit bool, nonce int64) error {
+	apiURL := api.config.HyperliquidAPIURL
+	if api.config.Environment == "testnet" {
+		apiURL = api.config.HyperliquidTestnetURL
+	}
+
+	action := map[string]interface{}{
+		"type":           "subAccountTransfer",
+		"subAccountUser": subaccountAddress,
+		"isDeposit":      isDeposit,
+		"usd":            int64(amountUSDC * 1e6),
+	}
+
+	signature, err := api.signerFor(account).SignL1Action(action, nil, nonce, api.isMainnet())
+	if err != nil {
+		return err
+	}
+
+	reqBody := map[string]interface{}{
+		"action":       action,
+		"nonce":        nonce,
+		"signature":    signature,
+		"vaultAddress": nil,
+	}
+
+	var response models.HyperliquidAPIResponse
+	if err := api.makeRequest(ctx, apiURL+"/exchange", "/exchange", account.APIWallet, 1, reqBody, &response); err != nil {
+		return err
+	}
+	if response.Status != "success" {
+		return fmt.Errorf("subaccount transfer failed with status: %s", response.Status)
+	}
+	return nil
+}
+
+// HyperliquidChainName is the "hyperliquidChain" field every user-signed
+// action (withdraw, approveAgent) carries, Hyperliquid's own convention
+// for telling mainnet and testnet signatures apart in that domain.
+func (api *HyperliquidAPI) HyperliquidChainName() string {
+	if api.isMainnet() {
+		return "Mainnet"
+	}
+	return "Testnet"
+}
+
+// SubmitApproveAgent submits an approveAgent action authorizing
+// agentAddress to trade on account's behalf, using signature the
+// follower's master wallet already produced out-of-band against
+// BuildApproveAgentTypedData(api.HyperliquidChainName(), agentAddress,
+// agentName, nonce). Unlike every other action this file signs, the
+// signature here never passes through api.signer or signerFor -- this
+// method only assembles and posts the envelope.
+func (api *HyperliquidAPI) SubmitApproveAgent(ctx context.Context, account models.TradingAccount, agentAddress, agentName string, nonce int64, signature map[string]interface{}) error {
+	apiURL := api.config.HyperliquidAPIURL
+	if api.config.Environment == "testnet" {
+		apiURL = api.config.HyperliquidTestnetURL
+	}
+
+	action := map[string]interface{}{
+		"type":             "approveAgent",
+		"hyperliquidChain": api.HyperliquidChainName(),
+		"signatureChainId": fmt.Sprintf("0x%x", api.config.SignatureChainID),
+		"agentAddress":     agentAddress,
+		"agentName":        agentName,
+		"nonce":            nonce,
+	}
+
+	reqBody := map[string]interface{}{
+		"action":    action,
+		"nonce":     nonce,
+		"signature": signature,
+	}
+
+	var response models.HyperliquidAPIResponse
+	if err := api.makeRequest(ctx, apiURL+"/exchange", "/exchange", account.APIWallet, 1, reqBody, &response); err != nil {
+		return err
+	}
+	if response.Status != "success" {
+		return fmt.Errorf("approve agent failed with status: %s", response.Status)
+	}
+	return nil
+}
+
 func (api *HyperliquidAPI) GetOrderStatus(ctx context.Context, userAddress string, oid int64) (map[string]interface{}, error) {
 	apiURL := api.config.HyperliquidAPIURL
 	if api.config.Environment == "testnet" {
@@ -594,83 +1258,136 @@ func (api *HyperliquidAPI) GetOrderStatus(ctx context.Context, userAddress strin
 	}
 
 	var status map[string]interface{}
-	err := api.makeRequest(ctx, apiURL+"/info", reqBody, &status)
+	err := api.makeRequest(ctx, apiURL+"/info", "/info", userAddress, 1, reqBody, &status)
 	return status, err
 }
 
-func (api *HyperliquidAPI) makeRequest(ctx context.Context, url string, reqBody interface{}, response interface{}) error {
+// makeRequest issues one Hyperliquid API call, blocking on the
+// endpoint+identity token bucket beforehand and retrying transient
+// failures (transport errors, HTTP 429) with jittered backoff up to
+// rateLimiter.maxRetries times. identity is the queried user address for
+// /info calls ("" for account-agnostic ones) or the API wallet address for
+// /exchange calls; weight is what Hyperliquid charges against that
+// identity's budget, from weightFor. A response body indicating the nonce
+// has expired is surfaced as an error satisfying IsNonceExpired, which
+// PlaceOrder uses to re-sign with a fresh nonce and retry once.
+func (api *HyperliquidAPI) makeRequest(ctx context.Context, url, endpoint, identity string, weight int, reqBody interface{}, response interface{}) error {
 	jsonData, err := json.Marshal(reqBody)
 	if err != nil {
 		return err
 	}
 
+	var lastErr error
+	lastRetryReason := ""
+	for attempt := 0; attempt <= api.rateLimiter.maxRetries; attempt++ {
+		if attempt > 0 {
+			if !api.rateLimiter.backoff(ctx, attempt-1) {
+				return ctx.Err()
+			}
+		}
+
+		if err := api.rateLimiter.acquire(ctx, endpoint, identity, weight); err != nil {
+			return err
+		}
+
+		start := time.Now()
+		body, retryReason, err := api.doRequest(ctx, url, jsonData)
+		api.rateLimiter.record(endpoint, weight, time.Since(start), err)
+
+		if err == nil {
+			log.Debug().Str("url", url).RawJSON("response", body).Msg("API response")
+			return json.Unmarshal(body, response)
+		}
+
+		lastErr = err
+		lastRetryReason = retryReason
+		if retryReason == "" {
+			return err
+		}
+		api.rateLimiter.retry(endpoint, retryReason)
+		if retryReason == "nonce_expired" {
+			// Resending the same signed body can't succeed; the caller
+			// re-signs with a fresh nonce instead.
+			return err
+		}
+	}
+
+	if lastRetryReason == "rate_limited" {
+		api.rateLimiter.drain(endpoint, identity)
+		return fmt.Errorf("%w: %v", ErrRateLimitExhausted, lastErr)
+	}
+	return fmt.Errorf("hyperliquid: giving up after %d retries: %w", api.rateLimiter.maxRetries, lastErr)
+}
+
+// doRequest performs a single HTTP round trip and classifies the outcome:
+// a non-empty retryReason means the caller should back off and try again
+// (a transport error or HTTP 429), except "nonce_expired", which needs a
+// re-signed request rather than a resend; err alone (empty retryReason)
+// means the caller should give up immediately.
+func (api *HyperliquidAPI) doRequest(ctx context.Context, url string, jsonData []byte) (body []byte, retryReason string, err error) {
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return err
+		return nil, "", err
 	}
-
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := api.httpClient.Do(req)
 	if err != nil {
-		return err
+		return nil, "transport", err
 	}
 	defer resp.Body.Close()
 
+	body, readErr := io.ReadAll(resp.Body)
+	if readErr != nil {
+		return nil, "transport", readErr
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, "rate_limited", fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+	}
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+		return nil, "", fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return err
+	if isNonceExpiredBody(body) {
+		return nil, "nonce_expired", errNonceExpired
 	}
 
-	log.Debug().Str("url", url).RawJSON("response", body).Msg("API response")
+	return body, "", nil
+}
+
+// errNonceExpired is returned (wrapped) by makeRequest when Hyperliquid
+// rejects an action because its nonce fell outside the acceptable window.
+var errNonceExpired = errors.New("hyperliquid: nonce expired")
 
-	return json.Unmarshal(body, response)
+// IsNonceExpired reports whether err (or one it wraps) is errNonceExpired.
+func IsNonceExpired(err error) bool {
+	return errors.Is(err, errNonceExpired)
 }
 
-// getAssetID returns the asset ID for a given asset name (perp or spot) with dynamic lookup
-func (api *HyperliquidAPI) getAssetID(asset string) (int, error) {
-	api.metaMutex.RLock()
-	defer api.metaMutex.RUnlock()
-
-	// Check perpetuals first
-	if api.perpMeta != nil {
-		for i, assetInfo := range api.perpMeta.Universe {
-			if assetInfo.Name == asset {
-				// Check if asset is delisted (if enhanced asset info is available)
-				if enhancedInfo, ok := interface{}(assetInfo).(models.EnhancedAssetInfo); ok {
-					if enhancedInfo.IsDelisted {
-						return 0, fmt.Errorf("asset %s is delisted", asset)
-					}
-				}
-				return i, nil
-			}
-		}
-	}
+// isNonceExpiredBody reports whether body looks like a Hyperliquid
+// response rejecting an action for an expired nonce, e.g.
+// {"status":"err","response":"... nonce ... ms is expired ..."}.
+func isNonceExpiredBody(body []byte) bool {
+	lower := strings.ToLower(string(body))
+	return strings.Contains(lower, "nonce") && strings.Contains(lower, "expired")
+}
 
-	// Check spot markets
-	if api.spotMeta != nil {
-		for _, pair := range api.spotMeta.Universe {
-			if pair.Name == asset {
-				return 10000 + pair.Index, nil
-			}
-		}
+// getAssetID returns the asset ID for a given asset name (perp or spot),
+// via the dynamically-refreshed AssetRegistry.
+func (api *HyperliquidAPI) getAssetID(asset string) (int, error) {
+	id, err := api.assetRegistry.ID(asset)
+	if err == nil {
+		return id, nil
 	}
 
-	// Check if asset is at open interest cap
+	// The registry may simply be stale (asset newly listed since the last
+	// Refresh); re-check before giving up.
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-
-	cappedAssets, err := api.GetPerpsAtOpenInterestCap(ctx)
-	if err == nil {
-		for _, cappedAsset := range cappedAssets {
-			if cappedAsset == asset {
-				return 0, fmt.Errorf("asset %s is at open interest cap", asset)
-			}
+	if refreshErr := api.assetRegistry.Refresh(ctx); refreshErr == nil {
+		if id, err = api.assetRegistry.ID(asset); err == nil {
+			return id, nil
 		}
 	}
 
@@ -688,10 +1405,22 @@ func (api *HyperliquidAPI) formatSize(size float64) string {
 	return strconv.FormatFloat(size, 'f', -1, 64)
 }
 
-// getOrderTypeCode with enhanced TIF support
-func (api *HyperliquidAPI) getOrderTypeCode(orderType string, tif string) map[string]interface{} {
+// getOrderTypeCode builds Hyperliquid's "t" order-type field: a trigger
+// object if order.Trigger is set, otherwise the usual limit/tif object.
+func (api *HyperliquidAPI) getOrderTypeCode(order *models.EnhancedOrderRequest) map[string]interface{} {
+	if order.Trigger != nil {
+		return map[string]interface{}{
+			"trigger": map[string]interface{}{
+				"triggerPx": order.Trigger.TriggerPx,
+				"isMarket":  order.Trigger.IsMarket,
+				"tpsl":      order.Trigger.Tpsl,
+			},
+		}
+	}
+
+	tif := order.Tif
 	if tif == "" {
-		switch orderType {
+		switch order.OrderType {
 		case "market":
 			tif = "Ioc"
 		case "limit":
@@ -0,0 +1,324 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"hyperliquid-copy-trading/internal/models"
+)
+
+// ErrSequenceGap is returned by ApplyDelta when a delta's sequence number
+// doesn't immediately follow the book's current one, meaning an update was
+// missed and the caller must re-snapshot via ApplySnapshot before applying
+// any further deltas.
+var ErrSequenceGap = fmt.Errorf("local order book: sequence gap detected, resync required")
+
+// bookLevel is one price level's resting size and order count.
+type bookLevel struct {
+	sz float64
+	n  int
+}
+
+// BookLevel is one price level in a checkpoint, with Px/Sz parsed out of
+// Hyperliquid's string-encoded L2Book levels for direct numeric use.
+type BookLevel struct {
+	Side string  `json:"side"` // "bid" or "ask"
+	Px   float64 `json:"px"`
+	Sz   float64 `json:"sz"`
+	N    int     `json:"n"`
+}
+
+// BookDelta is one incremental change to a coin's order book: every level
+// that moved since the previous snapshot/delta, with a monotonic Seq a
+// subscriber uses to detect a missed update.
+type BookDelta struct {
+	Coin string      `json:"coin"`
+	Seq  uint64      `json:"seq"`
+	Time int64       `json:"time"`
+	Bids []BookLevel `json:"bids"` // Sz == 0 means the level was removed
+	Asks []BookLevel `json:"asks"`
+}
+
+// LocalOrderBook maintains one coin's bid/ask ladder in memory from a full
+// snapshot plus a stream of incremental deltas, so MidPrice/SpreadBps and
+// depth checkpoints are available without a REST round-trip or re-parsing a
+// raw L2Book on every tick.
+type LocalOrderBook struct {
+	coin string
+
+	mu   sync.RWMutex
+	bids map[float64]bookLevel
+	asks map[float64]bookLevel
+	seq  uint64
+	time int64
+}
+
+// NewLocalOrderBook returns an empty LocalOrderBook for coin; call
+// ApplySnapshot before relying on MidPrice/SpreadBps/checkpoints.
+func NewLocalOrderBook(coin string) *LocalOrderBook {
+	return &LocalOrderBook{
+		coin: coin,
+		bids: make(map[float64]bookLevel),
+		asks: make(map[float64]bookLevel),
+	}
+}
+
+// ApplySnapshot replaces the book's entire ladder with book's contents and
+// resets the sequence counter to seq, establishing a new baseline that
+// subsequent ApplyDelta calls must follow from.
+func (lb *LocalOrderBook) ApplySnapshot(book models.L2Book, seq uint64) error {
+	bids, err := parseLevels(book.Levels["bids"])
+	if err != nil {
+		return fmt.Errorf("local order book: parsing bids: %w", err)
+	}
+	asks, err := parseLevels(book.Levels["asks"])
+	if err != nil {
+		return fmt.Errorf("local order book: parsing asks: %w", err)
+	}
+
+	lb.mu.Lock()
+	lb.bids = bids
+	lb.asks = asks
+	lb.seq = seq
+	lb.time = book.Time
+	lb.mu.Unlock()
+	return nil
+}
+
+// ApplyDelta applies delta's level changes on top of the current ladder.
+// It returns ErrSequenceGap without modifying the book if delta.Seq doesn't
+// immediately follow the book's current sequence; the caller must then
+// request a fresh snapshot via ApplySnapshot before retrying.
+func (lb *LocalOrderBook) ApplyDelta(delta BookDelta) error {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	if delta.Seq != lb.seq+1 {
+		return ErrSequenceGap
+	}
+
+	applySide(lb.bids, delta.Bids)
+	applySide(lb.asks, delta.Asks)
+	lb.seq = delta.Seq
+	lb.time = delta.Time
+	return nil
+}
+
+// reconcile is WebSocketClient's internal bridge from Hyperliquid's
+// full-snapshot-only l2Book feed to the diff model above: it diffs book
+// against the ladder's current contents, applies the result as the next
+// sequenced delta, and returns that delta for SubscribeBookDelta
+// subscribers. The first call against an empty book yields a delta that is
+// itself a full snapshot, which is the correct behavior for a fresh
+// subscriber.
+func (lb *LocalOrderBook) reconcile(book models.L2Book) (BookDelta, error) {
+	bids, err := parseLevels(book.Levels["bids"])
+	if err != nil {
+		return BookDelta{}, fmt.Errorf("local order book: parsing bids: %w", err)
+	}
+	asks, err := parseLevels(book.Levels["asks"])
+	if err != nil {
+		return BookDelta{}, fmt.Errorf("local order book: parsing asks: %w", err)
+	}
+
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	delta := BookDelta{
+		Coin: lb.coin,
+		Seq:  lb.seq + 1,
+		Time: book.Time,
+		Bids: diffSide(lb.bids, bids),
+		Asks: diffSide(lb.asks, asks),
+	}
+	for i := range delta.Bids {
+		delta.Bids[i].Side = "bid"
+	}
+	for i := range delta.Asks {
+		delta.Asks[i].Side = "ask"
+	}
+
+	lb.bids = bids
+	lb.asks = asks
+	lb.seq = delta.Seq
+	lb.time = book.Time
+
+	return delta, nil
+}
+
+// Seq returns the book's current sequence number.
+func (lb *LocalOrderBook) Seq() uint64 {
+	lb.mu.RLock()
+	defer lb.mu.RUnlock()
+	return lb.seq
+}
+
+// BookCheckpoint returns a models.L2Book-shaped snapshot of the top depth
+// levels per side, suitable for feeding the same consumers the raw feed
+// does (e.g. a UI depth chart).
+func (lb *LocalOrderBook) BookCheckpoint(depth int) models.L2Book {
+	lb.mu.RLock()
+	defer lb.mu.RUnlock()
+
+	return models.L2Book{
+		Coin: lb.coin,
+		Time: lb.time,
+		Levels: map[string][]models.PriceLevel{
+			"bids": topLevels(lb.bids, depth, true),
+			"asks": topLevels(lb.asks, depth, false),
+		},
+	}
+}
+
+// LevelCheckpoint returns the top depth levels of each side as a flat,
+// side-tagged slice (best bid/ask first), handy for a table-style UI that
+// doesn't want to special-case the bids/asks map shape.
+func (lb *LocalOrderBook) LevelCheckpoint(depth int) []BookLevel {
+	lb.mu.RLock()
+	defer lb.mu.RUnlock()
+
+	levels := make([]BookLevel, 0, depth*2)
+	for _, px := range sortedPrices(lb.bids, true, depth) {
+		l := lb.bids[px]
+		levels = append(levels, BookLevel{Side: "bid", Px: px, Sz: l.sz, N: l.n})
+	}
+	for _, px := range sortedPrices(lb.asks, false, depth) {
+		l := lb.asks[px]
+		levels = append(levels, BookLevel{Side: "ask", Px: px, Sz: l.sz, N: l.n})
+	}
+	return levels
+}
+
+// MidPrice returns the midpoint of the best bid and best ask, or 0 if
+// either side is currently empty.
+func (lb *LocalOrderBook) MidPrice() float64 {
+	lb.mu.RLock()
+	defer lb.mu.RUnlock()
+
+	bestBid, okBid := bestPrice(lb.bids, true)
+	bestAsk, okAsk := bestPrice(lb.asks, false)
+	if !okBid || !okAsk {
+		return 0
+	}
+	return (bestBid + bestAsk) / 2
+}
+
+// SpreadBps returns the best bid/ask spread in basis points of the mid
+// price, or 0 if either side is currently empty.
+func (lb *LocalOrderBook) SpreadBps() float64 {
+	lb.mu.RLock()
+	defer lb.mu.RUnlock()
+
+	bestBid, okBid := bestPrice(lb.bids, true)
+	bestAsk, okAsk := bestPrice(lb.asks, false)
+	if !okBid || !okAsk {
+		return 0
+	}
+	mid := (bestBid + bestAsk) / 2
+	if mid == 0 {
+		return 0
+	}
+	return (bestAsk - bestBid) / mid * 10000
+}
+
+// parseLevels converts Hyperliquid's string-encoded price levels into a
+// px -> bookLevel map.
+func parseLevels(raw []models.PriceLevel) (map[float64]bookLevel, error) {
+	out := make(map[float64]bookLevel, len(raw))
+	for _, l := range raw {
+		px, err := strconv.ParseFloat(l.Px, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price %q: %w", l.Px, err)
+		}
+		sz, err := strconv.ParseFloat(l.Sz, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid size %q: %w", l.Sz, err)
+		}
+		out[px] = bookLevel{sz: sz, n: l.N}
+	}
+	return out, nil
+}
+
+// diffSide returns the levels that differ between prev and next: changed
+// or added levels carry next's size, removed levels carry a zero size.
+func diffSide(prev, next map[float64]bookLevel) []BookLevel {
+	var changed []BookLevel
+	for px, level := range next {
+		if old, ok := prev[px]; !ok || old != level {
+			changed = append(changed, BookLevel{Px: px, Sz: level.sz, N: level.n})
+		}
+	}
+	for px := range prev {
+		if _, ok := next[px]; !ok {
+			changed = append(changed, BookLevel{Px: px, Sz: 0, N: 0})
+		}
+	}
+	return changed
+}
+
+// applySide mutates side in place per levels: a zero-size level removes its
+// price, anything else sets/replaces it.
+func applySide(side map[float64]bookLevel, levels []BookLevel) {
+	for _, l := range levels {
+		if l.Sz == 0 {
+			delete(side, l.Px)
+			continue
+		}
+		side[l.Px] = bookLevel{sz: l.Sz, n: l.N}
+	}
+}
+
+// sortedPrices returns up to depth prices from side, best-first: descending
+// for bids (desc == true), ascending for asks.
+func sortedPrices(side map[float64]bookLevel, desc bool, depth int) []float64 {
+	prices := make([]float64, 0, len(side))
+	for px := range side {
+		prices = append(prices, px)
+	}
+	sort.Slice(prices, func(i, j int) bool {
+		if desc {
+			return prices[i] > prices[j]
+		}
+		return prices[i] < prices[j]
+	})
+	if depth > 0 && len(prices) > depth {
+		prices = prices[:depth]
+	}
+	return prices
+}
+
+// bestPrice returns the best (highest for bids, lowest for asks) price in
+// side, and false if side is empty.
+func bestPrice(side map[float64]bookLevel, desc bool) (float64, bool) {
+	prices := sortedPrices(side, desc, 1)
+	if len(prices) == 0 {
+		return 0, false
+	}
+	return prices[0], true
+}
+
+// topLevels renders side's top depth prices back into Hyperliquid's
+// string-encoded PriceLevel shape, for BookCheckpoint.
+func topLevels(side map[float64]bookLevel, depth int, desc bool) []models.PriceLevel {
+	prices := sortedPrices(side, desc, depth)
+	out := make([]models.PriceLevel, 0, len(prices))
+	for _, px := range prices {
+		l := side[px]
+		out = append(out, models.PriceLevel{
+			Px: strconv.FormatFloat(px, 'f', -1, 64),
+			Sz: strconv.FormatFloat(l.sz, 'f', -1, 64),
+			N:  l.n,
+		})
+	}
+	return out
+}
+
+// coinKey normalizes a coin symbol for use as a map key (Hyperliquid coin
+// symbols are already case-sensitive identifiers, but trimming keeps stray
+// whitespace from a misconfigured caller from creating a duplicate book).
+func coinKey(coin string) string {
+	return strings.TrimSpace(coin)
+}
@@ -0,0 +1,410 @@
+package api
+
+import (
+	"fmt"
+	"sync"
+
+	"hyperliquid-copy-trading/internal/models"
+)
+
+// MarketDataSource abstracts over the transport that delivers Hyperliquid
+// market data, so the engine and copy-trading code can depend on channels
+// rather than a concrete websocket implementation. This is what lets a
+// self-hosted node's gRPC feed slot in for lower-latency fills without
+// rewriting downstream code - callers only ever see a MarketDataSource.
+type MarketDataSource interface {
+	SubscribeUserFills(userAddress string) (<-chan models.EnhancedTradeEvent, error)
+	SubscribeL2Book(coin string) (<-chan models.L2Book, error)
+	SubscribeAllMids() (<-chan map[string]string, error)
+	SubscribeCandles(coin, interval string) (<-chan models.Kline, error)
+	SubscribeBBO(coin string) (<-chan models.BBO, error)
+	Unsubscribe(subscriptionType, identifier string) error
+	Close() error
+}
+
+// wsMarketDataSource adapts the existing callback-based WebSocketClient to
+// MarketDataSource's channel-based surface. It is the default source: the
+// same gorilla-websocket transport chunk2-1/chunk2-2 built, just wrapped.
+type wsMarketDataSource struct {
+	client *WebSocketClient
+}
+
+// NewWebSocketMarketDataSource wraps an already-connected WebSocketClient as
+// a MarketDataSource.
+func NewWebSocketMarketDataSource(client *WebSocketClient) MarketDataSource {
+	return &wsMarketDataSource{client: client}
+}
+
+func (s *wsMarketDataSource) SubscribeUserFills(userAddress string) (<-chan models.EnhancedTradeEvent, error) {
+	ch := make(chan models.EnhancedTradeEvent, 256)
+	err := s.client.SubscribeUserFills(userAddress, func(event models.EnhancedTradeEvent) {
+		ch <- event
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+func (s *wsMarketDataSource) SubscribeL2Book(coin string) (<-chan models.L2Book, error) {
+	ch := make(chan models.L2Book, 64)
+	err := s.client.SubscribeL2Book(coin, func(book models.L2Book) {
+		ch <- book
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+func (s *wsMarketDataSource) SubscribeAllMids() (<-chan map[string]string, error) {
+	ch := make(chan map[string]string, 16)
+	err := s.client.SubscribeAllMids(func(mids map[string]string) {
+		ch <- mids
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+func (s *wsMarketDataSource) SubscribeCandles(coin, interval string) (<-chan models.Kline, error) {
+	ch := make(chan models.Kline, 64)
+	err := s.client.SubscribeCandles(coin, interval, func(kline models.Kline) {
+		ch <- kline
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+func (s *wsMarketDataSource) SubscribeBBO(coin string) (<-chan models.BBO, error) {
+	ch := make(chan models.BBO, 64)
+	err := s.client.SubscribeBBO(coin, func(bbo models.BBO) {
+		ch <- bbo
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+func (s *wsMarketDataSource) Unsubscribe(subscriptionType, identifier string) error {
+	return s.client.Unsubscribe(subscriptionType, identifier)
+}
+
+func (s *wsMarketDataSource) Close() error {
+	return s.client.Close()
+}
+
+// FakeMarketDataSource is an in-memory MarketDataSource for tests and
+// offline tooling: Push* methods feed data directly to whatever channels
+// are currently subscribed, with no network involved.
+type FakeMarketDataSource struct {
+	mu      sync.Mutex
+	fills   map[string][]chan models.EnhancedTradeEvent
+	books   map[string][]chan models.L2Book
+	mids    []chan map[string]string
+	candles map[string][]chan models.Kline
+	bbos    map[string][]chan models.BBO
+	closed  bool
+}
+
+// NewFakeMarketDataSource returns an empty FakeMarketDataSource ready to
+// accept subscriptions and Push* calls.
+func NewFakeMarketDataSource() *FakeMarketDataSource {
+	return &FakeMarketDataSource{
+		fills:   make(map[string][]chan models.EnhancedTradeEvent),
+		books:   make(map[string][]chan models.L2Book),
+		candles: make(map[string][]chan models.Kline),
+		bbos:    make(map[string][]chan models.BBO),
+	}
+}
+
+func (s *FakeMarketDataSource) SubscribeUserFills(userAddress string) (<-chan models.EnhancedTradeEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, fmt.Errorf("fake market data source is closed")
+	}
+	ch := make(chan models.EnhancedTradeEvent, 256)
+	s.fills[userAddress] = append(s.fills[userAddress], ch)
+	return ch, nil
+}
+
+func (s *FakeMarketDataSource) SubscribeL2Book(coin string) (<-chan models.L2Book, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, fmt.Errorf("fake market data source is closed")
+	}
+	ch := make(chan models.L2Book, 64)
+	s.books[coin] = append(s.books[coin], ch)
+	return ch, nil
+}
+
+func (s *FakeMarketDataSource) SubscribeAllMids() (<-chan map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, fmt.Errorf("fake market data source is closed")
+	}
+	ch := make(chan map[string]string, 16)
+	s.mids = append(s.mids, ch)
+	return ch, nil
+}
+
+func (s *FakeMarketDataSource) SubscribeCandles(coin, interval string) (<-chan models.Kline, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, fmt.Errorf("fake market data source is closed")
+	}
+	key := fmt.Sprintf("%s:%s", coin, interval)
+	ch := make(chan models.Kline, 64)
+	s.candles[key] = append(s.candles[key], ch)
+	return ch, nil
+}
+
+func (s *FakeMarketDataSource) SubscribeBBO(coin string) (<-chan models.BBO, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, fmt.Errorf("fake market data source is closed")
+	}
+	ch := make(chan models.BBO, 64)
+	s.bbos[coin] = append(s.bbos[coin], ch)
+	return ch, nil
+}
+
+// PushUserFill delivers event to every subscriber of userAddress's fills.
+func (s *FakeMarketDataSource) PushUserFill(userAddress string, event models.EnhancedTradeEvent) {
+	s.mu.Lock()
+	subs := append([]chan models.EnhancedTradeEvent(nil), s.fills[userAddress]...)
+	s.mu.Unlock()
+	for _, ch := range subs {
+		ch <- event
+	}
+}
+
+// PushL2Book delivers book to every subscriber of its coin.
+func (s *FakeMarketDataSource) PushL2Book(book models.L2Book) {
+	s.mu.Lock()
+	subs := append([]chan models.L2Book(nil), s.books[book.Coin]...)
+	s.mu.Unlock()
+	for _, ch := range subs {
+		ch <- book
+	}
+}
+
+// PushAllMids delivers mids to every allMids subscriber.
+func (s *FakeMarketDataSource) PushAllMids(mids map[string]string) {
+	s.mu.Lock()
+	subs := append([]chan map[string]string(nil), s.mids...)
+	s.mu.Unlock()
+	for _, ch := range subs {
+		ch <- mids
+	}
+}
+
+// PushCandle delivers kline to every subscriber of its coin+interval.
+func (s *FakeMarketDataSource) PushCandle(kline models.Kline) {
+	key := fmt.Sprintf("%s:%s", kline.Coin, kline.Interval)
+	s.mu.Lock()
+	subs := append([]chan models.Kline(nil), s.candles[key]...)
+	s.mu.Unlock()
+	for _, ch := range subs {
+		ch <- kline
+	}
+}
+
+// PushBBO delivers bbo to every subscriber of its coin.
+func (s *FakeMarketDataSource) PushBBO(bbo models.BBO) {
+	s.mu.Lock()
+	subs := append([]chan models.BBO(nil), s.bbos[bbo.Coin]...)
+	s.mu.Unlock()
+	for _, ch := range subs {
+		ch <- bbo
+	}
+}
+
+func (s *FakeMarketDataSource) Unsubscribe(subscriptionType, identifier string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch subscriptionType {
+	case "userFills":
+		delete(s.fills, identifier)
+	case "l2Book":
+		delete(s.books, identifier)
+	case "bbo":
+		delete(s.bbos, identifier)
+	case "allMids":
+		s.mids = nil
+	}
+	return nil
+}
+
+func (s *FakeMarketDataSource) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+// MultiSource fans in several MarketDataSources and de-duplicates user
+// fills by (coin, tid), so the same fill reported by more than one backend
+// (e.g. a gRPC node feed and a websocket failover) only reaches the caller
+// once.
+type MultiSource struct {
+	sources []MarketDataSource
+
+	seenMu sync.Mutex
+	seen   map[string]struct{} // dedup key -> present
+}
+
+// NewMultiSource fans in every source in sources.
+func NewMultiSource(sources ...MarketDataSource) *MultiSource {
+	return &MultiSource{
+		sources: sources,
+		seen:    make(map[string]struct{}),
+	}
+}
+
+func fillDedupKey(event models.EnhancedTradeEvent) string {
+	return fmt.Sprintf("%s:%d", event.Coin, event.Tid)
+}
+
+// SubscribeUserFills fans in userAddress's fills from every underlying
+// source into one channel, dropping duplicates by (coin, tid).
+func (m *MultiSource) SubscribeUserFills(userAddress string) (<-chan models.EnhancedTradeEvent, error) {
+	out := make(chan models.EnhancedTradeEvent, 256)
+	subscribed := 0
+
+	for _, source := range m.sources {
+		ch, err := source.SubscribeUserFills(userAddress)
+		if err != nil {
+			continue
+		}
+		subscribed++
+		go func(ch <-chan models.EnhancedTradeEvent) {
+			for event := range ch {
+				key := fillDedupKey(event)
+				m.seenMu.Lock()
+				_, dup := m.seen[key]
+				if !dup {
+					m.seen[key] = struct{}{}
+				}
+				m.seenMu.Unlock()
+				if !dup {
+					out <- event
+				}
+			}
+		}(ch)
+	}
+
+	if subscribed == 0 {
+		return nil, fmt.Errorf("market data: no source could subscribe to user fills for %s", userAddress)
+	}
+	return out, nil
+}
+
+// SubscribeL2Book fans in coin's L2 book from every underlying source; no
+// dedup is applied since snapshots aren't keyed by a stable id.
+func (m *MultiSource) SubscribeL2Book(coin string) (<-chan models.L2Book, error) {
+	out := make(chan models.L2Book, 64)
+	err := m.fanIn(func(s MarketDataSource) (interface{}, error) { return s.SubscribeL2Book(coin) },
+		func(v interface{}) { out <- v.(models.L2Book) })
+	return out, err
+}
+
+// SubscribeAllMids fans in all-mids updates from every underlying source.
+func (m *MultiSource) SubscribeAllMids() (<-chan map[string]string, error) {
+	out := make(chan map[string]string, 16)
+	err := m.fanIn(func(s MarketDataSource) (interface{}, error) { return s.SubscribeAllMids() },
+		func(v interface{}) { out <- v.(map[string]string) })
+	return out, err
+}
+
+// SubscribeCandles fans in coin@interval candles from every underlying source.
+func (m *MultiSource) SubscribeCandles(coin, interval string) (<-chan models.Kline, error) {
+	out := make(chan models.Kline, 64)
+	err := m.fanIn(func(s MarketDataSource) (interface{}, error) { return s.SubscribeCandles(coin, interval) },
+		func(v interface{}) { out <- v.(models.Kline) })
+	return out, err
+}
+
+// SubscribeBBO fans in coin's BBO updates from every underlying source.
+func (m *MultiSource) SubscribeBBO(coin string) (<-chan models.BBO, error) {
+	out := make(chan models.BBO, 64)
+	err := m.fanIn(func(s MarketDataSource) (interface{}, error) { return s.SubscribeBBO(coin) },
+		func(v interface{}) { out <- v.(models.BBO) })
+	return out, err
+}
+
+// fanIn subscribes via subscribe on every underlying source and forwards
+// each value it produces through deliver, run on its own goroutine per
+// source. It reports an error only if every source failed to subscribe.
+func (m *MultiSource) fanIn(subscribe func(MarketDataSource) (interface{}, error), deliver func(interface{})) error {
+	subscribed := 0
+	for _, source := range m.sources {
+		ch, err := subscribe(source)
+		if err != nil {
+			continue
+		}
+		subscribed++
+		go forwardChannel(ch, deliver)
+	}
+	if subscribed == 0 {
+		return fmt.Errorf("market data: no source could satisfy subscription")
+	}
+	return nil
+}
+
+// forwardChannel ranges over a typed channel returned as interface{} via
+// reflection-free duck typing: subscribe always returns one of the
+// <-chan T types above, so a type switch covers every case fanIn is used for.
+func forwardChannel(ch interface{}, deliver func(interface{})) {
+	switch c := ch.(type) {
+	case <-chan models.L2Book:
+		for v := range c {
+			deliver(v)
+		}
+	case <-chan map[string]string:
+		for v := range c {
+			deliver(v)
+		}
+	case <-chan models.Kline:
+		for v := range c {
+			deliver(v)
+		}
+	case <-chan models.BBO:
+		for v := range c {
+			deliver(v)
+		}
+	}
+}
+
+// Unsubscribe forwards to every underlying source; the first error, if any,
+// is returned after all sources have been attempted.
+func (m *MultiSource) Unsubscribe(subscriptionType, identifier string) error {
+	var firstErr error
+	for _, source := range m.sources {
+		if err := source.Unsubscribe(subscriptionType, identifier); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Close closes every underlying source; the first error, if any, is
+// returned after all sources have been attempted.
+func (m *MultiSource) Close() error {
+	var firstErr error
+	for _, source := range m.sources {
+		if err := source.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,205 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// RequestMetrics is a pluggable sink for the rate-limited client's
+// counters/histograms (requests, retries, latency, weight consumed). A
+// Prometheus-backed implementation wraps CounterVec/HistogramVec; the zero
+// value (noopMetrics, used when NewHyperliquidAPI isn't given one) discards
+// everything.
+type RequestMetrics interface {
+	// ObserveRequest records one completed HTTP round trip to endpoint,
+	// its weight, latency, and resulting error (nil on success).
+	ObserveRequest(endpoint string, weight int, latency time.Duration, err error)
+	// ObserveRetry records one retry of endpoint, tagged with why
+	// ("transport", "rate_limited", "nonce_expired").
+	ObserveRetry(endpoint string, reason string)
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) ObserveRequest(string, int, time.Duration, error) {}
+func (noopMetrics) ObserveRetry(string, string)                      {}
+
+// infoRequestWeightPerMinute and exchangeWeightPerMinute mirror
+// Hyperliquid's published per-address weight budgets. globalIPWeightPerMinute
+// is this process's own aggregate outbound budget across every address it
+// signs for, sized well above a single address's so it only binds once
+// enough wallets are active concurrently to plausibly trip Hyperliquid's
+// per-IP throttling.
+const (
+	infoRequestWeightPerMinute = 1200
+	exchangeWeightPerMinute    = 1200
+	globalIPWeightPerMinute    = 1200 * 8
+)
+
+// ErrRateLimitExhausted is returned (wrapped) by HyperliquidAPI's request
+// path when every retry of a call still came back HTTP 429, as opposed to
+// giving up for some other reason. A caller doing its own batch-level
+// scheduling (OrderEngine) uses errors.Is against this to tell "back off
+// and re-enqueue" apart from a hard failure worth dropping.
+var ErrRateLimitExhausted = errors.New("hyperliquid: rate limit exhausted after max retries")
+
+// weightFor returns the request weight Hyperliquid charges for endpoint.
+// /info requests cost a flat 1; /exchange requests scale with how many
+// orders are in the batch, matching Hyperliquid's published
+// "1 + floor(batchSize/40)" schedule.
+func weightFor(endpoint string, batchSize int) int {
+	if endpoint != "/exchange" {
+		return 1
+	}
+	if batchSize <= 1 {
+		return 1
+	}
+	return 1 + batchSize/40
+}
+
+// tokenBucket is a weight-based rate limiter: tokens refill continuously
+// at refillPerSec up to capacity, and take blocks until enough are
+// available or ctx is done.
+type tokenBucket struct {
+	mu           sync.Mutex
+	capacity     float64
+	tokens       float64
+	refillPerSec float64
+	lastRefill   time.Time
+}
+
+func newTokenBucket(capacityPerMinute int) *tokenBucket {
+	return &tokenBucket{
+		capacity:     float64(capacityPerMinute),
+		tokens:       float64(capacityPerMinute),
+		refillPerSec: float64(capacityPerMinute) / 60,
+		lastRefill:   time.Now(),
+	}
+}
+
+// drain zeroes the bucket's available tokens, forcing the next take to wait
+// until its weight has refilled from empty. Used when a request comes back rate-limited
+// anyway despite the bucket reporting room, so the caller backs off harder
+// than the bucket's own bookkeeping would otherwise have it do.
+func (b *tokenBucket) drain() {
+	b.mu.Lock()
+	b.tokens = 0
+	b.mu.Unlock()
+}
+
+func (b *tokenBucket) take(ctx context.Context, weight int) error {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.lastRefill).Seconds()*b.refillPerSec)
+		b.lastRefill = now
+
+		if b.tokens >= float64(weight) {
+			b.tokens -= float64(weight)
+			b.mu.Unlock()
+			return nil
+		}
+
+		wait := time.Duration((float64(weight) - b.tokens) / b.refillPerSec * float64(time.Second))
+		b.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// rateLimitedClient wraps makeRequest's HTTP round trips with a
+// per-endpoint+identity weight budget, 429-aware exponential backoff with
+// jitter, and pluggable RequestMetrics. identity is the API wallet address
+// for /exchange calls and the queried user address (or "" for
+// account-agnostic endpoints like meta/allMids) for /info calls.
+type rateLimitedClient struct {
+	metrics RequestMetrics
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	global  *tokenBucket
+
+	maxRetries int
+}
+
+func newRateLimitedClient(metrics RequestMetrics) *rateLimitedClient {
+	if metrics == nil {
+		metrics = noopMetrics{}
+	}
+	return &rateLimitedClient{
+		metrics:    metrics,
+		buckets:    make(map[string]*tokenBucket),
+		global:     newTokenBucket(globalIPWeightPerMinute),
+		maxRetries: 5,
+	}
+}
+
+func (c *rateLimitedClient) bucketFor(endpoint, identity string) *tokenBucket {
+	key := endpoint + ":" + identity
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, ok := c.buckets[key]
+	if ok {
+		return b
+	}
+
+	capacity := infoRequestWeightPerMinute
+	if endpoint == "/exchange" {
+		capacity = exchangeWeightPerMinute
+	}
+	b = newTokenBucket(capacity)
+	c.buckets[key] = b
+	return b
+}
+
+// acquire blocks until both the process-wide IP bucket and endpoint+
+// identity's own bucket have weight available; reporting the attempt's
+// outcome is left to the caller via record and retry. The global bucket is
+// taken first so a single saturated address can't let its wallet-level
+// bucket drain the shared IP budget out from under every other wallet.
+func (c *rateLimitedClient) acquire(ctx context.Context, endpoint, identity string, weight int) error {
+	if err := c.global.take(ctx, weight); err != nil {
+		return err
+	}
+	return c.bucketFor(endpoint, identity).take(ctx, weight)
+}
+
+// drain zeroes both endpoint+identity's bucket and the global IP bucket,
+// called after a request comes back rate-limited despite a bucket that
+// thought it had room -- Hyperliquid's own limiter is the ground truth.
+func (c *rateLimitedClient) drain(endpoint, identity string) {
+	c.bucketFor(endpoint, identity).drain()
+	c.global.drain()
+}
+
+func (c *rateLimitedClient) record(endpoint string, weight int, latency time.Duration, err error) {
+	c.metrics.ObserveRequest(endpoint, weight, latency, err)
+}
+
+func (c *rateLimitedClient) retry(endpoint, reason string) {
+	c.metrics.ObserveRetry(endpoint, reason)
+}
+
+// backoff sleeps an exponentially growing, jittered delay for attempt
+// (0-indexed), returning false if ctx expires first.
+func (c *rateLimitedClient) backoff(ctx context.Context, attempt int) bool {
+	base := time.Duration(1<<uint(attempt)) * 200 * time.Millisecond
+	jitter := time.Duration(rand.Int63n(int64(base)/2 + 1))
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(base + jitter):
+		return true
+	}
+}
@@ -2,15 +2,17 @@ package api
 
 import (
 	"crypto/ecdsa"
+	"encoding/binary"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
 	"strconv"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/vmihailenco/msgpack/v5"
 )
 
 type Signer struct {
@@ -35,13 +37,43 @@ func NewSigner(privateKeyHex string) (*Signer, error) {
 	return &Signer{privateKey: privateKey}, nil
 }
 
-func (s *Signer) SignAction(action interface{}, walletAddress string, nonce int64) (map[string]interface{}, error) {
-	// Create the EIP-712 typed data structure for Hyperliquid
-	actionBytes, err := json.Marshal(action)
+// zeroAddress is the EIP-712 verifyingContract Hyperliquid expects in every
+// domain it signs against: L1 actions and user-signed actions alike aren't
+// actually calls into a contract, so there's nothing real to put there.
+const zeroAddress = "0x0000000000000000000000000000000000000000"
+
+// phantomAgentDomain is the fixed EIP-712 domain Hyperliquid's L1 actions
+// (orders, cancels, batching, modify, subaccount management, ...) are
+// signed against. chainId 1337 and the "Exchange" name are constants of
+// Hyperliquid's L1, unrelated to the Arbitrum chain the wallet itself
+// lives on.
+var phantomAgentDomain = apitypes.TypedDataDomain{
+	Name:              "Exchange",
+	Version:           "1",
+	ChainId:           math.NewHexOrDecimal256(1337),
+	VerifyingContract: zeroAddress,
+}
+
+// SignL1Action signs action the way Hyperliquid's exchange actually
+// verifies it: action is msgpack-encoded and hashed together with nonce
+// and vaultAddress into a connectionId, which is wrapped in a phantomAgent
+// and EIP-712 signed against the fixed Exchange domain. This is the path
+// for every L1 action -- order placement, cancellation, batching, modify,
+// and subaccount management -- as opposed to SignUserSignedAction's real
+// Arbitrum domain for withdraw/approveAgent. isMainnet selects the
+// phantomAgent's "a"/"b" source, which Hyperliquid uses to keep mainnet
+// and testnet signatures from colliding.
+func (s *Signer) SignL1Action(action interface{}, vaultAddress *string, nonce int64, isMainnet bool) (map[string]interface{}, error) {
+	connectionID, err := l1ConnectionID(action, vaultAddress, nonce)
 	if err != nil {
 		return nil, err
 	}
 
+	source := "b"
+	if isMainnet {
+		source = "a"
+	}
+
 	typedData := apitypes.TypedData{
 		Types: apitypes.Types{
 			"EIP712Domain": []apitypes.Type{
@@ -50,27 +82,159 @@ func (s *Signer) SignAction(action interface{}, walletAddress string, nonce int6
 				{Name: "chainId", Type: "uint256"},
 				{Name: "verifyingContract", Type: "address"},
 			},
-			"HyperliquidTransaction": []apitypes.Type{
-				{Name: "action", Type: "string"},
-				{Name: "nonce", Type: "uint64"},
+			"Agent": []apitypes.Type{
+				{Name: "source", Type: "string"},
+				{Name: "connectionId", Type: "bytes32"},
+			},
+		},
+		PrimaryType: "Agent",
+		Domain:      phantomAgentDomain,
+		Message: apitypes.TypedDataMessage{
+			"source":       source,
+			"connectionId": connectionID.Bytes(),
+		},
+	}
+
+	return s.signTypedData(typedData)
+}
+
+// l1ConnectionID packs action via msgpack -- Hyperliquid's validators
+// verify against these bytes, not a JSON encoding -- appends the
+// big-endian nonce, then a vault-presence flag byte and the vault's 20
+// address bytes when one is set, and keccak256s the result. This is the
+// connectionId the signed phantomAgent commits to.
+func l1ConnectionID(action interface{}, vaultAddress *string, nonce int64) (common.Hash, error) {
+	packed, err := msgpack.Marshal(action)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("msgpack-encoding action: %w", err)
+	}
+
+	var nonceBytes [8]byte
+	binary.BigEndian.PutUint64(nonceBytes[:], uint64(nonce))
+	packed = append(packed, nonceBytes[:]...)
+
+	if vaultAddress == nil || *vaultAddress == "" {
+		packed = append(packed, 0x00)
+	} else {
+		packed = append(packed, 0x01)
+		packed = append(packed, common.HexToAddress(*vaultAddress).Bytes()...)
+	}
+
+	return crypto.Keccak256Hash(packed), nil
+}
+
+// userSignedActionType describes one of Hyperliquid's user-signed actions
+// (withdraw, approveAgent, ...): unlike L1 actions these are signed
+// directly against the real Arbitrum domain, so each needs its own
+// EIP-712 field list alongside the shared domain.
+type userSignedActionType struct {
+	primaryType string
+	fields      []apitypes.Type
+}
+
+var withdrawActionType = userSignedActionType{
+	primaryType: "HyperliquidTransaction:Withdraw",
+	fields: []apitypes.Type{
+		{Name: "hyperliquidChain", Type: "string"},
+		{Name: "destination", Type: "string"},
+		{Name: "amount", Type: "string"},
+		{Name: "time", Type: "uint64"},
+	},
+}
+
+// SignUserSignedAction signs a user-signed action (withdraw, agent
+// approval, ...) against Hyperliquid's real Arbitrum domain. message must
+// already carry every field actionType.fields declares.
+func (s *Signer) SignUserSignedAction(actionType userSignedActionType, message apitypes.TypedDataMessage) (map[string]interface{}, error) {
+	typedData := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": []apitypes.Type{
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			actionType.primaryType: actionType.fields,
+		},
+		PrimaryType: actionType.primaryType,
+		Domain: apitypes.TypedDataDomain{
+			Name:              "HyperliquidSignTransaction",
+			Version:           "1",
+			ChainId:           math.NewHexOrDecimal256(arbitrumChainID),
+			VerifyingContract: zeroAddress,
+		},
+		Message: message,
+	}
+
+	return s.signTypedData(typedData)
+}
+
+// approveAgentActionType is approveAgent's EIP-712 field list, signed
+// against the same real Arbitrum domain as withdrawActionType. Unlike
+// every Sign* method in this file, approveAgent is never signed by a
+// Signer this server holds -- it must come from the follower's own
+// master wallet, so only BuildApproveAgentTypedData (no signing) builds
+// against it.
+var approveAgentActionType = userSignedActionType{
+	primaryType: "HyperliquidTransaction:ApproveAgent",
+	fields: []apitypes.Type{
+		{Name: "hyperliquidChain", Type: "string"},
+		{Name: "agentAddress", Type: "address"},
+		{Name: "agentName", Type: "string"},
+		{Name: "nonce", Type: "uint64"},
+	},
+}
+
+// BuildApproveAgentTypedData returns the EIP-712 typed data a follower's
+// master wallet must sign out-of-band to authorize agentAddress (an
+// ephemeral agent wallet GenerateAgentKey produced) to trade on its
+// behalf. Deliberately the mirror image of SignUserSignedAction: it only
+// builds the message, it never signs it, since this server must never
+// hold the master wallet's private key.
+func BuildApproveAgentTypedData(hyperliquidChain, agentAddress, agentName string, nonce int64) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": []apitypes.Type{
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
 				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
 			},
+			approveAgentActionType.primaryType: approveAgentActionType.fields,
 		},
-		PrimaryType: "HyperliquidTransaction",
+		PrimaryType: approveAgentActionType.primaryType,
 		Domain: apitypes.TypedDataDomain{
 			Name:              "HyperliquidSignTransaction",
 			Version:           "1",
-			ChainId:           math.NewHexOrDecimal256(42161), // Arbitrum Chain ID
-			VerifyingContract: walletAddress,
+			ChainId:           math.NewHexOrDecimal256(arbitrumChainID),
+			VerifyingContract: zeroAddress,
 		},
 		Message: apitypes.TypedDataMessage{
-			"action":  string(actionBytes),
-			"nonce":   strconv.FormatInt(nonce, 10),
-			"chainId": "42161",
+			"hyperliquidChain": hyperliquidChain,
+			"agentAddress":     agentAddress,
+			"agentName":        agentName,
+			"nonce":            strconv.FormatInt(nonce, 10),
 		},
 	}
+}
 
-	// Hash and sign the typed data
+// SignWithdraw signs a Hyperliquid withdraw action moving amount USDC to
+// destination. hyperliquidChain is "Mainnet" or "Testnet", Hyperliquid's
+// own convention for distinguishing environments that both sign against
+// the same Arbitrum domain.
+func (s *Signer) SignWithdraw(hyperliquidChain, destination, amount string, timeMs int64) (map[string]interface{}, error) {
+	return s.SignUserSignedAction(withdrawActionType, apitypes.TypedDataMessage{
+		"hyperliquidChain": hyperliquidChain,
+		"destination":      destination,
+		"amount":           amount,
+		"time":             strconv.FormatInt(timeMs, 10),
+	})
+}
+
+// signTypedData hashes and signs an already-built EIP-712 typed data
+// struct; SignL1Action and SignUserSignedAction differ only in how they
+// build typedData, not in how it's hashed or signed.
+func (s *Signer) signTypedData(typedData apitypes.TypedData) (map[string]interface{}, error) {
 	domainSeparator, err := typedData.HashStruct("EIP712Domain", typedData.Domain.Map())
 	if err != nil {
 		return nil, err
@@ -101,7 +265,6 @@ func (s *Signer) SignAction(action interface{}, walletAddress string, nonce int6
 	}, nil
 }
 
-
 func (s *Signer) GetAddress() string {
 	publicKey := s.privateKey.Public()
 	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
@@ -4,166 +4,726 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"hyperliquid-copy-trading/internal/models"
+	"math/rand"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"hyperliquid-copy-trading/internal/models"
+
 	"github.com/gorilla/websocket"
 	"github.com/rs/zerolog/log"
 )
 
-// WebSocketClient handles real-time connections to Hyperliquid
+// ConnectionState describes WebSocketClient's current link health, so
+// downstream consumers (the copy engine in particular) can pause new copy
+// trades while the feed is Degraded instead of silently trading on a stale
+// or gapped stream.
+type ConnectionState int
+
+const (
+	StateConnecting ConnectionState = iota
+	StateConnected
+	StateReconnecting
+	StateDegraded
+)
+
+func (s ConnectionState) String() string {
+	switch s {
+	case StateConnecting:
+		return "connecting"
+	case StateConnected:
+		return "connected"
+	case StateReconnecting:
+		return "reconnecting"
+	case StateDegraded:
+		return "degraded"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	reconnectInitialBackoff = 500 * time.Millisecond
+	reconnectMaxBackoff     = 30 * time.Second
+	// degradedAfterAttempts is the reconnect attempt count after which the
+	// client reports StateDegraded rather than StateReconnecting, so a
+	// prolonged outage is distinguishable from a brief blip.
+	degradedAfterAttempts = 3
+	writeQueueSize        = 256
+	pongWait              = 60 * time.Second
+	pingInterval          = 20 * time.Second
+)
+
+// WebSocketClient handles real-time connections to Hyperliquid, with
+// automatic reconnection, subscription replay and REST-backfilled gap
+// recovery so a dropped connection never silently loses a fill.
 type WebSocketClient struct {
-	conn          *websocket.Conn
-	api           *HyperliquidAPI
-	subscriptions map[string]bool
+	api *HyperliquidAPI
+	url string
+
+	connMutex sync.RWMutex
+	conn      *websocket.Conn
+
+	subMutex      sync.RWMutex
+	subscriptions map[string]models.WebSocketMessage // keyed by subscriptionKey, replayed on reconnect
 	handlers      map[string][]func(interface{})
-	mutex         sync.RWMutex
-	ctx           context.Context
-	cancel        context.CancelFunc
+	lastFillSeen  map[string]int64 // userAddress -> last-seen fill time (ms), for backfill
+
+	booksMu        sync.Mutex
+	books          map[string]*LocalOrderBook     // coinKey -> reconstructed local book
+	deltaHandlers  map[string][]func(BookDelta)    // coinKey -> SubscribeBookDelta subscribers
+	checkpoints    map[string][]*checkpointStream  // coinKey -> SubscribeBookCheckpoint subscribers
+	resyncHandlers []func()                        // fired whenever a reconnect invalidates every local book
+
+	writeQueue chan websocketWrite
+
+	stateMu sync.RWMutex
+	state   ConnectionState
+	stateCh chan ConnectionState
+
+	nextID     uint64 // atomic; incremented by nextRequestID
+	pendingMu  sync.Mutex
+	pending    map[uint64]chan *ackResponse
+	ackMetrics ackMetrics
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// ackResponse is the server's reply to one id-carrying subscribe/
+// unsubscribe/post message, decoded loosely since Hyperliquid's ack and
+// error payload shapes differ by channel.
+type ackResponse struct {
+	Channel string          `json:"channel"`
+	Data    json.RawMessage `json:"data"`
+	err     error
 }
 
-// NewWebSocketClient creates a new WebSocket client
+// ackMetrics tracks round-trip latency of acked requests, so callers can
+// tell a slow-but-alive link apart from one that's actually stuck.
+type ackMetrics struct {
+	mu        sync.Mutex
+	count     int64
+	totalWait time.Duration
+}
+
+func (m *ackMetrics) record(d time.Duration) {
+	m.mu.Lock()
+	m.count++
+	m.totalWait += d
+	m.mu.Unlock()
+}
+
+// average returns the mean ack latency observed so far, or zero if none
+// have completed yet.
+func (m *ackMetrics) average() time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.count == 0 {
+		return 0
+	}
+	return m.totalWait / time.Duration(m.count)
+}
+
+// defaultAckTimeout bounds how long sendAwaitingAck waits for a server
+// acknowledgement before giving up, so a silently-dropped request doesn't
+// hang a Subscribe/Post call forever.
+const defaultAckTimeout = 10 * time.Second
+
+// websocketWrite is one queued outbound message; done carries the write's
+// result back to the caller so Subscribe/Unsubscribe/Ping can still return
+// an error synchronously.
+type websocketWrite struct {
+	msg  interface{}
+	done chan error
+}
+
+// NewWebSocketClient creates a new WebSocket client and starts its
+// reconnect supervisor, write pump and ping loop.
 func NewWebSocketClient(api *HyperliquidAPI) (*WebSocketClient, error) {
 	wsURL := "wss://api.hyperliquid.xyz/ws"
 	if api.config.Environment == "testnet" {
 		wsURL = "wss://api-testnet.hyperliquid.xyz/ws"
 	}
 
-	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to WebSocket: %w", err)
-	}
+	return newWebSocketClient(api, wsURL)
+}
 
+// newWebSocketClient is NewWebSocketClient with the endpoint taken as a
+// parameter instead of derived from api.config.Environment, so tests can
+// point it at a local httptest server instead of Hyperliquid's real one.
+func newWebSocketClient(api *HyperliquidAPI, wsURL string) (*WebSocketClient, error) {
 	ctx, cancel := context.WithCancel(context.Background())
-
 	client := &WebSocketClient{
-		conn:          conn,
 		api:           api,
-		subscriptions: make(map[string]bool),
+		url:           wsURL,
+		subscriptions: make(map[string]models.WebSocketMessage),
 		handlers:      make(map[string][]func(interface{})),
+		lastFillSeen:  make(map[string]int64),
+		books:         make(map[string]*LocalOrderBook),
+		deltaHandlers: make(map[string][]func(BookDelta)),
+		checkpoints:   make(map[string][]*checkpointStream),
+		pending:       make(map[uint64]chan *ackResponse),
+		writeQueue:    make(chan websocketWrite, writeQueueSize),
+		stateCh:       make(chan ConnectionState, 8),
 		ctx:           ctx,
 		cancel:        cancel,
 	}
 
-	// Start message handling goroutine
-	go client.handleMessages()
+	conn, err := client.dial()
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to connect to WebSocket: %w", err)
+	}
+	client.setConn(conn)
+	client.setState(StateConnected)
+
+	client.wg.Add(2)
+	go func() { defer client.wg.Done(); client.writePump() }()
+	go func() { defer client.wg.Done(); client.pingLoop() }()
+
+	client.wg.Add(1)
+	go func() { defer client.wg.Done(); client.supervise() }()
 
 	return client, nil
 }
 
-// SubscribeUserFills subscribes to user fills for real-time trade replication
-func (ws *WebSocketClient) SubscribeUserFills(userAddress string, handler func(models.EnhancedTradeEvent)) error {
-	subscriptionKey := fmt.Sprintf("userFills:%s", userAddress)
+// dial opens a fresh connection and arms its pong deadline/handler.
+func (ws *WebSocketClient) dial() (*websocket.Conn, error) {
+	conn, _, err := websocket.DefaultDialer.Dial(ws.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		conn.SetReadDeadline(time.Now().Add(pongWait))
+		return nil
+	})
+	return conn, nil
+}
 
-	ws.mutex.Lock()
-	if ws.subscriptions[subscriptionKey] {
-		ws.mutex.Unlock()
-		return fmt.Errorf("already subscribed to user fills for %s", userAddress)
+func (ws *WebSocketClient) setConn(conn *websocket.Conn) {
+	ws.connMutex.Lock()
+	ws.conn = conn
+	ws.connMutex.Unlock()
+}
+
+func (ws *WebSocketClient) getConn() *websocket.Conn {
+	ws.connMutex.RLock()
+	defer ws.connMutex.RUnlock()
+	return ws.conn
+}
+
+// ConnectionStateChanges returns the channel of connection-state
+// transitions; the engine should read from it to pause new copy trades
+// while the feed is Reconnecting or Degraded.
+func (ws *WebSocketClient) ConnectionStateChanges() <-chan ConnectionState {
+	return ws.stateCh
+}
+
+// State returns the current connection state.
+func (ws *WebSocketClient) State() ConnectionState {
+	ws.stateMu.RLock()
+	defer ws.stateMu.RUnlock()
+	return ws.state
+}
+
+func (ws *WebSocketClient) setState(s ConnectionState) {
+	ws.stateMu.Lock()
+	ws.state = s
+	ws.stateMu.Unlock()
+
+	select {
+	case ws.stateCh <- s:
+	default:
+		// A slow or absent consumer shouldn't block the supervisor loop;
+		// State() is always available as a pull-based fallback.
 	}
-	ws.subscriptions[subscriptionKey] = true
+}
+
+// supervise owns the read side of the connection's lifecycle: it runs
+// readLoop against the current conn, and on any read failure reconnects
+// with exponential backoff + jitter, replaying subscriptions and
+// backfilling any fills missed while disconnected.
+func (ws *WebSocketClient) supervise() {
+	for {
+		ws.readLoop(ws.getConn())
 
-	// Add handler
-	if ws.handlers[subscriptionKey] == nil {
-		ws.handlers[subscriptionKey] = make([]func(interface{}), 0)
+		if ws.ctx.Err() != nil {
+			return
+		}
+		if !ws.reconnect() {
+			return // client was closed mid-retry
+		}
 	}
-	ws.handlers[subscriptionKey] = append(ws.handlers[subscriptionKey], func(data interface{}) {
-		if tradeEvent, ok := data.(models.EnhancedTradeEvent); ok {
-			handler(tradeEvent)
+}
+
+// readLoop blocks reading JSON frames off conn until ctx is done or the
+// connection errors, dispatching each message to processMessage.
+func (ws *WebSocketClient) readLoop(conn *websocket.Conn) {
+	for {
+		if ws.ctx.Err() != nil {
+			return
 		}
-	})
-	ws.mutex.Unlock()
 
+		var msg models.WebSocketMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			if ws.ctx.Err() == nil {
+				log.Warn().Err(err).Msg("WebSocket read error, will reconnect")
+			}
+			return
+		}
+
+		ws.processMessage(msg)
+	}
+}
+
+// reconnect retries dial with exponential backoff + jitter until it
+// succeeds or the client is closed. Returns false if the client was closed
+// mid-retry.
+func (ws *WebSocketClient) reconnect() bool {
+	ws.setState(StateReconnecting)
+	backoff := reconnectInitialBackoff
+	attempt := 0
+
+	for {
+		attempt++
+		if attempt > degradedAfterAttempts {
+			ws.setState(StateDegraded)
+		}
+
+		select {
+		case <-ws.ctx.Done():
+			return false
+		case <-time.After(jitter(backoff)):
+		}
+
+		conn, err := ws.dial()
+		if err != nil {
+			log.Warn().Err(err).Int("attempt", attempt).Msg("WebSocket reconnect failed")
+			backoff = nextBackoff(backoff)
+			continue
+		}
+
+		ws.setConn(conn)
+		ws.setState(StateConnected)
+		ws.replaySubscriptions()
+		ws.backfillMissedFills()
+		ws.invalidateBooks()
+		return true
+	}
+}
+
+// nextBackoff doubles cur, capped at reconnectMaxBackoff.
+func nextBackoff(cur time.Duration) time.Duration {
+	next := cur * 2
+	if next > reconnectMaxBackoff {
+		return reconnectMaxBackoff
+	}
+	return next
+}
+
+// jitter randomizes a backoff duration to between half and all of d so many clients
+// reconnecting at once don't all retry in lockstep.
+func jitter(d time.Duration) time.Duration {
+	delta := time.Duration(rand.Int63n(int64(d)/2 + 1))
+	return d/2 + delta
+}
+
+// replaySubscriptions re-sends every active subscription over the new
+// connection; Hyperliquid's server has no memory of the prior one.
+func (ws *WebSocketClient) replaySubscriptions() {
+	ws.subMutex.RLock()
+	subs := make([]models.WebSocketMessage, 0, len(ws.subscriptions))
+	for _, sub := range ws.subscriptions {
+		subs = append(subs, sub)
+	}
+	ws.subMutex.RUnlock()
+
+	for _, sub := range subs {
+		if err := ws.send(sub); err != nil {
+			log.Error().Err(err).Msg("Failed to replay WebSocket subscription")
+		}
+	}
+}
+
+// backfillMissedFills calls the REST GetUserFills endpoint for every active
+// userFills subscription and replays any fill newer than the last one seen
+// over the socket, so a reconnect gap never reaches downstream consumers
+// silently.
+func (ws *WebSocketClient) backfillMissedFills() {
+	const prefix = "userFills:"
+
+	ws.subMutex.RLock()
+	type target struct {
+		user     string
+		lastSeen int64
+	}
+	var targets []target
+	for key := range ws.subscriptions {
+		if strings.HasPrefix(key, prefix) {
+			user := strings.TrimPrefix(key, prefix)
+			targets = append(targets, target{user: user, lastSeen: ws.lastFillSeen[user]})
+		}
+	}
+	ws.subMutex.RUnlock()
+
+	for _, t := range targets {
+		ctx, cancel := context.WithTimeout(ws.ctx, 10*time.Second)
+		fills, err := ws.api.GetUserFills(ctx, t.user)
+		cancel()
+		if err != nil {
+			log.Warn().Err(err).Str("user", t.user).Msg("Failed to backfill missed fills")
+			continue
+		}
+
+		for _, fill := range fills {
+			if fill.Time <= t.lastSeen {
+				continue
+			}
+			ws.dispatchUserFill(t.user, fill)
+		}
+	}
+}
+
+// send enqueues msg on the bounded write queue and waits for the write
+// pump to report its result, so callers keep a synchronous error return
+// without writing to conn directly from multiple goroutines.
+func (ws *WebSocketClient) send(msg interface{}) error {
+	write := websocketWrite{msg: msg, done: make(chan error, 1)}
+
+	select {
+	case ws.writeQueue <- write:
+	case <-ws.ctx.Done():
+		return fmt.Errorf("websocket client is closed")
+	}
+
+	select {
+	case err := <-write.done:
+		return err
+	case <-ws.ctx.Done():
+		return fmt.Errorf("websocket client is closed")
+	}
+}
+
+// nextRequestID returns the next monotonically increasing request id used
+// to correlate an outbound subscribe/unsubscribe/post message with its
+// acknowledgement.
+func (ws *WebSocketClient) nextRequestID() uint64 {
+	return atomic.AddUint64(&ws.nextID, 1)
+}
+
+// sendAwaitingAck sends msg (which must carry id in its "id" field) and
+// blocks until the server acks it, ctx is done, or defaultAckTimeout
+// elapses - whichever comes first. The wait is recorded in ws.ackMetrics
+// regardless of outcome so a slow link shows up even when requests time out.
+func (ws *WebSocketClient) sendAwaitingAck(ctx context.Context, id uint64, msg interface{}) (json.RawMessage, error) {
+	ch := make(chan *ackResponse, 1)
+	ws.pendingMu.Lock()
+	ws.pending[id] = ch
+	ws.pendingMu.Unlock()
+
+	started := time.Now()
+	defer func() {
+		ws.pendingMu.Lock()
+		delete(ws.pending, id)
+		ws.pendingMu.Unlock()
+	}()
+
+	if err := ws.send(msg); err != nil {
+		return nil, err
+	}
+
+	ackCtx, cancel := context.WithTimeout(ctx, defaultAckTimeout)
+	defer cancel()
+
+	select {
+	case resp := <-ch:
+		ws.ackMetrics.record(time.Since(started))
+		if resp.err != nil {
+			return nil, resp.err
+		}
+		return resp.Data, nil
+	case <-ackCtx.Done():
+		ws.ackMetrics.record(time.Since(started))
+		return nil, fmt.Errorf("websocket: request %d: %w", id, ackCtx.Err())
+	}
+}
+
+// resolveAck routes an id-carrying server message to the pending request
+// that's waiting on it, if any. Unknown ids (acks for requests that already
+// timed out) are dropped silently.
+func (ws *WebSocketClient) resolveAck(id uint64, resp *ackResponse) {
+	ws.pendingMu.Lock()
+	ch, ok := ws.pending[id]
+	ws.pendingMu.Unlock()
+	if !ok {
+		return
+	}
+
+	select {
+	case ch <- resp:
+	default:
+	}
+}
+
+// AverageAckLatency returns the mean round-trip time of acked subscribe/
+// unsubscribe/post requests observed so far.
+func (ws *WebSocketClient) AverageAckLatency() time.Duration {
+	return ws.ackMetrics.average()
+}
+
+// Post sends a Hyperliquid "post" action (an info or action request) over
+// the existing websocket connection and waits for its result, avoiding an
+// HTTPS round-trip on the hot path. requestType is "info" or "action" per
+// Hyperliquid's post-over-websocket protocol.
+func (ws *WebSocketClient) Post(ctx context.Context, requestType string, payload interface{}) (json.RawMessage, error) {
+	id := ws.nextRequestID()
+	msg := models.WebSocketMessage{
+		Method: "post",
+		ID:     &id,
+		Request: map[string]interface{}{
+			"type":    requestType,
+			"payload": payload,
+		},
+	}
+
+	return ws.sendAwaitingAck(ctx, id, msg)
+}
+
+// writePump is the sole writer of the connection for the client's entire
+// lifetime (across reconnects), serializing every Subscribe/Unsubscribe/
+// Ping/replay write through the bounded queue.
+func (ws *WebSocketClient) writePump() {
+	for {
+		select {
+		case <-ws.ctx.Done():
+			return
+		case write := <-ws.writeQueue:
+			conn := ws.getConn()
+			var err error
+			if conn == nil {
+				err = fmt.Errorf("no active websocket connection")
+			} else {
+				err = conn.WriteJSON(write.msg)
+			}
+			write.done <- err
+		}
+	}
+}
+
+// pingLoop periodically pings the current connection; combined with
+// dial's pong handler resetting the read deadline, a peer that stops
+// responding to pings causes readLoop's next ReadJSON to time out and
+// trigger a reconnect rather than hanging forever.
+func (ws *WebSocketClient) pingLoop() {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ws.ctx.Done():
+			return
+		case <-ticker.C:
+			conn := ws.getConn()
+			if conn == nil {
+				continue
+			}
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(5*time.Second)); err != nil {
+				log.Warn().Err(err).Msg("Failed to send WebSocket ping")
+			}
+		}
+	}
+}
+
+// SubscribeUserFills subscribes to user fills for real-time trade replication,
+// returning only once the server has acked the subscription (or reports an
+// error, or defaultAckTimeout elapses).
+func (ws *WebSocketClient) SubscribeUserFills(userAddress string, handler func(models.EnhancedTradeEvent)) error {
+	subscriptionKey := fmt.Sprintf("userFills:%s", userAddress)
+
+	ws.subMutex.Lock()
+	if _, exists := ws.subscriptions[subscriptionKey]; exists {
+		ws.subMutex.Unlock()
+		return fmt.Errorf("already subscribed to user fills for %s", userAddress)
+	}
+
+	id := ws.nextRequestID()
 	subscription := models.WebSocketMessage{
 		Method: "subscribe",
+		ID:     &id,
 		Subscription: map[string]interface{}{
 			"type": "userFills",
 			"user": userAddress,
 		},
 	}
+	ws.subscriptions[subscriptionKey] = subscription
 
-	return ws.conn.WriteJSON(subscription)
+	ws.handlers[subscriptionKey] = append(ws.handlers[subscriptionKey], func(data interface{}) {
+		if tradeEvent, ok := data.(models.EnhancedTradeEvent); ok {
+			handler(tradeEvent)
+		}
+	})
+	ws.subMutex.Unlock()
+
+	_, err := ws.sendAwaitingAck(ws.ctx, id, subscription)
+	return err
 }
 
-// SubscribeL2Book subscribes to L2 order book updates
+// SubscribeL2Book subscribes to L2 order book updates, returning only once
+// the server has acked the subscription.
 func (ws *WebSocketClient) SubscribeL2Book(coin string, handler func(models.L2Book)) error {
 	subscriptionKey := fmt.Sprintf("l2Book:%s", coin)
 
-	ws.mutex.Lock()
-	if ws.subscriptions[subscriptionKey] {
-		ws.mutex.Unlock()
+	ws.subMutex.Lock()
+	if _, exists := ws.subscriptions[subscriptionKey]; exists {
+		ws.subMutex.Unlock()
 		return fmt.Errorf("already subscribed to L2 book for %s", coin)
 	}
-	ws.subscriptions[subscriptionKey] = true
-
-	// Add handler
-	if ws.handlers[subscriptionKey] == nil {
-		ws.handlers[subscriptionKey] = make([]func(interface{}), 0)
-	}
-	ws.handlers[subscriptionKey] = append(ws.handlers[subscriptionKey], func(data interface{}) {
-		if l2Book, ok := data.(models.L2Book); ok {
-			handler(l2Book)
-		}
-	})
-	ws.mutex.Unlock()
 
+	id := ws.nextRequestID()
 	subscription := models.WebSocketMessage{
 		Method: "subscribe",
+		ID:     &id,
 		Subscription: map[string]interface{}{
 			"type": "l2Book",
 			"coin": coin,
 		},
 	}
+	ws.subscriptions[subscriptionKey] = subscription
+
+	ws.handlers[subscriptionKey] = append(ws.handlers[subscriptionKey], func(data interface{}) {
+		if l2Book, ok := data.(models.L2Book); ok {
+			handler(l2Book)
+		}
+	})
+	ws.subMutex.Unlock()
 
-	return ws.conn.WriteJSON(subscription)
+	_, err := ws.sendAwaitingAck(ws.ctx, id, subscription)
+	return err
 }
 
-// SubscribeAllMids subscribes to all mid prices
+// SubscribeAllMids subscribes to all mid prices, returning only once the
+// server has acked the subscription.
 func (ws *WebSocketClient) SubscribeAllMids(handler func(map[string]string)) error {
 	subscriptionKey := "allMids"
 
-	ws.mutex.Lock()
-	if ws.subscriptions[subscriptionKey] {
-		ws.mutex.Unlock()
+	ws.subMutex.Lock()
+	if _, exists := ws.subscriptions[subscriptionKey]; exists {
+		ws.subMutex.Unlock()
 		return fmt.Errorf("already subscribed to all mids")
 	}
-	ws.subscriptions[subscriptionKey] = true
 
-	// Add handler
-	if ws.handlers[subscriptionKey] == nil {
-		ws.handlers[subscriptionKey] = make([]func(interface{}), 0)
+	id := ws.nextRequestID()
+	subscription := models.WebSocketMessage{
+		Method: "subscribe",
+		ID:     &id,
+		Subscription: map[string]interface{}{
+			"type": "allMids",
+		},
 	}
+	ws.subscriptions[subscriptionKey] = subscription
+
 	ws.handlers[subscriptionKey] = append(ws.handlers[subscriptionKey], func(data interface{}) {
 		if mids, ok := data.(map[string]string); ok {
 			handler(mids)
 		}
 	})
-	ws.mutex.Unlock()
+	ws.subMutex.Unlock()
+
+	_, err := ws.sendAwaitingAck(ws.ctx, id, subscription)
+	return err
+}
+
+// SubscribeCandles subscribes to streaming candle updates for coin at the
+// given interval (e.g. "1m", "1h"), returning only once the server has
+// acked the subscription.
+func (ws *WebSocketClient) SubscribeCandles(coin, interval string, handler func(models.Kline)) error {
+	subscriptionKey := fmt.Sprintf("candle:%s:%s", coin, interval)
 
+	ws.subMutex.Lock()
+	if _, exists := ws.subscriptions[subscriptionKey]; exists {
+		ws.subMutex.Unlock()
+		return fmt.Errorf("already subscribed to candles for %s@%s", coin, interval)
+	}
+
+	id := ws.nextRequestID()
 	subscription := models.WebSocketMessage{
 		Method: "subscribe",
+		ID:     &id,
 		Subscription: map[string]interface{}{
-			"type": "allMids",
+			"type":     "candle",
+			"coin":     coin,
+			"interval": interval,
 		},
 	}
+	ws.subscriptions[subscriptionKey] = subscription
+
+	ws.handlers[subscriptionKey] = append(ws.handlers[subscriptionKey], func(data interface{}) {
+		if kline, ok := data.(models.Kline); ok {
+			handler(kline)
+		}
+	})
+	ws.subMutex.Unlock()
+
+	_, err := ws.sendAwaitingAck(ws.ctx, id, subscription)
+	return err
+}
+
+// SubscribeBBO subscribes to best-bid/offer updates for coin, returning
+// only once the server has acked the subscription.
+func (ws *WebSocketClient) SubscribeBBO(coin string, handler func(models.BBO)) error {
+	subscriptionKey := fmt.Sprintf("bbo:%s", coin)
+
+	ws.subMutex.Lock()
+	if _, exists := ws.subscriptions[subscriptionKey]; exists {
+		ws.subMutex.Unlock()
+		return fmt.Errorf("already subscribed to BBO for %s", coin)
+	}
+
+	id := ws.nextRequestID()
+	subscription := models.WebSocketMessage{
+		Method: "subscribe",
+		ID:     &id,
+		Subscription: map[string]interface{}{
+			"type": "bbo",
+			"coin": coin,
+		},
+	}
+	ws.subscriptions[subscriptionKey] = subscription
+
+	ws.handlers[subscriptionKey] = append(ws.handlers[subscriptionKey], func(data interface{}) {
+		if bbo, ok := data.(models.BBO); ok {
+			handler(bbo)
+		}
+	})
+	ws.subMutex.Unlock()
 
-	return ws.conn.WriteJSON(subscription)
+	_, err := ws.sendAwaitingAck(ws.ctx, id, subscription)
+	return err
 }
 
-// Unsubscribe removes a subscription
+// Unsubscribe removes a subscription, returning only once the server has
+// acked the unsubscribe.
 func (ws *WebSocketClient) Unsubscribe(subscriptionType, identifier string) error {
 	subscriptionKey := fmt.Sprintf("%s:%s", subscriptionType, identifier)
 	if identifier == "" {
 		subscriptionKey = subscriptionType
 	}
 
-	ws.mutex.Lock()
+	ws.subMutex.Lock()
 	delete(ws.subscriptions, subscriptionKey)
 	delete(ws.handlers, subscriptionKey)
-	ws.mutex.Unlock()
+	delete(ws.lastFillSeen, identifier)
+	ws.subMutex.Unlock()
 
+	id := ws.nextRequestID()
 	unsubscribe := models.WebSocketMessage{
 		Method: "unsubscribe",
+		ID:     &id,
 		Subscription: map[string]interface{}{
 			"type": subscriptionType,
 		},
@@ -173,37 +733,22 @@ func (ws *WebSocketClient) Unsubscribe(subscriptionType, identifier string) erro
 		switch subscriptionType {
 		case "userFills":
 			unsubscribe.Subscription.(map[string]interface{})["user"] = identifier
-		case "l2Book":
+		case "l2Book", "bbo":
 			unsubscribe.Subscription.(map[string]interface{})["coin"] = identifier
 		}
 	}
 
-	return ws.conn.WriteJSON(unsubscribe)
-}
-
-// handleMessages processes incoming WebSocket messages
-func (ws *WebSocketClient) handleMessages() {
-	defer ws.Close()
-
-	for {
-		select {
-		case <-ws.ctx.Done():
-			return
-		default:
-			var msg models.WebSocketMessage
-			err := ws.conn.ReadJSON(&msg)
-			if err != nil {
-				log.Error().Err(err).Msg("WebSocket read error")
-				return
-			}
-
-			ws.processMessage(msg)
-		}
-	}
+	_, err := ws.sendAwaitingAck(ws.ctx, id, unsubscribe)
+	return err
 }
 
 // processMessage handles different types of WebSocket messages
 func (ws *WebSocketClient) processMessage(msg models.WebSocketMessage) {
+	if msg.ID != nil {
+		ws.handleAck(msg)
+		return
+	}
+
 	if msg.Data == nil {
 		return
 	}
@@ -229,12 +774,34 @@ func (ws *WebSocketClient) processMessage(msg models.WebSocketMessage) {
 			ws.handleL2Book(dataBytes, subscription)
 		case "allMids":
 			ws.handleAllMids(dataBytes)
+		case "candle":
+			ws.handleCandle(dataBytes, subscription)
+		case "bbo":
+			ws.handleBBO(dataBytes, subscription)
 		default:
 			log.Debug().Str("type", msgType).Msg("Unknown WebSocket message type")
 		}
 	}
 }
 
+// handleAck decodes an id-carrying server message into an ackResponse and
+// routes it to the pending caller awaiting that id via resolveAck.
+func (ws *WebSocketClient) handleAck(msg models.WebSocketMessage) {
+	resp := &ackResponse{}
+	if msg.Error != nil {
+		resp.err = fmt.Errorf("websocket: server rejected request %d: %s", *msg.ID, *msg.Error)
+	} else if msg.Data != nil {
+		dataBytes, err := json.Marshal(msg.Data)
+		if err != nil {
+			resp.err = fmt.Errorf("websocket: marshaling ack data for request %d: %w", *msg.ID, err)
+		} else {
+			resp.Data = dataBytes
+		}
+	}
+
+	ws.resolveAck(*msg.ID, resp)
+}
+
 // handleUserFills processes user fill messages
 func (ws *WebSocketClient) handleUserFills(dataBytes []byte, subscription map[string]interface{}) {
 	userAddress, ok := subscription["user"].(string)
@@ -248,10 +815,21 @@ func (ws *WebSocketClient) handleUserFills(dataBytes []byte, subscription map[st
 		return
 	}
 
+	ws.dispatchUserFill(userAddress, tradeEvent)
+}
+
+// dispatchUserFill fans tradeEvent out to userAddress's registered
+// handlers and advances lastFillSeen, shared by the live read path and
+// reconnect's REST backfill so both feed the same gap-free stream.
+func (ws *WebSocketClient) dispatchUserFill(userAddress string, tradeEvent models.EnhancedTradeEvent) {
 	subscriptionKey := fmt.Sprintf("userFills:%s", userAddress)
-	ws.mutex.RLock()
+
+	ws.subMutex.Lock()
+	if tradeEvent.Time > ws.lastFillSeen[userAddress] {
+		ws.lastFillSeen[userAddress] = tradeEvent.Time
+	}
 	handlers := ws.handlers[subscriptionKey]
-	ws.mutex.RUnlock()
+	ws.subMutex.Unlock()
 
 	for _, handler := range handlers {
 		go handler(tradeEvent)
@@ -272,13 +850,190 @@ func (ws *WebSocketClient) handleL2Book(dataBytes []byte, subscription map[strin
 	}
 
 	subscriptionKey := fmt.Sprintf("l2Book:%s", coin)
-	ws.mutex.RLock()
+	ws.subMutex.RLock()
 	handlers := ws.handlers[subscriptionKey]
-	ws.mutex.RUnlock()
+	ws.subMutex.RUnlock()
 
 	for _, handler := range handlers {
 		go handler(l2Book)
 	}
+
+	ws.updateLocalBook(coin, l2Book)
+}
+
+// updateLocalBook feeds a raw l2Book snapshot through coin's LocalOrderBook,
+// which is Hyperliquid's only on-the-wire book format today; reconcile
+// derives the sequenced BookDelta SubscribeBookDelta subscribers expect.
+// SubscribeBookCheckpoint subscribers read the book on their own throttled
+// ticker instead, so they don't need to be touched here.
+func (ws *WebSocketClient) updateLocalBook(coin string, book models.L2Book) {
+	lb := ws.localBook(coin)
+
+	delta, err := lb.reconcile(book)
+	if err != nil {
+		log.Error().Err(err).Str("coin", coin).Msg("Failed to reconcile local order book")
+		return
+	}
+
+	ws.booksMu.Lock()
+	var handlers []func(BookDelta)
+	handlers = append(handlers, ws.deltaHandlers[coinKey(coin)]...)
+	ws.booksMu.Unlock()
+
+	for _, handler := range handlers {
+		go handler(delta)
+	}
+}
+
+// localBook returns coin's LocalOrderBook, creating it on first use.
+func (ws *WebSocketClient) localBook(coin string) *LocalOrderBook {
+	key := coinKey(coin)
+
+	ws.booksMu.Lock()
+	defer ws.booksMu.Unlock()
+
+	lb, ok := ws.books[key]
+	if !ok {
+		lb = NewLocalOrderBook(coin)
+		ws.books[key] = lb
+	}
+	return lb
+}
+
+// checkpointStream throttles a LocalOrderBook's checkpoint to one push per
+// interval for a single SubscribeBookCheckpoint subscriber.
+type checkpointStream struct {
+	depth    int
+	interval time.Duration
+	handler  func(models.L2Book)
+	stop     chan struct{}
+}
+
+// ensureL2BookFeed subscribes to coin's raw l2Book feed if nothing has yet,
+// so SubscribeBookCheckpoint/SubscribeBookDelta can piggyback on it without
+// tripping SubscribeL2Book's "already subscribed" guard.
+func (ws *WebSocketClient) ensureL2BookFeed(coin string) error {
+	subscriptionKey := fmt.Sprintf("l2Book:%s", coin)
+
+	ws.subMutex.Lock()
+	if _, exists := ws.subscriptions[subscriptionKey]; exists {
+		ws.subMutex.Unlock()
+		return nil
+	}
+
+	id := ws.nextRequestID()
+	subscription := models.WebSocketMessage{
+		Method: "subscribe",
+		ID:     &id,
+		Subscription: map[string]interface{}{
+			"type": "l2Book",
+			"coin": coin,
+		},
+	}
+	ws.subscriptions[subscriptionKey] = subscription
+	ws.subMutex.Unlock()
+
+	_, err := ws.sendAwaitingAck(ws.ctx, id, subscription)
+	return err
+}
+
+// SubscribeBookResync registers handler to fire whenever a reconnect
+// invalidates every reconstructed local order book, so a consumer (e.g.
+// CopyEngine) knows in-flight deltas/checkpoints are stale until the next
+// snapshot arrives.
+func (ws *WebSocketClient) SubscribeBookResync(handler func()) {
+	ws.booksMu.Lock()
+	ws.resyncHandlers = append(ws.resyncHandlers, handler)
+	ws.booksMu.Unlock()
+}
+
+// invalidateBooks drops every reconstructed local order book and notifies
+// SubscribeBookResync subscribers. A reconnect means Hyperliquid's l2Book
+// feed restarts from a fresh full snapshot, so anything built from the
+// pre-reconnect stream - and any delta sequence numbers derived from it -
+// is no longer trustworthy.
+func (ws *WebSocketClient) invalidateBooks() {
+	ws.booksMu.Lock()
+	hadBooks := len(ws.books) > 0
+	ws.books = make(map[string]*LocalOrderBook)
+	handlers := append([]func(){}, ws.resyncHandlers...)
+	ws.booksMu.Unlock()
+
+	if !hadBooks {
+		return
+	}
+	for _, handler := range handlers {
+		go handler()
+	}
+}
+
+// OrderBook returns coin's reconstructed order book, subscribing to its
+// live l2Book feed on first request. depth caps how many price levels per
+// side are returned; 0 means no cap.
+func (ws *WebSocketClient) OrderBook(coin string, depth int) (models.L2Book, error) {
+	if err := ws.ensureL2BookFeed(coin); err != nil {
+		return models.L2Book{}, fmt.Errorf("websocket: fetching order book for %s: %w", coin, err)
+	}
+	return ws.localBook(coin).BookCheckpoint(depth), nil
+}
+
+// SubscribeBookDelta feeds handler every incremental change to coin's order
+// book, reconstructed via LocalOrderBook from Hyperliquid's raw l2Book feed,
+// with a monotonic BookDelta.Seq subscribers can use to detect a missed
+// update.
+func (ws *WebSocketClient) SubscribeBookDelta(coin string, handler func(BookDelta)) error {
+	if err := ws.ensureL2BookFeed(coin); err != nil {
+		return fmt.Errorf("websocket: subscribing book delta feed for %s: %w", coin, err)
+	}
+
+	ws.booksMu.Lock()
+	ws.deltaHandlers[coinKey(coin)] = append(ws.deltaHandlers[coinKey(coin)], handler)
+	ws.booksMu.Unlock()
+
+	return nil
+}
+
+// SubscribeBookCheckpoint pushes a full top-depth snapshot of coin's order
+// book to handler at most once per interval, sourced from the same
+// LocalOrderBook SubscribeBookDelta maintains - useful for a UI depth chart
+// that doesn't need every individual level change.
+func (ws *WebSocketClient) SubscribeBookCheckpoint(coin string, depth int, interval time.Duration, handler func(models.L2Book)) error {
+	if err := ws.ensureL2BookFeed(coin); err != nil {
+		return fmt.Errorf("websocket: subscribing book checkpoint feed for %s: %w", coin, err)
+	}
+
+	stream := &checkpointStream{depth: depth, interval: interval, handler: handler, stop: make(chan struct{})}
+
+	ws.booksMu.Lock()
+	ws.checkpoints[coinKey(coin)] = append(ws.checkpoints[coinKey(coin)], stream)
+	ws.booksMu.Unlock()
+
+	lb := ws.localBook(coin)
+	ws.wg.Add(1)
+	go func() {
+		defer ws.wg.Done()
+		ws.runCheckpointStream(lb, stream)
+	}()
+
+	return nil
+}
+
+// runCheckpointStream pushes lb's checkpoint to stream.handler every
+// stream.interval until the client is closed or stream.stop is closed.
+func (ws *WebSocketClient) runCheckpointStream(lb *LocalOrderBook, stream *checkpointStream) {
+	ticker := time.NewTicker(stream.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ws.ctx.Done():
+			return
+		case <-stream.stop:
+			return
+		case <-ticker.C:
+			stream.handler(lb.BookCheckpoint(stream.depth))
+		}
+	}
 }
 
 // handleAllMids processes all mids messages
@@ -290,52 +1045,94 @@ func (ws *WebSocketClient) handleAllMids(dataBytes []byte) {
 	}
 
 	subscriptionKey := "allMids"
-	ws.mutex.RLock()
+	ws.subMutex.RLock()
 	handlers := ws.handlers[subscriptionKey]
-	ws.mutex.RUnlock()
+	ws.subMutex.RUnlock()
 
 	for _, handler := range handlers {
 		go handler(mids)
 	}
 }
 
-// Ping sends a ping message to keep connection alive
+// handleCandle processes candle update messages
+func (ws *WebSocketClient) handleCandle(dataBytes []byte, subscription map[string]interface{}) {
+	coin, ok := subscription["coin"].(string)
+	if !ok {
+		return
+	}
+	interval, ok := subscription["interval"].(string)
+	if !ok {
+		return
+	}
+
+	var kline models.Kline
+	if err := json.Unmarshal(dataBytes, &kline); err != nil {
+		log.Error().Err(err).Msg("Failed to unmarshal candle")
+		return
+	}
+
+	subscriptionKey := fmt.Sprintf("candle:%s:%s", coin, interval)
+	ws.subMutex.RLock()
+	handlers := ws.handlers[subscriptionKey]
+	ws.subMutex.RUnlock()
+
+	for _, handler := range handlers {
+		go handler(kline)
+	}
+}
+
+// handleBBO processes best-bid/offer update messages
+func (ws *WebSocketClient) handleBBO(dataBytes []byte, subscription map[string]interface{}) {
+	coin, ok := subscription["coin"].(string)
+	if !ok {
+		return
+	}
+
+	var bbo models.BBO
+	if err := json.Unmarshal(dataBytes, &bbo); err != nil {
+		log.Error().Err(err).Msg("Failed to unmarshal BBO")
+		return
+	}
+
+	subscriptionKey := fmt.Sprintf("bbo:%s", coin)
+	ws.subMutex.RLock()
+	handlers := ws.handlers[subscriptionKey]
+	ws.subMutex.RUnlock()
+
+	for _, handler := range handlers {
+		go handler(bbo)
+	}
+}
+
+// Ping sends a ping message to keep the connection alive. pingLoop already
+// does this automatically; exported for callers that want an out-of-band
+// liveness probe.
 func (ws *WebSocketClient) Ping() error {
-	return ws.conn.WriteMessage(websocket.PingMessage, []byte{})
+	conn := ws.getConn()
+	if conn == nil {
+		return fmt.Errorf("no active websocket connection")
+	}
+	return conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(5*time.Second))
 }
 
-// Close closes the WebSocket connection
+// Close shuts down the client: cancelling ctx alone wouldn't unblock a
+// goroutine parked in ReadJSON, so close the live connection first to force
+// it to return an error, then wait for the supervisor, write pump and ping
+// loop to all observe ctx and stop.
 func (ws *WebSocketClient) Close() error {
 	ws.cancel()
-	return ws.conn.Close()
-}
 
-// IsConnected checks if the WebSocket connection is still active
-func (ws *WebSocketClient) IsConnected() bool {
-	select {
-	case <-ws.ctx.Done():
-		return false
-	default:
-		return true
+	conn := ws.getConn()
+	var err error
+	if conn != nil {
+		err = conn.Close()
 	}
+
+	ws.wg.Wait()
+	return err
 }
 
-// StartPingLoop starts a goroutine that sends periodic ping messages
-func (ws *WebSocketClient) StartPingLoop(interval time.Duration) {
-	go func() {
-		ticker := time.NewTicker(interval)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ws.ctx.Done():
-				return
-			case <-ticker.C:
-				if err := ws.Ping(); err != nil {
-					log.Error().Err(err).Msg("Failed to send ping")
-					return
-				}
-			}
-		}
-	}()
+// IsConnected reports whether the client currently holds a live connection.
+func (ws *WebSocketClient) IsConnected() bool {
+	return ws.State() == StateConnected
 }
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"hyperliquid-copy-trading/internal/models"
+
+	"github.com/gorilla/websocket"
+)
+
+var testUpgrader = websocket.Upgrader{}
+
+// wsTestServer starts a local httptest server that upgrades every incoming
+// request to a WebSocket and hands each successive connection (1-indexed) to
+// connHandler, so a test can force-drop, refuse or inspect traffic on
+// whichever connection attempt it cares about.
+func wsTestServer(t *testing.T, connHandler func(n int, conn *websocket.Conn)) string {
+	t.Helper()
+
+	var n int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := testUpgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		connHandler(int(atomic.AddInt32(&n, 1)), conn)
+	}))
+	t.Cleanup(srv.Close)
+
+	return "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws"
+}
+
+// newTestWebSocketClient builds a WebSocketClient against a local test
+// server instead of Hyperliquid's real endpoint. api is left nil: none of
+// the paths these tests exercise (reconnect, backoff, subscription replay)
+// dereference it -- only backfillMissedFills does, and that's only reached
+// for a "userFills:" subscription, which these tests don't register.
+func newTestWebSocketClient(t *testing.T, wsURL string) *WebSocketClient {
+	t.Helper()
+	client, err := newWebSocketClient(nil, wsURL)
+	if err != nil {
+		t.Fatalf("newWebSocketClient: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// waitForState polls client.State() until it reaches want or timeout
+// elapses.
+func waitForState(t *testing.T, client *WebSocketClient, want ConnectionState, timeout time.Duration) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if client.State() == want {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for state %s, last seen %s", want, client.State())
+}
+
+// TestWebSocketClient_ReconnectsAfterForceDrop forces the first connection
+// closed immediately after the handshake -- the same symptom a mid-stream
+// network blip produces -- and checks the client dials again and settles
+// back into StateConnected rather than giving up.
+func TestWebSocketClient_ReconnectsAfterForceDrop(t *testing.T) {
+	connected := make(chan int, 2)
+
+	wsURL := wsTestServer(t, func(n int, conn *websocket.Conn) {
+		connected <- n
+		if n == 1 {
+			conn.Close()
+			return
+		}
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+
+	client := newTestWebSocketClient(t, wsURL)
+
+	select {
+	case n := <-connected:
+		if n != 1 {
+			t.Fatalf("expected first connection, got %d", n)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for initial connection")
+	}
+
+	select {
+	case n := <-connected:
+		if n != 2 {
+			t.Fatalf("expected reconnect to be the second connection, got %d", n)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for reconnect after force-drop")
+	}
+
+	waitForState(t, client, StateConnected, 2*time.Second)
+}
+
+// TestWebSocketClient_ReplaysSubscriptionsOnReconnect checks that a
+// subscription active before a force-drop is replayed on the reconnected
+// socket, since Hyperliquid's server has no memory of the dropped
+// connection's subscriptions.
+func TestWebSocketClient_ReplaysSubscriptionsOnReconnect(t *testing.T) {
+	replayed := make(chan models.WebSocketMessage, 1)
+
+	wsURL := wsTestServer(t, func(n int, conn *websocket.Conn) {
+		if n == 1 {
+			conn.Close()
+			return
+		}
+
+		var msg models.WebSocketMessage
+		if err := conn.ReadJSON(&msg); err == nil {
+			replayed <- msg
+		}
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+
+	client := newTestWebSocketClient(t, wsURL)
+
+	id := uint64(1)
+	client.subMutex.Lock()
+	client.subscriptions["l2Book:BTC"] = models.WebSocketMessage{
+		Method: "subscribe",
+		ID:     &id,
+		Subscription: map[string]interface{}{
+			"type": "l2Book",
+			"coin": "BTC",
+		},
+	}
+	client.subMutex.Unlock()
+
+	select {
+	case msg := <-replayed:
+		if msg.Method != "subscribe" {
+			t.Fatalf("expected replayed subscribe message, got method %q", msg.Method)
+		}
+		sub, ok := msg.Subscription.(map[string]interface{})
+		if !ok || sub["coin"] != "BTC" {
+			t.Fatalf("expected replayed subscription for BTC, got %#v", msg.Subscription)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for subscription replay after reconnect")
+	}
+}
+
+// TestWebSocketClient_DegradedAfterRepeatedFailures checks that a run of
+// failed reconnect attempts surfaces StateDegraded (so a caller like the
+// copy engine can pause new trades) and that the client still recovers once
+// the server becomes reachable again.
+func TestWebSocketClient_DegradedAfterRepeatedFailures(t *testing.T) {
+	var n int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count := atomic.AddInt32(&n, 1)
+		if count == 1 || count >= 5 {
+			conn, err := testUpgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			if count == 1 {
+				conn.Close()
+				return
+			}
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}
+		// Attempts 2-4: refuse the handshake, simulating an outage across
+		// several reconnect attempts.
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(srv.Close)
+
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws"
+	client := newTestWebSocketClient(t, wsURL)
+
+	waitForState(t, client, StateDegraded, 15*time.Second)
+	waitForState(t, client, StateConnected, 15*time.Second)
+}
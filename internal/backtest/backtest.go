@@ -0,0 +1,273 @@
+// Package backtest replays historical Hyperliquid fills against a copy-trading
+// configuration and produces the same performance/risk structs used by the
+// live engine, so a strategy can be vetted before it is switched on.
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"hyperliquid-copy-trading/internal/api"
+	"hyperliquid-copy-trading/internal/models"
+	"hyperliquid-copy-trading/internal/utils"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Config describes one backtest run.
+type Config struct {
+	Strategy       *models.CopyTradingStrategy
+	Follower       *models.PermissionlessFollower
+	TargetTraders  []string
+	StartTime      time.Time
+	EndTime        time.Time
+	StartingEquity float64
+
+	// SlippageBps is applied to every simulated fill; Run takes no L2Book
+	// snapshot to model slippage from.
+	SlippageBps float64
+}
+
+// BacktestRun is the persisted record of a completed backtest.
+type BacktestRun struct {
+	ID          int                        `json:"id"`
+	TargetTrader string                    `json:"target_trader"`
+	StartTime   time.Time                  `json:"start_time"`
+	EndTime     time.Time                  `json:"end_time"`
+	Performance models.StrategyPerformance `json:"performance"`
+	CreatedAt   time.Time                  `json:"created_at"`
+}
+
+// simFill is one simulated copy fill, used to build the equity/drawdown series.
+type simFill struct {
+	executedAt time.Time
+	side       string
+	size       float64
+	price      float64
+	pnl        float64
+}
+
+// Run replays every target trader's fills through the configured filters and
+// sizing rules, simulates execution with slippage, and returns the resulting
+// performance/risk profile plus an equity/drawdown time series compatible
+// with LeaderPerformanceAnalysis.TimeSeriesData.
+func Run(ctx context.Context, hl *api.HyperliquidAPI, cfg Config) (*models.StrategyPerformance, []models.TimeSeriesPoint, error) {
+	if len(cfg.TargetTraders) == 0 {
+		return nil, nil, fmt.Errorf("backtest: at least one target trader is required")
+	}
+	if cfg.EndTime.Before(cfg.StartTime) {
+		return nil, nil, fmt.Errorf("backtest: end time must be after start time")
+	}
+
+	var allFills []simFill
+	for _, trader := range cfg.TargetTraders {
+		fills, err := hl.GetUserFills(ctx, trader)
+		if err != nil {
+			return nil, nil, fmt.Errorf("backtest: failed to fetch fills for %s: %w", trader, err)
+		}
+
+		simulated, err := simulateTrader(fills, cfg)
+		if err != nil {
+			return nil, nil, fmt.Errorf("backtest: failed to simulate %s: %w", trader, err)
+		}
+		allFills = append(allFills, simulated...)
+	}
+
+	sort.Slice(allFills, func(i, j int) bool {
+		return allFills[i].executedAt.Before(allFills[j].executedAt)
+	})
+
+	performance, series := summarize(allFills, cfg.StartingEquity)
+	log.Info().
+		Int("fills", len(allFills)).
+		Float64("total_return", performance.TotalReturn).
+		Float64("max_drawdown", performance.MaxDrawdown).
+		Msg("Backtest run complete")
+
+	return performance, series, nil
+}
+
+// simulateTrader applies the copy filters and sizing to one trader's raw
+// fills and returns the resulting simulated copy fills.
+func simulateTrader(fills []models.EnhancedTradeEvent, cfg Config) ([]simFill, error) {
+	var results []simFill
+
+	for _, fill := range fills {
+		executedAt := time.Unix(fill.Time/1000, 0)
+		if executedAt.Before(cfg.StartTime) || executedAt.After(cfg.EndTime) {
+			continue
+		}
+
+		if cfg.Follower != nil && !passesFilters(cfg.Follower, fill) {
+			continue
+		}
+
+		price, err := utils.ParseFloat(fill.Px)
+		if err != nil {
+			continue
+		}
+		size, err := utils.ParseFloat(fill.Sz)
+		if err != nil {
+			continue
+		}
+
+		size = applySizing(cfg.Follower, size)
+		if size <= 0 {
+			continue
+		}
+
+		if cfg.Follower != nil && cfg.Follower.CopyFilters != nil && cfg.Follower.CopyFilters.TimeDelaySeconds > 0 {
+			executedAt = executedAt.Add(time.Duration(cfg.Follower.CopyFilters.TimeDelaySeconds) * time.Second)
+		}
+
+		fillPrice := applySlippage(price, fill.Side == "B", cfg.SlippageBps)
+
+		var pnl float64
+		if fill.ClosedPnl != "" {
+			closed, err := utils.ParseFloat(fill.ClosedPnl)
+			if err == nil {
+				ratio := 1.0
+				if orig, err := utils.ParseFloat(fill.Sz); err == nil && orig != 0 {
+					ratio = size / orig
+				}
+				pnl = closed * ratio
+			}
+		}
+
+		results = append(results, simFill{
+			executedAt: executedAt,
+			side:       fill.Side,
+			size:       size,
+			price:      fillPrice,
+			pnl:        pnl,
+		})
+	}
+
+	return results, nil
+}
+
+func passesFilters(follower *models.PermissionlessFollower, fill models.EnhancedTradeEvent) bool {
+	if len(follower.AssetWhitelist) > 0 {
+		found := false
+		for _, asset := range follower.AssetWhitelist {
+			if asset == fill.Coin {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	for _, asset := range follower.AssetBlacklist {
+		if asset == fill.Coin {
+			return false
+		}
+	}
+
+	if follower.CopyFilters == nil {
+		return true
+	}
+	filters := follower.CopyFilters
+
+	price, _ := utils.ParseFloat(fill.Px)
+	size, _ := utils.ParseFloat(fill.Sz)
+	positionValue := price * size
+
+	if filters.MinPositionValue > 0 && positionValue < filters.MinPositionValue {
+		return false
+	}
+	if filters.MaxPositionValue > 0 && positionValue > filters.MaxPositionValue {
+		return false
+	}
+	if filters.OnlyProfitableTrades && fill.ClosedPnl != "" {
+		if pnl, err := utils.ParseFloat(fill.ClosedPnl); err == nil && pnl <= 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func applySizing(follower *models.PermissionlessFollower, originalSize float64) float64 {
+	if follower == nil {
+		return originalSize
+	}
+
+	size := originalSize * (follower.CopyPercentage / 100.0)
+	if follower.MinTradeSize > 0 && size < follower.MinTradeSize {
+		return 0
+	}
+	if follower.MaxPositionSize > 0 && size > follower.MaxPositionSize {
+		size = follower.MaxPositionSize
+	}
+	return size
+}
+
+// applySlippage models execution slippage by moving the fill price against
+// the taker by slippageBps; order book depth is not taken into account.
+func applySlippage(price float64, isBuy bool, slippageBps float64) float64 {
+	if slippageBps <= 0 {
+		return price
+	}
+	adjustment := price * (slippageBps / 10000.0)
+	if isBuy {
+		return price + adjustment
+	}
+	return price - adjustment
+}
+
+// summarize rolls the simulated fills up into a StrategyPerformance and an
+// equity/drawdown time series.
+func summarize(fills []simFill, startingEquity float64) (*models.StrategyPerformance, []models.TimeSeriesPoint) {
+	perf := &models.StrategyPerformance{
+		LastUpdated: time.Now(),
+	}
+
+	if len(fills) == 0 {
+		return perf, nil
+	}
+
+	equity := startingEquity
+	peak := startingEquity
+	var maxDrawdown float64
+	var wins int
+	var totalReturn float64
+	var series []models.TimeSeriesPoint
+
+	for _, fill := range fills {
+		equity += fill.pnl
+		totalReturn += fill.pnl
+		if fill.pnl > 0 {
+			wins++
+		}
+
+		if equity > peak {
+			peak = equity
+		}
+		drawdown := 0.0
+		if peak > 0 {
+			drawdown = (peak - equity) / peak * 100
+		}
+		if drawdown > maxDrawdown {
+			maxDrawdown = drawdown
+		}
+
+		series = append(series,
+			models.TimeSeriesPoint{Timestamp: fill.executedAt, Value: equity, Type: "equity"},
+			models.TimeSeriesPoint{Timestamp: fill.executedAt, Value: drawdown, Type: "drawdown"},
+		)
+	}
+
+	perf.TotalTrades = len(fills)
+	perf.WinRate = float64(wins) / float64(len(fills))
+	perf.MaxDrawdown = maxDrawdown
+	if startingEquity > 0 {
+		perf.TotalReturn = totalReturn / startingEquity * 100
+	}
+	perf.AvgTradeReturn = totalReturn / float64(len(fills))
+
+	return perf, series
+}
@@ -0,0 +1,216 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"hyperliquid-copy-trading/config"
+	"hyperliquid-copy-trading/internal/api"
+	"hyperliquid-copy-trading/internal/engine"
+	"hyperliquid-copy-trading/internal/models"
+	"hyperliquid-copy-trading/internal/utils"
+)
+
+// FeeRate is the maker/taker fee charged on a simulated fill's notional
+// value, in basis points.
+type FeeRate struct {
+	MakerBps float64
+	TakerBps float64
+}
+
+// SlippageModel moves a simulated fill's price against the taker by Bps,
+// the same mechanism applySlippage uses for Run's live-fill backtests.
+type SlippageModel struct {
+	Bps float64
+}
+
+// ReplayConfig describes one engine-replay run: a single follower copying a
+// chronological leader trade feed through the live RiskManager/
+// PositionTracker stack, as opposed to Run's CopyFilters-only simulation.
+type ReplayConfig struct {
+	Follower       *models.Follower
+	LeaderTrades   []models.Trade // IsLeaderTrade == true; any order, sorted by ExecutedAt before replay
+	StartingEquity float64
+
+	// Fees/Slippage are keyed by asset; the "" entry is the fallback applied
+	// to assets with no specific entry.
+	Fees     map[string]FeeRate
+	Slippage map[string]SlippageModel
+
+	// Assets is optional: when set, replayed trades are scored against the
+	// same delisted/max-leverage checks a live run would see. A nil value
+	// replays with those checks skipped, matching Run's offline nature.
+	Assets *api.AssetRegistry
+}
+
+// SummaryReport is the result of one Replay run: the equity curve, every
+// simulated fill and the full RiskReport statistics set computed over them,
+// in a shape a future frontend report viewer can render directly from JSON.
+type SummaryReport struct {
+	FollowerID      int                      `json:"follower_id"`
+	InitialBalance  float64                  `json:"initial_balance"`
+	FinalBalance    float64                  `json:"final_balance"`
+	TotalFeesPaid   float64                  `json:"total_fees_paid"`
+	CAGR            float64                  `json:"cagr"`
+	Equity          []models.TimeSeriesPoint `json:"equity_curve"`
+	SimulatedTrades []models.Trade           `json:"simulated_trades"`
+	Stats           *engine.RiskReport       `json:"stats"`
+}
+
+// Replay drives cfg.Follower's copy of cfg.LeaderTrades through a fresh
+// engine.RiskManager exactly as AddFollower/processBatch would live, except
+// every approved trade is filled in-process: instantly at the leader's
+// price, adjusted for cfg.Slippage and charged cfg.Fees, with timestamps
+// taken verbatim from LeaderTrades so assessTimeRisk sees the same clock a
+// live run would.
+func Replay(ctx context.Context, cfg ReplayConfig) (*SummaryReport, error) {
+	if cfg.Follower == nil {
+		return nil, fmt.Errorf("backtest: ReplayConfig.Follower is required")
+	}
+	if len(cfg.LeaderTrades) == 0 {
+		return nil, fmt.Errorf("backtest: ReplayConfig.LeaderTrades is required")
+	}
+
+	trades := append([]models.Trade(nil), cfg.LeaderTrades...)
+	sort.Slice(trades, func(i, j int) bool {
+		return trades[i].ExecutedAt.Before(trades[j].ExecutedAt)
+	})
+
+	riskCfg := &config.Config{MaxPositionSize: cfg.Follower.MaxPositionSize * 10}
+	riskManager := engine.NewRiskManager(riskCfg, engine.NewTradeHistoryStore(nil), engine.NewPositionTracker(), nil, cfg.Assets)
+
+	report := &SummaryReport{
+		FollowerID:     cfg.Follower.ID,
+		InitialBalance: cfg.StartingEquity,
+	}
+
+	var simulated []models.Trade
+	for _, leaderTrade := range trades {
+		assessment := riskManager.AssessRiskAt(cfg.Follower, &leaderTrade, leaderTrade.ExecutedAt)
+		if !assessment.Approved || assessment.AdjustedSize <= 0 {
+			continue
+		}
+
+		fill, fee := simulateReplayFill(cfg, leaderTrade, assessment.AdjustedSize)
+		riskManager.RecordTrade(cfg.Follower, fill)
+		simulated = append(simulated, fill)
+		report.TotalFeesPaid += fee
+	}
+
+	report.SimulatedTrades = simulated
+	report.Stats = riskManager.PerformanceReport(cfg.Follower, simulated)
+	report.Equity = replayEquityCurve(cfg.StartingEquity, report.TotalFeesPaid, simulated)
+
+	if len(report.Equity) > 0 {
+		report.FinalBalance = report.Equity[len(report.Equity)-1].Value
+	} else {
+		report.FinalBalance = cfg.StartingEquity
+	}
+	if cfg.StartingEquity > 0 {
+		report.CAGR = utils.CalculateCompoundAnnualGrowthRate(cfg.StartingEquity, report.FinalBalance, replayYears(trades))
+	}
+
+	return report, nil
+}
+
+// simulateReplayFill applies cfg's per-asset fee and slippage models to one
+// approved leader trade and returns the resulting simulated fill plus the
+// fee charged on it.
+func simulateReplayFill(cfg ReplayConfig, leaderTrade models.Trade, adjustedSize float64) (models.Trade, float64) {
+	fee := feeRateFor(cfg.Fees, leaderTrade.Asset)
+	slip := slippageFor(cfg.Slippage, leaderTrade.Asset)
+
+	isBuy := leaderTrade.Side == "buy"
+	price := applySlippage(leaderTrade.Price, isBuy, slip.Bps)
+
+	takerRate := fee.TakerBps / 10000.0
+	if leaderTrade.OrderType == "limit" {
+		takerRate = fee.MakerBps / 10000.0
+	}
+	notional := adjustedSize * price
+	feePaid := notional * takerRate
+
+	fill := models.Trade{
+		LeaderAddress: leaderTrade.LeaderAddress,
+		FollowerID:    &cfg.Follower.ID,
+		Asset:         leaderTrade.Asset,
+		Side:          leaderTrade.Side,
+		Size:          adjustedSize,
+		Price:         price,
+		OrderType:     leaderTrade.OrderType,
+		IsLeaderTrade: false,
+		ExecutedAt:    leaderTrade.ExecutedAt,
+		Status:        "filled",
+	}
+	return fill, feePaid
+}
+
+// feeRateFor looks up asset's fee rate, falling back to the "" default entry
+// (zero fees if neither is configured).
+func feeRateFor(fees map[string]FeeRate, asset string) FeeRate {
+	if rate, ok := fees[asset]; ok {
+		return rate
+	}
+	return fees[""]
+}
+
+// slippageFor looks up asset's slippage model, falling back to the ""
+// default entry (no slippage if neither is configured).
+func slippageFor(slippage map[string]SlippageModel, asset string) SlippageModel {
+	if model, ok := slippage[asset]; ok {
+		return model
+	}
+	return slippage[""]
+}
+
+// replayEquityCurve folds simulated trades' FIFO-realized PnL (via
+// engine.MatchTradesFIFO, the same matching RiskManager.PerformanceReport
+// uses) and totalFees, spread evenly over the closed trades, into a running
+// balance, so the equity curve is consistent with report.Stats rather than
+// a separately derived series.
+func replayEquityCurve(startingEquity, totalFees float64, simulated []models.Trade) []models.TimeSeriesPoint {
+	if len(simulated) == 0 {
+		return nil
+	}
+
+	closed := engine.MatchTradesFIFO(simulated)
+	feePerTrade := utils.SafeDivide(totalFees, float64(len(closed)))
+
+	equity := startingEquity
+	peak := startingEquity
+	series := make([]models.TimeSeriesPoint, 0, len(closed)*2)
+
+	for _, ct := range closed {
+		equity += ct.PnL - feePerTrade
+		if equity > peak {
+			peak = equity
+		}
+		drawdown := 0.0
+		if peak > 0 {
+			drawdown = (peak - equity) / peak * 100
+		}
+
+		series = append(series,
+			models.TimeSeriesPoint{Timestamp: ct.ClosedAt, Value: equity, Type: "equity"},
+			models.TimeSeriesPoint{Timestamp: ct.ClosedAt, Value: drawdown, Type: "drawdown"},
+		)
+	}
+
+	return series
+}
+
+// replayYears converts trades' ExecutedAt span into years for
+// CalculateCompoundAnnualGrowthRate, the same "periods" argument
+// StrategyPerformance's CAGR uses elsewhere.
+func replayYears(trades []models.Trade) float64 {
+	if len(trades) < 2 {
+		return 1
+	}
+	span := trades[len(trades)-1].ExecutedAt.Sub(trades[0].ExecutedAt)
+	years := span.Hours() / (24 * 365)
+	if years <= 0 {
+		return 1
+	}
+	return years
+}
@@ -0,0 +1,215 @@
+// Package blacklist maintains a hot in-memory set of banned trader/wallet
+// addresses, periodically refreshed from Postgres plus optional external
+// OFAC-style/JSON sources, so database.PostgresDB can reject writes to a
+// blocked address without a query per call.
+package blacklist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"hyperliquid-copy-trading/internal/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+// Store holds the current blocked-address snapshot and keeps it fresh.
+type Store struct {
+	pool        *pgxpool.Pool
+	sourceURLs  []string
+	sourceFiles []string
+	httpClient  *http.Client
+
+	mu      sync.RWMutex
+	blocked map[string]struct{}
+}
+
+// NewStore returns a Store with an empty snapshot; call Start to load it
+// and keep it refreshing.
+func NewStore(pool *pgxpool.Pool, sourceURLs, sourceFiles []string) *Store {
+	return &Store{
+		pool:        pool,
+		sourceURLs:  sourceURLs,
+		sourceFiles: sourceFiles,
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+		blocked:     make(map[string]struct{}),
+	}
+}
+
+// Start runs an initial Refresh synchronously, so the Store is usable as
+// soon as Start returns, then keeps refreshing every interval until ctx is
+// done.
+func (s *Store) Start(ctx context.Context, interval time.Duration) error {
+	if err := s.Refresh(ctx); err != nil {
+		return err
+	}
+	go s.refreshLoop(ctx, interval)
+	return nil
+}
+
+func (s *Store) refreshLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Refresh(ctx); err != nil {
+				log.Error().Err(err).Msg("Failed to refresh address blacklist")
+			}
+		}
+	}
+}
+
+// Refresh reloads the in-memory set from blocked_addresses plus every
+// configured source. Addresses are compared case-insensitively.
+func (s *Store) Refresh(ctx context.Context) error {
+	next := make(map[string]struct{})
+
+	rows, err := s.pool.Query(ctx, "SELECT address FROM blocked_addresses")
+	if err != nil {
+		return fmt.Errorf("loading blocked_addresses: %w", err)
+	}
+	for rows.Next() {
+		var addr string
+		if err := rows.Scan(&addr); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning blocked address: %w", err)
+		}
+		next[strings.ToLower(addr)] = struct{}{}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, url := range s.sourceURLs {
+		addrs, err := s.fetchURL(ctx, url)
+		if err != nil {
+			log.Error().Err(err).Str("url", url).Msg("Failed to fetch blacklist source")
+			continue
+		}
+		for _, a := range addrs {
+			next[strings.ToLower(a)] = struct{}{}
+		}
+	}
+
+	for _, path := range s.sourceFiles {
+		addrs, err := readAddressFile(path)
+		if err != nil {
+			log.Error().Err(err).Str("file", path).Msg("Failed to read blacklist source file")
+			continue
+		}
+		for _, a := range addrs {
+			next[strings.ToLower(a)] = struct{}{}
+		}
+	}
+
+	s.mu.Lock()
+	s.blocked = next
+	s.mu.Unlock()
+	return nil
+}
+
+// fetchURL expects url to return a JSON array of addresses, the same shape
+// as a source file.
+func (s *Store) fetchURL(ctx context.Context, url string) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+
+	var addrs []string
+	if err := json.NewDecoder(resp.Body).Decode(&addrs); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", url, err)
+	}
+	return addrs, nil
+}
+
+func readAddressFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var addrs []string
+	if err := json.Unmarshal(data, &addrs); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return addrs, nil
+}
+
+// IsBlocked reports whether address (case-insensitive) is on the current
+// blacklist snapshot. Safe for concurrent use.
+func (s *Store) IsBlocked(address string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, blocked := s.blocked[strings.ToLower(address)]
+	return blocked
+}
+
+// Add persists a new manually-blocked address and makes it effective
+// immediately, without waiting for the next periodic Refresh.
+func (s *Store) Add(ctx context.Context, address, reason string) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO blocked_addresses (address, reason, source)
+		VALUES ($1, $2, 'manual')
+		ON CONFLICT (address) DO UPDATE SET reason = EXCLUDED.reason`,
+		address, reason,
+	)
+	if err != nil {
+		return fmt.Errorf("inserting blocked address: %w", err)
+	}
+
+	s.mu.Lock()
+	s.blocked[strings.ToLower(address)] = struct{}{}
+	s.mu.Unlock()
+	return nil
+}
+
+// Remove deletes address from the blocklist and makes the removal effective
+// immediately.
+func (s *Store) Remove(ctx context.Context, address string) error {
+	if _, err := s.pool.Exec(ctx, "DELETE FROM blocked_addresses WHERE address = $1", address); err != nil {
+		return fmt.Errorf("deleting blocked address: %w", err)
+	}
+
+	s.mu.Lock()
+	delete(s.blocked, strings.ToLower(address))
+	s.mu.Unlock()
+	return nil
+}
+
+// List returns every persisted blocked address, most recently added first.
+func (s *Store) List(ctx context.Context) ([]models.BlockedAddress, error) {
+	rows, err := s.pool.Query(ctx, "SELECT address, reason, source, created_at FROM blocked_addresses ORDER BY created_at DESC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.BlockedAddress
+	for rows.Next() {
+		var b models.BlockedAddress
+		if err := rows.Scan(&b.Address, &b.Reason, &b.Source, &b.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, b)
+	}
+	return out, rows.Err()
+}
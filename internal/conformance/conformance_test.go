@@ -0,0 +1,96 @@
+package conformance
+
+import "testing"
+
+// TestConformanceVectors runs the OrderEngine wallet-grouping/nonce corpus
+// (the one LoadVectors/Run load from vectors/*.json) through go test, so a
+// mismatch here fails `go test ./...` the same way any other regression
+// would, instead of only surfacing when someone remembers to separately run
+// the cmd/conformance binary.
+func TestConformanceVectors(t *testing.T) {
+	vectors, err := LoadVectors()
+	if err != nil {
+		t.Fatalf("loading conformance vectors: %v", err)
+	}
+	if len(vectors) == 0 {
+		t.Fatal("no conformance vectors loaded")
+	}
+
+	for _, r := range Run(vectors) {
+		r := r
+		t.Run(r.Vector.Name, func(t *testing.T) {
+			if !r.Passed() {
+				for _, f := range r.Failures {
+					t.Error(f)
+				}
+			}
+		})
+	}
+}
+
+// TestSizingVectors runs the CopyEngine position-sizing corpus.
+func TestSizingVectors(t *testing.T) {
+	vectors, err := LoadSizingVectors()
+	if err != nil {
+		t.Fatalf("loading sizing vectors: %v", err)
+	}
+	if len(vectors) == 0 {
+		t.Fatal("no sizing vectors loaded")
+	}
+
+	for _, r := range RunSizing(vectors) {
+		r := r
+		t.Run(r.Vector.Name, func(t *testing.T) {
+			if !r.Passed() {
+				for _, f := range r.Failures {
+					t.Error(f)
+				}
+			}
+		})
+	}
+}
+
+// TestDecideVectors runs the CopyEngine/RiskManager decision corpus.
+func TestDecideVectors(t *testing.T) {
+	vectors, err := LoadDecideVectors()
+	if err != nil {
+		t.Fatalf("loading decide vectors: %v", err)
+	}
+	if len(vectors) == 0 {
+		t.Fatal("no decide vectors loaded")
+	}
+
+	for _, r := range RunDecide(vectors) {
+		r := r
+		t.Run(r.Vector.Name, func(t *testing.T) {
+			if !r.Passed() {
+				for _, f := range r.Failures {
+					t.Error(f)
+				}
+			}
+		})
+	}
+}
+
+// TestPermissionlessVectors runs the PermissionlessCopyEngine copy-decision
+// corpus.
+func TestPermissionlessVectors(t *testing.T) {
+	vectors, err := LoadPermissionlessVectors()
+	if err != nil {
+		t.Fatalf("loading permissionless vectors: %v", err)
+	}
+	if len(vectors) == 0 {
+		t.Fatal("no permissionless vectors loaded")
+	}
+
+	for _, r := range RunPermissionless(vectors) {
+		r := r
+		t.Run(r.Vector.Name, func(t *testing.T) {
+			if !r.Passed() {
+				for _, f := range r.Failures {
+					t.Error(f)
+				}
+			}
+		})
+	}
+}
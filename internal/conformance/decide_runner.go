@@ -0,0 +1,64 @@
+package conformance
+
+import (
+	"fmt"
+	"math"
+
+	"hyperliquid-copy-trading/internal/engine"
+	"hyperliquid-copy-trading/internal/models"
+)
+
+// DecideResult is one DecideVector's outcome, mirroring Result and
+// SizingResult.
+type DecideResult struct {
+	Vector   DecideVector
+	Failures []string
+}
+
+// Passed reports whether Vector ran without any mismatch.
+func (r DecideResult) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// RunDecide drives every DecideVector through engine.Decide and reports any
+// mismatch against its expectation.
+func RunDecide(vectors []DecideVector) []DecideResult {
+	results := make([]DecideResult, 0, len(vectors))
+	for _, v := range vectors {
+		results = append(results, runDecideOne(v))
+	}
+	return results
+}
+
+func runDecideOne(v DecideVector) DecideResult {
+	got := engine.Decide(v.Input)
+
+	var failures []string
+	if got.Risk.Approved != v.Expected.Approved {
+		failures = append(failures, fmt.Sprintf("expected approved=%v, got %v (reason %q)", v.Expected.Approved, got.Risk.Approved, got.Risk.Reason))
+	} else if !v.Expected.Approved && got.Risk.Reason != v.Expected.Reason {
+		failures = append(failures, fmt.Sprintf("expected rejection reason %q, got %q", v.Expected.Reason, got.Risk.Reason))
+	}
+
+	if math.Abs(got.PositionSize-v.Expected.PositionSize) > sizeEpsilon {
+		failures = append(failures, fmt.Sprintf("expected position_size %.8f, got %.8f", v.Expected.PositionSize, got.PositionSize))
+	}
+
+	if v.Expected.Order != nil {
+		if got.Order == nil {
+			failures = append(failures, "expected an order, none produced")
+		} else if !ordersMatch(*v.Expected.Order, *got.Order) {
+			failures = append(failures, fmt.Sprintf("expected order %+v, got %+v", *v.Expected.Order, *got.Order))
+		}
+	} else if got.Order != nil {
+		failures = append(failures, fmt.Sprintf("expected no order, got %+v", *got.Order))
+	}
+
+	return DecideResult{Vector: v, Failures: failures}
+}
+
+// ordersMatch compares the fields Decide actually fills in; Nonce is
+// engine-assigned at submit time and not part of a pure decision.
+func ordersMatch(want, got models.OrderRequest) bool {
+	return want.Asset == got.Asset && want.IsBuy == got.IsBuy && want.Size == got.Size && want.OrderType == got.OrderType
+}
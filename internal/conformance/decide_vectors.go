@@ -0,0 +1,145 @@
+// DecideVector cases cover engine.Decide end to end: risk assessment,
+// position sizing and order construction for one follower copying one
+// leader trade, against a pinned history/position/volatility/margin
+// snapshot instead of a live RiskManager's DB-backed and streaming state.
+// This is the corpus sizing_vectors.go's doc comment flagged as missing —
+// CopyEngine.processBatch's risk-approval path, now exercisable offline
+// through the Decide seam instead of only implicitly via a running engine.
+package conformance
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"hyperliquid-copy-trading/internal/engine"
+	"hyperliquid-copy-trading/internal/models"
+)
+
+//go:embed vectors/decide/*.json
+var decideVectorFS embed.FS
+
+// decideVectorsDirEnv, when set, points LoadDecideVectors at a local
+// checkout of the sibling hyperlit-vectors repo instead of the corpus
+// embedded in this binary, so a branch of that repo can be exercised in CI
+// before its cases are vendored back here. HYPERLIT_VECTORS_BRANCH only
+// selects which branch that checkout is expected to already be on — this
+// package reads the directory as-is, it does not shell out to git to fetch
+// or switch it, matching the rest of this codebase never invoking external
+// processes.
+const (
+	decideVectorsBranchEnv  = "HYPERLIT_VECTORS_BRANCH"
+	decideVectorsDirEnv     = "HYPERLIT_VECTORS_DIR"
+	defaultDecideVectorsDir = "../hyperlit-vectors"
+)
+
+// DecideVector is one engine.Decide test case: the full DecisionInput it
+// reads, and the decision it's expected to produce.
+type DecideVector struct {
+	Name        string               `json:"name"`
+	Description string               `json:"description"`
+	Input       engine.DecisionInput `json:"input"`
+	Expected    DecideExpectation    `json:"expected"`
+}
+
+// DecideExpectation is the subset of engine.DecisionOutput a vector asserts
+// on. Reason is only compared when Approved is false, and Order is only
+// compared when it's non-nil, since a rejected or zero-sized decision has
+// no order to check.
+type DecideExpectation struct {
+	Approved     bool                 `json:"approved"`
+	Reason       string               `json:"reason,omitempty"`
+	PositionSize float64              `json:"position_size"`
+	Order        *models.OrderRequest `json:"order,omitempty"`
+}
+
+// LoadDecideVectors reads every decide vector, from a sibling
+// hyperlit-vectors checkout if HYPERLIT_VECTORS_BRANCH is set, or from the
+// corpus embedded in this binary otherwise.
+func LoadDecideVectors() ([]DecideVector, error) {
+	if branch := os.Getenv(decideVectorsBranchEnv); branch != "" {
+		return loadDecideVectorsFromDir(decideVectorsDir(), branch)
+	}
+
+	entries, err := decideVectorFS.ReadDir("vectors/decide")
+	if err != nil {
+		return nil, fmt.Errorf("reading embedded decide vectors: %w", err)
+	}
+
+	vectors := make([]DecideVector, 0, len(entries))
+	for _, entry := range entries {
+		data, err := decideVectorFS.ReadFile("vectors/decide/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("reading decide vector %s: %w", entry.Name(), err)
+		}
+
+		var v DecideVector
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, fmt.Errorf("parsing decide vector %s: %w", entry.Name(), err)
+		}
+		vectors = append(vectors, v)
+	}
+
+	return vectors, nil
+}
+
+func decideVectorsDir() string {
+	if dir := os.Getenv(decideVectorsDirEnv); dir != "" {
+		return dir
+	}
+	return defaultDecideVectorsDir
+}
+
+func loadDecideVectorsFromDir(dir, branch string) ([]DecideVector, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s (expected branch %q already checked out): %w", dir, branch, err)
+	}
+
+	vectors := make([]DecideVector, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("reading decide vector %s: %w", entry.Name(), err)
+		}
+
+		var v DecideVector
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, fmt.Errorf("parsing decide vector %s: %w", entry.Name(), err)
+		}
+		vectors = append(vectors, v)
+	}
+
+	return vectors, nil
+}
+
+// RecordDecideVector runs input through engine.Decide and packages the
+// result as a DecideVector ready to be written to vectors/decide/, so a
+// contributor adding a case can capture it from a real (or hand-built)
+// DecisionInput instead of hand-writing the expected_* fields. See
+// cmd/conformance's -record flag.
+func RecordDecideVector(name, description string, input engine.DecisionInput) DecideVector {
+	output := engine.Decide(input)
+
+	expected := DecideExpectation{
+		Approved:     output.Risk.Approved,
+		PositionSize: output.PositionSize,
+		Order:        output.Order,
+	}
+	if !output.Risk.Approved {
+		expected.Reason = output.Risk.Reason
+	}
+
+	return DecideVector{
+		Name:        name,
+		Description: description,
+		Input:       input,
+		Expected:    expected,
+	}
+}
@@ -0,0 +1,78 @@
+package conformance
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"hyperliquid-copy-trading/internal/models"
+)
+
+// recordedBatch is one BatchOrders call observed by mockExchangeClient,
+// capturing exactly what OrderEngine submitted for a single (venue, wallet)
+// group.
+type recordedBatch struct {
+	wallet string
+	nonce  int64
+	orders []*models.OrderRequest
+}
+
+// mockExchangeClient implements engine.ExchangeClient by recording every
+// BatchOrders call instead of sending it anywhere, so a Vector can diff the
+// engine's wallet-grouping and nonce assignment against its expectation
+// without touching a live exchange. The other ExchangeClient methods are
+// unused by ExecuteBatchStateless but are stubbed out so the mock still
+// satisfies the interface.
+type mockExchangeClient struct {
+	mu      sync.Mutex
+	batches []recordedBatch
+}
+
+func newMockExchangeClient() *mockExchangeClient {
+	return &mockExchangeClient{}
+}
+
+func (m *mockExchangeClient) BatchOrders(ctx context.Context, orders []*models.OrderRequest, apiWalletAddress string, nonce int64) (*models.OrderResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.batches = append(m.batches, recordedBatch{
+		wallet: apiWalletAddress,
+		nonce:  nonce,
+		orders: orders,
+	})
+
+	statuses := make([]models.OrderResponseStatus, len(orders))
+	for i := range orders {
+		statuses[i] = models.OrderResponseStatus{Resting: &models.OrderRestingInfo{Oid: int64(i)}}
+	}
+	return &models.OrderResponse{
+		Status: "ok",
+		Data:   models.OrderResponseData{Statuses: statuses},
+	}, nil
+}
+
+func (m *mockExchangeClient) snapshot() []recordedBatch {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make([]recordedBatch, len(m.batches))
+	copy(out, m.batches)
+	return out
+}
+
+func (m *mockExchangeClient) PlaceOrder(ctx context.Context, order *models.OrderRequest, apiWalletAddress string) (*models.OrderResponse, error) {
+	return nil, fmt.Errorf("mockExchangeClient: PlaceOrder not exercised by conformance vectors")
+}
+
+func (m *mockExchangeClient) CancelOrder(ctx context.Context, asset string, oid int64, apiWalletAddress string, nonce int64) (*models.HyperliquidAPIResponse, error) {
+	return nil, fmt.Errorf("mockExchangeClient: CancelOrder not exercised by conformance vectors")
+}
+
+func (m *mockExchangeClient) GetOrderStatus(ctx context.Context, userAddress string, oid int64) (map[string]interface{}, error) {
+	return nil, fmt.Errorf("mockExchangeClient: GetOrderStatus not exercised by conformance vectors")
+}
+
+func (m *mockExchangeClient) SubscribeUserEvents(walletAddress string) (chan models.UserEvent, error) {
+	return nil, fmt.Errorf("mockExchangeClient: SubscribeUserEvents not exercised by conformance vectors")
+}
@@ -0,0 +1,52 @@
+package conformance
+
+import (
+	"fmt"
+	"math"
+
+	"hyperliquid-copy-trading/internal/engine"
+)
+
+// PermissionlessResult is one PermissionlessVector's outcome, mirroring
+// DecideResult and SizingResult.
+type PermissionlessResult struct {
+	Vector   PermissionlessVector
+	Failures []string
+}
+
+// Passed reports whether Vector ran without any mismatch.
+func (r PermissionlessResult) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// RunPermissionless drives every PermissionlessVector through
+// engine.DecidePermissionlessCopy and reports any mismatch against its
+// expectation.
+func RunPermissionless(vectors []PermissionlessVector) []PermissionlessResult {
+	results := make([]PermissionlessResult, 0, len(vectors))
+	for _, v := range vectors {
+		results = append(results, runPermissionlessOne(v))
+	}
+	return results
+}
+
+func runPermissionlessOne(v PermissionlessVector) PermissionlessResult {
+	got := engine.DecidePermissionlessCopy(engine.PermissionlessDecisionInput{
+		Follower: v.Follower,
+		Trade:    v.Trade,
+		Now:      v.Now,
+	})
+
+	var failures []string
+	if got.Approved != v.Expected.Approved {
+		failures = append(failures, fmt.Sprintf("expected approved=%v, got %v (reason %q)", v.Expected.Approved, got.Approved, got.Reason))
+	} else if !v.Expected.Approved && got.Reason != v.Expected.Reason {
+		failures = append(failures, fmt.Sprintf("expected rejection reason %q, got %q", v.Expected.Reason, got.Reason))
+	}
+
+	if math.Abs(got.Size-v.Expected.Size) > sizeEpsilon {
+		failures = append(failures, fmt.Sprintf("expected size %.8f, got %.8f", v.Expected.Size, got.Size))
+	}
+
+	return PermissionlessResult{Vector: v, Failures: failures}
+}
@@ -0,0 +1,67 @@
+// PermissionlessVector cases cover engine.DecidePermissionlessCopy: the
+// CopyFilters (whitelist/blacklist, min/max position value, trading-hours
+// window, time delay) and sizing (copy percentage, min trade size,
+// MaxPositionSize clamping) pipeline a permissionless follower's copy
+// decision goes through before Router and Session would submit an order. Like
+// DecideVector, this deliberately stops short of order submission, since
+// that reads a live HyperliquidAPI through PermissionlessOrderClient rather
+// than a pure function of its inputs.
+package conformance
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"hyperliquid-copy-trading/internal/models"
+)
+
+//go:embed vectors/permissionless/*.json
+var permissionlessVectorFS embed.FS
+
+// PermissionlessVector is one DecidePermissionlessCopy test case: a
+// follower and leader trade in, at a pinned wall-clock reading, against the
+// expected approval/rejection and copy size.
+type PermissionlessVector struct {
+	Name        string                        `json:"name"`
+	Description string                        `json:"description"`
+	Follower    models.PermissionlessFollower `json:"follower"`
+	Trade       models.TradeEvent             `json:"trade"`
+	Now         time.Time                     `json:"now"`
+	Expected    PermissionlessExpectation     `json:"expected"`
+}
+
+// PermissionlessExpectation is the subset of
+// engine.PermissionlessDecisionOutput a vector asserts on. Reason is only
+// compared when Approved is false.
+type PermissionlessExpectation struct {
+	Approved bool    `json:"approved"`
+	Reason   string  `json:"reason,omitempty"`
+	Size     float64 `json:"size"`
+}
+
+// LoadPermissionlessVectors reads every vectors/permissionless/*.json file
+// embedded in the binary.
+func LoadPermissionlessVectors() ([]PermissionlessVector, error) {
+	entries, err := permissionlessVectorFS.ReadDir("vectors/permissionless")
+	if err != nil {
+		return nil, fmt.Errorf("reading embedded permissionless vectors: %w", err)
+	}
+
+	vectors := make([]PermissionlessVector, 0, len(entries))
+	for _, entry := range entries {
+		data, err := permissionlessVectorFS.ReadFile("vectors/permissionless/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("reading permissionless vector %s: %w", entry.Name(), err)
+		}
+
+		var v PermissionlessVector
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, fmt.Errorf("parsing permissionless vector %s: %w", entry.Name(), err)
+		}
+		vectors = append(vectors, v)
+	}
+
+	return vectors, nil
+}
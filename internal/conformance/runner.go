@@ -0,0 +1,109 @@
+package conformance
+
+import (
+	"context"
+	"fmt"
+
+	"hyperliquid-copy-trading/config"
+	"hyperliquid-copy-trading/internal/engine"
+	"hyperliquid-copy-trading/internal/models"
+)
+
+// Result is one Vector's outcome: Failures is empty iff the engine's
+// recorded BatchOrders calls matched Vector.Expected exactly.
+type Result struct {
+	Vector   Vector
+	Failures []string
+}
+
+// Passed reports whether Vector ran without any mismatch.
+func (r Result) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// Run drives every vector through a fresh OrderEngine wired to a
+// mockExchangeClient (db and the websocket manager are both nil, which
+// OrderStateStore and the resume-on-boot path already treat as the
+// offline/test case) and diffs what the engine actually submitted against
+// what the vector expects.
+func Run(vectors []Vector) []Result {
+	results := make([]Result, 0, len(vectors))
+	for _, v := range vectors {
+		results = append(results, runOne(v))
+	}
+	return results
+}
+
+func runOne(v Vector) Result {
+	mock := newMockExchangeClient()
+	exchanges := map[string]engine.ExchangeClient{models.DefaultVenue: mock}
+	oe := engine.NewOrderEngine(&config.Config{}, exchanges, nil, nil)
+
+	orders := make([]*models.OrderRequest, len(v.Orders))
+	for i := range v.Orders {
+		order := v.Orders[i]
+		orders[i] = &order
+	}
+
+	oe.ExecuteBatchStateless(context.Background(), orders, v.Followers, nil)
+	oe.Stop()
+
+	return Result{Vector: v, Failures: diff(v.Expected, mock.snapshot())}
+}
+
+// diff compares expected batches against what was actually recorded,
+// keyed by wallet since groupByWallet's backing map does not guarantee a
+// stable iteration order across wallets.
+func diff(expected []ExpectedBatch, actual []recordedBatch) []string {
+	var failures []string
+
+	actualByWallet := make(map[string]recordedBatch, len(actual))
+	for _, b := range actual {
+		actualByWallet[b.wallet] = b
+	}
+
+	seen := make(map[string]bool, len(expected))
+	for _, exp := range expected {
+		seen[exp.Wallet] = true
+
+		got, ok := actualByWallet[exp.Wallet]
+		if !ok {
+			failures = append(failures, fmt.Sprintf("wallet %s: expected a batch, none submitted", exp.Wallet))
+			continue
+		}
+
+		if len(got.orders) != len(exp.Orders) {
+			failures = append(failures, fmt.Sprintf("wallet %s: expected %d orders, got %d", exp.Wallet, len(exp.Orders), len(got.orders)))
+			continue
+		}
+
+		for _, order := range got.orders {
+			if order.Nonce != got.nonce {
+				failures = append(failures, fmt.Sprintf("wallet %s: order for %s has nonce %d, batch nonce is %d", exp.Wallet, order.Asset, order.Nonce, got.nonce))
+			}
+		}
+
+		for _, expOrder := range exp.Orders {
+			if !containsOrder(got.orders, expOrder) {
+				failures = append(failures, fmt.Sprintf("wallet %s: missing expected order %+v", exp.Wallet, expOrder))
+			}
+		}
+	}
+
+	for wallet := range actualByWallet {
+		if !seen[wallet] {
+			failures = append(failures, fmt.Sprintf("wallet %s: submitted a batch but no vector expectation covers it", wallet))
+		}
+	}
+
+	return failures
+}
+
+func containsOrder(orders []*models.OrderRequest, want ExpectedOrder) bool {
+	for _, order := range orders {
+		if order.Asset == want.Asset && order.IsBuy == want.IsBuy && order.Size == want.Size && order.ReduceOnly == want.ReduceOnly {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package conformance
+
+import (
+	"fmt"
+	"math"
+
+	"hyperliquid-copy-trading/internal/engine"
+)
+
+// sizeEpsilon tolerates floating point drift between a vector's hand-computed
+// expected_size and the tick-rounded result RunSizing actually produces.
+const sizeEpsilon = 1e-9
+
+// SizingResult is one SizingVector's outcome, mirroring Result.
+type SizingResult struct {
+	Vector   SizingVector
+	Failures []string
+}
+
+// Passed reports whether Vector ran without any mismatch.
+func (r SizingResult) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// RunSizing drives every SizingVector through engine.CalculatePositionSize
+// against its own offline instrument universe and reports any mismatch.
+func RunSizing(vectors []SizingVector) []SizingResult {
+	results := make([]SizingResult, 0, len(vectors))
+	for _, v := range vectors {
+		results = append(results, runSizingOne(v))
+	}
+	return results
+}
+
+func runSizingOne(v SizingVector) SizingResult {
+	instruments := v.instrumentRegistry()
+	got := engine.CalculatePositionSize(instruments, &v.Follower, &v.LeaderTrade, v.AdjustedSize)
+
+	var failures []string
+	if math.Abs(got-v.Expected) > sizeEpsilon {
+		failures = append(failures, fmt.Sprintf("expected size %.8f, got %.8f", v.Expected, got))
+	}
+
+	return SizingResult{Vector: v, Failures: failures}
+}
@@ -0,0 +1,70 @@
+// SizingVector cases cover engine.CalculatePositionSize directly: the pure
+// copy-percentage/risk-adjustment/MaxPositionSize/leverage-cap/tick-rounding
+// pipeline a leader trade goes through before an order is built. This is
+// deliberately narrower than "the copy-trading decision engine" end to end
+// — CopyEngine.processBatch's risk-approval and order-submission paths go
+// through *database.PostgresDB and a live ExchangeClient, neither of which
+// has an offline/mock seam the way OrderEngine's ExchangeClient does, so
+// partial fills, bracket (stop-loss/take-profit) ordering, and a leader
+// closing a position a follower never opened aren't exercised here. Sizing
+// is the part of the pipeline that's pure and worth pinning down.
+package conformance
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+
+	"hyperliquid-copy-trading/internal/api"
+	"hyperliquid-copy-trading/internal/exchange/meta"
+	"hyperliquid-copy-trading/internal/models"
+)
+
+//go:embed vectors/sizing/*.json
+var sizingVectorFS embed.FS
+
+// SizingVector is one CalculatePositionSize test case: a leader trade and
+// follower configuration in, against a given universe's instrument limits,
+// with the expected copy size out.
+type SizingVector struct {
+	Name         string                  `json:"name"`
+	Description  string                  `json:"description"`
+	Assets       map[string]api.AssetMeta `json:"assets"`
+	Follower     models.Follower         `json:"follower"`
+	LeaderTrade  models.Trade            `json:"leader_trade"`
+	AdjustedSize float64                 `json:"adjusted_size"`
+	Expected     float64                 `json:"expected_size"`
+}
+
+// LoadSizingVectors reads every vectors/sizing/*.json file embedded in the
+// binary.
+func LoadSizingVectors() ([]SizingVector, error) {
+	entries, err := sizingVectorFS.ReadDir("vectors/sizing")
+	if err != nil {
+		return nil, fmt.Errorf("reading embedded sizing vectors: %w", err)
+	}
+
+	vectors := make([]SizingVector, 0, len(entries))
+	for _, entry := range entries {
+		data, err := sizingVectorFS.ReadFile("vectors/sizing/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("reading sizing vector %s: %w", entry.Name(), err)
+		}
+
+		var v SizingVector
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, fmt.Errorf("parsing sizing vector %s: %w", entry.Name(), err)
+		}
+		vectors = append(vectors, v)
+	}
+
+	return vectors, nil
+}
+
+// instrumentRegistry builds an offline meta.Registry over assets, using
+// api.NewStaticAssetRegistry's pre-populated, no-live-fetch construction so
+// a vector's instrument universe is pinned without a Hyperliquid round
+// trip.
+func (v SizingVector) instrumentRegistry() *meta.Registry {
+	return meta.NewRegistry(api.NewStaticAssetRegistry(v.Assets))
+}
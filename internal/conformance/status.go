@@ -0,0 +1,93 @@
+package conformance
+
+import "os"
+
+// SkipEnabled reports whether SKIP_CONFORMANCE is set to a non-empty value,
+// letting both the conformance CLI and GET /conformance/status opt out of a
+// full run during local development, once the vector corpus gets heavy
+// enough that running it on every request or every CI invocation is
+// unwelcome.
+func SkipEnabled() bool {
+	return os.Getenv("SKIP_CONFORMANCE") != ""
+}
+
+// Status is the pass/fail summary of the most recent conformance run,
+// across all four vector corpora (OrderEngine wallet-grouping, CopyEngine
+// position sizing, CopyEngine/RiskManager decisions, and
+// PermissionlessCopyEngine copy decisions).
+type Status struct {
+	Skipped              bool `json:"skipped"`
+	OrderEnginePassed    int  `json:"order_engine_passed"`
+	OrderEngineFailed    int  `json:"order_engine_failed"`
+	SizingPassed         int  `json:"sizing_passed"`
+	SizingFailed         int  `json:"sizing_failed"`
+	DecidePassed         int  `json:"decide_passed"`
+	DecideFailed         int  `json:"decide_failed"`
+	PermissionlessPassed int  `json:"permissionless_passed"`
+	PermissionlessFailed int  `json:"permissionless_failed"`
+}
+
+// RunStatus loads and runs every embedded vector in all four corpora and
+// summarizes the outcome. It returns Status{Skipped: true} without running
+// anything if SkipEnabled.
+func RunStatus() (Status, error) {
+	if SkipEnabled() {
+		return Status{Skipped: true}, nil
+	}
+
+	vectors, err := LoadVectors()
+	if err != nil {
+		return Status{}, err
+	}
+	results := Run(vectors)
+
+	sizingVectors, err := LoadSizingVectors()
+	if err != nil {
+		return Status{}, err
+	}
+	sizingResults := RunSizing(sizingVectors)
+
+	decideVectors, err := LoadDecideVectors()
+	if err != nil {
+		return Status{}, err
+	}
+	decideResults := RunDecide(decideVectors)
+
+	permissionlessVectors, err := LoadPermissionlessVectors()
+	if err != nil {
+		return Status{}, err
+	}
+	permissionlessResults := RunPermissionless(permissionlessVectors)
+
+	status := Status{}
+	for _, r := range results {
+		if r.Passed() {
+			status.OrderEnginePassed++
+		} else {
+			status.OrderEngineFailed++
+		}
+	}
+	for _, r := range sizingResults {
+		if r.Passed() {
+			status.SizingPassed++
+		} else {
+			status.SizingFailed++
+		}
+	}
+	for _, r := range decideResults {
+		if r.Passed() {
+			status.DecidePassed++
+		} else {
+			status.DecideFailed++
+		}
+	}
+	for _, r := range permissionlessResults {
+		if r.Passed() {
+			status.PermissionlessPassed++
+		} else {
+			status.PermissionlessFailed++
+		}
+	}
+
+	return status, nil
+}
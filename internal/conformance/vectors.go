@@ -0,0 +1,84 @@
+// Package conformance is a regression harness for OrderEngine's
+// wallet-grouping and nonce-assignment logic, independent of live Hyperliquid
+// behavior. It loads JSON test vectors describing a leader trade already
+// expanded into per-follower orders, runs them through the real OrderEngine
+// against a mock ExchangeClient, and diffs the recorded BatchOrders calls
+// against each vector's expectation. This is the harness referenced by
+// OrderEngine's wallet-grouping doc comments as the thing to run before
+// touching groupByWallet or the nonce-assignment path.
+//
+// OrderEngine.processBatch itself is unexported, so vectors drive the engine
+// through ExecuteBatchStateless instead: it groups by (venue, wallet) and
+// assigns nonces the same way the tracked ExecuteBatch path does, but
+// resolves synchronously enough for a vector's expectations to be checked
+// once OrderEngine.Stop has drained its wallet goroutines.
+package conformance
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+
+	"hyperliquid-copy-trading/internal/models"
+)
+
+//go:embed vectors/*.json
+var vectorFS embed.FS
+
+// Vector is one conformance test case: a leader trade already expanded into
+// per-follower orders (Orders[i] belongs to Followers[i], matching the
+// index-parallel contract groupByWallet relies on), and the per-wallet
+// batches OrderEngine is expected to submit.
+type Vector struct {
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	Orders      []models.OrderRequest `json:"orders"`
+	Followers   []models.Follower     `json:"followers"`
+	Expected    []ExpectedBatch       `json:"expected_batches"`
+}
+
+// ExpectedBatch is the per-wallet BatchOrders call a Vector expects the mock
+// ExchangeClient to record, with Orders compared unordered since
+// groupByWallet's backing map does not preserve follower arrival order
+// across wallets (only within a wallet's own slice). Every vector in this
+// package trades on models.DefaultVenue, so the venue dimension of
+// groupByWallet's (venue, wallet) key isn't separately exercised here.
+type ExpectedBatch struct {
+	Wallet string          `json:"wallet"`
+	Orders []ExpectedOrder `json:"orders"`
+}
+
+// ExpectedOrder is the subset of models.OrderRequest a vector asserts on;
+// Nonce is deliberately omitted since the engine assigns it at submit time,
+// and every order in a batch sharing the batch's single nonce is checked by
+// the runner directly against the recorded batch, not per order.
+type ExpectedOrder struct {
+	Asset      string  `json:"asset"`
+	IsBuy      bool    `json:"is_buy"`
+	Size       float64 `json:"size"`
+	ReduceOnly bool    `json:"reduce_only"`
+}
+
+// LoadVectors reads every vectors/*.json file embedded in the binary.
+func LoadVectors() ([]Vector, error) {
+	entries, err := vectorFS.ReadDir("vectors")
+	if err != nil {
+		return nil, fmt.Errorf("reading embedded vectors: %w", err)
+	}
+
+	vectors := make([]Vector, 0, len(entries))
+	for _, entry := range entries {
+		data, err := vectorFS.ReadFile("vectors/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("reading vector %s: %w", entry.Name(), err)
+		}
+
+		var v Vector
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, fmt.Errorf("parsing vector %s: %w", entry.Name(), err)
+		}
+		vectors = append(vectors, v)
+	}
+
+	return vectors, nil
+}
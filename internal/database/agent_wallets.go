@@ -0,0 +1,179 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"hyperliquid-copy-trading/internal/database/dbretry"
+	"hyperliquid-copy-trading/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SaveAgentWallet inserts wallet, implementing api.AgentWalletStore so
+// AgentWalletManager can persist a generated agent wallet without
+// internal/api importing this package directly. wallet.FollowerID is nil
+// at this point -- BindAgentWallet attaches it once CreateFollower has
+// assigned a real id, since agent wallet generation and approval both
+// happen before a follower row exists to reference.
+func (db *PostgresDB) SaveAgentWallet(ctx context.Context, wallet *models.AgentWallet) error {
+	defer db.markWrite(ctx)
+
+	query := `
+		INSERT INTO agent_wallets (follower_id, master_address, address, encrypted_key, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+
+	return dbretry.Do(ctx, db.retryMaxElapsed, func(ctx context.Context) error {
+		return db.pool.QueryRow(ctx, query,
+			wallet.FollowerID,
+			wallet.MasterAddress,
+			wallet.Address,
+			wallet.EncryptedKey,
+			wallet.ExpiresAt,
+		).Scan(&wallet.ID, &wallet.CreatedAt)
+	})
+}
+
+// GetAgentWalletByAddress returns the agent wallet generated for address,
+// or nil if there is none -- used during enrollment, before a follower id
+// exists to look it up by.
+func (db *PostgresDB) GetAgentWalletByAddress(ctx context.Context, address string) (*models.AgentWallet, error) {
+	query := `
+		SELECT id, follower_id, master_address, address, encrypted_key, expires_at, approved_at, revoked_at, created_at
+		FROM agent_wallets
+		WHERE address = $1`
+
+	var w models.AgentWallet
+	err := dbretry.Do(ctx, db.retryMaxElapsed, func(ctx context.Context) error {
+		return db.ReadOnly(ctx).QueryRow(ctx, query, address).Scan(
+			&w.ID,
+			&w.FollowerID,
+			&w.MasterAddress,
+			&w.Address,
+			&w.EncryptedKey,
+			&w.ExpiresAt,
+			&w.ApprovedAt,
+			&w.RevokedAt,
+			&w.CreatedAt,
+		)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting agent wallet for address %s: %w", address, err)
+	}
+	return &w, nil
+}
+
+// GetAgentWallet returns followerID's bound agent wallet, or nil if it
+// has none.
+func (db *PostgresDB) GetAgentWallet(ctx context.Context, followerID int) (*models.AgentWallet, error) {
+	query := `
+		SELECT id, follower_id, master_address, address, encrypted_key, expires_at, approved_at, revoked_at, created_at
+		FROM agent_wallets
+		WHERE follower_id = $1`
+
+	var w models.AgentWallet
+	err := dbretry.Do(ctx, db.retryMaxElapsed, func(ctx context.Context) error {
+		return db.ReadOnly(ctx).QueryRow(ctx, query, followerID).Scan(
+			&w.ID,
+			&w.FollowerID,
+			&w.MasterAddress,
+			&w.Address,
+			&w.EncryptedKey,
+			&w.ExpiresAt,
+			&w.ApprovedAt,
+			&w.RevokedAt,
+			&w.CreatedAt,
+		)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting agent wallet for follower %d: %w", followerID, err)
+	}
+	return &w, nil
+}
+
+// ListApprovedAgentWallets returns every agent wallet that has been
+// approved and not revoked, regardless of expiry -- AgentWalletManager
+// filters out already-expired ones itself, since that decision belongs to
+// the caller's clock, not the query. Implements api.AgentWalletStore for
+// AgentWalletManager's startup reload.
+func (db *PostgresDB) ListApprovedAgentWallets(ctx context.Context) ([]*models.AgentWallet, error) {
+	query := `
+		SELECT id, follower_id, master_address, address, encrypted_key, expires_at, approved_at, revoked_at, created_at
+		FROM agent_wallets
+		WHERE approved_at IS NOT NULL AND revoked_at IS NULL`
+
+	rows, err := dbretry.Query(ctx, db.retryMaxElapsed, func(ctx context.Context) (pgx.Rows, error) {
+		return db.ReadOnly(ctx).Query(ctx, query)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing approved agent wallets: %w", err)
+	}
+	defer rows.Close()
+
+	var wallets []*models.AgentWallet
+	for rows.Next() {
+		var w models.AgentWallet
+		if err := rows.Scan(
+			&w.ID,
+			&w.FollowerID,
+			&w.MasterAddress,
+			&w.Address,
+			&w.EncryptedKey,
+			&w.ExpiresAt,
+			&w.ApprovedAt,
+			&w.RevokedAt,
+			&w.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		wallets = append(wallets, &w)
+	}
+
+	return wallets, rows.Err()
+}
+
+// MarkAgentWalletApproved records that the agent wallet at address has
+// had its approveAgent action accepted by Hyperliquid, implementing
+// api.AgentWalletStore for AgentWalletManager.ApproveAgent.
+func (db *PostgresDB) MarkAgentWalletApproved(ctx context.Context, address string) error {
+	defer db.markWrite(ctx)
+
+	query := `UPDATE agent_wallets SET approved_at = NOW() WHERE address = $1`
+	return dbretry.Do(ctx, db.retryMaxElapsed, func(ctx context.Context) error {
+		_, err := db.pool.Exec(ctx, query, address)
+		return err
+	})
+}
+
+// BindAgentWallet attaches an already-approved agent wallet to followerID,
+// once CreateFollower has assigned it a real id. Implements
+// api.AgentWalletStore for AgentWalletManager.BindFollower.
+func (db *PostgresDB) BindAgentWallet(ctx context.Context, address string, followerID int) error {
+	defer db.markWrite(ctx)
+
+	query := `UPDATE agent_wallets SET follower_id = $1 WHERE address = $2`
+	return dbretry.Do(ctx, db.retryMaxElapsed, func(ctx context.Context) error {
+		_, err := db.pool.Exec(ctx, query, followerID, address)
+		return err
+	})
+}
+
+// RevokeAgentWallet marks followerID's agent wallet revoked, implementing
+// api.AgentWalletStore for AgentWalletManager.RevokeAgent.
+func (db *PostgresDB) RevokeAgentWallet(ctx context.Context, followerID int) error {
+	defer db.markWrite(ctx)
+
+	query := `UPDATE agent_wallets SET revoked_at = NOW() WHERE follower_id = $1`
+	return dbretry.Do(ctx, db.retryMaxElapsed, func(ctx context.Context) error {
+		_, err := db.pool.Exec(ctx, query, followerID)
+		return err
+	})
+}
@@ -0,0 +1,111 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"hyperliquid-copy-trading/internal/database/dbretry"
+	"hyperliquid-copy-trading/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SaveBridgeJob inserts job if job.ID is unset, or updates the existing row
+// otherwise, implementing api.BridgeJobStore so BridgeManager can persist a
+// BridgeJob at every status transition without internal/api importing this
+// package directly.
+func (db *PostgresDB) SaveBridgeJob(ctx context.Context, job *models.BridgeJob) error {
+	defer db.markWrite(ctx)
+
+	if job.ID == 0 {
+		query := `
+			INSERT INTO bridge_jobs (source_chain_id, source_wallet, source_token, source_amount,
+				provider, quote_amount_out, quote_fee, quote_eta_ms, status, source_tx_hash,
+				arbitrum_tx_hash, error_message)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			RETURNING id, created_at, updated_at`
+
+		return dbretry.Do(ctx, db.retryMaxElapsed, func(ctx context.Context) error {
+			return db.pool.QueryRow(ctx, query,
+				job.Source.ChainID,
+				job.Source.Wallet,
+				job.Source.Token,
+				job.Source.Amount,
+				job.Provider,
+				job.Quote.AmountOut,
+				job.Quote.Fee,
+				job.Quote.EstimatedTime.Milliseconds(),
+				job.Status,
+				job.SourceTxHash,
+				job.ArbitrumTxHash,
+				job.ErrorMessage,
+			).Scan(&job.ID, &job.CreatedAt, &job.UpdatedAt)
+		})
+	}
+
+	query := `
+		UPDATE bridge_jobs SET
+			status = $1, source_tx_hash = $2, arbitrum_tx_hash = $3, error_message = $4, updated_at = NOW()
+		WHERE id = $5
+		RETURNING updated_at`
+
+	return dbretry.Do(ctx, db.retryMaxElapsed, func(ctx context.Context) error {
+		return db.pool.QueryRow(ctx, query,
+			job.Status,
+			job.SourceTxHash,
+			job.ArbitrumTxHash,
+			job.ErrorMessage,
+			job.ID,
+		).Scan(&job.UpdatedAt)
+	})
+}
+
+// GetPendingBridgeJobs returns every BridgeJob not yet in a terminal state
+// (DepositedToHL or Failed), for BridgeManager.Resume to pick back up after
+// a restart.
+func (db *PostgresDB) GetPendingBridgeJobs(ctx context.Context) ([]*models.BridgeJob, error) {
+	query := `
+		SELECT id, source_chain_id, source_wallet, source_token, source_amount,
+			provider, quote_amount_out, quote_fee, quote_eta_ms, status, source_tx_hash,
+			arbitrum_tx_hash, error_message, created_at, updated_at
+		FROM bridge_jobs
+		WHERE status NOT IN ($1, $2)
+		ORDER BY created_at ASC`
+
+	rows, err := dbretry.Query(ctx, db.retryMaxElapsed, func(ctx context.Context) (pgx.Rows, error) {
+		return db.ReadOnly(ctx).Query(ctx, query, models.BridgeStatusDepositedToHL, models.BridgeStatusFailed)
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []*models.BridgeJob
+	for rows.Next() {
+		var j models.BridgeJob
+		var etaMs int64
+		err := rows.Scan(
+			&j.ID,
+			&j.Source.ChainID,
+			&j.Source.Wallet,
+			&j.Source.Token,
+			&j.Source.Amount,
+			&j.Provider,
+			&j.Quote.AmountOut,
+			&j.Quote.Fee,
+			&etaMs,
+			&j.Status,
+			&j.SourceTxHash,
+			&j.ArbitrumTxHash,
+			&j.ErrorMessage,
+			&j.CreatedAt,
+			&j.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		j.Quote.EstimatedTime = time.Duration(etaMs) * time.Millisecond
+		jobs = append(jobs, &j)
+	}
+	return jobs, rows.Err()
+}
@@ -0,0 +1,103 @@
+// Package dbretry retries transient Postgres failures — connection resets,
+// serialization failures, deadlocks, and "the database system is starting
+// up"-style unavailability — using the same doubling-backoff-plus-jitter
+// shape api.WebSocketClient already uses for reconnects. It never retries
+// an error that resubmitting the same statement wouldn't fix, such as a
+// unique violation.
+package dbretry
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	initialBackoff = 50 * time.Millisecond
+	maxBackoff     = 2 * time.Second
+)
+
+// Retryable reports whether err is a transient Postgres failure worth
+// retrying: SQLSTATE class 08 (connection exception), 40001
+// (serialization_failure), 40P01 (deadlock_detected), and 57P03
+// (cannot_connect_now). Everything else — including 23505
+// (unique_violation), which a retry would only resubmit unchanged — is
+// left to the caller.
+func Retryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	if strings.HasPrefix(pgErr.Code, "08") {
+		return true
+	}
+	switch pgErr.Code {
+	case "40001", "40P01", "57P03":
+		return true
+	default:
+		return false
+	}
+}
+
+// Do retries op until it succeeds, returns a non-retryable error, ctx is
+// cancelled, or maxElapsed has passed since the first attempt. maxElapsed
+// of zero disables the deadline and leaves cancellation entirely to ctx.
+func Do(ctx context.Context, maxElapsed time.Duration, op func(ctx context.Context) error) error {
+	start := time.Now()
+	backoff := initialBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := op(ctx)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+		if !Retryable(err) {
+			return err
+		}
+		if maxElapsed > 0 && time.Since(start) > maxElapsed {
+			return err
+		}
+
+		log.Warn().Err(err).Int("attempt", attempt).Msg("Retrying transient database error")
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(jitter(backoff)):
+		}
+		backoff = nextBackoff(backoff)
+	}
+}
+
+// Query is Do's counterpart for operations that return a value alongside
+// the error, such as pool.Query or pool.QueryRow().Scan(...).
+func Query[T any](ctx context.Context, maxElapsed time.Duration, op func(ctx context.Context) (T, error)) (T, error) {
+	var result T
+	err := Do(ctx, maxElapsed, func(ctx context.Context) error {
+		var opErr error
+		result, opErr = op(ctx)
+		return opErr
+	})
+	return result, err
+}
+
+func nextBackoff(cur time.Duration) time.Duration {
+	next := cur * 2
+	if next > maxBackoff {
+		return maxBackoff
+	}
+	return next
+}
+
+func jitter(d time.Duration) time.Duration {
+	delta := time.Duration(rand.Int63n(int64(d)/2 + 1))
+	return d/2 + delta
+}
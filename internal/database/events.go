@@ -0,0 +1,71 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+
+	"hyperliquid-copy-trading/internal/database/dbretry"
+	"hyperliquid-copy-trading/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// RecordLeaderEvent appends event to the immutable leader_events log.
+// Callers don't set event.ID/CreatedAt; both are filled in from the
+// insert.
+func (db *PostgresDB) RecordLeaderEvent(ctx context.Context, event *models.LeaderEvent) error {
+	defer db.markWrite(ctx)
+
+	payload, err := json.Marshal(event.Payload)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO leader_events (leader, asset, event_type, height, payload)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+
+	return dbretry.Do(ctx, db.retryMaxElapsed, func(ctx context.Context) error {
+		return db.pool.QueryRow(ctx, query,
+			event.Leader, event.Asset, event.EventType, event.Height, payload,
+		).Scan(&event.ID, &event.CreatedAt)
+	})
+}
+
+// QueryLeaderEvents returns every leader_events row matching filter with
+// height in [fromHeight, toHeight], ordered oldest first, for a
+// late-joining follower (or dashboard) to replay leader activity it
+// missed over its own join window.
+func (db *PostgresDB) QueryLeaderEvents(ctx context.Context, filter models.EventFilter, fromHeight, toHeight int64) ([]models.LeaderEvent, error) {
+	query := `
+		SELECT id, leader, asset, event_type, height, payload, created_at
+		FROM leader_events
+		WHERE height >= $1 AND height <= $2
+			AND ($3 = '' OR leader = $3)
+			AND ($4 = '' OR asset = $4)
+			AND ($5 = '' OR event_type = $5)
+		ORDER BY height ASC, id ASC`
+
+	rows, err := dbretry.Query(ctx, db.retryMaxElapsed, func(ctx context.Context) (pgx.Rows, error) {
+		return db.ReadOnly(ctx).Query(ctx, query, fromHeight, toHeight, filter.Leader, filter.Asset, filter.EventType)
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.LeaderEvent
+	for rows.Next() {
+		var e models.LeaderEvent
+		var payload []byte
+		if err := rows.Scan(&e.ID, &e.Leader, &e.Asset, &e.EventType, &e.Height, &payload, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(payload, &e.Payload); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
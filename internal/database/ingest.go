@@ -0,0 +1,315 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"hyperliquid-copy-trading/internal/database/dbretry"
+	"hyperliquid-copy-trading/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog/log"
+)
+
+// ingestMetrics are the Prometheus series backing BulkCreateTrades and
+// BulkCreateCopyTrades, scoped to their own registry rather than the global
+// default so more than one PostgresDB in the same process doesn't collide
+// on registration.
+type ingestMetrics struct {
+	registry     *prometheus.Registry
+	batchSize    prometheus.Histogram
+	flushLatency prometheus.Histogram
+	rowsPerSec   prometheus.Gauge
+}
+
+func newIngestMetrics() *ingestMetrics {
+	m := &ingestMetrics{
+		registry: prometheus.NewRegistry(),
+		batchSize: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "trade_ingest_batch_size",
+			Help:    "Rows written per BulkCreateTrades/BulkCreateCopyTrades call.",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 10),
+		}),
+		flushLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "trade_ingest_flush_latency_seconds",
+			Help:    "Time taken by a single BulkCreateTrades/BulkCreateCopyTrades call.",
+			Buckets: prometheus.DefBuckets,
+		}),
+		rowsPerSec: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "trade_ingest_rows_per_second",
+			Help: "Rows per second achieved by the most recent bulk insert.",
+		}),
+	}
+	m.registry.MustRegister(m.batchSize, m.flushLatency, m.rowsPerSec)
+	return m
+}
+
+func (m *ingestMetrics) observe(rows int, elapsed time.Duration) {
+	m.batchSize.Observe(float64(rows))
+	m.flushLatency.Observe(elapsed.Seconds())
+	if elapsed > 0 {
+		m.rowsPerSec.Set(float64(rows) / elapsed.Seconds())
+	}
+}
+
+// nextvals draws n values from seq in one round trip, for bulk inserts that
+// need IDs assigned before a CopyFrom, which has no RETURNING clause. seq is
+// always one of this package's own sequence name constants, never
+// caller-supplied, so building the query with fmt.Sprintf is safe.
+func (db *PostgresDB) nextvals(ctx context.Context, seq string, n int) ([]int64, error) {
+	rows, err := dbretry.Query(ctx, db.retryMaxElapsed, func(ctx context.Context) (pgx.Rows, error) {
+		return db.pool.Query(ctx, fmt.Sprintf("SELECT nextval('%s') FROM generate_series(1, $1)", seq), n)
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]int64, 0, n)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// tradeCopySource adapts a []*models.Trade to pgx.CopyFromSource for
+// BulkCreateTrades. Callers must have already assigned ID and CreatedAt.
+type tradeCopySource struct {
+	trades []*models.Trade
+	idx    int
+}
+
+func newTradeCopySource(trades []*models.Trade) *tradeCopySource {
+	return &tradeCopySource{trades: trades, idx: -1}
+}
+
+func (s *tradeCopySource) Next() bool {
+	s.idx++
+	return s.idx < len(s.trades)
+}
+
+func (s *tradeCopySource) Values() ([]interface{}, error) {
+	t := s.trades[s.idx]
+	return []interface{}{
+		t.ID, t.LeaderAddress, t.FollowerID, t.Asset, t.Side, t.Size, t.Price,
+		t.OrderType, t.IsLeaderTrade, t.ExecutedAt, t.HyperliquidTxID, t.Status,
+		t.Fee, t.Funding, t.CreatedAt,
+	}, nil
+}
+
+func (s *tradeCopySource) Err() error { return nil }
+
+// BulkCreateTrades inserts trades in a single pgx.CopyFrom round trip,
+// for the fan-out case where one leader trade produces a trades row per
+// follower and CreateTrade's per-row INSERT round trip would bottleneck.
+// IDs are pre-allocated from trades_id_seq in one SELECT so CopyFrom, which
+// has no RETURNING, can still populate trade.ID/CreatedAt the way
+// CreateTrade does.
+func (db *PostgresDB) BulkCreateTrades(ctx context.Context, trades []*models.Trade) error {
+	if len(trades) == 0 {
+		return nil
+	}
+	defer db.markWrite(ctx)
+
+	start := time.Now()
+	ids, err := db.nextvals(ctx, "trades_id_seq", len(trades))
+	if err != nil {
+		return fmt.Errorf("allocating trade ids: %w", err)
+	}
+
+	now := time.Now()
+	for i, t := range trades {
+		t.ID = int(ids[i])
+		t.CreatedAt = now
+	}
+
+	err = dbretry.Do(ctx, db.retryMaxElapsed, func(ctx context.Context) error {
+		_, err := db.pool.CopyFrom(ctx,
+			pgx.Identifier{"trades"},
+			[]string{"id", "leader_address", "follower_id", "asset", "side", "size", "price",
+				"order_type", "is_leader_trade", "executed_at", "hyperliquid_tx_id", "status",
+				"fee", "funding", "created_at"},
+			newTradeCopySource(trades),
+		)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	db.ingestMetrics.observe(len(trades), time.Since(start))
+	return nil
+}
+
+// copyTradeCopySource adapts a []*models.CopyTrade to pgx.CopyFromSource
+// for BulkCreateCopyTrades. Callers must have already assigned ID and
+// CreatedAt.
+type copyTradeCopySource struct {
+	copyTrades []*models.CopyTrade
+	idx        int
+}
+
+func newCopyTradeCopySource(copyTrades []*models.CopyTrade) *copyTradeCopySource {
+	return &copyTradeCopySource{copyTrades: copyTrades, idx: -1}
+}
+
+func (s *copyTradeCopySource) Next() bool {
+	s.idx++
+	return s.idx < len(s.copyTrades)
+}
+
+func (s *copyTradeCopySource) Values() ([]interface{}, error) {
+	c := s.copyTrades[s.idx]
+	return []interface{}{
+		c.ID, c.OriginalTraderAddress, c.FollowerID, c.OriginalTradeHash, c.Asset, c.Side,
+		c.OriginalSize, c.CopiedSize, c.OriginalPrice, c.ExecutedPrice, c.Slippage,
+		c.DelayMs, c.Status, c.ErrorMessage, c.ExecutedAt, c.CreatedAt,
+	}, nil
+}
+
+func (s *copyTradeCopySource) Err() error { return nil }
+
+// BulkCreateCopyTrades is BulkCreateTrades' counterpart for copy_trades,
+// rejecting the whole batch with ErrBlockedAddress if any entry targets a
+// blocked address, matching CreateCopyTrade's single-row check.
+func (db *PostgresDB) BulkCreateCopyTrades(ctx context.Context, copyTrades []*models.CopyTrade) error {
+	if len(copyTrades) == 0 {
+		return nil
+	}
+	for _, c := range copyTrades {
+		if db.isBlocked(c.OriginalTraderAddress) {
+			return ErrBlockedAddress
+		}
+	}
+	defer db.markWrite(ctx)
+
+	start := time.Now()
+	ids, err := db.nextvals(ctx, "copy_trades_id_seq", len(copyTrades))
+	if err != nil {
+		return fmt.Errorf("allocating copy trade ids: %w", err)
+	}
+
+	now := time.Now()
+	for i, c := range copyTrades {
+		c.ID = int(ids[i])
+		c.CreatedAt = now
+	}
+
+	err = dbretry.Do(ctx, db.retryMaxElapsed, func(ctx context.Context) error {
+		_, err := db.pool.CopyFrom(ctx,
+			pgx.Identifier{"copy_trades"},
+			[]string{"id", "original_trader_address", "follower_id", "original_trade_hash", "asset",
+				"side", "original_size", "copied_size", "original_price", "executed_price", "slippage",
+				"delay_ms", "status", "error_message", "executed_at", "created_at"},
+			newCopyTradeCopySource(copyTrades),
+		)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	db.ingestMetrics.observe(len(copyTrades), time.Since(start))
+	return nil
+}
+
+// TradeIngestor buffers trades submitted via Submit and flushes them with
+// BulkCreateTrades once MaxOrderBatchSize trades have accumulated or
+// OrderBatchInterval has elapsed since the last flush, trading a small
+// amount of added latency for one COPY round trip instead of one INSERT
+// per trade under fan-out load.
+type TradeIngestor struct {
+	db        *PostgresDB
+	batchSize int
+	interval  time.Duration
+
+	queue chan *models.Trade
+	done  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// NewTradeIngestor returns a TradeIngestor that is not yet accepting
+// trades; call Start to begin its flush loop.
+func NewTradeIngestor(db *PostgresDB, batchSize int, interval time.Duration) *TradeIngestor {
+	return &TradeIngestor{
+		db:        db,
+		batchSize: batchSize,
+		interval:  interval,
+		queue:     make(chan *models.Trade, batchSize*4),
+		done:      make(chan struct{}),
+	}
+}
+
+// Start runs the flush loop in the background until Stop is called.
+func (ti *TradeIngestor) Start() {
+	ti.wg.Add(1)
+	go ti.run()
+}
+
+// Stop flushes any trades still buffered and waits for the flush loop to
+// exit.
+func (ti *TradeIngestor) Stop() {
+	close(ti.done)
+	ti.wg.Wait()
+}
+
+// Submit enqueues trade for the next flush. It blocks if the ingestor's
+// internal buffer is full, applying backpressure to the caller instead of
+// dropping trades.
+func (ti *TradeIngestor) Submit(trade *models.Trade) {
+	ti.queue <- trade
+}
+
+func (ti *TradeIngestor) run() {
+	defer ti.wg.Done()
+
+	ticker := time.NewTicker(ti.interval)
+	defer ticker.Stop()
+
+	batch := make([]*models.Trade, 0, ti.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := ti.db.BulkCreateTrades(ctx, batch); err != nil {
+			log.Error().Err(err).Int("batch_size", len(batch)).Msg("Failed to flush trade ingestion batch")
+		}
+		cancel()
+		batch = make([]*models.Trade, 0, ti.batchSize)
+	}
+
+	for {
+		select {
+		case <-ti.done:
+			// select has no case priority, so a done/queue race could
+			// otherwise pick done while trades are still sitting in the
+			// channel buffer, silently dropping them instead of flushing.
+			// Drain whatever Submit already enqueued before the final flush.
+			for drained := false; !drained; {
+				select {
+				case t := <-ti.queue:
+					batch = append(batch, t)
+				default:
+					drained = true
+				}
+			}
+			flush()
+			return
+		case t := <-ti.queue:
+			batch = append(batch, t)
+			if len(batch) >= ti.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
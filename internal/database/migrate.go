@@ -0,0 +1,243 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+//go:embed migrations/*.sql
+var migrationFS embed.FS
+
+// migration is one numbered schema change, loaded from the pair of files
+// internal/database/migrations/NNNN_name.up.sql and .down.sql.
+type migration struct {
+	Version  int
+	Name     string
+	UpSQL    string
+	DownSQL  string
+	Checksum string // sha256 of UpSQL; catches a migration file edited after it was already applied
+}
+
+var migrationFileRe = regexp.MustCompile(`^(\d{4})_([a-zA-Z0-9]+)\.(up|down)\.sql$`)
+
+// loadMigrations reads every embedded migrations/*.sql file and pairs up
+// and down files into a version-ordered list.
+func loadMigrations() ([]migration, error) {
+	entries, err := migrationFS.ReadDir("migrations")
+	if err != nil {
+		return nil, fmt.Errorf("reading embedded migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*migration)
+	for _, entry := range entries {
+		groups := migrationFileRe.FindStringSubmatch(entry.Name())
+		if groups == nil {
+			return nil, fmt.Errorf("migration file %q does not match NNNN_name.(up|down).sql", entry.Name())
+		}
+
+		version, _ := strconv.Atoi(groups[1])
+		name := groups[2]
+		direction := groups[3]
+
+		data, err := migrationFS.ReadFile(path.Join("migrations", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("reading migration %s: %w", entry.Name(), err)
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &migration{Version: version, Name: name}
+			byVersion[version] = mig
+		} else if mig.Name != name {
+			return nil, fmt.Errorf("migration %04d has mismatched names %q and %q", version, mig.Name, name)
+		}
+
+		switch direction {
+		case "up":
+			mig.UpSQL = string(data)
+		case "down":
+			mig.DownSQL = string(data)
+		}
+	}
+
+	migrations := make([]migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.UpSQL == "" {
+			return nil, fmt.Errorf("migration %04d_%s is missing its .up.sql file", mig.Version, mig.Name)
+		}
+		sum := sha256.Sum256([]byte(mig.UpSQL))
+		mig.Checksum = hex.EncodeToString(sum[:])
+		migrations = append(migrations, *mig)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+const createSchemaMigrationsTable = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+	version    INTEGER PRIMARY KEY,
+	name       TEXT NOT NULL,
+	checksum   TEXT NOT NULL,
+	applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+)`
+
+// Migrate applies every embedded migration newer than what's recorded in
+// schema_migrations, each inside its own transaction, and fails loudly if an
+// already-applied migration's up.sql no longer matches the checksum
+// recorded when it ran — the file was edited in place instead of being
+// added as a new migration.
+func Migrate(ctx context.Context, pool *pgxpool.Pool) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	if _, err := pool.Exec(ctx, createSchemaMigrationsTable); err != nil {
+		return fmt.Errorf("creating schema_migrations table: %w", err)
+	}
+
+	applied, err := appliedChecksums(ctx, pool)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		checksum, ok := applied[m.Version]
+		if !ok {
+			if err := applyMigration(ctx, pool, m); err != nil {
+				return err
+			}
+			continue
+		}
+		if checksum != m.Checksum {
+			return fmt.Errorf("migration %04d_%s has been modified since it was applied (checksum mismatch)", m.Version, m.Name)
+		}
+	}
+
+	return nil
+}
+
+func appliedChecksums(ctx context.Context, pool *pgxpool.Pool) (map[int]string, error) {
+	rows, err := pool.Query(ctx, "SELECT version, checksum FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("reading applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]string)
+	for rows.Next() {
+		var version int
+		var checksum string
+		if err := rows.Scan(&version, &checksum); err != nil {
+			return nil, fmt.Errorf("scanning applied migration: %w", err)
+		}
+		applied[version] = checksum
+	}
+	return applied, rows.Err()
+}
+
+func applyMigration(ctx context.Context, pool *pgxpool.Pool, m migration) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction for migration %04d_%s: %w", m.Version, m.Name, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, m.UpSQL); err != nil {
+		return fmt.Errorf("applying migration %04d_%s: %w", m.Version, m.Name, err)
+	}
+
+	if _, err := tx.Exec(ctx,
+		"INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)",
+		m.Version, m.Name, m.Checksum,
+	); err != nil {
+		return fmt.Errorf("recording migration %04d_%s: %w", m.Version, m.Name, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing migration %04d_%s: %w", m.Version, m.Name, err)
+	}
+
+	log.Info().Int("version", m.Version).Str("name", m.Name).Msg("Applied database migration")
+	return nil
+}
+
+// Rollback reverts the n most recently applied migrations, most recent
+// first, each inside its own transaction.
+func Rollback(ctx context.Context, pool *pgxpool.Pool, n int) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+	byVersion := make(map[int]migration, len(migrations))
+	for _, m := range migrations {
+		byVersion[m.Version] = m
+	}
+
+	rows, err := pool.Query(ctx, "SELECT version FROM schema_migrations ORDER BY version DESC LIMIT $1", n)
+	if err != nil {
+		return fmt.Errorf("reading applied migrations: %w", err)
+	}
+	var versions []int
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning applied migration: %w", err)
+		}
+		versions = append(versions, version)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, version := range versions {
+		m, ok := byVersion[version]
+		if !ok {
+			return fmt.Errorf("migration version %d is recorded as applied but no longer exists on disk", version)
+		}
+		if m.DownSQL == "" {
+			return fmt.Errorf("migration %04d_%s has no down.sql, cannot roll back", m.Version, m.Name)
+		}
+		if err := rollbackMigration(ctx, pool, m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func rollbackMigration(ctx context.Context, pool *pgxpool.Pool, m migration) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction for rollback of migration %04d_%s: %w", m.Version, m.Name, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, m.DownSQL); err != nil {
+		return fmt.Errorf("rolling back migration %04d_%s: %w", m.Version, m.Name, err)
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM schema_migrations WHERE version = $1", m.Version); err != nil {
+		return fmt.Errorf("un-recording migration %04d_%s: %w", m.Version, m.Name, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing rollback of migration %04d_%s: %w", m.Version, m.Name, err)
+	}
+
+	log.Info().Int("version", m.Version).Str("name", m.Name).Msg("Rolled back database migration")
+	return nil
+}
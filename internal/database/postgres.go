@@ -2,18 +2,42 @@ package database
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"hyperliquid-copy-trading/internal/blacklist"
+	"hyperliquid-copy-trading/internal/database/dbretry"
 	"hyperliquid-copy-trading/internal/models"
+	"hyperliquid-copy-trading/internal/pnl"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog/log"
 )
 
+// ErrBlockedAddress is returned by CreateCopyTrade, CreatePermissionlessFollower,
+// and CreateTraderDiscovery when the address they target is on the
+// blacklist.Store the PostgresDB was built with.
+var ErrBlockedAddress = errors.New("database: address is blocked")
+
 type PostgresDB struct {
-	pool *pgxpool.Pool
+	pool              *pgxpool.Pool
+	replica           *pgxpool.Pool // nil if no DatabaseReplicaURLs were configured
+	replicaLagWindow  time.Duration
+	retryMaxElapsed   time.Duration
+	lotMatchingMethod pnl.Method
+	blacklist         *blacklist.Store
+	ingestMetrics     *ingestMetrics
 }
 
-func NewPostgresDB(databaseURL string) (*PostgresDB, error) {
+// NewPostgresDB connects to the primary at databaseURL and, if
+// replicaURLs is non-empty, to its first entry as a read replica; only one
+// replica connection is made even if more are listed, since pgxpool itself
+// has no notion of a multi-host read pool. replicaLagWindow is how long
+// ReadOnly keeps steering a request's reads back to the primary after one
+// of its writes, see WithReadStickiness.
+func NewPostgresDB(databaseURL string, replicaURLs []string, retryMaxElapsed time.Duration, lotMatchingMethod string, replicaLagWindow time.Duration) (*PostgresDB, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -29,44 +53,132 @@ func NewPostgresDB(databaseURL string) (*PostgresDB, error) {
 
 	log.Info().Msg("Connected to PostgreSQL database")
 
-	return &PostgresDB{pool: pool}, nil
+	if err := Migrate(ctx, pool); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("running database migrations: %w", err)
+	}
+
+	var replica *pgxpool.Pool
+	if len(replicaURLs) > 0 {
+		replica, err = pgxpool.New(ctx, replicaURLs[0])
+		if err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("connecting to read replica: %w", err)
+		}
+		if err := replica.Ping(ctx); err != nil {
+			pool.Close()
+			replica.Close()
+			return nil, fmt.Errorf("pinging read replica: %w", err)
+		}
+		log.Info().Msg("Connected to PostgreSQL read replica")
+	}
+
+	return &PostgresDB{
+		pool:              pool,
+		replica:           replica,
+		replicaLagWindow:  replicaLagWindow,
+		retryMaxElapsed:   retryMaxElapsed,
+		lotMatchingMethod: pnl.Method(lotMatchingMethod),
+		ingestMetrics:     newIngestMetrics(),
+	}, nil
 }
 
 func (db *PostgresDB) Close() {
 	db.pool.Close()
+	if db.replica != nil {
+		db.replica.Close()
+	}
+}
+
+// SetBlacklist attaches the address blacklist store CreateCopyTrade,
+// CreatePermissionlessFollower, and CreateTraderDiscovery consult before
+// writing. Called once from main after the store's first Refresh completes;
+// a PostgresDB with no store attached performs no blacklist checks.
+func (db *PostgresDB) SetBlacklist(store *blacklist.Store) {
+	db.blacklist = store
+}
+
+func (db *PostgresDB) isBlocked(address string) bool {
+	return db.blacklist != nil && db.blacklist.IsBlocked(address)
+}
+
+// ErrBlacklistUnavailable is returned by the blocked-address admin methods
+// when no blacklist.Store has been attached via SetBlacklist.
+var ErrBlacklistUnavailable = errors.New("database: blacklist store not configured")
+
+// AddBlockedAddress blocks address immediately, persisting it as a manual
+// entry so it survives the next blacklist.Store refresh.
+func (db *PostgresDB) AddBlockedAddress(ctx context.Context, address, reason string) error {
+	if db.blacklist == nil {
+		return ErrBlacklistUnavailable
+	}
+	return db.blacklist.Add(ctx, address, reason)
+}
+
+// RemoveBlockedAddress unblocks address immediately.
+func (db *PostgresDB) RemoveBlockedAddress(ctx context.Context, address string) error {
+	if db.blacklist == nil {
+		return ErrBlacklistUnavailable
+	}
+	return db.blacklist.Remove(ctx, address)
+}
+
+// ListBlockedAddresses returns every persisted blocked address.
+func (db *PostgresDB) ListBlockedAddresses(ctx context.Context) ([]models.BlockedAddress, error) {
+	if db.blacklist == nil {
+		return nil, ErrBlacklistUnavailable
+	}
+	return db.blacklist.List(ctx)
+}
+
+// Pool exposes the underlying connection pool for the migration CLI flags
+// in main.go (--migrate-only, --rollback), which need to run Migrate/
+// Rollback without going through a query method on PostgresDB itself.
+func (db *PostgresDB) Pool() *pgxpool.Pool {
+	return db.pool
+}
+
+// IngestMetricsRegistry returns the Prometheus registry backing
+// BulkCreateTrades/BulkCreateCopyTrades' batch-size, flush-latency, and
+// rows/sec series, for a caller to expose on a metrics endpoint.
+func (db *PostgresDB) IngestMetricsRegistry() *prometheus.Registry {
+	return db.ingestMetrics.registry
 }
 
 func (db *PostgresDB) CreateFollower(ctx context.Context, follower *models.Follower) error {
+	defer db.markWrite(ctx)
 	query := `
-		INSERT INTO followers (user_id, leader_address, api_wallet_address, copy_percentage, 
+		INSERT INTO followers (user_id, leader_address, api_wallet_address, copy_percentage,
 			max_position_size, stop_loss_percentage, take_profit_percentage, is_active, risk_settings)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id, created_at, updated_at`
 
-	err := db.pool.QueryRow(ctx, query,
-		follower.UserID,
-		follower.LeaderAddress,
-		follower.APIWalletAddress,
-		follower.CopyPercentage,
-		follower.MaxPositionSize,
-		follower.StopLossPercentage,
-		follower.TakeProfitPercentage,
-		follower.IsActive,
-		follower.RiskSettings,
-	).Scan(&follower.ID, &follower.CreatedAt, &follower.UpdatedAt)
-
-	return err
+	return dbretry.Do(ctx, db.retryMaxElapsed, func(ctx context.Context) error {
+		return db.pool.QueryRow(ctx, query,
+			follower.UserID,
+			follower.LeaderAddress,
+			follower.APIWalletAddress,
+			follower.CopyPercentage,
+			follower.MaxPositionSize,
+			follower.StopLossPercentage,
+			follower.TakeProfitPercentage,
+			follower.IsActive,
+			follower.RiskSettings,
+		).Scan(&follower.ID, &follower.CreatedAt, &follower.UpdatedAt)
+	})
 }
 
 func (db *PostgresDB) GetFollowers(ctx context.Context) ([]models.Follower, error) {
 	query := `
 		SELECT id, user_id, leader_address, api_wallet_address, copy_percentage,
-			max_position_size, stop_loss_percentage, take_profit_percentage, 
+			max_position_size, stop_loss_percentage, take_profit_percentage,
 			is_active, risk_settings, created_at, updated_at
 		FROM followers
 		ORDER BY created_at DESC`
 
-	rows, err := db.pool.Query(ctx, query)
+	rows, err := dbretry.Query(ctx, db.retryMaxElapsed, func(ctx context.Context) (pgx.Rows, error) {
+		return db.ReadOnly(ctx).Query(ctx, query)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -101,12 +213,14 @@ func (db *PostgresDB) GetFollowers(ctx context.Context) ([]models.Follower, erro
 func (db *PostgresDB) GetFollowersByLeader(ctx context.Context, leaderAddress string) ([]models.Follower, error) {
 	query := `
 		SELECT id, user_id, leader_address, api_wallet_address, copy_percentage,
-			max_position_size, stop_loss_percentage, take_profit_percentage, 
+			max_position_size, stop_loss_percentage, take_profit_percentage,
 			is_active, risk_settings, created_at, updated_at
 		FROM followers
 		WHERE leader_address = $1 AND is_active = true`
 
-	rows, err := db.pool.Query(ctx, query, leaderAddress)
+	rows, err := dbretry.Query(ctx, db.retryMaxElapsed, func(ctx context.Context) (pgx.Rows, error) {
+		return db.pool.Query(ctx, query, leaderAddress)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -139,64 +253,74 @@ func (db *PostgresDB) GetFollowersByLeader(ctx context.Context, leaderAddress st
 }
 
 func (db *PostgresDB) UpdateFollower(ctx context.Context, follower *models.Follower) error {
+	defer db.markWrite(ctx)
 	query := `
-		UPDATE followers 
+		UPDATE followers
 		SET copy_percentage = $1, max_position_size = $2, stop_loss_percentage = $3,
 			take_profit_percentage = $4, is_active = $5, risk_settings = $6, updated_at = NOW()
 		WHERE id = $7`
 
-	_, err := db.pool.Exec(ctx, query,
-		follower.CopyPercentage,
-		follower.MaxPositionSize,
-		follower.StopLossPercentage,
-		follower.TakeProfitPercentage,
-		follower.IsActive,
-		follower.RiskSettings,
-		follower.ID,
-	)
-
-	return err
+	return dbretry.Do(ctx, db.retryMaxElapsed, func(ctx context.Context) error {
+		_, err := db.pool.Exec(ctx, query,
+			follower.CopyPercentage,
+			follower.MaxPositionSize,
+			follower.StopLossPercentage,
+			follower.TakeProfitPercentage,
+			follower.IsActive,
+			follower.RiskSettings,
+			follower.ID,
+		)
+		return err
+	})
 }
 
 func (db *PostgresDB) DeleteFollower(ctx context.Context, id int) error {
+	defer db.markWrite(ctx)
 	query := `DELETE FROM followers WHERE id = $1`
-	_, err := db.pool.Exec(ctx, query, id)
-	return err
+	return dbretry.Do(ctx, db.retryMaxElapsed, func(ctx context.Context) error {
+		_, err := db.pool.Exec(ctx, query, id)
+		return err
+	})
 }
 
 func (db *PostgresDB) CreateTrade(ctx context.Context, trade *models.Trade) error {
+	defer db.markWrite(ctx)
 	query := `
-		INSERT INTO trades (leader_address, follower_id, asset, side, size, price, 
-			order_type, is_leader_trade, executed_at, hyperliquid_tx_id, status)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		INSERT INTO trades (leader_address, follower_id, asset, side, size, price,
+			order_type, is_leader_trade, executed_at, hyperliquid_tx_id, status, fee, funding)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 		RETURNING id, created_at`
 
-	err := db.pool.QueryRow(ctx, query,
-		trade.LeaderAddress,
-		trade.FollowerID,
-		trade.Asset,
-		trade.Side,
-		trade.Size,
-		trade.Price,
-		trade.OrderType,
-		trade.IsLeaderTrade,
-		trade.ExecutedAt,
-		trade.HyperliquidTxID,
-		trade.Status,
-	).Scan(&trade.ID, &trade.CreatedAt)
-
-	return err
+	return dbretry.Do(ctx, db.retryMaxElapsed, func(ctx context.Context) error {
+		return db.pool.QueryRow(ctx, query,
+			trade.LeaderAddress,
+			trade.FollowerID,
+			trade.Asset,
+			trade.Side,
+			trade.Size,
+			trade.Price,
+			trade.OrderType,
+			trade.IsLeaderTrade,
+			trade.ExecutedAt,
+			trade.HyperliquidTxID,
+			trade.Status,
+			trade.Fee,
+			trade.Funding,
+		).Scan(&trade.ID, &trade.CreatedAt)
+	})
 }
 
 func (db *PostgresDB) GetTrades(ctx context.Context, limit, offset int) ([]models.Trade, error) {
 	query := `
-		SELECT id, leader_address, follower_id, asset, side, size, price, 
+		SELECT id, leader_address, follower_id, asset, side, size, price,
 			order_type, is_leader_trade, executed_at, hyperliquid_tx_id, status, created_at
 		FROM trades
 		ORDER BY executed_at DESC
 		LIMIT $1 OFFSET $2`
 
-	rows, err := db.pool.Query(ctx, query, limit, offset)
+	rows, err := dbretry.Query(ctx, db.retryMaxElapsed, func(ctx context.Context) (pgx.Rows, error) {
+		return db.ReadOnly(ctx).Query(ctx, query, limit, offset)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -231,13 +355,15 @@ func (db *PostgresDB) GetTrades(ctx context.Context, limit, offset int) ([]model
 
 func (db *PostgresDB) GetTradesByFollower(ctx context.Context, followerID int) ([]models.Trade, error) {
 	query := `
-		SELECT id, leader_address, follower_id, asset, side, size, price, 
+		SELECT id, leader_address, follower_id, asset, side, size, price,
 			order_type, is_leader_trade, executed_at, hyperliquid_tx_id, status, created_at
 		FROM trades
 		WHERE follower_id = $1
 		ORDER BY executed_at DESC`
 
-	rows, err := db.pool.Query(ctx, query, followerID)
+	rows, err := dbretry.Query(ctx, db.retryMaxElapsed, func(ctx context.Context) (pgx.Rows, error) {
+		return db.ReadOnly(ctx).Query(ctx, query, followerID)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -270,9 +396,97 @@ func (db *PostgresDB) GetTradesByFollower(ctx context.Context, followerID int) (
 	return trades, nil
 }
 
+// CreateOrderState inserts a new order lifecycle record, usually at the
+// moment OrderEngine queues or submits a batch, before the exchange has
+// assigned an order id.
+func (db *PostgresDB) CreateOrderState(ctx context.Context, state *models.OrderState) error {
+	defer db.markWrite(ctx)
+	query := `
+		INSERT INTO order_states (follower_id, api_wallet_address, venue, asset, nonce, oid, status, error_message)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at, updated_at`
+
+	return dbretry.Do(ctx, db.retryMaxElapsed, func(ctx context.Context) error {
+		return db.pool.QueryRow(ctx, query,
+			state.FollowerID,
+			state.APIWalletAddress,
+			state.Venue,
+			state.Asset,
+			state.Nonce,
+			state.OID,
+			state.Status,
+			state.ErrorMessage,
+		).Scan(&state.ID, &state.CreatedAt, &state.UpdatedAt)
+	})
+}
+
+// UpdateOrderStateStatus advances an existing order state record to status,
+// recording oid once the exchange assigns one and errorMsg for a failed or
+// rejected transition.
+func (db *PostgresDB) UpdateOrderStateStatus(ctx context.Context, id int64, status models.OrderStatus, oid *int64, errorMsg string) error {
+	defer db.markWrite(ctx)
+	query := `
+		UPDATE order_states
+		SET status = $1, oid = COALESCE($2, oid), error_message = $3, updated_at = NOW()
+		WHERE id = $4`
+
+	return dbretry.Do(ctx, db.retryMaxElapsed, func(ctx context.Context) error {
+		_, err := db.pool.Exec(ctx, query, status, oid, errorMsg, id)
+		return err
+	})
+}
+
+// GetOpenOrderStates returns every order state not yet in a terminal status,
+// so OrderEngine can resume monitoring them after a crash or restart.
+func (db *PostgresDB) GetOpenOrderStates(ctx context.Context) ([]models.OrderState, error) {
+	query := `
+		SELECT id, follower_id, api_wallet_address, venue, asset, nonce, oid, status, error_message, created_at, updated_at
+		FROM order_states
+		WHERE status NOT IN ($1, $2, $3, $4)
+		ORDER BY created_at`
+
+	rows, err := dbretry.Query(ctx, db.retryMaxElapsed, func(ctx context.Context) (pgx.Rows, error) {
+		return db.pool.Query(ctx, query,
+			models.OrderStatusFilled,
+			models.OrderStatusCancelled,
+			models.OrderStatusRejected,
+			models.OrderStatusFailed,
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var states []models.OrderState
+	for rows.Next() {
+		var s models.OrderState
+		err := rows.Scan(
+			&s.ID,
+			&s.FollowerID,
+			&s.APIWalletAddress,
+			&s.Venue,
+			&s.Asset,
+			&s.Nonce,
+			&s.OID,
+			&s.Status,
+			&s.ErrorMessage,
+			&s.CreatedAt,
+			&s.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, s)
+	}
+
+	return states, nil
+}
+
 func (db *PostgresDB) UpsertPosition(ctx context.Context, position *models.Position) error {
+	defer db.markWrite(ctx)
 	query := `
-		INSERT INTO positions (user_address, asset, side, size, entry_price, current_price, 
+		INSERT INTO positions (user_address, asset, side, size, entry_price, current_price,
 			unrealized_pnl, margin_used, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
 		ON CONFLICT (user_address, asset)
@@ -286,18 +500,18 @@ func (db *PostgresDB) UpsertPosition(ctx context.Context, position *models.Posit
 			updated_at = NOW()
 		RETURNING id`
 
-	err := db.pool.QueryRow(ctx, query,
-		position.UserAddress,
-		position.Asset,
-		position.Side,
-		position.Size,
-		position.EntryPrice,
-		position.CurrentPrice,
-		position.UnrealizedPnL,
-		position.MarginUsed,
-	).Scan(&position.ID)
-
-	return err
+	return dbretry.Do(ctx, db.retryMaxElapsed, func(ctx context.Context) error {
+		return db.pool.QueryRow(ctx, query,
+			position.UserAddress,
+			position.Asset,
+			position.Side,
+			position.Size,
+			position.EntryPrice,
+			position.CurrentPrice,
+			position.UnrealizedPnL,
+			position.MarginUsed,
+		).Scan(&position.ID)
+	})
 }
 
 func (db *PostgresDB) GetPositions(ctx context.Context, userAddress string) ([]models.Position, error) {
@@ -307,7 +521,9 @@ func (db *PostgresDB) GetPositions(ctx context.Context, userAddress string) ([]m
 		FROM positions
 		WHERE user_address = $1`
 
-	rows, err := db.pool.Query(ctx, query, userAddress)
+	rows, err := dbretry.Query(ctx, db.retryMaxElapsed, func(ctx context.Context) (pgx.Rows, error) {
+		return db.ReadOnly(ctx).Query(ctx, query, userAddress)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -340,28 +556,33 @@ func (db *PostgresDB) GetPositions(ctx context.Context, userAddress string) ([]m
 // === PERMISSIONLESS COPY TRADING DATABASE METHODS ===
 
 func (db *PostgresDB) CreatePermissionlessFollower(ctx context.Context, follower *models.PermissionlessFollower) error {
+	if db.isBlocked(follower.TargetTraderAddress) {
+		return ErrBlockedAddress
+	}
+	defer db.markWrite(ctx)
+
 	query := `
-		INSERT INTO permissionless_followers (user_id, target_trader_address, api_wallet_address, 
+		INSERT INTO permissionless_followers (user_id, target_trader_address, api_wallet_address,
 			copy_percentage, max_position_size, min_trade_size, asset_whitelist, asset_blacklist,
 			auto_discovery_enabled, copy_filters, is_active)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		RETURNING id, created_at, updated_at`
 
-	err := db.pool.QueryRow(ctx, query,
-		follower.UserID,
-		follower.TargetTraderAddress,
-		follower.APIWalletAddress,
-		follower.CopyPercentage,
-		follower.MaxPositionSize,
-		follower.MinTradeSize,
-		follower.AssetWhitelist,
-		follower.AssetBlacklist,
-		follower.AutoDiscoveryEnabled,
-		follower.CopyFilters,
-		follower.IsActive,
-	).Scan(&follower.ID, &follower.CreatedAt, &follower.UpdatedAt)
-
-	return err
+	return dbretry.Do(ctx, db.retryMaxElapsed, func(ctx context.Context) error {
+		return db.pool.QueryRow(ctx, query,
+			follower.UserID,
+			follower.TargetTraderAddress,
+			follower.APIWalletAddress,
+			follower.CopyPercentage,
+			follower.MaxPositionSize,
+			follower.MinTradeSize,
+			follower.AssetWhitelist,
+			follower.AssetBlacklist,
+			follower.AutoDiscoveryEnabled,
+			follower.CopyFilters,
+			follower.IsActive,
+		).Scan(&follower.ID, &follower.CreatedAt, &follower.UpdatedAt)
+	})
 }
 
 func (db *PostgresDB) GetPermissionlessFollowersByTrader(ctx context.Context, traderAddress string) ([]*models.PermissionlessFollower, error) {
@@ -372,7 +593,9 @@ func (db *PostgresDB) GetPermissionlessFollowersByTrader(ctx context.Context, tr
 		FROM permissionless_followers
 		WHERE target_trader_address = $1 AND is_active = true`
 
-	rows, err := db.pool.Query(ctx, query, traderAddress)
+	rows, err := dbretry.Query(ctx, db.retryMaxElapsed, func(ctx context.Context) (pgx.Rows, error) {
+		return db.pool.Query(ctx, query, traderAddress)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -406,7 +629,167 @@ func (db *PostgresDB) GetPermissionlessFollowersByTrader(ctx context.Context, tr
 	return followers, nil
 }
 
+// GetPermissionlessFollowerByID loads a single permissionless follower by
+// id, for ProfitFixer.FixFollowerProfit to resolve a followerID into the
+// wallet/trader address pair it needs to replay fills for.
+func (db *PostgresDB) GetPermissionlessFollowerByID(ctx context.Context, followerID int) (*models.PermissionlessFollower, error) {
+	query := `
+		SELECT id, user_id, target_trader_address, api_wallet_address, copy_percentage,
+			max_position_size, min_trade_size, asset_whitelist, asset_blacklist,
+			auto_discovery_enabled, copy_filters, is_active, created_at, updated_at
+		FROM permissionless_followers
+		WHERE id = $1`
+
+	var f models.PermissionlessFollower
+	err := dbretry.Do(ctx, db.retryMaxElapsed, func(ctx context.Context) error {
+		return db.pool.QueryRow(ctx, query, followerID).Scan(
+			&f.ID,
+			&f.UserID,
+			&f.TargetTraderAddress,
+			&f.APIWalletAddress,
+			&f.CopyPercentage,
+			&f.MaxPositionSize,
+			&f.MinTradeSize,
+			&f.AssetWhitelist,
+			&f.AssetBlacklist,
+			&f.AutoDiscoveryEnabled,
+			&f.CopyFilters,
+			&f.IsActive,
+			&f.CreatedAt,
+			&f.UpdatedAt,
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// GetAllPermissionlessFollowers loads every permissionless follower, active
+// or not, for the startup gap-scan hook to walk.
+func (db *PostgresDB) GetAllPermissionlessFollowers(ctx context.Context) ([]*models.PermissionlessFollower, error) {
+	query := `
+		SELECT id, user_id, target_trader_address, api_wallet_address, copy_percentage,
+			max_position_size, min_trade_size, asset_whitelist, asset_blacklist,
+			auto_discovery_enabled, copy_filters, is_active, created_at, updated_at
+		FROM permissionless_followers`
+
+	rows, err := dbretry.Query(ctx, db.retryMaxElapsed, func(ctx context.Context) (pgx.Rows, error) {
+		return db.pool.Query(ctx, query)
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var followers []*models.PermissionlessFollower
+	for rows.Next() {
+		var f models.PermissionlessFollower
+		err := rows.Scan(
+			&f.ID,
+			&f.UserID,
+			&f.TargetTraderAddress,
+			&f.APIWalletAddress,
+			&f.CopyPercentage,
+			&f.MaxPositionSize,
+			&f.MinTradeSize,
+			&f.AssetWhitelist,
+			&f.AssetBlacklist,
+			&f.AutoDiscoveryEnabled,
+			&f.CopyFilters,
+			&f.IsActive,
+			&f.CreatedAt,
+			&f.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		followers = append(followers, &f)
+	}
+
+	return followers, nil
+}
+
+// UpdatePermissionlessFollower persists follower's mutable fields -- the
+// same set a config-driven reload is allowed to change on an existing
+// follower without dropping its WebSocket subscription. TargetTraderAddress
+// and APIWalletAddress are immutable; changing either means removing the
+// follower and adding a new one instead.
+func (db *PostgresDB) UpdatePermissionlessFollower(ctx context.Context, follower *models.PermissionlessFollower) error {
+	defer db.markWrite(ctx)
+	query := `
+		UPDATE permissionless_followers
+		SET copy_percentage = $1, max_position_size = $2, min_trade_size = $3,
+			asset_whitelist = $4, asset_blacklist = $5, auto_discovery_enabled = $6,
+			copy_filters = $7, is_active = $8, updated_at = NOW()
+		WHERE id = $9`
+
+	return dbretry.Do(ctx, db.retryMaxElapsed, func(ctx context.Context) error {
+		_, err := db.pool.Exec(ctx, query,
+			follower.CopyPercentage,
+			follower.MaxPositionSize,
+			follower.MinTradeSize,
+			follower.AssetWhitelist,
+			follower.AssetBlacklist,
+			follower.AutoDiscoveryEnabled,
+			follower.CopyFilters,
+			follower.IsActive,
+			follower.ID,
+		)
+		return err
+	})
+}
+
+// DeletePermissionlessFollower removes followerID outright, mirroring
+// DeleteFollower's hard delete for the consent-based flow.
+func (db *PostgresDB) DeletePermissionlessFollower(ctx context.Context, followerID int) error {
+	defer db.markWrite(ctx)
+	query := `DELETE FROM permissionless_followers WHERE id = $1`
+	return dbretry.Do(ctx, db.retryMaxElapsed, func(ctx context.Context) error {
+		_, err := db.pool.Exec(ctx, query, followerID)
+		return err
+	})
+}
+
+// UpsertPermissionlessFollowerPnL replaces followerID's reconstructed PnL
+// row with analytics/assetBreakdown, stamped with the [since, until) window
+// ProfitFixer replayed to produce them.
+func (db *PostgresDB) UpsertPermissionlessFollowerPnL(ctx context.Context, followerID int, analytics *models.PnLAnalytics, assetBreakdown map[string]float64, since, until time.Time) error {
+	query := `
+		INSERT INTO permissionless_follower_pnl (follower_id, total_pnl, win_rate, total_trades,
+			profitable_trades, asset_breakdown, window_since, window_until)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (follower_id) DO UPDATE SET
+			total_pnl = EXCLUDED.total_pnl,
+			win_rate = EXCLUDED.win_rate,
+			total_trades = EXCLUDED.total_trades,
+			profitable_trades = EXCLUDED.profitable_trades,
+			asset_breakdown = EXCLUDED.asset_breakdown,
+			window_since = EXCLUDED.window_since,
+			window_until = EXCLUDED.window_until,
+			updated_at = NOW()`
+
+	return dbretry.Do(ctx, db.retryMaxElapsed, func(ctx context.Context) error {
+		_, err := db.pool.Exec(ctx, query,
+			followerID,
+			analytics.TotalPnL,
+			analytics.WinRate,
+			analytics.TotalTrades,
+			analytics.ProfitableTrades,
+			assetBreakdown,
+			since,
+			until,
+		)
+		return err
+	})
+}
+
 func (db *PostgresDB) CreateCopyTrade(ctx context.Context, copyTrade *models.CopyTrade) error {
+	if db.isBlocked(copyTrade.OriginalTraderAddress) {
+		return ErrBlockedAddress
+	}
+	defer db.markWrite(ctx)
+
 	query := `
 		INSERT INTO copy_trades (original_trader_address, follower_id, original_trade_hash,
 			asset, side, original_size, copied_size, original_price, executed_price,
@@ -414,24 +797,24 @@ func (db *PostgresDB) CreateCopyTrade(ctx context.Context, copyTrade *models.Cop
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 		RETURNING id, created_at`
 
-	err := db.pool.QueryRow(ctx, query,
-		copyTrade.OriginalTraderAddress,
-		copyTrade.FollowerID,
-		copyTrade.OriginalTradeHash,
-		copyTrade.Asset,
-		copyTrade.Side,
-		copyTrade.OriginalSize,
-		copyTrade.CopiedSize,
-		copyTrade.OriginalPrice,
-		copyTrade.ExecutedPrice,
-		copyTrade.Slippage,
-		copyTrade.DelayMs,
-		copyTrade.Status,
-		copyTrade.ErrorMessage,
-		copyTrade.ExecutedAt,
-	).Scan(&copyTrade.ID, &copyTrade.CreatedAt)
-
-	return err
+	return dbretry.Do(ctx, db.retryMaxElapsed, func(ctx context.Context) error {
+		return db.pool.QueryRow(ctx, query,
+			copyTrade.OriginalTraderAddress,
+			copyTrade.FollowerID,
+			copyTrade.OriginalTradeHash,
+			copyTrade.Asset,
+			copyTrade.Side,
+			copyTrade.OriginalSize,
+			copyTrade.CopiedSize,
+			copyTrade.OriginalPrice,
+			copyTrade.ExecutedPrice,
+			copyTrade.Slippage,
+			copyTrade.DelayMs,
+			copyTrade.Status,
+			copyTrade.ErrorMessage,
+			copyTrade.ExecutedAt,
+		).Scan(&copyTrade.ID, &copyTrade.CreatedAt)
+	})
 }
 
 func (db *PostgresDB) GetCopyTradesByFollower(ctx context.Context, followerID int) ([]*models.CopyTrade, error) {
@@ -443,7 +826,9 @@ func (db *PostgresDB) GetCopyTradesByFollower(ctx context.Context, followerID in
 		WHERE follower_id = $1
 		ORDER BY executed_at DESC`
 
-	rows, err := db.pool.Query(ctx, query, followerID)
+	rows, err := dbretry.Query(ctx, db.retryMaxElapsed, func(ctx context.Context) (pgx.Rows, error) {
+		return db.pool.Query(ctx, query, followerID)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -480,6 +865,11 @@ func (db *PostgresDB) GetCopyTradesByFollower(ctx context.Context, followerID in
 }
 
 func (db *PostgresDB) CreateTraderDiscovery(ctx context.Context, discovery *models.TraderDiscovery) error {
+	if db.isBlocked(discovery.Address) {
+		return ErrBlockedAddress
+	}
+	defer db.markWrite(ctx)
+
 	query := `
 		INSERT INTO trader_discovery (address, first_discovered, total_volume, trade_count,
 			win_rate, profit_loss, max_drawdown, sharpe_ratio, last_activity, is_active,
@@ -502,25 +892,25 @@ func (db *PostgresDB) CreateTraderDiscovery(ctx context.Context, discovery *mode
 			updated_at = NOW()
 		RETURNING id, updated_at`
 
-	err := db.pool.QueryRow(ctx, query,
-		discovery.Address,
-		discovery.FirstDiscovered,
-		discovery.TotalVolume,
-		discovery.TradeCount,
-		discovery.WinRate,
-		discovery.ProfitLoss,
-		discovery.MaxDrawdown,
-		discovery.SharpeRatio,
-		discovery.LastActivity,
-		discovery.IsActive,
-		discovery.FollowerCount,
-		discovery.AssetBreakdown,
-		discovery.PerformanceGrade,
-		discovery.RiskLevel,
-		discovery.TradingStyle,
-	).Scan(&discovery.ID, &discovery.UpdatedAt)
-
-	return err
+	return dbretry.Do(ctx, db.retryMaxElapsed, func(ctx context.Context) error {
+		return db.pool.QueryRow(ctx, query,
+			discovery.Address,
+			discovery.FirstDiscovered,
+			discovery.TotalVolume,
+			discovery.TradeCount,
+			discovery.WinRate,
+			discovery.ProfitLoss,
+			discovery.MaxDrawdown,
+			discovery.SharpeRatio,
+			discovery.LastActivity,
+			discovery.IsActive,
+			discovery.FollowerCount,
+			discovery.AssetBreakdown,
+			discovery.PerformanceGrade,
+			discovery.RiskLevel,
+			discovery.TradingStyle,
+		).Scan(&discovery.ID, &discovery.UpdatedAt)
+	})
 }
 
 func (db *PostgresDB) GetTopTraders(ctx context.Context, limit int) ([]*models.TraderDiscovery, error) {
@@ -533,7 +923,9 @@ func (db *PostgresDB) GetTopTraders(ctx context.Context, limit int) ([]*models.T
 		ORDER BY sharpe_ratio DESC, total_volume DESC
 		LIMIT $1`
 
-	rows, err := db.pool.Query(ctx, query, limit)
+	rows, err := dbretry.Query(ctx, db.retryMaxElapsed, func(ctx context.Context) (pgx.Rows, error) {
+		return db.ReadOnly(ctx).Query(ctx, query, limit)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -2,33 +2,30 @@ package database
 
 import (
 	"context"
+	"hyperliquid-copy-trading/internal/database/dbretry"
 	"hyperliquid-copy-trading/internal/models"
 	"time"
+
+	"github.com/jackc/pgx/v5"
 )
 
-// GetLeaderPerformance calculates performance metrics for a leader
+// GetLeaderPerformance calculates performance metrics for a leader from the
+// realized_pnl table (see pnl.Matcher), not from a side-based cashflow
+// heuristic that conflates cashflow with realized PnL.
 func (db *PostgresDB) GetLeaderPerformance(ctx context.Context, leaderAddress string, days int) (*models.PnLAnalytics, error) {
 	query := `
 		WITH trade_pnl AS (
-			SELECT 
-				t.id,
-				t.side,
-				t.size,
-				t.price,
-				t.executed_at,
-				CASE 
-					WHEN t.side = 'sell' THEN t.size * t.price
-					ELSE -t.size * t.price
-				END as pnl_contribution
-			FROM trades t
-			WHERE t.leader_address = $1 
-				AND t.is_leader_trade = true
+			SELECT
+				r.realized_pnl - r.fees as pnl_contribution,
+				t.executed_at
+			FROM realized_pnl r
+			JOIN trades t ON t.id = r.trade_id
+			WHERE r.leader_address = $1
+				AND r.follower_id IS NULL
 				AND t.executed_at >= NOW() - INTERVAL '%d days'
-				AND t.status = 'filled'
-			ORDER BY t.executed_at
 		),
 		daily_pnl AS (
-			SELECT 
+			SELECT
 				DATE(executed_at) as trade_date,
 				SUM(pnl_contribution) as daily_pnl
 			FROM trade_pnl
@@ -36,7 +33,7 @@ func (db *PostgresDB) GetLeaderPerformance(ctx context.Context, leaderAddress st
 			ORDER BY trade_date
 		),
 		performance_metrics AS (
-			SELECT 
+			SELECT
 				COUNT(*) as total_trades,
 				SUM(CASE WHEN pnl_contribution > 0 THEN 1 ELSE 0 END) as profitable_trades,
 				SUM(pnl_contribution) as total_pnl,
@@ -44,15 +41,15 @@ func (db *PostgresDB) GetLeaderPerformance(ctx context.Context, leaderAddress st
 				STDDEV(pnl_contribution) as pnl_stddev
 			FROM trade_pnl
 		)
-		SELECT 
+		SELECT
 			pm.total_trades,
 			pm.profitable_trades,
 			pm.total_pnl,
-			CASE 
+			CASE
 				WHEN pm.total_trades > 0 THEN pm.profitable_trades::float / pm.total_trades::float
 				ELSE 0
 			END as win_rate,
-			CASE 
+			CASE
 				WHEN pm.pnl_stddev > 0 AND pm.pnl_stddev IS NOT NULL THEN pm.avg_pnl / pm.pnl_stddev
 				ELSE 0
 			END as sharpe_ratio,
@@ -64,17 +61,17 @@ func (db *PostgresDB) GetLeaderPerformance(ctx context.Context, leaderAddress st
 	var analytics models.PnLAnalytics
 	var dailyPnLArray []float64
 
-	row := db.pool.QueryRow(ctx, query, leaderAddress, days)
-	
-	err := row.Scan(
-		&analytics.TotalTrades,
-		&analytics.ProfitableTrades,
-		&analytics.TotalPnL,
-		&analytics.WinRate,
-		&analytics.SharpeRatio,
-		&dailyPnLArray,
-	)
-	
+	err := dbretry.Do(ctx, db.retryMaxElapsed, func(ctx context.Context) error {
+		return db.pool.QueryRow(ctx, query, leaderAddress, days).Scan(
+			&analytics.TotalTrades,
+			&analytics.ProfitableTrades,
+			&analytics.TotalPnL,
+			&analytics.WinRate,
+			&analytics.SharpeRatio,
+			&dailyPnLArray,
+		)
+	})
+
 	if err != nil {
 		return nil, err
 	}
@@ -93,54 +90,117 @@ func (db *PostgresDB) GetLeaderPerformance(ctx context.Context, leaderAddress st
 func (db *PostgresDB) calculateMaxDrawdown(ctx context.Context, leaderAddress string, days int) (float64, error) {
 	query := `
 		WITH cumulative_pnl AS (
-			SELECT 
-				executed_at,
-				SUM(CASE 
-					WHEN side = 'sell' THEN size * price
-					ELSE -size * price
-				END) OVER (ORDER BY executed_at) as running_pnl
-			FROM trades
-			WHERE leader_address = $1 
-				AND is_leader_trade = true
-				AND executed_at >= NOW() - INTERVAL '%d days'
-				AND status = 'filled'
-			ORDER BY executed_at
+			SELECT
+				t.executed_at,
+				SUM(r.realized_pnl - r.fees) OVER (ORDER BY t.executed_at) as running_pnl
+			FROM realized_pnl r
+			JOIN trades t ON t.id = r.trade_id
+			WHERE r.leader_address = $1
+				AND r.follower_id IS NULL
+				AND t.executed_at >= NOW() - INTERVAL '%d days'
+			ORDER BY t.executed_at
 		),
 		running_max AS (
-			SELECT 
+			SELECT
 				executed_at,
 				running_pnl,
 				MAX(running_pnl) OVER (ORDER BY executed_at ROWS UNBOUNDED PRECEDING) as running_max_pnl
 			FROM cumulative_pnl
 		)
-		SELECT 
+		SELECT
 			COALESCE(MIN(running_pnl - running_max_pnl), 0) as max_drawdown
 		FROM running_max`
 
-	var maxDrawdown float64
-	err := db.pool.QueryRow(ctx, query, leaderAddress, days).Scan(&maxDrawdown)
-	return maxDrawdown, err
+	return dbretry.Query(ctx, db.retryMaxElapsed, func(ctx context.Context) (float64, error) {
+		var maxDrawdown float64
+		err := db.pool.QueryRow(ctx, query, leaderAddress, days).Scan(&maxDrawdown)
+		return maxDrawdown, err
+	})
+}
+
+// SaveLeaderPerformanceSnapshot upserts leaderAddress's latest incrementally
+// maintained metrics into leader_performance_snapshots, so a later read can
+// fetch them in O(1) instead of re-scanning every historical fill. Called
+// periodically by analytics.AnalyticsAggregator.
+func (db *PostgresDB) SaveLeaderPerformanceSnapshot(ctx context.Context, snapshot models.LeaderPerformanceSnapshot) error {
+	query := `
+		INSERT INTO leader_performance_snapshots
+			(leader_address, total_pnl, total_trades, profitable_trades, win_rate, sharpe_ratio, max_drawdown, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (leader_address) DO UPDATE SET
+			total_pnl = EXCLUDED.total_pnl,
+			total_trades = EXCLUDED.total_trades,
+			profitable_trades = EXCLUDED.profitable_trades,
+			win_rate = EXCLUDED.win_rate,
+			sharpe_ratio = EXCLUDED.sharpe_ratio,
+			max_drawdown = EXCLUDED.max_drawdown,
+			updated_at = EXCLUDED.updated_at`
+
+	return dbretry.Do(ctx, db.retryMaxElapsed, func(ctx context.Context) error {
+		_, err := db.pool.Exec(ctx, query,
+			snapshot.LeaderAddress,
+			snapshot.TotalPnL,
+			snapshot.TotalTrades,
+			snapshot.ProfitableTrades,
+			snapshot.WinRate,
+			snapshot.SharpeRatio,
+			snapshot.MaxDrawdown,
+			snapshot.UpdatedAt,
+		)
+		return err
+	})
+}
+
+// GetLeaderPerformanceSnapshot fetches leaderAddress's most recently
+// persisted snapshot, the O(1) counterpart to GetLeaderPerformance's full
+// window-function scan.
+func (db *PostgresDB) GetLeaderPerformanceSnapshot(ctx context.Context, leaderAddress string) (*models.LeaderPerformanceSnapshot, error) {
+	query := `
+		SELECT leader_address, total_pnl, total_trades, profitable_trades, win_rate, sharpe_ratio, max_drawdown, updated_at
+		FROM leader_performance_snapshots
+		WHERE leader_address = $1`
+
+	snapshot, err := dbretry.Query(ctx, db.retryMaxElapsed, func(ctx context.Context) (models.LeaderPerformanceSnapshot, error) {
+		var snapshot models.LeaderPerformanceSnapshot
+		err := db.pool.QueryRow(ctx, query, leaderAddress).Scan(
+			&snapshot.LeaderAddress,
+			&snapshot.TotalPnL,
+			&snapshot.TotalTrades,
+			&snapshot.ProfitableTrades,
+			&snapshot.WinRate,
+			&snapshot.SharpeRatio,
+			&snapshot.MaxDrawdown,
+			&snapshot.UpdatedAt,
+		)
+		return snapshot, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
 }
 
 // GetActiveLeaders returns all leaders with active followers
 func (db *PostgresDB) GetActiveLeaders(ctx context.Context) ([]models.Leader, error) {
 	query := `
-		SELECT DISTINCT 
+		SELECT DISTINCT
 			f.leader_address,
 			COUNT(f.id) as follower_count,
-			COALESCE(SUM(CASE 
+			COALESCE(SUM(CASE
 				WHEN t.side = 'sell' THEN t.size * t.price
 				ELSE -t.size * t.price
 			END), 0) as total_volume
 		FROM followers f
-		LEFT JOIN trades t ON f.leader_address = t.leader_address 
+		LEFT JOIN trades t ON f.leader_address = t.leader_address
 			AND t.is_leader_trade = true
 			AND t.executed_at >= NOW() - INTERVAL '30 days'
 		WHERE f.is_active = true
 		GROUP BY f.leader_address
 		ORDER BY follower_count DESC`
 
-	rows, err := db.pool.Query(ctx, query)
+	rows, err := dbretry.Query(ctx, db.retryMaxElapsed, func(ctx context.Context) (pgx.Rows, error) {
+		return db.pool.Query(ctx, query)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -174,29 +234,21 @@ func (db *PostgresDB) GetActiveLeaders(ctx context.Context) ([]models.Leader, er
 	return leaders, nil
 }
 
-// GetFollowerPnL calculates PnL for a specific follower
+// GetFollowerPnL calculates PnL for a specific follower from the
+// realized_pnl table, matching GetLeaderPerformance.
 func (db *PostgresDB) GetFollowerPnL(ctx context.Context, followerID int, days int) (*models.PnLAnalytics, error) {
 	query := `
 		WITH trade_pnl AS (
-			SELECT 
-				t.id,
-				t.side,
-				t.size,
-				t.price,
-				t.executed_at,
-				CASE 
-					WHEN t.side = 'sell' THEN t.size * t.price
-					ELSE -t.size * t.price
-				END as pnl_contribution
-			FROM trades t
-			WHERE t.follower_id = $1 
-				AND t.is_leader_trade = false
+			SELECT
+				r.realized_pnl - r.fees as pnl_contribution,
+				t.executed_at
+			FROM realized_pnl r
+			JOIN trades t ON t.id = r.trade_id
+			WHERE r.follower_id = $1
 				AND t.executed_at >= NOW() - INTERVAL '%d days'
-				AND t.status = 'filled'
-			ORDER BY t.executed_at
 		),
 		daily_pnl AS (
-			SELECT 
+			SELECT
 				DATE(executed_at) as trade_date,
 				SUM(pnl_contribution) as daily_pnl
 			FROM trade_pnl
@@ -204,7 +256,7 @@ func (db *PostgresDB) GetFollowerPnL(ctx context.Context, followerID int, days i
 			ORDER BY trade_date
 		),
 		performance_metrics AS (
-			SELECT 
+			SELECT
 				COUNT(*) as total_trades,
 				SUM(CASE WHEN pnl_contribution > 0 THEN 1 ELSE 0 END) as profitable_trades,
 				SUM(pnl_contribution) as total_pnl,
@@ -212,15 +264,15 @@ func (db *PostgresDB) GetFollowerPnL(ctx context.Context, followerID int, days i
 				STDDEV(pnl_contribution) as pnl_stddev
 			FROM trade_pnl
 		)
-		SELECT 
+		SELECT
 			pm.total_trades,
 			pm.profitable_trades,
 			pm.total_pnl,
-			CASE 
+			CASE
 				WHEN pm.total_trades > 0 THEN pm.profitable_trades::float / pm.total_trades::float
 				ELSE 0
 			END as win_rate,
-			CASE 
+			CASE
 				WHEN pm.pnl_stddev > 0 AND pm.pnl_stddev IS NOT NULL THEN pm.avg_pnl / pm.pnl_stddev
 				ELSE 0
 			END as sharpe_ratio,
@@ -232,17 +284,17 @@ func (db *PostgresDB) GetFollowerPnL(ctx context.Context, followerID int, days i
 	var analytics models.PnLAnalytics
 	var dailyPnLArray []float64
 
-	row := db.pool.QueryRow(ctx, query, followerID, days)
-	
-	err := row.Scan(
-		&analytics.TotalTrades,
-		&analytics.ProfitableTrades,
-		&analytics.TotalPnL,
-		&analytics.WinRate,
-		&analytics.SharpeRatio,
-		&dailyPnLArray,
-	)
-	
+	err := dbretry.Do(ctx, db.retryMaxElapsed, func(ctx context.Context) error {
+		return db.pool.QueryRow(ctx, query, followerID, days).Scan(
+			&analytics.TotalTrades,
+			&analytics.ProfitableTrades,
+			&analytics.TotalPnL,
+			&analytics.WinRate,
+			&analytics.SharpeRatio,
+			&dailyPnLArray,
+		)
+	})
+
 	if err != nil {
 		return nil, err
 	}
@@ -261,31 +313,29 @@ func (db *PostgresDB) GetFollowerPnL(ctx context.Context, followerID int, days i
 func (db *PostgresDB) calculateFollowerMaxDrawdown(ctx context.Context, followerID int, days int) (float64, error) {
 	query := `
 		WITH cumulative_pnl AS (
-			SELECT 
-				executed_at,
-				SUM(CASE 
-					WHEN side = 'sell' THEN size * price
-					ELSE -size * price
-				END) OVER (ORDER BY executed_at) as running_pnl
-			FROM trades
-			WHERE follower_id = $1 
-				AND is_leader_trade = false
-				AND executed_at >= NOW() - INTERVAL '%d days'
-				AND status = 'filled'
-			ORDER BY executed_at
+			SELECT
+				t.executed_at,
+				SUM(r.realized_pnl - r.fees) OVER (ORDER BY t.executed_at) as running_pnl
+			FROM realized_pnl r
+			JOIN trades t ON t.id = r.trade_id
+			WHERE r.follower_id = $1
+				AND t.executed_at >= NOW() - INTERVAL '%d days'
+			ORDER BY t.executed_at
 		),
 		running_max AS (
-			SELECT 
+			SELECT
 				executed_at,
 				running_pnl,
 				MAX(running_pnl) OVER (ORDER BY executed_at ROWS UNBOUNDED PRECEDING) as running_max_pnl
 			FROM cumulative_pnl
 		)
-		SELECT 
+		SELECT
 			COALESCE(MIN(running_pnl - running_max_pnl), 0) as max_drawdown
 		FROM running_max`
 
-	var maxDrawdown float64
-	err := db.pool.QueryRow(ctx, query, followerID, days).Scan(&maxDrawdown)
-	return maxDrawdown, err
+	return dbretry.Query(ctx, db.retryMaxElapsed, func(ctx context.Context) (float64, error) {
+		var maxDrawdown float64
+		err := db.pool.QueryRow(ctx, query, followerID, days).Scan(&maxDrawdown)
+		return maxDrawdown, err
+	})
 }
@@ -0,0 +1,166 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"hyperliquid-copy-trading/internal/database/dbretry"
+	"hyperliquid-copy-trading/internal/models"
+	"hyperliquid-copy-trading/internal/pnl"
+	"hyperliquid-copy-trading/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// RecomputeLeaderRealizedPnL replays every filled trade leaderAddress has
+// made as a leader through a fresh pnl.Matcher (grouped by coin, FIFO/LIFO/
+// weighted-average per db.lotMatchingMethod) and replaces its realized_pnl
+// rows with the result. Called after CreateTrade stores a new leader fill so
+// GetLeaderPerformance always reads up-to-date lot-matched PnL.
+func (db *PostgresDB) RecomputeLeaderRealizedPnL(ctx context.Context, leaderAddress string) ([]models.RealizedPnL, error) {
+	trades, err := db.getOrderedTrades(ctx, "leader_address = $1 AND is_leader_trade = true", leaderAddress)
+	if err != nil {
+		return nil, fmt.Errorf("loading leader trades for realized PnL: %w", err)
+	}
+
+	rows, err := db.matchRealizedPnL(trades)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.replaceRealizedPnL(ctx, "leader_address = $1 AND follower_id IS NULL", []any{leaderAddress}, rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// RecomputeFollowerRealizedPnL is RecomputeLeaderRealizedPnL's counterpart
+// for a single follower's copied trades.
+func (db *PostgresDB) RecomputeFollowerRealizedPnL(ctx context.Context, followerID int) ([]models.RealizedPnL, error) {
+	trades, err := db.getOrderedTrades(ctx, "follower_id = $1 AND is_leader_trade = false", followerID)
+	if err != nil {
+		return nil, fmt.Errorf("loading follower trades for realized PnL: %w", err)
+	}
+
+	rows, err := db.matchRealizedPnL(trades)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.replaceRealizedPnL(ctx, "follower_id = $1", []any{followerID}, rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// getOrderedTrades loads every filled trade matching whereClause (a single
+// "column = $1" predicate), ordered by asset then executed_at so each
+// (owner, coin) group's fills arrive to pnl.Matcher in execution order.
+func (db *PostgresDB) getOrderedTrades(ctx context.Context, whereClause string, arg any) ([]models.Trade, error) {
+	query := fmt.Sprintf(`
+		SELECT id, leader_address, follower_id, asset, side, size, price,
+			order_type, is_leader_trade, executed_at, hyperliquid_tx_id, status, fee, funding, created_at
+		FROM trades
+		WHERE %s AND status = 'filled'
+		ORDER BY asset, executed_at`, whereClause)
+
+	rows, err := dbretry.Query(ctx, db.retryMaxElapsed, func(ctx context.Context) (pgx.Rows, error) {
+		return db.pool.Query(ctx, query, arg)
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var trades []models.Trade
+	for rows.Next() {
+		var t models.Trade
+		if err := rows.Scan(
+			&t.ID, &t.LeaderAddress, &t.FollowerID, &t.Asset, &t.Side, &t.Size, &t.Price,
+			&t.OrderType, &t.IsLeaderTrade, &t.ExecutedAt, &t.HyperliquidTxID, &t.Status,
+			&t.Fee, &t.Funding, &t.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		trades = append(trades, t)
+	}
+	return trades, rows.Err()
+}
+
+// matchRealizedPnL groups trades by coin (they're already ordered by asset,
+// executed_at) and runs each coin's fills through its own pnl.Matcher using
+// db.lotMatchingMethod, since a matcher's open lots are only ever compared
+// within one (owner, coin) group.
+func (db *PostgresDB) matchRealizedPnL(trades []models.Trade) ([]models.RealizedPnL, error) {
+	matchers := make(map[string]*pnl.Matcher)
+	var rows []models.RealizedPnL
+	for _, t := range trades {
+		m, ok := matchers[t.Asset]
+		if !ok {
+			m = pnl.NewMatcher(db.lotMatchingMethod)
+			matchers[t.Asset] = m
+		}
+		matched, err := m.Process(t)
+		if err != nil {
+			return nil, fmt.Errorf("matching trade %d: %w", t.ID, err)
+		}
+		rows = append(rows, matched...)
+	}
+	return rows, nil
+}
+
+// replaceRealizedPnL deletes whereClause's existing realized_pnl rows and
+// inserts rows, all inside one transaction so a read never sees a partial
+// recompute.
+func (db *PostgresDB) replaceRealizedPnL(ctx context.Context, whereClause string, args []any, rows []models.RealizedPnL) error {
+	return dbretry.Do(ctx, db.retryMaxElapsed, func(ctx context.Context) error {
+		tx, err := db.pool.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(ctx)
+
+		if _, err := tx.Exec(ctx, fmt.Sprintf("DELETE FROM realized_pnl WHERE %s", whereClause), args...); err != nil {
+			return fmt.Errorf("clearing existing realized_pnl rows: %w", err)
+		}
+
+		for _, r := range rows {
+			if _, err := tx.Exec(ctx, `
+				INSERT INTO realized_pnl (trade_id, leader_address, follower_id, coin, matched_qty, entry_price, exit_price, realized_pnl, fees)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+				r.TradeID, r.LeaderAddress, r.FollowerID, r.Coin, r.MatchedQty, r.EntryPrice, r.ExitPrice, r.RealizedPnL, r.Fees,
+			); err != nil {
+				return fmt.Errorf("inserting realized_pnl row for trade %d: %w", r.TradeID, err)
+			}
+		}
+
+		return tx.Commit(ctx)
+	})
+}
+
+// GetLeaderUnrealizedPnL marks leaderAddress's still-open lots (replayed
+// through one pnl.Matcher, which keeps each coin's lots apart the same way
+// RecomputeLeaderRealizedPnL's per-coin matchers do) against
+// markPrices, the map[string]string shape HyperliquidAPI.GetAllMids/the
+// allMids subscription already deliver.
+func (db *PostgresDB) GetLeaderUnrealizedPnL(ctx context.Context, leaderAddress string, markPrices map[string]string) ([]models.UnrealizedPnL, error) {
+	trades, err := db.getOrderedTrades(ctx, "leader_address = $1 AND is_leader_trade = true", leaderAddress)
+	if err != nil {
+		return nil, fmt.Errorf("loading leader trades for unrealized PnL: %w", err)
+	}
+
+	prices := make(map[string]float64, len(markPrices))
+	for coin, px := range markPrices {
+		if v, err := utils.ParseFloat(px); err == nil {
+			prices[coin] = v
+		}
+	}
+
+	m := pnl.NewMatcher(db.lotMatchingMethod)
+	for _, t := range trades {
+		if _, err := m.Process(t); err != nil {
+			return nil, fmt.Errorf("matching trade %d: %w", t.ID, err)
+		}
+	}
+	return m.MarkToMarket(prices), nil
+}
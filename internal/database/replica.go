@@ -0,0 +1,65 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// stickyState tracks, for one request context tree, how long reads should
+// still be pinned to the primary after the most recent write performed
+// against it.
+type stickyState struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+type stickyCtxKey struct{}
+
+// WithReadStickiness attaches fresh read-your-writes tracking state to ctx.
+// Call once per incoming HTTP request (see handlers.ReadYourWritesMiddleware):
+// every write PostgresDB performs against a descendant of the returned ctx
+// pins ReadOnly's reads on that same ctx tree to the primary for
+// Config.ReplicaLagWindow, so a replica lagging behind the write can't
+// surface a stale read moments later in the same request.
+func WithReadStickiness(ctx context.Context) context.Context {
+	return context.WithValue(ctx, stickyCtxKey{}, &stickyState{})
+}
+
+// markWrite records that a write just happened on ctx, so reads against the
+// same ctx tree stick to the primary for ReplicaLagWindow. A no-op if ctx
+// was never passed through WithReadStickiness, or no replicas are
+// configured.
+func (db *PostgresDB) markWrite(ctx context.Context) {
+	if db.replica == nil || db.replicaLagWindow <= 0 {
+		return
+	}
+	if s, ok := ctx.Value(stickyCtxKey{}).(*stickyState); ok {
+		s.mu.Lock()
+		s.until = time.Now().Add(db.replicaLagWindow)
+		s.mu.Unlock()
+	}
+}
+
+func (db *PostgresDB) stickToPrimary(ctx context.Context) bool {
+	s, ok := ctx.Value(stickyCtxKey{}).(*stickyState)
+	if !ok {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return time.Now().Before(s.until)
+}
+
+// ReadOnly returns the pool a read-heavy query should run against: the
+// replica pool when Config.DatabaseReplicaURLs is set, unless ctx is still
+// inside the post-write stickiness window tracked by WithReadStickiness, or
+// no replicas were configured at all, in which case it returns the primary.
+func (db *PostgresDB) ReadOnly(ctx context.Context) *pgxpool.Pool {
+	if db.replica == nil || db.stickToPrimary(ctx) {
+		return db.pool
+	}
+	return db.replica
+}
@@ -0,0 +1,169 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"hyperliquid-copy-trading/internal/models"
+	"hyperliquid-copy-trading/internal/utils"
+
+	"github.com/rs/zerolog/log"
+)
+
+// defaultVolatilityInterval/defaultVolatilityWindow are the candle interval
+// and lookback length AssetVolatilityProvider refreshes on, matching a
+// typical ATR(14) setup.
+const (
+	defaultVolatilityInterval = "15m"
+	defaultVolatilityWindow   = 14
+)
+
+// assetVolatilityMaxAge is how long a cached ATR/stddev snapshot is
+// considered fresh enough to skip an on-demand refresh.
+const assetVolatilityMaxAge = 10 * time.Minute
+
+// candleSource fetches recent candles for an asset, implemented by
+// HyperliquidAPI.GetCandleSnapshot.
+type candleSource func(ctx context.Context, coin, interval string, limit int) ([]models.Kline, error)
+
+// assetVolatility is the cached ATR/stddev snapshot for one asset.
+type assetVolatility struct {
+	atr     float64
+	stdDev  float64 // percentage, from utils.CalculateVolatility
+	updated time.Time
+}
+
+// AssetVolatilityProvider replaces a static per-asset risk table with rolling
+// ATR and return-stddev computed from real candle history, refreshed
+// periodically from Hyperliquid's candleSnapshot endpoint.
+type AssetVolatilityProvider struct {
+	fetch    candleSource
+	interval string
+	window   int
+
+	mu    sync.RWMutex
+	cache map[string]assetVolatility
+}
+
+// NewAssetVolatilityProvider builds a provider using Hyperliquid's
+// candleSnapshot endpoint at the default interval/window.
+func NewAssetVolatilityProvider(fetch candleSource) *AssetVolatilityProvider {
+	return &AssetVolatilityProvider{
+		fetch:    fetch,
+		interval: defaultVolatilityInterval,
+		window:   defaultVolatilityWindow,
+		cache:    make(map[string]assetVolatility),
+	}
+}
+
+// Refresh re-fetches candles for asset and updates its cached ATR/stddev.
+// Callers typically run this on a timer per tracked asset; AssessRisk reads
+// whatever is currently cached rather than fetching inline, so a slow/failed
+// refresh never blocks order flow.
+func (p *AssetVolatilityProvider) Refresh(ctx context.Context, asset string) error {
+	candles, err := p.fetch(ctx, asset, p.interval, p.window+1)
+	if err != nil {
+		return err
+	}
+	if len(candles) < 2 {
+		return nil
+	}
+
+	atr := averageTrueRange(candles)
+	stdDev := utils.CalculateVolatility(closeReturns(candles))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cache[asset] = assetVolatility{atr: atr, stdDev: stdDev, updated: time.Now()}
+	return nil
+}
+
+// averageTrueRange computes Wilder's ATR over the given candles using the
+// standard true-range definition (max of high-low, |high-prevClose|,
+// |low-prevClose|).
+func averageTrueRange(candles []models.Kline) float64 {
+	if len(candles) < 2 {
+		return 0
+	}
+
+	var sum float64
+	for i := 1; i < len(candles); i++ {
+		high, low, prevClose := candles[i].High, candles[i].Low, candles[i-1].Close
+		trueRange := utils.Max(high-low, utils.Max(utils.Abs(high-prevClose), utils.Abs(low-prevClose)))
+		sum += trueRange
+	}
+	return sum / float64(len(candles)-1)
+}
+
+// closeReturns converts a candle series into close-to-close percentage
+// returns, the input CalculateVolatility expects.
+func closeReturns(candles []models.Kline) []float64 {
+	if len(candles) < 2 {
+		return nil
+	}
+
+	returns := make([]float64, 0, len(candles)-1)
+	for i := 1; i < len(candles); i++ {
+		if candles[i-1].Close == 0 {
+			continue
+		}
+		returns = append(returns, (candles[i].Close-candles[i-1].Close)/candles[i-1].Close)
+	}
+	return returns
+}
+
+// RefreshIfStale refreshes asset's cached volatility if it has never been
+// fetched or is older than maxAge. Intended for call sites that see assets
+// on-demand (e.g. a leader trade event) rather than on a fixed schedule.
+func (p *AssetVolatilityProvider) RefreshIfStale(ctx context.Context, asset string, maxAge time.Duration) error {
+	p.mu.RLock()
+	v, ok := p.cache[asset]
+	p.mu.RUnlock()
+
+	if ok && time.Since(v.updated) < maxAge {
+		return nil
+	}
+	return p.Refresh(ctx, asset)
+}
+
+// Get returns the last-refreshed volatility snapshot for asset, and whether
+// one exists yet.
+func (p *AssetVolatilityProvider) Get(asset string) (assetVolatility, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	v, ok := p.cache[asset]
+	return v, ok
+}
+
+// ATR returns the cached ATR for asset, or 0 if not yet refreshed.
+func (p *AssetVolatilityProvider) ATR(asset string) float64 {
+	v, _ := p.Get(asset)
+	return v.atr
+}
+
+// RunRefreshLoop periodically refreshes every asset in assets until ctx is
+// cancelled. Intended to be started once per tracked asset set at engine
+// startup.
+func (p *AssetVolatilityProvider) RunRefreshLoop(ctx context.Context, assets []string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	refreshAll := func() {
+		for _, asset := range assets {
+			if err := p.Refresh(ctx, asset); err != nil {
+				log.Warn().Err(err).Str("asset", asset).Msg("Failed to refresh asset volatility")
+			}
+		}
+	}
+
+	refreshAll()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			refreshAll()
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package engine
+
+import "time"
+
+// Clock abstracts a single wall-clock reading, the same way ExchangeClient
+// abstracts a single venue connection: PermissionlessCopyEngine holds one so
+// shouldCopyTrade's OnlyDuringHours/TimeDelaySeconds checks can be driven by
+// a fixed instant from a conformance vector instead of the real clock.
+type Clock interface {
+	Now() time.Time
+}
+
+// realClock is the Clock every production PermissionlessCopyEngine uses.
+type realClock struct{}
+
+// NewRealClock returns a Clock backed by time.Now.
+func NewRealClock() Clock { return realClock{} }
+
+func (realClock) Now() time.Time { return time.Now() }
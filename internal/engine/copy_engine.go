@@ -2,9 +2,12 @@ package engine
 
 import (
 	"context"
+	"fmt"
 	"hyperliquid-copy-trading/config"
 	"hyperliquid-copy-trading/internal/api"
 	"hyperliquid-copy-trading/internal/database"
+	"hyperliquid-copy-trading/internal/exchange/meta"
+	"hyperliquid-copy-trading/internal/execution"
 	"hyperliquid-copy-trading/internal/models"
 	"hyperliquid-copy-trading/internal/utils"
 	"hyperliquid-copy-trading/internal/websocket"
@@ -12,20 +15,27 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 	"github.com/rs/zerolog/log"
 )
 
 type CopyEngine struct {
-	config         *config.Config
-	db             *database.PostgresDB
-	wsManager      *websocket.Manager
-	orderEngine    *OrderEngine
-	riskManager    *RiskManager
-	hyperliquidAPI *api.HyperliquidAPI
-	activeLeaders  map[string]bool
-	leadersMutex   sync.RWMutex
-	shutdown       chan struct{}
-	wg             sync.WaitGroup
+	config          *config.Config
+	db              *database.PostgresDB
+	wsManager       *websocket.Manager
+	orderEngine     *OrderEngine
+	riskManager     *RiskManager
+	positionTracker *PositionTracker
+	hyperliquidAPI  *api.HyperliquidAPI
+	agentWallets    *api.AgentWalletManager
+	instruments     *meta.Registry
+	marketWS        *api.WebSocketClient
+	hub             *websocket.Hub
+	events          *eventBus
+	activeLeaders   map[string]bool
+	leadersMutex    sync.RWMutex
+	shutdown        chan struct{}
+	wg              sync.WaitGroup
 }
 
 func NewCopyEngine(cfg *config.Config, db *database.PostgresDB, wsManager *websocket.Manager) *CopyEngine {
@@ -34,16 +44,64 @@ func NewCopyEngine(cfg *config.Config, db *database.PostgresDB, wsManager *webso
 		log.Fatal().Err(err).Msg("Failed to initialize Hyperliquid API")
 	}
 
-	return &CopyEngine{
-		config:         cfg,
-		db:             db,
-		wsManager:      wsManager,
-		orderEngine:    NewOrderEngine(cfg, hyperliquidAPI),
-		riskManager:    NewRiskManager(cfg),
-		hyperliquidAPI: hyperliquidAPI,
-		activeLeaders:  make(map[string]bool),
-		shutdown:       make(chan struct{}),
+	positionTracker := NewPositionTracker()
+	volatilityProvider := NewAssetVolatilityProvider(hyperliquidAPI.GetCandleSnapshot)
+	exchanges := map[string]ExchangeClient{
+		models.DefaultVenue: NewHyperliquidExchangeClient(hyperliquidAPI, wsManager),
 	}
+	instruments := meta.NewRegistry(hyperliquidAPI.AssetRegistry())
+	wsManager.SetInstruments(instruments)
+
+	// The live order-book feed is best-effort: a copy trade falls back to a
+	// plain market order (the prior behavior) if it can't connect.
+	marketWS, err := api.NewWebSocketClient(hyperliquidAPI)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to connect live market-data WebSocket, copies will use market orders without book-aware pricing")
+	}
+
+	// AgentWalletManager installs itself into hyperliquidAPI's signer
+	// resolver, so every follower added from here on trades through its
+	// own ephemeral agent wallet rather than the config-level signer.
+	agentKeyCipher, err := api.NewAESGCMKeyCipher(cfg.AgentKeyEncryptionKey)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize agent wallet key cipher")
+	}
+	agentWallets := api.NewAgentWalletManager(db, agentKeyCipher, hyperliquidAPI, cfg.AgentWalletTTL)
+
+	ce := &CopyEngine{
+		config:          cfg,
+		db:              db,
+		wsManager:       wsManager,
+		orderEngine:     NewOrderEngine(cfg, exchanges, db, wsManager),
+		riskManager:     NewRiskManager(cfg, NewTradeHistoryStore(db), positionTracker, volatilityProvider, hyperliquidAPI.AssetRegistry()),
+		positionTracker: positionTracker,
+		hyperliquidAPI:  hyperliquidAPI,
+		agentWallets:    agentWallets,
+		instruments:     instruments,
+		marketWS:        marketWS,
+		hub:             websocket.NewHub(),
+		events:          newEventBus(),
+		activeLeaders:   make(map[string]bool),
+		shutdown:        make(chan struct{}),
+	}
+
+	if marketWS != nil {
+		marketWS.SubscribeBookResync(ce.onBookResync)
+	}
+
+	return ce
+}
+
+// onBookResync fires when the live order-book feed reconnects and every
+// reconstructed book is invalidated pending its next snapshot, so
+// subscribeHealth dashboards see the discontinuity instead of silently
+// pricing against a stale book.
+func (ce *CopyEngine) onBookResync() {
+	log.Warn().Msg("Order book feed reconnected; reconstructed books invalidated pending resync")
+	ce.hub.Publish(websocket.Event{
+		Kind: "health",
+		Data: map[string]interface{}{"order_book_resync": true},
+	})
 }
 
 func (ce *CopyEngine) Start() {
@@ -79,6 +137,27 @@ func (ce *CopyEngine) Start() {
 		ce.performMaintenance(ctx)
 	}()
 
+	// Relay order lifecycle events to subscribed outbound WebSocket clients
+	ce.wg.Add(1)
+	go func() {
+		defer ce.wg.Done()
+		ce.relayOrderEvents()
+	}()
+
+	// Periodically push a health snapshot to subscribeHealth clients
+	ce.wg.Add(1)
+	go func() {
+		defer ce.wg.Done()
+		ce.broadcastHealth(ctx)
+	}()
+
+	// Refresh the instrument tick/lot-size cache on a jittered interval
+	ce.wg.Add(1)
+	go func() {
+		defer ce.wg.Done()
+		ce.instruments.Run(ctx, ce.shutdown)
+	}()
+
 	log.Info().Msg("Copy Trading Engine started successfully")
 }
 
@@ -86,6 +165,11 @@ func (ce *CopyEngine) Stop() {
 	log.Info().Msg("Stopping Copy Trading Engine")
 	close(ce.shutdown)
 	ce.wg.Wait()
+	if ce.marketWS != nil {
+		if err := ce.marketWS.Close(); err != nil {
+			log.Warn().Err(err).Msg("Failed to close live market-data WebSocket")
+		}
+	}
 	log.Info().Msg("Copy Trading Engine stopped")
 }
 
@@ -111,27 +195,87 @@ func (ce *CopyEngine) GetOrderQueueStatus() map[string]interface{} {
 	return ce.orderEngine.GetQueueStatus()
 }
 
-// AddFollower adds a new follower and starts monitoring their leader
-func (ce *CopyEngine) AddFollower(ctx context.Context, follower *models.Follower) error {
+// BeginAgentEnrollment generates a fresh agent wallet for masterAddress,
+// not yet bound to any follower, and returns the typed data masterAddress
+// must sign out-of-band to authorize it. The caller relays
+// agentAddress/nonce/typedData to the client, collects the resulting
+// signature into a models.AgentApproval, and passes agentAddress and the
+// approval to AddFollower.
+func (ce *CopyEngine) BeginAgentEnrollment(ctx context.Context, masterAddress, agentName string) (agentAddress string, nonce int64, typedData apitypes.TypedData, err error) {
+	agentAddress, nonce, err = ce.agentWallets.GenerateAgent(ctx, masterAddress, agentName)
+	if err != nil {
+		return "", 0, apitypes.TypedData{}, err
+	}
+	typedData = api.BuildApproveAgentTypedData(ce.hyperliquidAPI.HyperliquidChainName(), agentAddress, agentName, nonce)
+	return agentAddress, nonce, typedData, nil
+}
+
+// AddFollower adds a new follower, approving its agent wallet before
+// anything is persisted and binding it to the follower's id once
+// CreateFollower has assigned one: follower.APIWalletAddress is
+// overwritten with agentAddress once approval's signature is accepted, so
+// a caller can never create a follower against a raw, server-held private
+// key.
+func (ce *CopyEngine) AddFollower(ctx context.Context, follower *models.Follower, agentAddress string, approval models.AgentApproval) error {
+	if err := ce.agentWallets.ApproveAgent(ctx, agentAddress, approval); err != nil {
+		return fmt.Errorf("approving agent wallet: %w", err)
+	}
+	follower.APIWalletAddress = agentAddress
+
 	// Create follower in database
 	if err := ce.db.CreateFollower(ctx, follower); err != nil {
 		return err
 	}
-	
+
+	if err := ce.agentWallets.BindFollower(ctx, agentAddress, follower.ID); err != nil {
+		return fmt.Errorf("binding agent wallet: %w", err)
+	}
+
 	// Start monitoring the leader if not already monitored
 	ce.leadersMutex.Lock()
 	if !ce.activeLeaders[follower.LeaderAddress] {
 		ce.activeLeaders[follower.LeaderAddress] = true
 		ce.leadersMutex.Unlock()
-		ce.startMonitoringLeader(follower.LeaderAddress)
+		ce.startMonitoringLeader(ctx, follower.LeaderAddress)
 	} else {
 		ce.leadersMutex.Unlock()
 	}
-	
+
+	ce.startMonitoringMargin(follower.APIWalletAddress)
+
+	if err := ce.riskManager.LoadHistory(ctx, follower.ID); err != nil {
+		log.Warn().Err(err).Int("follower_id", follower.ID).Msg("Failed to seed trade history, overtrading windows will warm up from empty")
+	}
+
 	log.Info().Int("follower_id", follower.ID).Str("leader", follower.LeaderAddress).Msg("Follower added")
 	return nil
 }
 
+// startMonitoringMargin subscribes to the follower's margin stream and feeds
+// every update into the risk manager's pre-trade liquidation guard.
+func (ce *CopyEngine) startMonitoringMargin(apiWalletAddress string) {
+	marginChannel, err := ce.wsManager.SubscribeMargin(apiWalletAddress)
+	if err != nil {
+		log.Error().Err(err).Str("wallet", apiWalletAddress).Msg("Failed to subscribe to margin stream")
+		return
+	}
+
+	ce.wg.Add(1)
+	go func() {
+		defer ce.wg.Done()
+		for state := range marginChannel {
+			ce.riskManager.UpdateMarginState(apiWalletAddress, state)
+
+			if state.RiskRate >= ce.config.MaxMarginRiskRate {
+				log.Warn().
+					Str("wallet", apiWalletAddress).
+					Float64("risk_rate", state.RiskRate).
+					Msg("Follower margin risk rate breached threshold")
+			}
+		}
+	}()
+}
+
 // RemoveFollower removes a follower and stops monitoring their leader if no other followers
 func (ce *CopyEngine) RemoveFollower(ctx context.Context, followerID int) error {
 	// Delete from database
@@ -187,7 +331,7 @@ func (ce *CopyEngine) loadActiveFollowers(ctx context.Context) {
 		ce.leadersMutex.RUnlock()
 
 		if !isMonitored {
-			ce.startMonitoringLeader(leaderAddress)
+			ce.startMonitoringLeader(context.Background(), leaderAddress)
 		}
 	}
 
@@ -201,12 +345,18 @@ func (ce *CopyEngine) loadActiveFollowers(ctx context.Context) {
 	ce.leadersMutex.Unlock()
 }
 
-func (ce *CopyEngine) startMonitoringLeader(leaderAddress string) {
+// startMonitoringLeader subscribes to leaderAddress's trade/user streams.
+// ctx governs only the in-flight subscription setup (the WebSocket dial);
+// if the caller's request context is cancelled (e.g. the HTTP client that
+// triggered AddFollower disconnects) before the dial completes, setup is
+// aborted rather than left to finish in the background. Once connected,
+// the client's lifecycle is independent of ctx and stops only via Stop().
+func (ce *CopyEngine) startMonitoringLeader(ctx context.Context, leaderAddress string) {
 	ce.leadersMutex.Lock()
 	ce.activeLeaders[leaderAddress] = true
 	ce.leadersMutex.Unlock()
 
-	tradeChannel, userChannel, err := ce.wsManager.SubscribeToLeader(leaderAddress)
+	tradeChannel, userChannel, err := ce.wsManager.SubscribeToLeader(ctx, models.DefaultVenue, leaderAddress)
 	if err != nil {
 		log.Error().Err(err).Str("leader", leaderAddress).Msg("Failed to subscribe to leader")
 		return
@@ -231,7 +381,7 @@ func (ce *CopyEngine) startMonitoringLeader(leaderAddress string) {
 
 func (ce *CopyEngine) stopMonitoringLeader(leaderAddress string) {
 	delete(ce.activeLeaders, leaderAddress)
-	ce.wsManager.UnsubscribeFromLeader(leaderAddress)
+	ce.wsManager.UnsubscribeFromLeader(models.DefaultVenue, leaderAddress)
 	log.Info().Str("leader", leaderAddress).Msg("Stopped monitoring leader")
 }
 
@@ -277,6 +427,7 @@ func (ce *CopyEngine) processLeaderTrade(leaderAddress string, tradeEvent models
 	// Store leader trade
 	price, _ := strconv.ParseFloat(tradeEvent.Px, 64)
 	size, _ := strconv.ParseFloat(tradeEvent.Sz, 64)
+	fee, _ := strconv.ParseFloat(tradeEvent.Fee, 64)
 
 	leaderTrade := &models.Trade{
 		LeaderAddress:   leaderAddress,
@@ -289,11 +440,40 @@ func (ce *CopyEngine) processLeaderTrade(leaderAddress string, tradeEvent models
 		ExecutedAt:      time.Unix(tradeEvent.Time/1000, 0),
 		HyperliquidTxID: tradeEvent.Hash,
 		Status:          "filled",
+		Fee:             fee,
 	}
 
 	if err := ce.db.CreateTrade(ctx, leaderTrade); err != nil {
 		log.Error().Err(err).Msg("Failed to store leader trade")
-	}
+	} else if _, err := ce.db.RecomputeLeaderRealizedPnL(ctx, leaderAddress); err != nil {
+		log.Error().Err(err).Str("leader", leaderAddress).Msg("Failed to recompute leader realized PnL")
+	}
+
+	ce.hub.Publish(websocket.Event{
+		Kind:          "leader_trade",
+		LeaderAddress: leaderAddress,
+		Coin:          tradeEvent.Coin,
+		Data:          leaderTrade,
+	})
+
+	ce.recordEvent(ctx, leaderAddress, tradeEvent.Coin, "trade", tradeEvent.Time, map[string]interface{}{
+		"side":  tradeEvent.Side,
+		"price": tradeEvent.Px,
+		"size":  tradeEvent.Sz,
+		"hash":  tradeEvent.Hash,
+		"fee":   tradeEvent.Fee,
+	})
+
+	// Opportunistically keep this asset's ATR/stddev snapshot warm so
+	// AssessRisk's volatility-adaptive sizing has real data by the time the
+	// batch below runs risk checks.
+	ce.wg.Add(1)
+	go func() {
+		defer ce.wg.Done()
+		if err := ce.riskManager.volatility.RefreshIfStale(ctx, tradeEvent.Coin, assetVolatilityMaxAge); err != nil {
+			log.Warn().Err(err).Str("asset", tradeEvent.Coin).Msg("Failed to refresh asset volatility")
+		}
+	}()
 
 	// Get followers for this leader
 	followers, err := ce.db.GetFollowersByLeader(ctx, leaderAddress)
@@ -328,7 +508,8 @@ func (ce *CopyEngine) processFollowersInBatches(ctx context.Context, followers [
 }
 
 func (ce *CopyEngine) processBatch(ctx context.Context, followers []models.Follower, tradeEvent models.TradeEvent, leaderTrade *models.Trade) {
-	var orders []*models.OrderRequest
+	var trackedOrders, statelessOrders []*models.OrderRequest
+	var trackedFollowers, statelessFollowers []models.Follower
 
 	for _, follower := range followers {
 		// Risk assessment
@@ -348,16 +529,29 @@ func (ce *CopyEngine) processBatch(ctx context.Context, followers []models.Follo
 			continue
 		}
 
-		// Create order
-		order := &models.OrderRequest{
-			Asset:     leaderTrade.Asset,
-			IsBuy:     leaderTrade.Side == "buy",
-			Size:      positionSize,
-			OrderType: "market",
-			Nonce:     time.Now().UnixMilli() + int64(follower.ID), // Ensure unique nonce
+		if err := ce.instruments.Validate(leaderTrade.Asset, positionSize, leaderTrade.Price); err != nil {
+			log.Warn().
+				Err(err).
+				Int("follower_id", follower.ID).
+				Str("asset", leaderTrade.Asset).
+				Msg("Copy rejected by instrument validation")
+			continue
 		}
 
-		orders = append(orders, order)
+		if follower.StopLossPercentage != nil || follower.TakeProfitPercentage != nil {
+			ce.executeBracketTrade(ctx, follower, leaderTrade, positionSize)
+		} else {
+			order := ce.buildCopyOrder(leaderTrade, positionSize, follower.ID)
+			order.RiskScore = riskAssessment.RiskScore
+
+			if follower.CopyMode == models.CopyModeStateless {
+				statelessOrders = append(statelessOrders, order)
+				statelessFollowers = append(statelessFollowers, follower)
+			} else {
+				trackedOrders = append(trackedOrders, order)
+				trackedFollowers = append(trackedFollowers, follower)
+			}
+		}
 
 		// Store follower trade
 		followerTrade := &models.Trade{
@@ -375,16 +569,248 @@ func (ce *CopyEngine) processBatch(ctx context.Context, followers []models.Follo
 
 		if err := ce.db.CreateTrade(ctx, followerTrade); err != nil {
 			log.Error().Err(err).Int("follower_id", follower.ID).Msg("Failed to store follower trade")
+		} else if _, err := ce.db.RecomputeFollowerRealizedPnL(ctx, follower.ID); err != nil {
+			log.Error().Err(err).Int("follower_id", follower.ID).Msg("Failed to recompute follower realized PnL")
 		}
+		ce.riskManager.RecordTrade(&follower, *followerTrade)
 	}
 
 	// Execute batch orders
-	if len(orders) > 0 {
-		ce.orderEngine.ExecuteBatch(ctx, orders, followers)
+	if len(trackedOrders) > 0 {
+		ce.orderEngine.ExecuteBatch(ctx, leaderTrade.LeaderAddress, trackedOrders, trackedFollowers, PriorityMarket)
+	}
+	if len(statelessOrders) > 0 {
+		ce.orderEngine.ExecuteBatchStateless(ctx, statelessOrders, statelessFollowers, nil)
 	}
 }
 
+// ExecuteSmartOrder drives a SmartCopyOrder to completion using the
+// algorithm registered under order.ExecutionStrategy (see the execution
+// package), falling back to immediate market execution for unknown or
+// "immediate" strategies.
+func (ce *CopyEngine) ExecuteSmartOrder(ctx context.Context, order *models.SmartCopyOrder, apiWalletAddress string) error {
+	algo, ok := execution.Get(order.ExecutionStrategy)
+	if !ok || order.ExecutionStrategy == "immediate" {
+		return ce.executeSmartOrderImmediate(ctx, order, apiWalletAddress)
+	}
+
+	books := func(ctx context.Context, asset string) (models.L2Book, error) {
+		book, err := ce.hyperliquidAPI.GetL2Book(ctx, asset)
+		if err != nil {
+			return models.L2Book{}, err
+		}
+		return *book, nil
+	}
+
+	placer := smartOrderPlacer{hyperliquidAPI: ce.hyperliquidAPI, apiWalletAddress: apiWalletAddress}
+	return execution.Run(ctx, algo, order, books, placer)
+}
+
+func (ce *CopyEngine) executeSmartOrderImmediate(ctx context.Context, order *models.SmartCopyOrder, apiWalletAddress string) error {
+	req := &models.EnhancedOrderRequest{
+		Asset:     order.Asset,
+		IsBuy:     order.Side == "buy",
+		Size:      order.TargetSize - order.TotalExecuted,
+		OrderType: "market",
+	}
+
+	response, err := ce.hyperliquidAPI.PlaceOrder(ctx, req, models.TradingAccount{APIWallet: apiWalletAddress})
+	if err != nil {
+		return err
+	}
+
+	for _, status := range response.Data.Statuses {
+		if status.Filled != nil {
+			filled, _ := utils.ParseFloat(status.Filled.TotalSz)
+			avgPrice, _ := utils.ParseFloat(status.Filled.AvgPx)
+			order.PartialExecutions = append(order.PartialExecutions, models.PartialExecution{
+				Size:      filled,
+				Price:     avgPrice,
+				Timestamp: time.Now(),
+			})
+			order.TotalExecuted += filled
+			order.AveragePrice = avgPrice
+		}
+	}
+
+	now := time.Now()
+	order.CompletedAt = &now
+	order.Status = "completed"
+	return nil
+}
+
+// smartOrderPlacer adapts HyperliquidAPI.PlaceOrder to execution.Placer.
+type smartOrderPlacer struct {
+	hyperliquidAPI   *api.HyperliquidAPI
+	apiWalletAddress string
+}
+
+func (p smartOrderPlacer) PlaceOrder(ctx context.Context, order models.OrderRequest) (float64, float64, error) {
+	req := &models.EnhancedOrderRequest{
+		Asset:      order.Asset,
+		IsBuy:      order.IsBuy,
+		Size:       order.Size,
+		Price:      order.Price,
+		OrderType:  order.OrderType,
+		ReduceOnly: order.ReduceOnly,
+	}
+
+	response, err := p.hyperliquidAPI.PlaceOrder(ctx, req, models.TradingAccount{APIWallet: p.apiWalletAddress})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var filled, avgPrice float64
+	for _, status := range response.Data.Statuses {
+		if status.Filled != nil {
+			filled, _ = utils.ParseFloat(status.Filled.TotalSz)
+			avgPrice, _ = utils.ParseFloat(status.Filled.AvgPx)
+		}
+	}
+
+	return filled, avgPrice, nil
+}
+
+// executeBracketTrade wraps a follower's copy order as an entry + TP/SL
+// bracket so StopLossPercentage/TakeProfitPercentage are enforced atomically
+// instead of as separate follow-up orders placed after the fact.
+func (ce *CopyEngine) executeBracketTrade(ctx context.Context, follower models.Follower, leaderTrade *models.Trade, positionSize float64) {
+	isBuy := leaderTrade.Side == "buy"
+	entry := models.EnhancedOrderRequest{
+		Asset:     leaderTrade.Asset,
+		IsBuy:     isBuy,
+		Size:      positionSize,
+		OrderType: "market",
+	}
+
+	var takeProfit, stopLoss *models.EnhancedOrderRequest
+	if follower.TakeProfitPercentage != nil {
+		tpPrice := leaderTrade.Price * (1 + signedPercentage(isBuy, *follower.TakeProfitPercentage))
+		takeProfit = &models.EnhancedOrderRequest{
+			Asset:     leaderTrade.Asset,
+			IsBuy:     !isBuy,
+			Size:      positionSize,
+			Price:     &tpPrice,
+			OrderType: "limit",
+			Tif:       "Gtc",
+		}
+	}
+	if follower.StopLossPercentage != nil {
+		slPrice := leaderTrade.Price * (1 - signedPercentage(isBuy, *follower.StopLossPercentage))
+		stopLoss = &models.EnhancedOrderRequest{
+			Asset:     leaderTrade.Asset,
+			IsBuy:     !isBuy,
+			Size:      positionSize,
+			Price:     &slPrice,
+			OrderType: "limit",
+			Tif:       "Gtc",
+		}
+	}
+
+	linkID := fmt.Sprintf("%s-%d-%d", leaderTrade.Asset, follower.ID, time.Now().UnixNano())
+	bracket := models.NewBracketOrder(entry, linkID, takeProfit, stopLoss)
+
+	if _, err := ce.orderEngine.ExecuteBracketOrder(ctx, follower.Venue, bracket, follower.APIWalletAddress); err != nil {
+		log.Error().
+			Err(err).
+			Int("follower_id", follower.ID).
+			Str("asset", leaderTrade.Asset).
+			Msg("Failed to execute bracket trade")
+	}
+}
+
+// signedPercentage flips the sign of a TP/SL percentage offset depending on
+// trade direction, so long TPs sit above entry and short TPs sit below it.
+func signedPercentage(isBuy bool, pct float64) float64 {
+	if isBuy {
+		return pct / 100.0
+	}
+	return -pct / 100.0
+}
+
+// buildCopyOrder builds the OrderRequest for a copied trade. When a live
+// order-book connection is available, it converts the copy to a marketable
+// limit order priced off the book's current top-of-book (capped at
+// config.MaxCopySlippageBps past it) instead of a blind market order, so a
+// thin book can't blow through the follower's expected fill price. It falls
+// back to a plain market order if no book is available yet.
+func (ce *CopyEngine) buildCopyOrder(leaderTrade *models.Trade, size float64, followerID int) *models.OrderRequest {
+	order := &models.OrderRequest{
+		Asset:     leaderTrade.Asset,
+		IsBuy:     leaderTrade.Side == "buy",
+		Size:      size,
+		OrderType: "market",
+		Nonce:     time.Now().UnixMilli() + int64(followerID), // Ensure unique nonce
+	}
+
+	if ce.marketWS == nil {
+		return order
+	}
+
+	book, err := ce.marketWS.OrderBook(leaderTrade.Asset, 1)
+	if err != nil {
+		return order
+	}
+
+	price, ok := marketableLimitPrice(book, order.IsBuy, ce.config.MaxCopySlippageBps)
+	if !ok {
+		return order
+	}
+
+	price = ce.instruments.RoundPrice(leaderTrade.Asset, price)
+	order.OrderType = "limit"
+	order.Price = &price
+	order.Tif = "Ioc"
+	return order
+}
+
+// marketableLimitPrice derives a limit price that should cross the book
+// immediately: top-of-book plus slippageBps for a buy, minus it for a sell.
+// Returns false if book has no liquidity on the relevant side.
+func marketableLimitPrice(book models.L2Book, isBuy bool, slippageBps float64) (float64, bool) {
+	side := "asks"
+	if !isBuy {
+		side = "bids"
+	}
+
+	levels, ok := book.Levels[side]
+	if !ok || len(levels) == 0 {
+		return 0, false
+	}
+
+	top, err := strconv.ParseFloat(levels[0].Px, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	slip := top * slippageBps / 10000
+	if isBuy {
+		return top + slip, true
+	}
+	return top - slip, true
+}
+
+// GetOrderBook returns coin's reconstructed order book from the live
+// market-data WebSocket, subscribing to its l2Book feed on first request.
+func (ce *CopyEngine) GetOrderBook(coin string) (models.L2Book, error) {
+	if ce.marketWS == nil {
+		return models.L2Book{}, fmt.Errorf("order book: no live market data connection available")
+	}
+	return ce.marketWS.OrderBook(coin, 20)
+}
+
 func (ce *CopyEngine) calculatePositionSize(follower *models.Follower, leaderTrade *models.Trade, adjustedSize float64) float64 {
+	return CalculatePositionSize(ce.instruments, follower, leaderTrade, adjustedSize)
+}
+
+// CalculatePositionSize derives a follower's copy size from the leader's
+// trade: copy-percentage scaling, the risk manager's adjustedSize cap,
+// the follower's MaxPositionSize limit, the instrument's leverage cap, and
+// finally lot-size rounding, applied in that order. It's a free function
+// (rather than a CopyEngine method) so the conformance harness can exercise
+// the sizing pipeline against a meta.Registry directly, without
+// constructing a full CopyEngine and its live dependencies.
+func CalculatePositionSize(instruments *meta.Registry, follower *models.Follower, leaderTrade *models.Trade, adjustedSize float64) float64 {
 	// Base size calculation using copy percentage
 	baseSize := leaderTrade.Size * (follower.CopyPercentage / 100.0)
 
@@ -398,8 +824,48 @@ func (ce *CopyEngine) calculatePositionSize(follower *models.Follower, leaderTra
 		baseSize = follower.MaxPositionSize
 	}
 
-	// Round to appropriate precision (would get this from meta info in production)
-	return utils.RoundToDecimals(baseSize, 3)
+	baseSize = applyLeverageCap(instruments, follower, leaderTrade.Asset, baseSize)
+
+	// Round down to the instrument's lot size so the order never gets
+	// rejected for excess precision.
+	return instruments.RoundSize(leaderTrade.Asset, baseSize)
+}
+
+// applyLeverageCap scales size down if the follower's requested leverage
+// (follower.RiskSettings["leverage"]) exceeds what the instrument allows,
+// so a copy never implies more leverage than Hyperliquid permits on that
+// asset.
+func applyLeverageCap(instruments *meta.Registry, follower *models.Follower, asset string, size float64) float64 {
+	desired, ok := follower.RiskSettings["leverage"].(float64)
+	if !ok || desired <= 0 {
+		return size
+	}
+
+	inst, ok := instruments.Get(asset)
+	if !ok || inst.MaxLeverage <= 0 || desired <= float64(inst.MaxLeverage) {
+		return size
+	}
+
+	return size * (float64(inst.MaxLeverage) / desired)
+}
+
+// GetInstruments returns every cached instrument's tick/lot-size and
+// leverage limits, keyed by coin.
+func (ce *CopyEngine) GetInstruments() map[string]meta.Instrument {
+	return ce.instruments.All()
+}
+
+// GetInstrument returns coin's cached instrument limits, and whether it is
+// known.
+func (ce *CopyEngine) GetInstrument(coin string) (meta.Instrument, bool) {
+	return ce.instruments.Get(coin)
+}
+
+// MinInstrumentNotional returns the lowest minimum order notional across
+// every cached instrument, for validating a follower's MaxPositionSize
+// against the exchange floor.
+func (ce *CopyEngine) MinInstrumentNotional() float64 {
+	return ce.instruments.MinNotional()
 }
 
 func (ce *CopyEngine) processLeaderUserEvent(leaderAddress string, userEvent models.UserEvent) {
@@ -408,14 +874,21 @@ func (ce *CopyEngine) processLeaderUserEvent(leaderAddress string, userEvent mod
 		Str("type", userEvent.Type).
 		Msg("Leader user event")
 
+	payload, ok := userEvent.Data.(map[string]interface{})
+	if !ok {
+		payload = map[string]interface{}{"raw": userEvent.Data}
+	}
+
 	// Handle different user event types
 	switch userEvent.Type {
 	case "order":
-		// Handle order events (filled, cancelled, etc.)
+		if status, _ := payload["status"].(string); status == "filled" {
+			ce.recordEvent(context.Background(), leaderAddress, eventAsset(payload), "fill", eventHeight(payload), payload)
+		}
 	case "liquidation":
-		// Handle liquidation events
+		ce.recordEvent(context.Background(), leaderAddress, eventAsset(payload), "liquidation", eventHeight(payload), payload)
 	case "funding":
-		// Handle funding payments
+		ce.recordEvent(context.Background(), leaderAddress, eventAsset(payload), "funding", eventHeight(payload), payload)
 	}
 }
 
@@ -443,24 +916,24 @@ func (ce *CopyEngine) updatePositions(ctx context.Context) {
 		return
 	}
 
-	userAddresses := make(map[string]bool)
+	followerIDsByWallet := make(map[string][]int)
 	for _, follower := range followers {
 		if follower.IsActive {
-			userAddresses[follower.APIWalletAddress] = true
+			followerIDsByWallet[follower.APIWalletAddress] = append(followerIDsByWallet[follower.APIWalletAddress], follower.ID)
 		}
 	}
 
 	// Update positions for each user
-	for userAddress := range userAddresses {
+	for userAddress, followerIDs := range followerIDsByWallet {
 		ce.wg.Add(1)
-		go func(addr string) {
+		go func(addr string, followerIDs []int) {
 			defer ce.wg.Done()
-			ce.updateUserPositions(ctx, addr)
-		}(userAddress)
+			ce.updateUserPositions(ctx, addr, followerIDs)
+		}(userAddress, followerIDs)
 	}
 }
 
-func (ce *CopyEngine) updateUserPositions(ctx context.Context, userAddress string) {
+func (ce *CopyEngine) updateUserPositions(ctx context.Context, userAddress string, followerIDs []int) {
 	userState, err := ce.hyperliquidAPI.GetUserState(ctx, userAddress)
 	if err != nil {
 		log.Error().Err(err).Str("user", userAddress).Msg("Failed to get user state")
@@ -482,6 +955,16 @@ func (ce *CopyEngine) updateUserPositions(ctx context.Context, userAddress strin
 		if err := ce.db.UpsertPosition(ctx, position); err != nil {
 			log.Error().Err(err).Str("user", userAddress).Msg("Failed to update position")
 		}
+		ce.positionTracker.Update(userAddress, *position)
+
+		for _, followerID := range followerIDs {
+			ce.hub.Publish(websocket.Event{
+				Kind:       "pnl_update",
+				FollowerID: followerID,
+				Coin:       position.Asset,
+				Data:       position,
+			})
+		}
 	}
 }
 
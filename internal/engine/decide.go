@@ -0,0 +1,134 @@
+package engine
+
+import (
+	"time"
+
+	"hyperliquid-copy-trading/config"
+	"hyperliquid-copy-trading/internal/api"
+	"hyperliquid-copy-trading/internal/exchange/meta"
+	"hyperliquid-copy-trading/internal/models"
+)
+
+// AssetVolatilitySnapshot pins one asset's cached ATR/stddev reading for
+// Decide, instead of a live candle fetch through AssetVolatilityProvider.Refresh.
+type AssetVolatilitySnapshot struct {
+	Asset  string  `json:"asset"`
+	ATR    float64 `json:"atr"`
+	StdDev float64 `json:"std_dev"`
+}
+
+// DecisionConfig is the subset of config.Config that AssessRisk reads,
+// pinned separately from a deployment's live config so a vector's risk
+// thresholds can't drift with the environment it happens to run in.
+type DecisionConfig struct {
+	MaxMarginRiskRate float64 `json:"max_margin_risk_rate"`
+	MinMarginLevel    float64 `json:"min_margin_level"`
+}
+
+// DecisionInput is everything processBatch gathers for one follower before
+// calling AssessRisk, CalculatePositionSize and buildCopyOrder, collected
+// into a single value so the decision can be replayed deterministically
+// from a conformance vector instead of a live engine.
+type DecisionInput struct {
+	Follower    models.Follower          `json:"follower"`
+	LeaderTrade models.Trade             `json:"leader_trade"`
+	Assets      map[string]api.AssetMeta `json:"assets"`
+
+	// History is the follower's trade history prior to LeaderTrade, oldest
+	// first, seeding the overtrading and cooldown-after-loss windows the
+	// same way ringTradeHistoryStore does live.
+	History []models.Trade `json:"history"`
+
+	// HasMargin gates Margin the same way an unset marginStates entry does
+	// live: no margin stream yet means the margin guard is skipped rather
+	// than evaluated against a zero value.
+	HasMargin  bool                      `json:"has_margin"`
+	Margin     models.LiveMarginState    `json:"margin"`
+	Positions  []models.Position         `json:"positions"`
+	Volatility []AssetVolatilitySnapshot `json:"volatility"`
+
+	Config DecisionConfig `json:"config"`
+
+	// Now is the decision's wall-clock reading, threaded into
+	// RiskManager.AssessRiskAt instead of time.Now() so the outcome doesn't
+	// depend on when the vector happens to run.
+	Now time.Time `json:"now"`
+}
+
+// DecisionOutput is what Decide produces for one follower: the risk
+// assessment, the final copy size after sizing, and the order that would be
+// submitted. Order is nil if the trade was rejected or sized down to
+// nothing.
+type DecisionOutput struct {
+	Risk         *RiskAssessment      `json:"risk"`
+	PositionSize float64              `json:"position_size"`
+	Order        *models.OrderRequest `json:"order"`
+}
+
+// Decide runs one follower's copy-trade decision — risk assessment,
+// position sizing, and order construction — as a pure function of input,
+// with no DB, WebSocket or exchange API access. It is the same pipeline
+// processBatch drives live followers through (AssessRisk, then
+// CalculatePositionSize, then instrument validation), factored out so the
+// conformance harness can pin it down with vectors instead of only
+// exercising it implicitly through a running engine. It deliberately stops
+// short of buildCopyOrder's marketable-limit pricing, since that reads a
+// live order book Decide has no access to; the order it returns is always
+// a plain market order, same as buildCopyOrder's no-book-available fallback.
+func Decide(input DecisionInput) DecisionOutput {
+	follower := input.Follower
+	leaderTrade := input.LeaderTrade
+
+	history := NewTradeHistoryStore(nil)
+	for _, t := range input.History {
+		history.Record(follower.ID, t)
+	}
+
+	positions := NewPositionTracker()
+	for _, p := range input.Positions {
+		positions.Update(follower.APIWalletAddress, p)
+	}
+
+	volatility := NewAssetVolatilityProvider(nil)
+	for _, v := range input.Volatility {
+		volatility.cache[v.Asset] = assetVolatility{atr: v.ATR, stdDev: v.StdDev, updated: input.Now}
+	}
+
+	assets := api.NewStaticAssetRegistry(input.Assets)
+	instruments := meta.NewRegistry(assets)
+
+	cfg := &config.Config{
+		MaxMarginRiskRate: input.Config.MaxMarginRiskRate,
+		MinMarginLevel:    input.Config.MinMarginLevel,
+	}
+
+	riskManager := NewRiskManager(cfg, history, positions, volatility, assets)
+	if input.HasMargin {
+		riskManager.UpdateMarginState(follower.APIWalletAddress, input.Margin)
+	}
+
+	risk := riskManager.AssessRiskAt(&follower, &leaderTrade, input.Now)
+	if !risk.Approved {
+		return DecisionOutput{Risk: risk}
+	}
+
+	size := CalculatePositionSize(instruments, &follower, &leaderTrade, risk.AdjustedSize)
+	if size <= 0 {
+		return DecisionOutput{Risk: risk, PositionSize: size}
+	}
+
+	if err := instruments.Validate(leaderTrade.Asset, size, leaderTrade.Price); err != nil {
+		risk.Approved = false
+		risk.Reason = err.Error()
+		return DecisionOutput{Risk: risk, PositionSize: size}
+	}
+
+	order := &models.OrderRequest{
+		Asset:     leaderTrade.Asset,
+		IsBuy:     leaderTrade.Side == "buy",
+		Size:      size,
+		OrderType: "market",
+	}
+
+	return DecisionOutput{Risk: risk, PositionSize: size, Order: order}
+}
@@ -0,0 +1,129 @@
+package engine
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// traderCandidate is one address's rolling discovery state: short and long
+// windows are scored independently so a tick can tell "good and still
+// trading" apart from "was good, gone quiet since." short/long are only
+// ever mutated by the single discoverActiveTraders goroutine that fetches
+// and folds in new fills; lastSeen/lastFetched can be touched from any
+// trade-event goroutine, so those two get their own mutex.
+type traderCandidate struct {
+	address   string
+	short     *candidateWindow
+	long      *candidateWindow
+	firstSeen time.Time
+
+	mu          sync.Mutex
+	lastSeen    time.Time
+	lastFetched time.Time
+}
+
+func (c *traderCandidate) touch(seenAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if seenAt.After(c.lastSeen) {
+		c.lastSeen = seenAt
+	}
+}
+
+func (c *traderCandidate) lastSeenAt() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastSeen
+}
+
+// fetchWatermark returns the end of the range already folded into
+// short/long; setFetchWatermark advances it.
+func (c *traderCandidate) fetchWatermark() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastFetched
+}
+
+func (c *traderCandidate) setFetchWatermark(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if t.After(c.lastFetched) {
+		c.lastFetched = t
+	}
+}
+
+// candidateLRU bounds how many addresses the discovery pipeline keeps
+// rolling windows for. Without a cap, every address seen across every
+// monitored trade stream would accumulate forever; candidateLRU evicts the
+// least-recently-seen address once DiscoveryCandidateCacheSize is
+// exceeded, the same way a bounded cache would.
+type candidateLRU struct {
+	mu       sync.Mutex
+	capacity int
+
+	shortWindow time.Duration
+	longWindow  time.Duration
+
+	items map[string]*list.Element
+	order *list.List // front = most recently seen
+}
+
+func newCandidateLRU(capacity int, shortWindow, longWindow time.Duration) *candidateLRU {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &candidateLRU{
+		capacity:    capacity,
+		shortWindow: shortWindow,
+		longWindow:  longWindow,
+		items:       make(map[string]*list.Element),
+		order:       list.New(),
+	}
+}
+
+// touch registers address as a live discovery candidate (creating it, and
+// evicting the least-recently-seen candidate if now over capacity, on
+// first sight) and records seenAt as activity, without itself fetching or
+// scoring any fills.
+func (c *candidateLRU) touch(address string, seenAt time.Time) *traderCandidate {
+	c.mu.Lock()
+	el, ok := c.items[address]
+	var cand *traderCandidate
+	if ok {
+		c.order.MoveToFront(el)
+		cand = el.Value.(*traderCandidate)
+	} else {
+		cand = &traderCandidate{
+			address:   address,
+			short:     newCandidateWindow(c.shortWindow),
+			long:      newCandidateWindow(c.longWindow),
+			firstSeen: seenAt,
+		}
+		c.items[address] = c.order.PushFront(cand)
+
+		if c.order.Len() > c.capacity {
+			if oldest := c.order.Back(); oldest != nil {
+				evicted := oldest.Value.(*traderCandidate)
+				delete(c.items, evicted.address)
+				c.order.Remove(oldest)
+			}
+		}
+	}
+	c.mu.Unlock()
+
+	cand.touch(seenAt)
+	return cand
+}
+
+// snapshot returns every tracked candidate, for a discovery tick to score.
+func (c *candidateLRU) snapshot() []*traderCandidate {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	out := make([]*traderCandidate, 0, len(c.items))
+	for el := c.order.Front(); el != nil; el = el.Next() {
+		out = append(out, el.Value.(*traderCandidate))
+	}
+	return out
+}
@@ -0,0 +1,212 @@
+package engine
+
+import (
+	"container/heap"
+	"math"
+	"time"
+
+	"hyperliquid-copy-trading/config"
+)
+
+// candidateFill is one fill recorded against a discovery candidate -- the
+// unit candidateWindow's ring buffer and eviction heap both track.
+type candidateFill struct {
+	timestamp time.Time
+	pnl       float64
+	notional  float64
+	asset     string
+}
+
+// candidateFillHeap is a min-heap of *candidateFill keyed by timestamp,
+// mirroring followerDispatchHeap's container/heap shape in
+// follower_scheduler.go. candidateWindow.evict pops from it while the root
+// is older than the window cutoff, which is how "entries older than the
+// window" get dropped without rescanning the whole buffer on every fill.
+type candidateFillHeap []*candidateFill
+
+func (h candidateFillHeap) Len() int            { return len(h) }
+func (h candidateFillHeap) Less(i, j int) bool  { return h[i].timestamp.Before(h[j].timestamp) }
+func (h candidateFillHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *candidateFillHeap) Push(x interface{}) { *h = append(*h, x.(*candidateFill)) }
+func (h *candidateFillHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
+
+// candidateMetrics are the rolling-window quality metrics compositeScore
+// combines into one discovery score.
+type candidateMetrics struct {
+	Sharpe         float64
+	MaxDrawdown    float64
+	ProfitFactor   float64
+	AvgHoldTime    time.Duration
+	Herfindahl     float64
+	TradeCount     int
+	WinCount       int
+	TotalNotional  float64
+	AssetBreakdown map[string]float64
+}
+
+// candidateWindow is a time-bounded rolling buffer of one candidate's
+// fills. byTime is the eviction heap; chrono holds the same fills in
+// arrival order (a candidate's own WebSocket trade stream is already
+// chronological, so this is just the heap's pop order kept around) because
+// maxDrawdown, profit factor, Herfindahl and holding time need the fill
+// path, not just its sum/sum-of-squares moments. sumPnL/sumSqPnL/
+// sumNotional are maintained incrementally on every push and evict, so
+// Sharpe's mean/stddev are O(1) to read at any tick instead of rescanning
+// the window.
+type candidateWindow struct {
+	window time.Duration
+
+	byTime candidateFillHeap
+	chrono []*candidateFill
+
+	sumPnL      float64
+	sumSqPnL    float64
+	sumNotional float64
+}
+
+func newCandidateWindow(window time.Duration) *candidateWindow {
+	w := &candidateWindow{window: window}
+	heap.Init(&w.byTime)
+	return w
+}
+
+// push records f and evicts anything that falls out of the window as of
+// f's timestamp.
+func (w *candidateWindow) push(f *candidateFill) {
+	heap.Push(&w.byTime, f)
+	w.chrono = append(w.chrono, f)
+	w.sumPnL += f.pnl
+	w.sumSqPnL += f.pnl * f.pnl
+	w.sumNotional += f.notional
+
+	w.evict(f.timestamp.Add(-w.window))
+}
+
+func (w *candidateWindow) evict(cutoff time.Time) {
+	for w.byTime.Len() > 0 && w.byTime[0].timestamp.Before(cutoff) {
+		stale := heap.Pop(&w.byTime).(*candidateFill)
+		w.sumPnL -= stale.pnl
+		w.sumSqPnL -= stale.pnl * stale.pnl
+		w.sumNotional -= stale.notional
+	}
+
+	if len(w.chrono) == 0 {
+		return
+	}
+	i := 0
+	for i < len(w.chrono) && w.chrono[i].timestamp.Before(cutoff) {
+		i++
+	}
+	if i > 0 {
+		w.chrono = append(w.chrono[:0:0], w.chrono[i:]...)
+	}
+}
+
+// metrics computes this window's current Sharpe-like ratio, max drawdown,
+// profit factor, average per-asset holding time and asset-concentration
+// Herfindahl index, in one pass over the in-window fills.
+func (w *candidateWindow) metrics() candidateMetrics {
+	n := len(w.chrono)
+	if n == 0 {
+		return candidateMetrics{AssetBreakdown: map[string]float64{}}
+	}
+
+	mean := w.sumPnL / float64(n)
+	variance := w.sumSqPnL/float64(n) - mean*mean
+	if variance < 0 {
+		variance = 0
+	}
+	stddev := math.Sqrt(variance)
+
+	// Annualize by the candidate's own observed trade frequency rather
+	// than assuming one trade per day, since fills arrive at whatever
+	// cadence the trader actually trades at.
+	span := w.chrono[n-1].timestamp.Sub(w.chrono[0].timestamp)
+	var sharpe float64
+	if stddev > 0 && span > 0 {
+		tradesPerYear := float64(n) / span.Hours() * 24 * 365
+		sharpe = (mean / stddev) * math.Sqrt(tradesPerYear)
+	}
+
+	var equity, peak, maxDrawdown float64
+	var sumWin, sumLoss float64
+	var winCount int
+	assetBreakdown := make(map[string]float64, 4)
+	lastByAsset := make(map[string]time.Time, 4)
+	var holdSum time.Duration
+	var holdCount int
+
+	for _, f := range w.chrono {
+		equity += f.pnl
+		if equity > peak {
+			peak = equity
+		}
+		if dd := peak - equity; dd > maxDrawdown {
+			maxDrawdown = dd
+		}
+
+		if f.pnl > 0 {
+			sumWin += f.pnl
+			winCount++
+		} else {
+			sumLoss += -f.pnl
+		}
+
+		assetBreakdown[f.asset] += f.notional
+		if last, ok := lastByAsset[f.asset]; ok {
+			holdSum += f.timestamp.Sub(last)
+			holdCount++
+		}
+		lastByAsset[f.asset] = f.timestamp
+	}
+
+	var profitFactor float64
+	switch {
+	case sumLoss > 0:
+		profitFactor = sumWin / sumLoss
+	case sumWin > 0:
+		profitFactor = sumWin
+	}
+
+	var herfindahl float64
+	if w.sumNotional > 0 {
+		for _, notional := range assetBreakdown {
+			share := notional / w.sumNotional
+			herfindahl += share * share
+		}
+	}
+
+	var avgHold time.Duration
+	if holdCount > 0 {
+		avgHold = holdSum / time.Duration(holdCount)
+	}
+
+	return candidateMetrics{
+		Sharpe:         sharpe,
+		MaxDrawdown:    maxDrawdown,
+		ProfitFactor:   profitFactor,
+		AvgHoldTime:    avgHold,
+		Herfindahl:     herfindahl,
+		TradeCount:     n,
+		WinCount:       winCount,
+		TotalNotional:  w.sumNotional,
+		AssetBreakdown: assetBreakdown,
+	}
+}
+
+// compositeScore combines m into the single ranking score discovery ticks
+// sort candidates by: w1*sharpe - w2*maxDD + w3*log(1+profitFactor) -
+// w4*herfindahl.
+func compositeScore(m candidateMetrics, weights config.DiscoveryWeights) float64 {
+	return weights.Sharpe*m.Sharpe -
+		weights.Drawdown*m.MaxDrawdown +
+		weights.ProfitFactor*math.Log1p(m.ProfitFactor) -
+		weights.Herfindahl*m.Herfindahl
+}
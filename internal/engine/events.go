@@ -0,0 +1,144 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"hyperliquid-copy-trading/internal/models"
+	"hyperliquid-copy-trading/internal/websocket"
+
+	"github.com/rs/zerolog/log"
+)
+
+// eventSubBufferSize bounds each SubscribeEvents channel's pending queue; a
+// subscriber that falls behind gets events dropped rather than stalling
+// recordEvent (mirrors websocket.outboundBufferSize's drop-on-slow-consumer
+// semantics).
+const eventSubBufferSize = 64
+
+// eventBus fans a persisted models.LeaderEvent out to live Go-channel
+// subscribers (SubscribeEvents), independent of ce.hub, which only serves
+// outbound WebSocket dashboards.
+type eventBus struct {
+	mu   sync.Mutex
+	subs map[int]eventSub
+	next int
+}
+
+type eventSub struct {
+	filter models.EventFilter
+	ch     chan models.LeaderEvent
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{subs: make(map[int]eventSub)}
+}
+
+func (b *eventBus) subscribe(filter models.EventFilter) (int, chan models.LeaderEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.next++
+	id := b.next
+	ch := make(chan models.LeaderEvent, eventSubBufferSize)
+	b.subs[id] = eventSub{filter: filter, ch: ch}
+	return id, ch
+}
+
+func (b *eventBus) unsubscribe(id int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if sub, ok := b.subs[id]; ok {
+		close(sub.ch)
+		delete(b.subs, id)
+	}
+}
+
+func (b *eventBus) publish(event models.LeaderEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, sub := range b.subs {
+		if !sub.filter.Matches(event) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			log.Warn().Msg("Leader event subscriber channel full, dropping event")
+		}
+	}
+}
+
+// SubscribeEvents returns a channel of every future LeaderEvent matching
+// filter, for a follower client or analytics consumer that wants live
+// leader activity without polling GetEvents. The channel is closed once
+// ctx is done.
+func (ce *CopyEngine) SubscribeEvents(ctx context.Context, filter models.EventFilter) <-chan models.LeaderEvent {
+	id, ch := ce.events.subscribe(filter)
+	go func() {
+		<-ctx.Done()
+		ce.events.unsubscribe(id)
+	}()
+	return ch
+}
+
+// GetEvents replays every persisted LeaderEvent matching filter whose
+// height falls in [from, to], for a late-joining follower or analytics
+// dashboard to backfill leader activity it missed over its own join
+// window.
+func (ce *CopyEngine) GetEvents(ctx context.Context, filter models.EventFilter, from, to time.Time) ([]models.LeaderEvent, error) {
+	return ce.db.QueryLeaderEvents(ctx, filter, from.UnixMilli(), to.UnixMilli())
+}
+
+// recordEvent persists event to the immutable leader_events log and fans
+// it out to both SubscribeEvents subscribers and ce.hub's outbound
+// WebSocket dashboards, so a late-joining follower can always backfill via
+// GetEvents what a live subscriber already saw pushed.
+func (ce *CopyEngine) recordEvent(ctx context.Context, leader, asset, eventType string, height int64, payload map[string]interface{}) {
+	event := &models.LeaderEvent{
+		Leader:    leader,
+		Asset:     asset,
+		EventType: eventType,
+		Height:    height,
+		Payload:   payload,
+	}
+
+	if err := ce.db.RecordLeaderEvent(ctx, event); err != nil {
+		log.Error().Err(err).Str("leader", leader).Str("event_type", eventType).Msg("Failed to persist leader event")
+		return
+	}
+
+	ce.events.publish(*event)
+	ce.hub.Publish(websocket.Event{
+		Kind:          "leader_event",
+		LeaderAddress: leader,
+		Coin:          asset,
+		Data:          event,
+	})
+}
+
+// eventAsset extracts the coin/asset field a raw userEvents payload
+// carries under "coin", falling back to "unknown" so recordEvent always
+// has a non-empty topic component.
+func eventAsset(payload map[string]interface{}) string {
+	if coin, ok := payload["coin"].(string); ok && coin != "" {
+		return coin
+	}
+	return "unknown"
+}
+
+// eventHeight extracts the millisecond timestamp a raw userEvents payload
+// carries under "time", falling back to the current time when absent.
+func eventHeight(payload map[string]interface{}) int64 {
+	switch t := payload["time"].(type) {
+	case float64:
+		return int64(t)
+	case int64:
+		return t
+	default:
+		return time.Now().UnixMilli()
+	}
+}
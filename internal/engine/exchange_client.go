@@ -0,0 +1,99 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"hyperliquid-copy-trading/internal/api"
+	"hyperliquid-copy-trading/internal/models"
+	"hyperliquid-copy-trading/internal/websocket"
+)
+
+// ExchangeClient is the surface OrderEngine needs from a venue's trading
+// API: placing and cancelling orders, batching them, polling status, and
+// streaming user events. hyperliquidExchangeClient is the only
+// implementation today, but the interface is what lets a follower copy
+// trades onto a venue other than Hyperliquid (dYdX, GMX, a CEX bridge) and
+// lets tests substitute a mock instead of patching HTTP.
+type ExchangeClient interface {
+	BatchOrders(ctx context.Context, orders []*models.OrderRequest, apiWalletAddress string, nonce int64) (*models.OrderResponse, error)
+	PlaceOrder(ctx context.Context, order *models.OrderRequest, apiWalletAddress string) (*models.OrderResponse, error)
+	CancelOrder(ctx context.Context, asset string, oid int64, apiWalletAddress string, nonce int64) (*models.HyperliquidAPIResponse, error)
+	GetOrderStatus(ctx context.Context, userAddress string, oid int64) (map[string]interface{}, error)
+	SubscribeUserEvents(walletAddress string) (chan models.UserEvent, error)
+}
+
+// EnhancedOrderClient is implemented by venues that support Hyperliquid-style
+// contingent orders (OTO/OTOCO parent-child brackets with Tif/ClOid/LinkID).
+// ExecuteBracketOrder needs it; a venue that only implements the baseline
+// ExchangeClient can't place brackets.
+type EnhancedOrderClient interface {
+	PlaceEnhancedOrder(ctx context.Context, order *models.EnhancedOrderRequest, apiWalletAddress string) (*models.OrderResponse, error)
+}
+
+// hyperliquidExchangeClient adapts *api.HyperliquidAPI and the shared
+// *websocket.Manager to ExchangeClient, converting the engine's
+// venue-agnostic models.OrderRequest to Hyperliquid's
+// models.EnhancedOrderRequest at the boundary.
+type hyperliquidExchangeClient struct {
+	api       *api.HyperliquidAPI
+	wsManager *websocket.Manager
+}
+
+// NewHyperliquidExchangeClient returns the ExchangeClient OrderEngine should
+// register under models.DefaultVenue.
+func NewHyperliquidExchangeClient(hyperliquidAPI *api.HyperliquidAPI, wsManager *websocket.Manager) ExchangeClient {
+	return &hyperliquidExchangeClient{api: hyperliquidAPI, wsManager: wsManager}
+}
+
+func toEnhancedOrder(order *models.OrderRequest) *models.EnhancedOrderRequest {
+	tif := order.Tif
+	if tif == "" {
+		tif = "Gtc"
+	}
+
+	return &models.EnhancedOrderRequest{
+		Asset:      order.Asset,
+		IsBuy:      order.IsBuy,
+		Size:       order.Size,
+		Price:      order.Price,
+		OrderType:  order.OrderType,
+		ReduceOnly: order.ReduceOnly,
+		Tif:        tif,
+	}
+}
+
+func (c *hyperliquidExchangeClient) BatchOrders(ctx context.Context, orders []*models.OrderRequest, apiWalletAddress string, nonce int64) (*models.OrderResponse, error) {
+	enhanced := make([]*models.EnhancedOrderRequest, len(orders))
+	for i, order := range orders {
+		enhanced[i] = toEnhancedOrder(order)
+	}
+	return c.api.BatchOrders(ctx, enhanced, models.TradingAccount{APIWallet: apiWalletAddress})
+}
+
+func (c *hyperliquidExchangeClient) PlaceOrder(ctx context.Context, order *models.OrderRequest, apiWalletAddress string) (*models.OrderResponse, error) {
+	return c.api.PlaceOrder(ctx, toEnhancedOrder(order), models.TradingAccount{APIWallet: apiWalletAddress})
+}
+
+func (c *hyperliquidExchangeClient) PlaceEnhancedOrder(ctx context.Context, order *models.EnhancedOrderRequest, apiWalletAddress string) (*models.OrderResponse, error) {
+	return c.api.PlaceOrder(ctx, order, models.TradingAccount{APIWallet: apiWalletAddress})
+}
+
+func (c *hyperliquidExchangeClient) CancelOrder(ctx context.Context, asset string, oid int64, apiWalletAddress string, nonce int64) (*models.HyperliquidAPIResponse, error) {
+	return c.api.CancelOrder(ctx, asset, oid, models.TradingAccount{APIWallet: apiWalletAddress}, nonce)
+}
+
+func (c *hyperliquidExchangeClient) GetOrderStatus(ctx context.Context, userAddress string, oid int64) (map[string]interface{}, error) {
+	return c.api.GetOrderStatus(ctx, userAddress, oid)
+}
+
+// SubscribeUserEvents dispatches to the shared websocket.Manager under
+// models.DefaultVenue, the same way OrderEngine's fill tracking already
+// subscribed before venues existed.
+func (c *hyperliquidExchangeClient) SubscribeUserEvents(walletAddress string) (chan models.UserEvent, error) {
+	if c.wsManager == nil {
+		return nil, fmt.Errorf("no websocket manager configured for venue %q", models.DefaultVenue)
+	}
+	_, userChannel, err := c.wsManager.SubscribeToLeader(context.Background(), models.DefaultVenue, walletAddress)
+	return userChannel, err
+}
@@ -0,0 +1,166 @@
+package engine
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"hyperliquid-copy-trading/internal/models"
+)
+
+// followerDispatchItem is one WalletBatch waiting for a dispatch slot,
+// ranked by the fairness key chunk7-5 asked for: higher Tier first, then
+// lower RiskScore, then earlier Arrival breaks any further tie.
+type followerDispatchItem struct {
+	batch     *WalletBatch
+	tier      models.FollowerTier
+	riskScore float64
+	arrival   time.Time
+	dispatch  func(*WalletBatch)
+	index     int
+}
+
+type followerDispatchHeap []*followerDispatchItem
+
+func (h followerDispatchHeap) Len() int { return len(h) }
+
+func (h followerDispatchHeap) Less(i, j int) bool {
+	a, b := h[i], h[j]
+	if a.tier != b.tier {
+		return a.tier > b.tier
+	}
+	if a.riskScore != b.riskScore {
+		return a.riskScore < b.riskScore
+	}
+	return a.arrival.Before(b.arrival)
+}
+
+func (h followerDispatchHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *followerDispatchHeap) Push(x interface{}) {
+	item := x.(*followerDispatchItem)
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *followerDispatchHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	item.index = -1
+	*h = old[:n-1]
+	return item
+}
+
+// followerScheduler hands WalletBatches to a bounded pool of workers in
+// (Tier desc, RiskScore asc, Arrival asc) order, instead of OrderEngine
+// firing one unthrottled goroutine per wallet. Under backpressure -- a
+// burst of leader trades, or Hyperliquid rate-limiting the wallets sharing
+// this process -- this is what lets a high-tier, low-risk follower's order
+// leave before a low-tier one queued behind it, rather than racing on Go's
+// unordered goroutine scheduling.
+type followerScheduler struct {
+	mu    sync.Mutex
+	items followerDispatchHeap
+
+	notify   chan struct{}
+	shutdown chan struct{}
+	wg       sync.WaitGroup
+}
+
+// newFollowerScheduler starts workers goroutines draining the fairness
+// heap; workers bounds how many WalletBatches can be in flight to the
+// exchange at once, which is what makes the ordering matter at all.
+func newFollowerScheduler(workers int) *followerScheduler {
+	if workers <= 0 {
+		workers = 1
+	}
+
+	s := &followerScheduler{
+		// Sized to workers, not 1: one processBatch call can submit many
+		// items in a row, each calling signal once. A capacity-1 channel
+		// coalesces that whole burst into a single wakeup, so only the one
+		// worker it wakes ever drains the heap -- the rest stay parked and
+		// FollowerSchedulerWorkers stops mattering. Sizing to workers lets
+		// every idle worker pick up its own wakeup token instead of losing
+		// it to a fuller channel.
+		notify:   make(chan struct{}, workers),
+		shutdown: make(chan struct{}),
+	}
+	heap.Init(&s.items)
+
+	for i := 0; i < workers; i++ {
+		s.wg.Add(1)
+		go s.drain()
+	}
+
+	return s
+}
+
+// submit enqueues batch for dispatch, ranked against every other currently
+// queued batch by tier/riskScore/arrival. dispatch is called exactly once,
+// from one of the scheduler's workers, once batch reaches the front.
+func (s *followerScheduler) submit(batch *WalletBatch, tier models.FollowerTier, riskScore float64, arrival time.Time, dispatch func(*WalletBatch)) {
+	s.mu.Lock()
+	heap.Push(&s.items, &followerDispatchItem{
+		batch:     batch,
+		tier:      tier,
+		riskScore: riskScore,
+		arrival:   arrival,
+		dispatch:  dispatch,
+	})
+	s.mu.Unlock()
+	s.signal()
+}
+
+func (s *followerScheduler) signal() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (s *followerScheduler) pop() *followerDispatchItem {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.items.Len() == 0 {
+		return nil
+	}
+	return heap.Pop(&s.items).(*followerDispatchItem)
+}
+
+func (s *followerScheduler) drain() {
+	defer s.wg.Done()
+
+	for {
+		item := s.pop()
+		if item == nil {
+			select {
+			case <-s.shutdown:
+				return
+			case <-s.notify:
+				continue
+			}
+		}
+
+		item.dispatch(item.batch)
+
+		select {
+		case <-s.shutdown:
+			return
+		default:
+		}
+	}
+}
+
+// stop waits for every in-flight dispatch to return before returning
+// itself; it does not drop whatever is still queued in s.items.
+func (s *followerScheduler) stop() {
+	close(s.shutdown)
+	s.wg.Wait()
+}
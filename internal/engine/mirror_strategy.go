@@ -0,0 +1,176 @@
+package engine
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	"hyperliquid-copy-trading/internal/models"
+	"hyperliquid-copy-trading/internal/utils"
+)
+
+// ProportionalMirror reproduces PermissionlessCopyEngine's original
+// behavior: each follower's CopyFilters gate the trade via
+// shouldCopyTradeAt, then calculateCopySize scales it by
+// follower.CopyPercentage, clamped to MaxPositionSize. It's the default
+// MirrorStrategy, stateless aside from the Clock it evaluates filters
+// against.
+type ProportionalMirror struct {
+	clock Clock
+}
+
+// NewProportionalMirror builds a ProportionalMirror that evaluates
+// time-based CopyFilters against clock.
+func NewProportionalMirror(clock Clock) *ProportionalMirror {
+	return &ProportionalMirror{clock: clock}
+}
+
+// OnLeaderTrade implements MirrorStrategy.
+func (m *ProportionalMirror) OnLeaderTrade(ctx context.Context, leader string, trade models.TradeEvent, followers []*models.PermissionlessFollower) []OrderIntent {
+	now := m.clock.Now()
+
+	var intents []OrderIntent
+	for _, follower := range followers {
+		if !follower.IsActive {
+			continue
+		}
+		if approved, _ := shouldCopyTradeAt(follower, trade, now); !approved {
+			continue
+		}
+
+		size := calculateCopySize(follower, trade)
+		if size <= 0 {
+			continue
+		}
+
+		intents = append(intents, OrderIntent{
+			FollowerID: follower.ID,
+			Order:      copyOrder(trade, size),
+		})
+	}
+	return intents
+}
+
+// DeltaMirror tracks the leader's own inferred position per asset
+// (accumulated from the trade stream) and each follower's believed position
+// (accumulated from orders DeltaMirror itself has emitted), and on every
+// leader trade emits whatever order closes the gap between
+// follower.CopyPercentage of the leader's position and what the follower is
+// believed to hold. A trade a follower's Session fails to submit, or one
+// that happened while the engine was down, is absorbed into the next
+// event's delta instead of leaving the follower's position silently wrong
+// forever the way ProportionalMirror's per-event-only copy would.
+type DeltaMirror struct {
+	clock Clock
+
+	mu               sync.Mutex
+	leaderPosition   map[string]float64
+	followerPosition map[int]map[string]float64
+}
+
+// NewDeltaMirror builds an empty DeltaMirror that evaluates time-based
+// CopyFilters against clock.
+func NewDeltaMirror(clock Clock) *DeltaMirror {
+	return &DeltaMirror{
+		clock:            clock,
+		leaderPosition:   make(map[string]float64),
+		followerPosition: make(map[int]map[string]float64),
+	}
+}
+
+// OnLeaderTrade implements MirrorStrategy.
+func (m *DeltaMirror) OnLeaderTrade(ctx context.Context, leader string, trade models.TradeEvent, followers []*models.PermissionlessFollower) []OrderIntent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	size, _ := utils.ParseFloat(trade.Sz)
+	signedSize := size
+	if trade.Side != "B" {
+		signedSize = -size
+	}
+	m.leaderPosition[trade.Coin] += signedSize
+
+	now := m.clock.Now()
+	price, _ := utils.ParseFloat(trade.Px)
+
+	var intents []OrderIntent
+	for _, follower := range followers {
+		if !follower.IsActive {
+			continue
+		}
+		if approved, _ := shouldCopyTradeAt(follower, trade, now); !approved {
+			continue
+		}
+
+		positions, ok := m.followerPosition[follower.ID]
+		if !ok {
+			positions = make(map[string]float64)
+			m.followerPosition[follower.ID] = positions
+		}
+
+		target := m.leaderPosition[trade.Coin] * (follower.CopyPercentage / 100.0)
+		delta := target - positions[trade.Coin]
+		if delta == 0 {
+			continue
+		}
+
+		orderSize := math.Abs(delta)
+		isBuy := delta > 0
+
+		if price > 0 && follower.MaxPositionSize > 0 {
+			if maxSize := follower.MaxPositionSize / price; orderSize > maxSize {
+				orderSize = maxSize
+			}
+		}
+		if orderSize < follower.MinTradeSize {
+			continue
+		}
+
+		applied := orderSize
+		if !isBuy {
+			applied = -applied
+		}
+
+		coin := trade.Coin
+		intents = append(intents, OrderIntent{
+			FollowerID: follower.ID,
+			Order:      copyOrderSide(trade, orderSize, isBuy),
+			// Only advance the believed position once Router.Dispatch
+			// confirms this order actually went out -- a failed submission
+			// (rate limit, margin rejection, network failure) otherwise
+			// leaves positions ahead of reality, and the real gap it was
+			// supposed to close never gets picked up by a later delta.
+			OnResult: func(err error) {
+				if err != nil {
+					return
+				}
+				m.mu.Lock()
+				positions[coin] += applied
+				m.mu.Unlock()
+			},
+		})
+	}
+	return intents
+}
+
+// copyOrder builds the market IOC order ProportionalMirror submits, sized
+// at size and carrying trade's own side.
+func copyOrder(trade models.TradeEvent, size float64) *models.EnhancedOrderRequest {
+	return copyOrderSide(trade, size, trade.Side == "B")
+}
+
+// copyOrderSide is copyOrder with the side taken explicitly instead of read
+// off trade, since DeltaMirror's reconciled order can run opposite to the
+// leader's own side (e.g. the leader sells but a follower's position is
+// still under target).
+func copyOrderSide(trade models.TradeEvent, size float64, isBuy bool) *models.EnhancedOrderRequest {
+	price, _ := utils.ParseFloat(trade.Px)
+	return &models.EnhancedOrderRequest{
+		Asset:     trade.Coin,
+		IsBuy:     isBuy,
+		Size:      size,
+		Price:     &price,
+		OrderType: "market",
+		Tif:       "Ioc",
+	}
+}
@@ -2,112 +2,458 @@ package engine
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"hyperliquid-copy-trading/config"
 	"hyperliquid-copy-trading/internal/api"
+	"hyperliquid-copy-trading/internal/database"
 	"hyperliquid-copy-trading/internal/models"
+	"hyperliquid-copy-trading/internal/websocket"
+	"math/rand"
 	"sync"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog/log"
 )
 
 type OrderEngine struct {
-	config         *config.Config
-	hyperliquidAPI *api.HyperliquidAPI
-	orderQueue     chan *OrderBatch
-	shutdown       chan struct{}
-	wg             sync.WaitGroup
+	config       *config.Config
+	exchanges    map[string]ExchangeClient
+	wsManager    *websocket.Manager
+	stateStore   OrderStateStore
+	orderQueue   *orderPriorityQueue
+	queueMetrics *orderQueueMetrics
+	scheduler    *followerScheduler
+	shutdown     chan struct{}
+	wg           sync.WaitGroup
+
+	eventsMu sync.Mutex
+	events   []chan models.OrderState
+
+	// fillWaiters holds one chan models.OrderUpdate per in-flight oid,
+	// populated by consumeUserEvents and drained by monitorOrderStatus so a
+	// push notification resolves an order without waiting on the next REST
+	// poll.
+	fillWaiters sync.Map // map[int64]chan models.OrderUpdate
+	// subscribedWallets dedups the userEvents consumer goroutine started per
+	// follower API wallet address.
+	subscribedWallets sync.Map // map[string]struct{}
 }
 
+// OrderBatch is one unit of queued work: a set of orders paired with their
+// followers, tagged with the leader/asset/priority the priority queue uses
+// to order delivery and coalesce superseded batches.
 type OrderBatch struct {
-	Orders    []*models.OrderRequest
-	Followers []models.Follower
-	Timestamp time.Time
+	Orders        []*models.OrderRequest
+	Followers     []models.Follower
+	Timestamp     time.Time
+	LeaderAddress string
+	Asset         string
+	Priority      OrderPriority
 }
 
-func NewOrderEngine(cfg *config.Config, api *api.HyperliquidAPI) *OrderEngine {
+// NewOrderEngine builds an OrderEngine that dispatches each order to the
+// ExchangeClient registered in exchanges under its Follower.Venue (falling
+// back to models.DefaultVenue). Callers trading only Hyperliquid can pass
+// map[string]ExchangeClient{models.DefaultVenue: NewHyperliquidExchangeClient(...)}.
+func NewOrderEngine(cfg *config.Config, exchanges map[string]ExchangeClient, db *database.PostgresDB, wsManager *websocket.Manager) *OrderEngine {
+	queueMetrics := newOrderQueueMetrics()
+
 	engine := &OrderEngine{
-		config:         cfg,
-		hyperliquidAPI: api,
-		orderQueue:     make(chan *OrderBatch, 1000),
-		shutdown:       make(chan struct{}),
+		config:       cfg,
+		exchanges:    exchanges,
+		wsManager:    wsManager,
+		stateStore:   NewOrderStateStore(db),
+		orderQueue:   newOrderPriorityQueue(defaultQueueCapacity, queueMetrics),
+		queueMetrics: queueMetrics,
+		scheduler:    newFollowerScheduler(cfg.FollowerSchedulerWorkers),
+		shutdown:     make(chan struct{}),
 	}
 
 	// Start order processing worker
 	engine.wg.Add(1)
 	go engine.processOrders()
 
+	engine.resumeOpenOrders()
+
 	return engine
 }
 
-func (oe *OrderEngine) ExecuteBatch(ctx context.Context, orders []*models.OrderRequest, followers []models.Follower) {
+// exchangeFor resolves venue (defaulting to models.DefaultVenue for an empty
+// string, e.g. an OrderState persisted before Venue existed) to its
+// registered ExchangeClient.
+func (oe *OrderEngine) exchangeFor(venue string) (ExchangeClient, error) {
+	if venue == "" {
+		venue = models.DefaultVenue
+	}
+	client, ok := oe.exchanges[venue]
+	if !ok {
+		return nil, fmt.Errorf("no exchange client registered for venue %q", venue)
+	}
+	return client, nil
+}
+
+// resumeOpenOrders scans stateStore for orders a previous crash or restart
+// left in a non-terminal state and resumes monitoring each one. An order
+// that never reached a known exchange order id before the process died has
+// nothing left to poll for, so it's marked failed instead.
+func (oe *OrderEngine) resumeOpenOrders() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	open, err := oe.stateStore.OpenOrders(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load open order states for resume")
+		return
+	}
+
+	for _, state := range open {
+		if state.OID == nil {
+			oe.transition(context.Background(), state.ID, state.FollowerID, nil, models.OrderStatusFailed,
+				"order engine restarted before an order id was assigned")
+			continue
+		}
+
+		log.Info().
+			Int64("oid", *state.OID).
+			Int("follower_id", state.FollowerID).
+			Msg("Resuming order status monitoring after restart")
+
+		oe.wg.Add(1)
+		go func(s models.OrderState) {
+			defer oe.wg.Done()
+			waiter := oe.registerFillWaiter(s.Venue, s.APIWalletAddress, *s.OID)
+			oe.monitorOrderStatus(context.Background(), s.Venue, *s.OID, s.APIWalletAddress, s.FollowerID, s.ID, s.CreatedAt, waiter)
+		}(state)
+	}
+}
+
+// registerFillWaiter returns a channel that receives a models.OrderUpdate
+// the first time a userEvents push notification for oid arrives, starting
+// walletAddress's userEvents consumer goroutine on venue if this is the
+// first order being monitored for that venue/wallet pair.
+func (oe *OrderEngine) registerFillWaiter(venue, walletAddress string, oid int64) chan models.OrderUpdate {
+	ch := make(chan models.OrderUpdate, 1)
+	oe.fillWaiters.Store(oid, ch)
+	oe.ensureUserEventConsumer(venue, walletAddress)
+	return ch
+}
+
+// ensureUserEventConsumer subscribes to walletAddress's userEvents stream on
+// venue through that venue's ExchangeClient (a no-op if already subscribed)
+// and starts a goroutine routing incoming updates to the matching
+// fillWaiters entry, so monitorOrderStatus resolves a fill from the push
+// stream instead of always waiting for its next REST poll.
+func (oe *OrderEngine) ensureUserEventConsumer(venue, walletAddress string) {
+	client, err := oe.exchangeFor(venue)
+	if err != nil {
+		log.Error().Err(err).Str("wallet", walletAddress).Msg("Cannot start user event consumer")
+		return
+	}
+
+	key := venue + "|" + walletAddress
+	if _, alreadyRunning := oe.subscribedWallets.LoadOrStore(key, struct{}{}); alreadyRunning {
+		return
+	}
+
+	userChannel, err := client.SubscribeUserEvents(walletAddress)
+	if err != nil {
+		log.Error().Err(err).Str("wallet", walletAddress).Str("venue", venue).Msg("Failed to subscribe to user events for fill tracking")
+		oe.subscribedWallets.Delete(key)
+		return
+	}
+
+	oe.wg.Add(1)
+	go func() {
+		defer oe.wg.Done()
+		oe.consumeUserEvents(userChannel)
+	}()
+}
+
+func (oe *OrderEngine) consumeUserEvents(userChannel chan models.UserEvent) {
+	for {
+		select {
+		case <-oe.shutdown:
+			return
+		case event, ok := <-userChannel:
+			if !ok {
+				return
+			}
+			for _, update := range extractOrderUpdates(event) {
+				if ch, ok := oe.fillWaiters.LoadAndDelete(update.Oid); ok {
+					select {
+					case ch.(chan models.OrderUpdate) <- update:
+					default:
+					}
+				}
+			}
+		}
+	}
+}
+
+// extractOrderUpdates pulls any fill/cancel order updates out of a
+// userEvents message's raw payload, keyed by oid so OrderEngine's per-order
+// waiters can resolve them without polling GetOrderStatus.
+func extractOrderUpdates(event models.UserEvent) []models.OrderUpdate {
+	data, ok := event.Data.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	var updates []models.OrderUpdate
+
+	if fills, ok := data["fills"].([]interface{}); ok {
+		for _, raw := range fills {
+			fill, ok := raw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			oidFloat, ok := fill["oid"].(float64)
+			if !ok {
+				continue
+			}
+			updates = append(updates, models.OrderUpdate{
+				Oid:    int64(oidFloat),
+				Status: "filled",
+				Raw:    fill,
+			})
+		}
+	}
+
+	if cancel, ok := data["cancel"].(map[string]interface{}); ok {
+		if oidFloat, ok := cancel["oid"].(float64); ok {
+			updates = append(updates, models.OrderUpdate{
+				Oid:    int64(oidFloat),
+				Status: "cancelled",
+				Raw:    cancel,
+			})
+		}
+	}
+
+	return updates
+}
+
+// ExecuteBatch queues orders for leaderAddress at priority, coalescing into
+// an already-queued batch for the same leader/asset/priority if one hasn't
+// been sent yet. If the queue is full and there's nothing to coalesce into,
+// the batch is dropped (see ExecuteBatchWithDeadline for a blocking variant).
+func (oe *OrderEngine) ExecuteBatch(ctx context.Context, leaderAddress string, orders []*models.OrderRequest, followers []models.Follower, priority OrderPriority) {
 	batch := &OrderBatch{
-		Orders:    orders,
-		Followers: followers,
-		Timestamp: time.Now(),
+		Orders:        orders,
+		Followers:     followers,
+		Timestamp:     time.Now(),
+		LeaderAddress: leaderAddress,
+		Asset:         batchAsset(orders),
+		Priority:      priority,
 	}
 
-	select {
-	case oe.orderQueue <- batch:
-		log.Debug().Int("orders", len(orders)).Msg("Order batch queued")
-	default:
-		log.Warn().Msg("Order queue full, dropping batch")
+	if oe.orderQueue.tryPush(batch) {
+		oe.orderQueue.recordQueued(priority)
+		log.Debug().Int("orders", len(orders)).Str("priority", priority.String()).Msg("Order batch queued")
+		return
+	}
+
+	oe.orderQueue.recordDrop(priority)
+	log.Warn().Str("priority", priority.String()).Msg("Order queue full, dropping batch")
+}
+
+// ExecuteBatchWithDeadline behaves like ExecuteBatch, but blocks until the
+// batch is queued (waiting for backpressure to clear as older batches drain)
+// or ctx is done, instead of dropping it. Leader-event handlers that would
+// rather apply flow control than lose a signal during a volatility spike
+// should use this instead of ExecuteBatch.
+func (oe *OrderEngine) ExecuteBatchWithDeadline(ctx context.Context, leaderAddress string, orders []*models.OrderRequest, followers []models.Follower, priority OrderPriority) error {
+	batch := &OrderBatch{
+		Orders:        orders,
+		Followers:     followers,
+		Timestamp:     time.Now(),
+		LeaderAddress: leaderAddress,
+		Asset:         batchAsset(orders),
+		Priority:      priority,
+	}
+
+	for {
+		if oe.orderQueue.tryPush(batch) {
+			oe.orderQueue.recordQueued(priority)
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			oe.orderQueue.recordDrop(priority)
+			return ctx.Err()
+		case <-oe.orderQueue.spaceAvailable:
+		}
+	}
+}
+
+// batchAsset returns the asset orders share. ExecuteBatch is always called
+// with the orders generated for a single leader trade, so they're always for
+// the same asset and the first one is representative.
+func batchAsset(orders []*models.OrderRequest) string {
+	if len(orders) == 0 {
+		return ""
 	}
+	return orders[0].Asset
 }
 
 func (oe *OrderEngine) processOrders() {
 	defer oe.wg.Done()
 
 	for {
+		if batch := oe.orderQueue.pop(); batch != nil {
+			oe.processBatch(batch)
+			continue
+		}
+
 		select {
 		case <-oe.shutdown:
 			return
-		case batch := <-oe.orderQueue:
-			oe.processBatch(batch)
+		case <-oe.orderQueue.notify:
 		}
 	}
 }
 
 func (oe *OrderEngine) processBatch(batch *OrderBatch) {
 	ctx := context.Background()
-	
-	// Group orders by API wallet
+
+	walletGroups := groupByWallet(batch.Orders, batch.Followers, batch.Timestamp)
+
+	// Hand each wallet batch to the fairness scheduler instead of firing an
+	// unthrottled goroutine per wallet: under backpressure it's this, not
+	// Go's unordered map iteration, that decides which wallet's orders get
+	// a dispatch slot first.
+	for _, walletBatch := range walletGroups {
+		oe.wg.Add(1)
+		wb := walletBatch
+		oe.scheduler.submit(wb, wb.Tier, wb.RiskScore, wb.Arrival, func(wb *WalletBatch) {
+			defer oe.wg.Done()
+			oe.processWalletBatch(ctx, wb)
+		})
+	}
+}
+
+type WalletBatch struct {
+	APIWalletAddress string
+	Venue            string
+	Orders           []*models.OrderRequest
+	Followers        []models.Follower
+
+	// Tier, RiskScore and Arrival are this batch's fairness key for
+	// followerScheduler: the first follower's Tier (descending), then the
+	// first order's RiskScore (ascending), then Arrival (ascending) break
+	// ties, matching the (risk_score, follower_tier, arrival_time)
+	// ordering chunk7-5 asked for.
+	Tier      models.FollowerTier
+	RiskScore float64
+	Arrival   time.Time
+
+	// RetryCount counts how many times this batch has been re-submitted to
+	// oe.scheduler after Hyperliquid rate-limited every retry of its
+	// BatchOrders call. Zero for a batch's first dispatch attempt.
+	RetryCount int
+}
+
+// maxWalletBatchRetries bounds how many times processWalletBatch will
+// re-enqueue the same WalletBatch after it was rate-limited, before giving
+// up and marking its orders failed.
+const maxWalletBatchRetries = 5
+
+// groupByWallet buckets orders (paired with each order's follower by index)
+// into one WalletBatch per distinct (venue, APIWalletAddress) pair, so every
+// wallet's orders on a given venue can be nonced and submitted together as a
+// single BatchOrders call. arrival is stamped onto every resulting batch as
+// its scheduling arrival time.
+func groupByWallet(orders []*models.OrderRequest, followers []models.Follower, arrival time.Time) map[string]*WalletBatch {
 	walletGroups := make(map[string]*WalletBatch)
-	
-	for i, order := range batch.Orders {
-		if i >= len(batch.Followers) {
+
+	for i, order := range orders {
+		if i >= len(followers) {
 			continue
 		}
-		
-		follower := batch.Followers[i]
-		walletAddr := follower.APIWalletAddress
-		
-		if walletGroups[walletAddr] == nil {
-			walletGroups[walletAddr] = &WalletBatch{
-				APIWalletAddress: walletAddr,
-				Orders:          []*models.OrderRequest{},
-				Followers:       []models.Follower{},
+
+		follower := followers[i]
+		venue := follower.Venue
+		if venue == "" {
+			venue = models.DefaultVenue
+		}
+		key := venue + "|" + follower.APIWalletAddress
+
+		if walletGroups[key] == nil {
+			walletGroups[key] = &WalletBatch{
+				APIWalletAddress: follower.APIWalletAddress,
+				Venue:            venue,
+				Orders:           []*models.OrderRequest{},
+				Followers:        []models.Follower{},
+				Tier:             follower.Tier,
+				RiskScore:        order.RiskScore,
+				Arrival:          arrival,
 			}
 		}
-		
-		walletGroups[walletAddr].Orders = append(walletGroups[walletAddr].Orders, order)
-		walletGroups[walletAddr].Followers = append(walletGroups[walletAddr].Followers, follower)
+
+		walletGroups[key].Orders = append(walletGroups[key].Orders, order)
+		walletGroups[key].Followers = append(walletGroups[key].Followers, follower)
 	}
 
-	// Process each wallet batch
-	for _, walletBatch := range walletGroups {
+	return walletGroups
+}
+
+// ExecuteBatchStateless submits orders immediately via BatchOrders, grouped
+// and nonced the same way as the tracked ExecuteBatch path, but skips
+// OrderState persistence and monitorBatchStatus entirely. It's for
+// high-frequency followers or external systems that already track fills
+// through their own subscription and don't need the engine's per-order
+// GetOrderStatus polling. onResponse, if non-nil, is called once per wallet
+// group with its raw BatchOrders response (or the error BatchOrders
+// returned) as soon as it acknowledges.
+func (oe *OrderEngine) ExecuteBatchStateless(ctx context.Context, orders []*models.OrderRequest, followers []models.Follower, onResponse func(walletAddress string, response *models.OrderResponse, err error)) {
+	for _, wb := range groupByWallet(orders, followers, time.Now()) {
 		oe.wg.Add(1)
 		go func(wb *WalletBatch) {
 			defer oe.wg.Done()
-			oe.processWalletBatch(ctx, wb)
-		}(walletBatch)
+			oe.executeWalletBatchStateless(ctx, wb, onResponse)
+		}(wb)
 	}
 }
 
-type WalletBatch struct {
-	APIWalletAddress string
-	Orders          []*models.OrderRequest
-	Followers       []models.Follower
+func (oe *OrderEngine) executeWalletBatchStateless(ctx context.Context, batch *WalletBatch, onResponse func(string, *models.OrderResponse, error)) {
+	if len(batch.Orders) == 0 {
+		return
+	}
+
+	nonce := time.Now().UnixMilli()
+	for _, order := range batch.Orders {
+		order.Nonce = nonce
+	}
+
+	log.Info().
+		Str("wallet", batch.APIWalletAddress).
+		Str("venue", batch.Venue).
+		Int("orders", len(batch.Orders)).
+		Int64("nonce", nonce).
+		Msg("Processing stateless wallet batch")
+
+	client, err := oe.exchangeFor(batch.Venue)
+	if err != nil {
+		log.Error().Err(err).Str("wallet", batch.APIWalletAddress).Msg("Failed to resolve exchange for stateless batch")
+		if onResponse != nil {
+			onResponse(batch.APIWalletAddress, nil, err)
+		}
+		return
+	}
+
+	response, err := client.BatchOrders(ctx, batch.Orders, batch.APIWalletAddress, nonce)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("wallet", batch.APIWalletAddress).
+			Msg("Failed to execute stateless batch orders")
+	}
+
+	if onResponse != nil {
+		onResponse(batch.APIWalletAddress, response, err)
+	}
 }
 
 func (oe *OrderEngine) processWalletBatch(ctx context.Context, batch *WalletBatch) {
@@ -125,20 +471,38 @@ func (oe *OrderEngine) processWalletBatch(ctx context.Context, batch *WalletBatc
 
 	log.Info().
 		Str("wallet", batch.APIWalletAddress).
+		Str("venue", batch.Venue).
 		Int("orders", len(batch.Orders)).
 		Int64("nonce", nonce).
 		Msg("Processing wallet batch")
 
+	client, err := oe.exchangeFor(batch.Venue)
+	if err != nil {
+		log.Error().Err(err).Str("wallet", batch.APIWalletAddress).Msg("Failed to resolve exchange for batch")
+		oe.markOrdersStatus(ctx, batch, models.OrderStatusFailed, err.Error())
+		return
+	}
+
 	// Execute batch order
-	response, err := oe.hyperliquidAPI.BatchOrders(ctx, batch.Orders, batch.APIWalletAddress, nonce)
+	response, err := client.BatchOrders(ctx, batch.Orders, batch.APIWalletAddress, nonce)
 	if err != nil {
+		if errors.Is(err, api.ErrRateLimitExhausted) {
+			log.Warn().
+				Err(err).
+				Str("wallet", batch.APIWalletAddress).
+				Int("retry_count", batch.RetryCount).
+				Msg("Batch orders rate-limited, rescheduling")
+			oe.rescheduleRateLimited(ctx, batch, err)
+			return
+		}
+
 		log.Error().
 			Err(err).
 			Str("wallet", batch.APIWalletAddress).
 			Msg("Failed to execute batch orders")
-		
+
 		// Mark all orders as failed
-		oe.markOrdersStatus(batch, "failed", err.Error())
+		oe.markOrdersStatus(ctx, batch, models.OrderStatusFailed, err.Error())
 		return
 	}
 
@@ -148,88 +512,170 @@ func (oe *OrderEngine) processWalletBatch(ctx context.Context, batch *WalletBatc
 			Str("wallet", batch.APIWalletAddress).
 			Int("orders", len(batch.Orders)).
 			Msg("Batch orders executed successfully")
-		
-		oe.markOrdersStatus(batch, "submitted", "")
-		
+
+		states := oe.markOrdersStatus(ctx, batch, models.OrderStatusSubmitted, "")
+
 		// Start monitoring order status
 		oe.wg.Add(1)
 		go func() {
 			defer oe.wg.Done()
-			oe.monitorBatchStatus(ctx, batch, response)
+			oe.monitorBatchStatus(ctx, batch, response, states)
 		}()
 	} else {
 		log.Error().
 			Str("wallet", batch.APIWalletAddress).
 			Interface("response", response).
 			Msg("Batch order execution failed")
-		
-		oe.markOrdersStatus(batch, "failed", "API returned error status")
+
+		oe.markOrdersStatus(ctx, batch, models.OrderStatusFailed, "API returned error status")
 	}
 }
 
-func (oe *OrderEngine) markOrdersStatus(batch *WalletBatch, status string, errorMsg string) {
-	// In a real implementation, you would update the database here
-	for i, order := range batch.Orders {
-		if i < len(batch.Followers) {
-			follower := batch.Followers[i]
-			log.Debug().
-				Int("follower_id", follower.ID).
-				Str("status", status).
-				Str("asset", order.Asset).
-				Float64("size", order.Size).
-				Str("error", errorMsg).
-				Msg("Order status updated")
+// rescheduleRateLimited handles a BatchOrders call that came back wrapping
+// api.ErrRateLimitExhausted: rather than marking batch's orders failed, it
+// backs off and re-submits the same *WalletBatch to oe.scheduler so
+// RetryCount carries over and the batch doesn't re-run risk/position-size
+// logic through groupByWallet. Once RetryCount exceeds
+// maxWalletBatchRetries, it gives up and marks the batch failed like any
+// other unrecoverable error.
+func (oe *OrderEngine) rescheduleRateLimited(ctx context.Context, batch *WalletBatch, cause error) {
+	batch.RetryCount++
+	if batch.RetryCount > maxWalletBatchRetries {
+		log.Error().
+			Str("wallet", batch.APIWalletAddress).
+			Int("retry_count", batch.RetryCount).
+			Msg("Exhausted rate-limit re-enqueue attempts, failing batch")
+		oe.markOrdersStatus(ctx, batch, models.OrderStatusFailed, cause.Error())
+		if oe.queueMetrics != nil {
+			oe.queueMetrics.recordRateLimitDrop()
 		}
+		return
 	}
-}
 
-func (oe *OrderEngine) monitorBatchStatus(ctx context.Context, batch *WalletBatch, response *models.HyperliquidAPIResponse) {
-	// Extract order IDs from response
-	data, ok := response.Data.(map[string]interface{})
-	if !ok {
-		log.Error().Msg("Invalid response data format")
-		return
+	if oe.queueMetrics != nil {
+		oe.queueMetrics.recordRateLimitRequeue()
 	}
 
-	statuses, ok := data["statuses"].([]interface{})
-	if !ok {
-		log.Error().Msg("No order statuses in response")
-		return
+	delay := rateLimitBackoff(batch.RetryCount - 1)
+
+	oe.wg.Add(1)
+	go func() {
+		defer oe.wg.Done()
+		select {
+		case <-oe.shutdown:
+			return
+		case <-time.After(delay):
+		}
+
+		oe.wg.Add(1)
+		oe.scheduler.submit(batch, batch.Tier, batch.RiskScore, batch.Arrival, func(wb *WalletBatch) {
+			defer oe.wg.Done()
+			oe.processWalletBatch(ctx, wb)
+		})
+	}()
+}
+
+// rateLimitBackoff mirrors rateLimitedClient.backoff's exponential-with-
+// jitter shape (unexported in the api package), scaled to whole seconds
+// since a rate-limited wallet batch needs to back off far longer than a
+// single HTTP retry.
+func rateLimitBackoff(attempt int) time.Duration {
+	base := time.Duration(1<<uint(attempt)) * time.Second
+	jitter := time.Duration(rand.Int63n(int64(base)/2 + 1))
+	return base + jitter
+}
+
+// markOrdersStatus persists a new OrderState row for every order in batch at
+// status, publishing each to SubscribeOrderEvents, and returns the created
+// states parallel to batch.Orders (nil where a follower was missing) so a
+// caller can later transition them by id once more is known (e.g. an oid).
+func (oe *OrderEngine) markOrdersStatus(ctx context.Context, batch *WalletBatch, status models.OrderStatus, errorMsg string) []*models.OrderState {
+	states := make([]*models.OrderState, len(batch.Orders))
+
+	for i, order := range batch.Orders {
+		if i >= len(batch.Followers) {
+			continue
+		}
+		follower := batch.Followers[i]
+
+		state := &models.OrderState{
+			FollowerID:       follower.ID,
+			APIWalletAddress: batch.APIWalletAddress,
+			Venue:            batch.Venue,
+			Asset:            order.Asset,
+			Nonce:            order.Nonce,
+			Status:           status,
+			ErrorMessage:     errorMsg,
+		}
+		if err := oe.stateStore.Create(ctx, state); err != nil {
+			log.Error().Err(err).Msg("Failed to persist order state")
+		}
+		states[i] = state
+		oe.publish(*state)
+
+		log.Debug().
+			Int("follower_id", follower.ID).
+			Str("status", string(status)).
+			Str("asset", order.Asset).
+			Float64("size", order.Size).
+			Str("error", errorMsg).
+			Msg("Order status updated")
 	}
 
+	return states
+}
+
+func (oe *OrderEngine) monitorBatchStatus(ctx context.Context, batch *WalletBatch, response *models.OrderResponse, states []*models.OrderState) {
 	// Monitor each order
-	for i, statusData := range statuses {
-		if i >= len(batch.Orders) || i >= len(batch.Followers) {
+	for i, status := range response.Data.Statuses {
+		if i >= len(batch.Orders) || i >= len(batch.Followers) || i >= len(states) {
 			continue
 		}
 
-		statusMap, ok := statusData.(map[string]interface{})
-		if !ok {
+		if status.Resting == nil {
 			continue
 		}
 
-		// Extract order ID if available
-		if resting, ok := statusMap["resting"].(map[string]interface{}); ok {
-			if oidFloat, exists := resting["oid"].(float64); exists {
-				oid := int64(oidFloat)
-				follower := batch.Followers[i]
-				
-				oe.wg.Add(1)
-				go func(orderID int64, f models.Follower) {
-					defer oe.wg.Done()
-					oe.monitorOrderStatus(ctx, orderID, f.APIWalletAddress, f.ID)
-				}(oid, follower)
-			}
+		oid := status.Resting.Oid
+		follower := batch.Followers[i]
+		state := states[i]
+
+		var stateID int64
+		if state != nil {
+			oe.transition(ctx, state.ID, follower.ID, &oid, models.OrderStatusResting, "")
+			stateID = state.ID
 		}
+
+		waiter := oe.registerFillWaiter(batch.Venue, follower.APIWalletAddress, oid)
+
+		oe.wg.Add(1)
+		go func(orderID int64, f models.Follower, sID int64, w chan models.OrderUpdate) {
+			defer oe.wg.Done()
+			oe.monitorOrderStatus(ctx, batch.Venue, orderID, f.APIWalletAddress, f.ID, sID, batch.Arrival, w)
+		}(oid, follower, stateID, waiter)
 	}
 }
 
-func (oe *OrderEngine) monitorOrderStatus(ctx context.Context, orderID int64, walletAddress string, followerID int) {
+// monitorOrderStatus resolves orderID to a terminal status, preferring a
+// push notification on waiter (populated by consumeUserEvents from the
+// follower's userEvents stream) over the REST GetOrderStatus poll that
+// otherwise runs every 10 seconds as a fallback for a missed or delayed
+// push. arrival is the originating WalletBatch's arrival time, threaded
+// through purely so a terminal fill can be timed against it for
+// GetQueueStatus's p50/p99 leader-fill-to-follower-fill latency.
+func (oe *OrderEngine) monitorOrderStatus(ctx context.Context, venue string, orderID int64, walletAddress string, followerID int, stateID int64, arrival time.Time, waiter chan models.OrderUpdate) {
 	maxAttempts := 30 // 5 minutes with 10-second intervals
 	attempt := 0
 
 	ticker := time.NewTicker(10 * time.Second)
 	defer ticker.Stop()
+	defer oe.fillWaiters.Delete(orderID)
+
+	client, err := oe.exchangeFor(venue)
+	if err != nil {
+		log.Error().Err(err).Int64("oid", orderID).Msg("Cannot monitor order status")
+		return
+	}
 
 	for {
 		select {
@@ -237,10 +683,13 @@ func (oe *OrderEngine) monitorOrderStatus(ctx context.Context, orderID int64, wa
 			return
 		case <-oe.shutdown:
 			return
+		case update := <-waiter:
+			oe.handleOrderUpdate(ctx, update, followerID, stateID, arrival)
+			return
 		case <-ticker.C:
 			attempt++
-			
-			status, err := oe.hyperliquidAPI.GetOrderStatus(ctx, walletAddress, orderID)
+
+			status, err := client.GetOrderStatus(ctx, walletAddress, orderID)
 			if err != nil {
 				log.Error().
 					Err(err).
@@ -262,19 +711,19 @@ func (oe *OrderEngine) monitorOrderStatus(ctx context.Context, orderID int64, wa
 						Int64("oid", orderID).
 						Int("follower_id", followerID).
 						Msg("Order filled successfully")
-					
+
 					// Update trade status in database
-					oe.updateTradeStatus(followerID, orderID, "filled", status)
+					oe.updateTradeStatus(ctx, stateID, followerID, orderID, models.OrderStatusFilled, status, arrival)
 					return
-					
+
 				case "cancelled", "rejected":
 					log.Warn().
 						Int64("oid", orderID).
 						Int("follower_id", followerID).
 						Str("status", orderStatus).
 						Msg("Order cancelled or rejected")
-					
-					oe.updateTradeStatus(followerID, orderID, orderStatus, status)
+
+					oe.updateTradeStatus(ctx, stateID, followerID, orderID, models.OrderStatus(orderStatus), status, arrival)
 					return
 				}
 			}
@@ -290,18 +739,99 @@ func (oe *OrderEngine) monitorOrderStatus(ctx context.Context, orderID int64, wa
 	}
 }
 
-func (oe *OrderEngine) updateTradeStatus(followerID int, orderID int64, status string, orderData map[string]interface{}) {
-	// In a real implementation, update the database here
+// handleOrderUpdate resolves a push notification from consumeUserEvents the
+// same way monitorOrderStatus's REST poll resolves a terminal GetOrderStatus
+// response. arrival is passed through to updateTradeStatus for fill-latency
+// tracking.
+func (oe *OrderEngine) handleOrderUpdate(ctx context.Context, update models.OrderUpdate, followerID int, stateID int64, arrival time.Time) {
+	switch update.Status {
+	case "filled":
+		log.Info().
+			Int64("oid", update.Oid).
+			Int("follower_id", followerID).
+			Msg("Order filled successfully (push)")
+		oe.updateTradeStatus(ctx, stateID, followerID, update.Oid, models.OrderStatusFilled, update.Raw, arrival)
+	case "cancelled", "rejected":
+		log.Warn().
+			Int64("oid", update.Oid).
+			Int("follower_id", followerID).
+			Str("status", update.Status).
+			Msg("Order cancelled or rejected (push)")
+		oe.updateTradeStatus(ctx, stateID, followerID, update.Oid, models.OrderStatus(update.Status), update.Raw, arrival)
+	default:
+		log.Debug().Int64("oid", update.Oid).Str("status", update.Status).Msg("Unhandled push order update status")
+	}
+}
+
+// updateTradeStatus persists orderID's terminal status and, when status is
+// OrderStatusFilled and arrival is known, records the leader-fill-to-
+// follower-fill latency backing GetQueueStatus's p50/p99.
+func (oe *OrderEngine) updateTradeStatus(ctx context.Context, stateID int64, followerID int, orderID int64, status models.OrderStatus, orderData map[string]interface{}, arrival time.Time) {
+	oe.transition(ctx, stateID, followerID, &orderID, status, "")
+
+	if status == models.OrderStatusFilled && !arrival.IsZero() && oe.queueMetrics != nil {
+		oe.queueMetrics.recordFillLatency(time.Since(arrival))
+	}
+
 	log.Debug().
 		Int("follower_id", followerID).
 		Int64("oid", orderID).
-		Str("status", status).
+		Str("status", string(status)).
 		Interface("order_data", orderData).
 		Msg("Trade status updated")
 }
 
+// transition persists status (and oid/errorMsg when provided) for an
+// in-flight order's state record identified by stateID, then publishes the
+// update to every SubscribeOrderEvents subscriber.
+func (oe *OrderEngine) transition(ctx context.Context, stateID int64, followerID int, oid *int64, status models.OrderStatus, errorMsg string) {
+	if err := oe.stateStore.UpdateStatus(ctx, stateID, status, oid, errorMsg); err != nil {
+		log.Error().Err(err).Int64("state_id", stateID).Msg("Failed to persist order state transition")
+	}
+
+	oe.publish(models.OrderState{
+		ID:           stateID,
+		FollowerID:   followerID,
+		OID:          oid,
+		Status:       status,
+		ErrorMessage: errorMsg,
+		UpdatedAt:    time.Now(),
+	})
+}
+
+// SubscribeOrderEvents returns a channel that receives every order state
+// transition OrderEngine makes, so another subsystem (e.g. a live dashboard)
+// can follow an order's lifecycle without polling the database.
+func (oe *OrderEngine) SubscribeOrderEvents() <-chan models.OrderState {
+	ch := make(chan models.OrderState, 64)
+	oe.eventsMu.Lock()
+	oe.events = append(oe.events, ch)
+	oe.eventsMu.Unlock()
+	return ch
+}
+
+func (oe *OrderEngine) publish(state models.OrderState) {
+	oe.eventsMu.Lock()
+	subs := append([]chan models.OrderState(nil), oe.events...)
+	oe.eventsMu.Unlock()
+
+	for _, ch := range subs {
+		select {
+		case ch <- state:
+		default:
+			// A slow subscriber shouldn't stall order processing; it'll pick
+			// up a fresher state on the next transition.
+		}
+	}
+}
+
 func (oe *OrderEngine) ExecuteSingleOrder(ctx context.Context, order *models.OrderRequest, follower *models.Follower) error {
-	response, err := oe.hyperliquidAPI.PlaceOrder(ctx, order, follower.APIWalletAddress)
+	client, err := oe.exchangeFor(follower.Venue)
+	if err != nil {
+		return err
+	}
+
+	response, err := client.PlaceOrder(ctx, order, follower.APIWalletAddress)
 	if err != nil {
 		return err
 	}
@@ -319,9 +849,73 @@ func (oe *OrderEngine) ExecuteSingleOrder(ctx context.Context, order *models.Ord
 	return nil
 }
 
-func (oe *OrderEngine) CancelOrder(ctx context.Context, asset string, orderID int64, walletAddress string) error {
+// ExecuteBracketOrder places a contingent entry + TP/SL order built by
+// models.NewBracketOrder. The entry is placed first; on a fill, its children
+// are placed immediately afterward so the follower's StopLossPercentage /
+// TakeProfitPercentage are enforced atomically rather than as separate
+// follow-up orders issued later by the engine. venue's ExchangeClient must
+// also implement EnhancedOrderClient, since brackets need the LinkID/
+// Contingency/Children fields a plain ExchangeClient doesn't carry.
+func (oe *OrderEngine) ExecuteBracketOrder(ctx context.Context, venue string, bracket models.EnhancedOrderRequest, apiWalletAddress string) (*models.OrderResponse, error) {
+	client, err := oe.exchangeFor(venue)
+	if err != nil {
+		return nil, err
+	}
+	enhancedClient, ok := client.(EnhancedOrderClient)
+	if !ok {
+		return nil, fmt.Errorf("venue %q does not support bracket orders", venue)
+	}
+
+	response, err := enhancedClient.PlaceEnhancedOrder(ctx, &bracket, apiWalletAddress)
+	if err != nil {
+		return nil, fmt.Errorf("bracket entry failed: %w", err)
+	}
+
+	if len(bracket.Children) == 0 {
+		return response, nil
+	}
+
+	entryFilled := false
+	for _, status := range response.Data.Statuses {
+		if status.Filled != nil {
+			entryFilled = true
+			break
+		}
+	}
+
+	if bracket.Contingency == models.ContingencyOTO || bracket.Contingency == models.ContingencyOTOCO {
+		if !entryFilled {
+			log.Debug().
+				Str("link_id", bracket.LinkID).
+				Msg("Bracket entry resting, children deferred until fill")
+			return response, nil
+		}
+	}
+
+	for _, child := range bracket.Children {
+		childResp, err := enhancedClient.PlaceEnhancedOrder(ctx, &child, apiWalletAddress)
+		if err != nil {
+			log.Error().
+				Err(err).
+				Str("link_id", bracket.LinkID).
+				Str("asset", child.Asset).
+				Msg("Failed to place bracket child order")
+			continue
+		}
+		response.Data.Statuses = append(response.Data.Statuses, childResp.Data.Statuses...)
+	}
+
+	return response, nil
+}
+
+func (oe *OrderEngine) CancelOrder(ctx context.Context, venue, asset string, orderID int64, walletAddress string) error {
+	client, err := oe.exchangeFor(venue)
+	if err != nil {
+		return err
+	}
+
 	nonce := time.Now().UnixMilli()
-	response, err := oe.hyperliquidAPI.CancelOrder(ctx, asset, orderID, walletAddress, nonce)
+	response, err := client.CancelOrder(ctx, asset, orderID, walletAddress, nonce)
 	if err != nil {
 		return err
 	}
@@ -336,32 +930,41 @@ func (oe *OrderEngine) CancelOrder(ctx context.Context, asset string, orderID in
 func (oe *OrderEngine) Stop() {
 	close(oe.shutdown)
 	oe.wg.Wait()
+	oe.scheduler.stop()
 }
 
-// GetQueueStatus returns current queue statistics
+// GetQueueStatus returns current queue statistics, including per-priority
+// depths, per-priority drop counters, and the age of the oldest queued
+// batch.
 func (oe *OrderEngine) GetQueueStatus() map[string]interface{} {
-	return map[string]interface{}{
-		"queue_length": len(oe.orderQueue),
-		"queue_capacity": cap(oe.orderQueue),
-	}
+	return oe.orderQueue.status()
+}
+
+// MetricsRegistry returns the Prometheus registry backing the order queue's
+// queued/dropped counters and depth gauges, for a caller to expose on a
+// metrics endpoint.
+func (oe *OrderEngine) MetricsRegistry() *prometheus.Registry {
+	return oe.queueMetrics.registry
 }
 
 // FlushQueue processes all remaining orders in queue
 func (oe *OrderEngine) FlushQueue(ctx context.Context) {
 	log.Info().Msg("Flushing order queue")
-	
+
 	timeout := time.After(30 * time.Second)
-	
+
 	for {
 		select {
 		case <-timeout:
 			log.Warn().Msg("Queue flush timeout")
 			return
-		case batch := <-oe.orderQueue:
-			oe.processBatch(batch)
 		default:
-			log.Info().Msg("Order queue flushed")
-			return
+			batch := oe.orderQueue.pop()
+			if batch == nil {
+				log.Info().Msg("Order queue flushed")
+				return
+			}
+			oe.processBatch(batch)
 		}
 	}
 }
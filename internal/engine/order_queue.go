@@ -0,0 +1,312 @@
+package engine
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// OrderPriority ranks which batches OrderEngine's queue drains first: lower
+// values are serviced before higher ones. Market and stop-loss orders race
+// the clock against slippage or an ongoing loss, so they jump ahead of
+// routine rebalancing batches when the queue is under load.
+type OrderPriority int
+
+const (
+	PriorityMarket OrderPriority = iota
+	PriorityStopLoss
+	PriorityNormal
+	PriorityRebalance
+	numPriorities
+)
+
+// String renders p for logging and as a Prometheus label value.
+func (p OrderPriority) String() string {
+	switch p {
+	case PriorityMarket:
+		return "market"
+	case PriorityStopLoss:
+		return "stop_loss"
+	case PriorityNormal:
+		return "normal"
+	case PriorityRebalance:
+		return "rebalance"
+	default:
+		return "unknown"
+	}
+}
+
+// defaultQueueCapacity bounds the total number of batches held across every
+// priority level, matching the capacity of the single channel this queue
+// replaces.
+const defaultQueueCapacity = 1000
+
+// orderQueueMetrics are the Prometheus series backing GetQueueStatus, scoped
+// to their own registry rather than the global default so more than one
+// OrderEngine in the same process doesn't collide on registration.
+type orderQueueMetrics struct {
+	registry         *prometheus.Registry
+	queued           *prometheus.CounterVec
+	dropped          *prometheus.CounterVec
+	depth            *prometheus.GaugeVec
+	rateLimitRetry   prometheus.Counter
+	rateLimitDropped prometheus.Counter
+
+	// rateLimitRetryCount and rateLimitDroppedCount mirror rateLimitRetry/
+	// rateLimitDropped as plain counters, since a prometheus.Counter doesn't
+	// expose its current value and GetQueueStatus needs one to report
+	// alongside the existing plain dropped[priority] counters.
+	rateLimitRetryCount   uint64
+	rateLimitDroppedCount uint64
+
+	// fillLatencies is a bounded ring buffer of leader-fill-to-follower-fill
+	// durations, used to compute GetOrderQueueStatus's p50/p99 without
+	// pulling in a Prometheus histogram quantile dependency for what's
+	// fundamentally a small, cheap in-memory rollup.
+	latencyMu       sync.Mutex
+	fillLatencies   []time.Duration
+	fillLatencyNext int
+}
+
+const maxTrackedFillLatencies = 500
+
+func newOrderQueueMetrics() *orderQueueMetrics {
+	m := &orderQueueMetrics{
+		registry: prometheus.NewRegistry(),
+		queued: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "order_queue_batches_queued_total",
+			Help: "Order batches successfully enqueued, by priority.",
+		}, []string{"priority"}),
+		dropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "order_queue_batches_dropped_total",
+			Help: "Order batches dropped because the queue was full, by priority.",
+		}, []string{"priority"}),
+		depth: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "order_queue_depth",
+			Help: "Current number of queued batches, by priority.",
+		}, []string{"priority"}),
+		rateLimitRetry: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "order_queue_rate_limit_requeued_total",
+			Help: "Wallet batches re-enqueued after Hyperliquid rate-limited every retry.",
+		}),
+		rateLimitDropped: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "order_queue_rate_limit_dropped_total",
+			Help: "Wallet batches dropped after exhausting rate-limit re-enqueue attempts.",
+		}),
+	}
+	m.registry.MustRegister(m.queued, m.dropped, m.depth, m.rateLimitRetry, m.rateLimitDropped)
+	return m
+}
+
+// recordRateLimitRequeue counts one WalletBatch sent back through the
+// scheduler after Hyperliquid rate-limited every retry of its BatchOrders
+// call.
+func (m *orderQueueMetrics) recordRateLimitRequeue() {
+	m.rateLimitRetry.Inc()
+	atomic.AddUint64(&m.rateLimitRetryCount, 1)
+}
+
+// recordRateLimitDrop counts one WalletBatch given up on after exhausting
+// its rate-limit re-enqueue attempts.
+func (m *orderQueueMetrics) recordRateLimitDrop() {
+	m.rateLimitDropped.Inc()
+	atomic.AddUint64(&m.rateLimitDroppedCount, 1)
+}
+
+// recordFillLatency records the time between a leader trade landing and one
+// of its follower orders being filled, backing
+// GetOrderQueueStatus's p50/p99.
+func (m *orderQueueMetrics) recordFillLatency(d time.Duration) {
+	m.latencyMu.Lock()
+	defer m.latencyMu.Unlock()
+
+	if len(m.fillLatencies) < maxTrackedFillLatencies {
+		m.fillLatencies = append(m.fillLatencies, d)
+		return
+	}
+	m.fillLatencies[m.fillLatencyNext] = d
+	m.fillLatencyNext = (m.fillLatencyNext + 1) % maxTrackedFillLatencies
+}
+
+// latencyPercentiles returns the p50 and p99 (in seconds) of the most
+// recently recorded fill latencies, or (0, 0) if none have been recorded
+// yet.
+func (m *orderQueueMetrics) latencyPercentiles() (p50, p99 float64) {
+	m.latencyMu.Lock()
+	samples := append([]time.Duration(nil), m.fillLatencies...)
+	m.latencyMu.Unlock()
+
+	if len(samples) == 0 {
+		return 0, 0
+	}
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+
+	percentile := func(p float64) float64 {
+		idx := int(p * float64(len(samples)-1))
+		return samples[idx].Seconds()
+	}
+
+	return percentile(0.50), percentile(0.99)
+}
+
+// orderQueueCoalesceKey groups batches that should replace one another
+// rather than both being delivered: a new batch for the same leader, asset
+// and priority supersedes whatever hasn't been sent yet.
+func orderQueueCoalesceKey(batch *OrderBatch) string {
+	return batch.LeaderAddress + "|" + batch.Asset + "|" + batch.Priority.String()
+}
+
+// orderPriorityQueue is OrderEngine's replacement for a single
+// `chan *OrderBatch`: it drains PriorityMarket before PriorityStopLoss
+// before PriorityNormal before PriorityRebalance, and coalesces a batch
+// arriving for a leader/asset/priority that's already queued instead of
+// enqueueing both.
+type orderPriorityQueue struct {
+	mu       sync.Mutex
+	levels   [numPriorities][]*OrderBatch
+	pending  map[string]*OrderBatch // orderQueueCoalesceKey -> the queued *OrderBatch it would merge into
+	capacity int
+	size     int
+	dropped  [numPriorities]uint64
+
+	notify         chan struct{} // signaled (non-blocking) on every successful push
+	spaceAvailable chan struct{} // signaled (non-blocking) on every pop
+
+	metrics *orderQueueMetrics
+}
+
+func newOrderPriorityQueue(capacity int, metrics *orderQueueMetrics) *orderPriorityQueue {
+	return &orderPriorityQueue{
+		pending:        make(map[string]*OrderBatch),
+		capacity:       capacity,
+		notify:         make(chan struct{}, 1),
+		spaceAvailable: make(chan struct{}, 1),
+		metrics:        metrics,
+	}
+}
+
+// tryPush enqueues batch, coalescing into an already-queued batch for the
+// same leader/asset/priority if one exists, or returns false if the queue
+// is at capacity and there is nothing to coalesce into.
+func (q *orderPriorityQueue) tryPush(batch *OrderBatch) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	key := orderQueueCoalesceKey(batch)
+	if existing, ok := q.pending[key]; ok {
+		existing.Orders = batch.Orders
+		existing.Followers = batch.Followers
+		existing.Timestamp = batch.Timestamp
+		q.signal(q.notify)
+		return true
+	}
+
+	if q.size >= q.capacity {
+		return false
+	}
+
+	q.levels[batch.Priority] = append(q.levels[batch.Priority], batch)
+	q.pending[key] = batch
+	q.size++
+	if q.metrics != nil {
+		q.metrics.depth.WithLabelValues(batch.Priority.String()).Inc()
+	}
+	q.signal(q.notify)
+	return true
+}
+
+// pop removes and returns the oldest batch from the highest-priority
+// non-empty level, or nil if the queue is empty.
+func (q *orderPriorityQueue) pop() *OrderBatch {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for p := OrderPriority(0); p < numPriorities; p++ {
+		if len(q.levels[p]) == 0 {
+			continue
+		}
+
+		batch := q.levels[p][0]
+		q.levels[p] = q.levels[p][1:]
+		delete(q.pending, orderQueueCoalesceKey(batch))
+		q.size--
+		if q.metrics != nil {
+			q.metrics.depth.WithLabelValues(p.String()).Dec()
+		}
+		q.signal(q.spaceAvailable)
+		return batch
+	}
+	return nil
+}
+
+func (q *orderPriorityQueue) recordDrop(priority OrderPriority) {
+	q.mu.Lock()
+	q.dropped[priority]++
+	q.mu.Unlock()
+
+	if q.metrics != nil {
+		q.metrics.dropped.WithLabelValues(priority.String()).Inc()
+	}
+}
+
+func (q *orderPriorityQueue) recordQueued(priority OrderPriority) {
+	if q.metrics != nil {
+		q.metrics.queued.WithLabelValues(priority.String()).Inc()
+	}
+}
+
+func (q *orderPriorityQueue) signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// status reports per-priority queue depth, drop counters and the age of the
+// oldest still-queued batch, backing GetQueueStatus.
+func (q *orderPriorityQueue) status() map[string]interface{} {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	depths := make(map[string]int, numPriorities)
+	dropped := make(map[string]uint64, numPriorities)
+	var oldest time.Time
+
+	for p := OrderPriority(0); p < numPriorities; p++ {
+		depths[p.String()] = len(q.levels[p])
+		dropped[p.String()] = q.dropped[p]
+		if len(q.levels[p]) > 0 {
+			ts := q.levels[p][0].Timestamp
+			if oldest.IsZero() || ts.Before(oldest) {
+				oldest = ts
+			}
+		}
+	}
+
+	oldestAge := 0.0
+	if !oldest.IsZero() {
+		oldestAge = time.Since(oldest).Seconds()
+	}
+
+	result := map[string]interface{}{
+		"queue_length":             q.size,
+		"queue_capacity":           q.capacity,
+		"depth_by_priority":        depths,
+		"dropped_by_priority":      dropped,
+		"oldest_batch_age_seconds": oldestAge,
+	}
+
+	if q.metrics != nil {
+		p50, p99 := q.metrics.latencyPercentiles()
+		result["rate_limit_requeued"] = atomic.LoadUint64(&q.metrics.rateLimitRetryCount)
+		result["rate_limit_dropped"] = atomic.LoadUint64(&q.metrics.rateLimitDroppedCount)
+		result["fill_latency_p50_seconds"] = p50
+		result["fill_latency_p99_seconds"] = p99
+	}
+
+	return result
+}
@@ -0,0 +1,49 @@
+package engine
+
+import (
+	"context"
+
+	"hyperliquid-copy-trading/internal/database"
+	"hyperliquid-copy-trading/internal/models"
+)
+
+// OrderStateStore persists OrderEngine's order lifecycle FSM so an order
+// still in flight when the process crashes or restarts isn't silently
+// forgotten. The default implementation writes straight through to
+// Postgres; db may be nil for offline/test use, in which case every method
+// is a no-op and OpenOrders always returns none.
+type OrderStateStore interface {
+	Create(ctx context.Context, state *models.OrderState) error
+	UpdateStatus(ctx context.Context, id int64, status models.OrderStatus, oid *int64, errorMsg string) error
+	OpenOrders(ctx context.Context) ([]models.OrderState, error)
+}
+
+type postgresOrderStateStore struct {
+	db *database.PostgresDB
+}
+
+// NewOrderStateStore returns the Postgres-backed OrderStateStore.
+func NewOrderStateStore(db *database.PostgresDB) OrderStateStore {
+	return &postgresOrderStateStore{db: db}
+}
+
+func (s *postgresOrderStateStore) Create(ctx context.Context, state *models.OrderState) error {
+	if s.db == nil {
+		return nil
+	}
+	return s.db.CreateOrderState(ctx, state)
+}
+
+func (s *postgresOrderStateStore) UpdateStatus(ctx context.Context, id int64, status models.OrderStatus, oid *int64, errorMsg string) error {
+	if s.db == nil {
+		return nil
+	}
+	return s.db.UpdateOrderStateStatus(ctx, id, status, oid, errorMsg)
+}
+
+func (s *postgresOrderStateStore) OpenOrders(ctx context.Context) ([]models.OrderState, error) {
+	if s.db == nil {
+		return nil, nil
+	}
+	return s.db.GetOpenOrderStates(ctx)
+}
@@ -0,0 +1,58 @@
+package engine
+
+import (
+	"time"
+
+	"hyperliquid-copy-trading/internal/models"
+)
+
+// PermissionlessDecisionInput is everything ProportionalMirror.OnLeaderTrade
+// reads for one follower when it calls shouldCopyTradeAt and calculateCopySize,
+// collected into a single value the same way DecisionInput lets Decide be
+// replayed deterministically from a conformance vector instead of a live
+// engine.
+type PermissionlessDecisionInput struct {
+	Follower models.PermissionlessFollower `json:"follower"`
+	Trade    models.TradeEvent             `json:"trade"`
+
+	// Now is the decision's wall-clock reading, threaded into
+	// shouldCopyTradeAt instead of time.Now()/time.Since so OnlyDuringHours
+	// and TimeDelaySeconds don't depend on when the vector happens to run.
+	Now time.Time `json:"now"`
+}
+
+// PermissionlessDecisionOutput is what DecidePermissionlessCopy produces for
+// one follower: whether the trade passed CopyFilters, why not if it didn't,
+// and the resulting copy size (zero if rejected or sized down to nothing).
+type PermissionlessDecisionOutput struct {
+	Approved bool    `json:"approved"`
+	Reason   string  `json:"reason,omitempty"`
+	Size     float64 `json:"size"`
+}
+
+// DecidePermissionlessCopy runs one follower's permissionless copy-trade
+// decision -- CopyFilters, then sizing -- as a pure function of input, with
+// no DB, WebSocket or exchange API access. It is the same pipeline
+// ProportionalMirror drives live followers through (shouldCopyTradeAt, then
+// calculateCopySize), factored out so the conformance harness can pin it
+// down with vectors instead of only exercising it implicitly through a
+// running engine. It deliberately stops short of the order submission
+// Router and Session perform, since that reads a live HyperliquidAPI through
+// PermissionlessOrderClient -- the seam a recording fake substitutes for in
+// an integration-style test instead.
+func DecidePermissionlessCopy(input PermissionlessDecisionInput) PermissionlessDecisionOutput {
+	follower := input.Follower
+	trade := input.Trade
+
+	approved, reason := shouldCopyTradeAt(&follower, trade, input.Now)
+	if !approved {
+		return PermissionlessDecisionOutput{Approved: false, Reason: reason}
+	}
+
+	size := calculateCopySize(&follower, trade)
+	if size <= 0 {
+		return PermissionlessDecisionOutput{Approved: false, Reason: "sized to zero", Size: size}
+	}
+
+	return PermissionlessDecisionOutput{Approved: true, Size: size}
+}
@@ -7,42 +7,89 @@ import (
 	"hyperliquid-copy-trading/internal/api"
 	"hyperliquid-copy-trading/internal/database"
 	"hyperliquid-copy-trading/internal/models"
+	"hyperliquid-copy-trading/internal/pnl"
 	"hyperliquid-copy-trading/internal/utils"
 	"hyperliquid-copy-trading/internal/websocket"
+	"math"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/rs/zerolog/log"
 )
 
+// PermissionlessOrderClient is the subset of HyperliquidAPI's order-placement
+// surface Session.SubmitOrder needs, factored out so a recording fake can
+// stand in for a live HyperliquidAPI in conformance vectors that exercise
+// handleTraderTrade end to end instead of only through DecidePermissionlessCopy.
+type PermissionlessOrderClient interface {
+	PlaceOrder(ctx context.Context, order *models.EnhancedOrderRequest, account models.TradingAccount) (*models.OrderResponse, error)
+}
+
 // PermissionlessCopyEngine enables copying any trader without their registration
 type PermissionlessCopyEngine struct {
-	config           *config.Config
-	db               *database.PostgresDB
-	hyperliquidAPI   *api.HyperliquidAPI
-	wsManager        *websocket.Manager
-	
+	config         *config.Config
+	db             *database.PostgresDB
+	hyperliquidAPI *api.HyperliquidAPI
+	orderClient    PermissionlessOrderClient
+	wsManager      *websocket.Manager
+	clock          Clock
+
+	// router dispatches strategy's OrderIntents to each follower's Session;
+	// strategy decides what those intents are for a given leader trade.
+	// See Router/MirrorStrategy in router.go.
+	router   *Router
+	strategy MirrorStrategy
+
 	// Track discovered traders and their followers
 	discoveredTraders map[string]*TraderInfo
 	traderFollowers   map[string][]*models.PermissionlessFollower
 	tradersMutex      sync.RWMutex
-	
+
 	// Performance tracking for auto-discovery
 	performanceTracker *PerformanceTracker
-	
+
+	// candidates holds the rolling (Sharpe, drawdown, profit factor,
+	// Herfindahl) windows discoverActiveTraders scores every tick, bounded
+	// to DiscoveryCandidateCacheSize addresses. See discovery_candidates.go.
+	candidates *candidateLRU
+
+	// recommendations is the last-scored leaderboard GetTraderRecommendations
+	// reads, keyed by address; recommendationsMu guards it independently of
+	// tradersMutex since a tick rebuilds it wholesale.
+	recommendations   map[string]*scoredDiscovery
+	recommendationsMu sync.RWMutex
+
+	// RecommendationChanged receives an address every time a discovery tick
+	// changes that address's score enough to re-rank it, so the API layer
+	// can push fresh candidates instead of polling GetTraderRecommendations.
+	RecommendationChanged chan string
+
+	// profitFixer reconstructs a follower's realized PnL from its own fill
+	// history, for FixFollowerProfit and the startup gap-scan hook.
+	profitFixer *ProfitFixer
+
 	shutdown chan struct{}
 	wg       sync.WaitGroup
 }
 
+// scoredDiscovery pairs a persisted models.TraderDiscovery snapshot with
+// the raw composite score it was ranked by, since TraderDiscovery itself
+// has no score column.
+type scoredDiscovery struct {
+	discovery *models.TraderDiscovery
+	score     float64
+}
+
 type TraderInfo struct {
-	Address            string                 `json:"address"`
-	FirstDiscovered    time.Time             `json:"first_discovered"`
-	TotalVolume        float64               `json:"total_volume"`
-	TradeCount         int                   `json:"trade_count"`
-	PerformanceMetrics *models.PnLAnalytics  `json:"performance_metrics"`
-	LastActivity       time.Time             `json:"last_activity"`
-	IsActive           bool                  `json:"is_active"`
-	AssetBreakdown     map[string]float64    `json:"asset_breakdown"`
+	Address            string               `json:"address"`
+	FirstDiscovered    time.Time            `json:"first_discovered"`
+	TotalVolume        float64              `json:"total_volume"`
+	TradeCount         int                  `json:"trade_count"`
+	PerformanceMetrics *models.PnLAnalytics `json:"performance_metrics"`
+	LastActivity       time.Time            `json:"last_activity"`
+	IsActive           bool                 `json:"is_active"`
+	AssetBreakdown     map[string]float64   `json:"asset_breakdown"`
 }
 
 type PerformanceTracker struct {
@@ -58,11 +105,11 @@ func (pt *PerformanceTracker) AnalyzeTraderPerformance(fills []models.EnhancedTr
 	analytics := &models.PnLAnalytics{
 		TotalTrades: len(fills),
 	}
-	
+
 	// Calculate basic metrics
 	var totalPnL float64
 	var profitableTrades int
-	
+
 	for _, fill := range fills {
 		// Parse closed PnL if available
 		if fill.ClosedPnl != "" {
@@ -75,13 +122,13 @@ func (pt *PerformanceTracker) AnalyzeTraderPerformance(fills []models.EnhancedTr
 			}
 		}
 	}
-	
+
 	analytics.TotalPnL = totalPnL
 	analytics.ProfitableTrades = profitableTrades
 	if len(fills) > 0 {
 		analytics.WinRate = float64(profitableTrades) / float64(len(fills))
 	}
-	
+
 	return analytics, nil
 }
 
@@ -91,16 +138,60 @@ func NewPermissionlessCopyEngine(cfg *config.Config, db *database.PostgresDB, ws
 		log.Fatal().Err(err).Msg("Failed to initialize Hyperliquid API")
 	}
 
-	return &PermissionlessCopyEngine{
-		config:             cfg,
-		db:                 db,
-		hyperliquidAPI:     hyperliquidAPI,
-		wsManager:          wsManager,
-		discoveredTraders:  make(map[string]*TraderInfo),
-		traderFollowers:    make(map[string][]*models.PermissionlessFollower),
-		performanceTracker: NewPerformanceTracker(),
-		shutdown:           make(chan struct{}),
+	clock := NewRealClock()
+
+	pce := &PermissionlessCopyEngine{
+		config:                cfg,
+		db:                    db,
+		hyperliquidAPI:        hyperliquidAPI,
+		orderClient:           hyperliquidAPI,
+		wsManager:             wsManager,
+		clock:                 clock,
+		router:                NewRouter(),
+		strategy:              NewProportionalMirror(clock),
+		discoveredTraders:     make(map[string]*TraderInfo),
+		traderFollowers:       make(map[string][]*models.PermissionlessFollower),
+		performanceTracker:    NewPerformanceTracker(),
+		candidates:            newCandidateLRU(cfg.DiscoveryCandidateCacheSize, cfg.DiscoveryShortWindow, cfg.DiscoveryLongWindow),
+		recommendations:       make(map[string]*scoredDiscovery),
+		RecommendationChanged: make(chan string, 64),
+		profitFixer:           NewProfitFixer(hyperliquidAPI, db, pnl.Method(cfg.LotMatchingMethod)),
+		shutdown:              make(chan struct{}),
 	}
+
+	pce.loadPersistedRecommendations()
+
+	return pce
+}
+
+// loadPersistedRecommendations seeds pce.recommendations from trader_discovery
+// so a restart doesn't lose the leaderboard GetTraderRecommendations serves --
+// scores are approximate (profit factor and Herfindahl aren't persisted
+// columns) until the next discovery tick recomputes them exactly.
+func (pce *PermissionlessCopyEngine) loadPersistedRecommendations() {
+	traders, err := pce.db.GetTopTraders(context.Background(), pce.config.DiscoveryTopK)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to load persisted trader discovery leaderboard")
+		return
+	}
+
+	pce.recommendationsMu.Lock()
+	defer pce.recommendationsMu.Unlock()
+	for _, td := range traders {
+		score := pce.config.DiscoveryScoreWeights.Sharpe*td.SharpeRatio -
+			pce.config.DiscoveryScoreWeights.Drawdown*td.MaxDrawdown
+		pce.recommendations[td.Address] = &scoredDiscovery{discovery: td, score: score}
+	}
+}
+
+// SetMirrorStrategy swaps the MirrorStrategy handleTraderTrade drives every
+// leader event through -- NewDeltaMirror(clock) instead of the default
+// NewProportionalMirror(clock), for example. Safe to call while the engine
+// is running; takes effect on the next leader trade.
+func (pce *PermissionlessCopyEngine) SetMirrorStrategy(strategy MirrorStrategy) {
+	pce.tradersMutex.Lock()
+	defer pce.tradersMutex.Unlock()
+	pce.strategy = strategy
 }
 
 // AddPermissionlessFollower allows copying any trader address
@@ -128,11 +219,15 @@ func (pce *PermissionlessCopyEngine) AddPermissionlessFollower(ctx context.Conte
 	// Add to tracking
 	pce.tradersMutex.Lock()
 	pce.traderFollowers[follower.TargetTraderAddress] = append(
-		pce.traderFollowers[follower.TargetTraderAddress], 
+		pce.traderFollowers[follower.TargetTraderAddress],
 		follower,
 	)
 	pce.tradersMutex.Unlock()
 
+	// Give the follower a Session so pce.router can dispatch OrderIntents
+	// to it once a MirrorStrategy produces one.
+	pce.router.AddSession(NewSession(follower.ID, follower.APIWalletAddress, pce.orderClient, pce.config.SessionOrdersPerMinute))
+
 	// Start monitoring if not already
 	if err := pce.startMonitoringTrader(follower.TargetTraderAddress); err != nil {
 		log.Error().Err(err).Str("trader", follower.TargetTraderAddress).Msg("Failed to start monitoring")
@@ -146,6 +241,79 @@ func (pce *PermissionlessCopyEngine) AddPermissionlessFollower(ctx context.Conte
 	return nil
 }
 
+// ListPermissionlessFollowers returns every follower currently tracked in
+// memory, across every monitored trader, for StrategyConfigLoader to diff
+// against a reloaded config.
+func (pce *PermissionlessCopyEngine) ListPermissionlessFollowers() []*models.PermissionlessFollower {
+	pce.tradersMutex.RLock()
+	defer pce.tradersMutex.RUnlock()
+
+	out := make([]*models.PermissionlessFollower, 0, len(pce.traderFollowers))
+	for _, followers := range pce.traderFollowers {
+		out = append(out, followers...)
+	}
+	return out
+}
+
+// UpdatePermissionlessFollower applies follower's mutable fields (everything
+// but its id, user, wallet and target trader) to the tracked copy of an
+// existing follower and persists the change. It does not touch monitoring
+// or the follower's Session, since neither depends on these fields.
+func (pce *PermissionlessCopyEngine) UpdatePermissionlessFollower(ctx context.Context, follower *models.PermissionlessFollower) error {
+	if err := pce.db.UpdatePermissionlessFollower(ctx, follower); err != nil {
+		return fmt.Errorf("failed to update follower: %w", err)
+	}
+
+	pce.tradersMutex.Lock()
+	defer pce.tradersMutex.Unlock()
+	followers := pce.traderFollowers[follower.TargetTraderAddress]
+	for i, existing := range followers {
+		if existing.ID == follower.ID {
+			followers[i] = follower
+			break
+		}
+	}
+
+	return nil
+}
+
+// RemovePermissionlessFollower deletes followerID, drops it from
+// traderAddress's tracked follower list and its Session, and -- if that was
+// the last follower watching traderAddress -- stops monitoring the trader
+// entirely, the same reconciliation CopyEngine.loadActiveFollowers does for
+// consent-based followers.
+func (pce *PermissionlessCopyEngine) RemovePermissionlessFollower(ctx context.Context, followerID int, traderAddress string) error {
+	if err := pce.db.DeletePermissionlessFollower(ctx, followerID); err != nil {
+		return fmt.Errorf("failed to delete follower: %w", err)
+	}
+
+	pce.tradersMutex.Lock()
+	followers := pce.traderFollowers[traderAddress]
+	for i, existing := range followers {
+		if existing.ID == followerID {
+			followers = append(followers[:i], followers[i+1:]...)
+			break
+		}
+	}
+	if len(followers) == 0 {
+		delete(pce.traderFollowers, traderAddress)
+	} else {
+		pce.traderFollowers[traderAddress] = followers
+	}
+	stillMonitored := len(followers) > 0
+	pce.tradersMutex.Unlock()
+
+	pce.router.RemoveSession(followerID)
+
+	if !stillMonitored {
+		pce.wsManager.UnsubscribeFromLeader(models.DefaultVenue, traderAddress)
+		log.Info().Str("trader", traderAddress).Msg("Stopped monitoring trader, no followers remain")
+	}
+
+	log.Info().Int("follower_id", followerID).Str("trader", traderAddress).Msg("Permissionless follower removed")
+	return nil
+}
+
 // discoverTrader analyzes any address to determine if it's a viable trader
 func (pce *PermissionlessCopyEngine) discoverTrader(ctx context.Context, address string) (*TraderInfo, error) {
 	pce.tradersMutex.RLock()
@@ -175,13 +343,13 @@ func (pce *PermissionlessCopyEngine) discoverTrader(ctx context.Context, address
 	// Calculate performance metrics
 	if len(fills) > 0 {
 		traderInfo.LastActivity = time.Unix(fills[0].Time/1000, 0)
-		
+
 		// Calculate volume and asset breakdown
 		for _, fill := range fills {
 			price, _ := utils.ParseFloat(fill.Px)
 			size, _ := utils.ParseFloat(fill.Sz)
 			volume := price * size
-			
+
 			traderInfo.TotalVolume += volume
 			traderInfo.AssetBreakdown[fill.Coin] += volume
 		}
@@ -205,7 +373,7 @@ func (pce *PermissionlessCopyEngine) discoverTrader(ctx context.Context, address
 func (pce *PermissionlessCopyEngine) startMonitoringTrader(traderAddress string) error {
 	// Check if already monitoring
 	pce.tradersMutex.RLock()
-	isMonitored := pce.wsManager.IsMonitoring(traderAddress)
+	_, isMonitored := pce.wsManager.GetTradeStream(models.DefaultVenue, traderAddress)
 	pce.tradersMutex.RUnlock()
 
 	if isMonitored {
@@ -213,7 +381,7 @@ func (pce *PermissionlessCopyEngine) startMonitoringTrader(traderAddress string)
 	}
 
 	// Subscribe to trader's fills and order updates
-	tradeChannel, userChannel, err := pce.wsManager.SubscribeToLeader(traderAddress)
+	tradeChannel, userChannel, err := pce.wsManager.SubscribeToLeader(context.Background(), models.DefaultVenue, traderAddress)
 	if err != nil {
 		return fmt.Errorf("failed to subscribe to trader: %w", err)
 	}
@@ -249,7 +417,11 @@ func (pce *PermissionlessCopyEngine) processTraderActivity(traderAddress string,
 	}
 }
 
-// handleTraderTrade processes a trader's trade and triggers copying
+// handleTraderTrade processes a trader's trade and triggers copying. The
+// actual per-follower decision is pce.strategy's job (ProportionalMirror by
+// default); handleTraderTrade only gathers followers and hands the
+// resulting OrderIntents to pce.router for execution, so swapping in
+// DeltaMirror or another MirrorStrategy doesn't touch this method at all.
 func (pce *PermissionlessCopyEngine) handleTraderTrade(traderAddress string, tradeEvent models.TradeEvent) {
 	ctx := context.Background()
 
@@ -267,42 +439,81 @@ func (pce *PermissionlessCopyEngine) handleTraderTrade(traderAddress string, tra
 	// Get followers for this trader
 	pce.tradersMutex.RLock()
 	followers := pce.traderFollowers[traderAddress]
+	strategy := pce.strategy
 	pce.tradersMutex.RUnlock()
 
 	if len(followers) == 0 {
 		return
 	}
 
-	// Process each follower
+	intents := strategy.OnLeaderTrade(ctx, traderAddress, tradeEvent, followers)
+	if len(intents) == 0 {
+		return
+	}
+
+	go pce.dispatchCopyTrades(ctx, followers, tradeEvent, intents)
+}
+
+// dispatchCopyTrades submits intents through pce.router and records a
+// CopyTrade for each one that reached the exchange, matching what
+// executeCopyTrade used to do inline before Router/MirrorStrategy split
+// decision from execution.
+func (pce *PermissionlessCopyEngine) dispatchCopyTrades(ctx context.Context, followers []*models.PermissionlessFollower, trade models.TradeEvent, intents []OrderIntent) {
+	byID := make(map[int]*models.PermissionlessFollower, len(followers))
 	for _, follower := range followers {
-		if !follower.IsActive {
+		byID[follower.ID] = follower
+	}
+
+	errs := pce.router.Dispatch(ctx, intents)
+	for i, intent := range intents {
+		follower, ok := byID[intent.FollowerID]
+		if !ok {
 			continue
 		}
 
-		// Apply copy filters
-		if !pce.shouldCopyTrade(follower, tradeEvent) {
-			log.Debug().
-				Str("trader", traderAddress).
+		if err := errs[i]; err != nil {
+			log.Error().
+				Err(err).
+				Str("trader", follower.TargetTraderAddress).
 				Str("follower", follower.UserID).
-				Msg("Trade filtered out")
+				Msg("Failed to execute copy trade")
 			continue
 		}
 
-		// Calculate copy size
-		copySize := pce.calculateCopySize(follower, tradeEvent)
-		if copySize <= 0 {
-			continue
-		}
+		log.Info().
+			Str("trader", follower.TargetTraderAddress).
+			Str("follower", follower.UserID).
+			Str("coin", trade.Coin).
+			Float64("size", intent.Order.Size).
+			Msg("Copy trade executed successfully")
 
-		// Execute copy trade
-		go pce.executeCopyTrade(ctx, follower, tradeEvent, copySize)
+		copyTrade := &models.CopyTrade{
+			OriginalTraderAddress: follower.TargetTraderAddress,
+			FollowerID:            follower.ID,
+			OriginalTradeHash:     trade.Hash,
+			Asset:                 trade.Coin,
+			Side:                  trade.Side,
+			OriginalSize:          trade.Sz,
+			CopiedSize:            fmt.Sprintf("%.6f", intent.Order.Size),
+			OriginalPrice:         trade.Px,
+			ExecutedAt:            pce.clock.Now(),
+			Status:                "executed",
+		}
+		if err := pce.db.CreateCopyTrade(ctx, copyTrade); err != nil {
+			log.Error().Err(err).Msg("Failed to store copy trade record")
+		}
 	}
 }
 
-// shouldCopyTrade applies filters to determine if trade should be copied
-func (pce *PermissionlessCopyEngine) shouldCopyTrade(follower *models.PermissionlessFollower, trade models.TradeEvent) bool {
+// shouldCopyTradeAt is the pure filter check ProportionalMirror and
+// DeltaMirror both drive, with the "now" used by
+// OnlyDuringHours and TimeDelaySeconds taken as an explicit parameter
+// instead of time.Now()/time.Since, so the decision is a pure function of
+// its inputs -- the seam DecidePermissionlessCopy's conformance vectors pin
+// down. reason is empty when approved is true.
+func shouldCopyTradeAt(follower *models.PermissionlessFollower, trade models.TradeEvent, now time.Time) (approved bool, reason string) {
 	if follower.CopyFilters == nil {
-		return true
+		return true, ""
 	}
 
 	filters := follower.CopyFilters
@@ -317,14 +528,14 @@ func (pce *PermissionlessCopyEngine) shouldCopyTrade(follower *models.Permission
 			}
 		}
 		if !found {
-			return false
+			return false, "asset not in whitelist"
 		}
 	}
 
 	if len(follower.AssetBlacklist) > 0 {
 		for _, asset := range follower.AssetBlacklist {
 			if asset == trade.Coin {
-				return false
+				return false, "asset in blacklist"
 			}
 		}
 	}
@@ -335,109 +546,60 @@ func (pce *PermissionlessCopyEngine) shouldCopyTrade(follower *models.Permission
 	positionValue := price * size
 
 	if positionValue < filters.MinPositionValue || positionValue > filters.MaxPositionValue {
-		return false
+		return false, "position value outside configured range"
 	}
 
 	// Check time restrictions
 	if filters.OnlyDuringHours != nil {
-		currentHour := time.Now().Hour()
+		currentHour := now.Hour()
 		if currentHour < filters.OnlyDuringHours.StartHour || currentHour > filters.OnlyDuringHours.EndHour {
-			return false
+			return false, "outside configured trading hours"
 		}
 	}
 
 	// Apply time delay if specified
 	if filters.TimeDelaySeconds > 0 {
 		tradeTime := time.Unix(trade.Time/1000, 0)
-		if time.Since(tradeTime) < time.Duration(filters.TimeDelaySeconds)*time.Second {
-			// Schedule for later execution
-			go func() {
-				time.Sleep(time.Duration(filters.TimeDelaySeconds) * time.Second)
-				// Re-execute the copy logic
-			}()
-			return false
+		if now.Sub(tradeTime) < time.Duration(filters.TimeDelaySeconds)*time.Second {
+			return false, "time delay not yet elapsed"
 		}
 	}
 
-	return true
+	return true, ""
 }
 
-// calculateCopySize determines the appropriate size for copying
-func (pce *PermissionlessCopyEngine) calculateCopySize(follower *models.PermissionlessFollower, trade models.TradeEvent) float64 {
+// calculateCopySize determines the appropriate size for copying. It reads
+// no engine state, so it's a standalone function rather than a method --
+// the same seam DecidePermissionlessCopy's conformance vectors drive.
+func calculateCopySize(follower *models.PermissionlessFollower, trade models.TradeEvent) float64 {
 	originalSize, _ := utils.ParseFloat(trade.Sz)
-	
+
 	// Apply copy percentage
 	copySize := originalSize * (follower.CopyPercentage / 100.0)
-	
+
 	// Apply minimum size filter
 	if copySize < follower.MinTradeSize {
 		return 0
 	}
-	
+
 	// Apply maximum position size
 	price, _ := utils.ParseFloat(trade.Px)
 	positionValue := copySize * price
-	
+
 	if positionValue > follower.MaxPositionSize {
 		copySize = follower.MaxPositionSize / price
 	}
-	
-	return copySize
-}
 
-// executeCopyTrade executes the actual copy trade
-func (pce *PermissionlessCopyEngine) executeCopyTrade(ctx context.Context, follower *models.PermissionlessFollower, trade models.TradeEvent, size float64) {
-	price, _ := utils.ParseFloat(trade.Px)
-	
-	order := &models.EnhancedOrderRequest{
-		Asset:     trade.Coin,
-		IsBuy:     trade.Side == "B",
-		Size:      size,
-		Price:     &price,
-		OrderType: "market", // Copy as market order for immediate execution
-		Tif:       "Ioc",    // Immediate or Cancel
-	}
-
-	response, err := pce.hyperliquidAPI.PlaceOrder(ctx, order, follower.APIWalletAddress)
-	if err != nil {
-		log.Error().
-			Err(err).
-			Str("trader", follower.TargetTraderAddress).
-			Str("follower", follower.UserID).
-			Msg("Failed to execute copy trade")
-		return
-	}
-
-	if response.Status == "ok" {
-		log.Info().
-			Str("trader", follower.TargetTraderAddress).
-			Str("follower", follower.UserID).
-			Str("coin", trade.Coin).
-			Float64("size", size).
-			Msg("Copy trade executed successfully")
-
-		// Store the copy trade record
-		copyTrade := &models.CopyTrade{
-			OriginalTraderAddress: follower.TargetTraderAddress,
-			FollowerID:           follower.ID,
-			OriginalTradeHash:    trade.Hash,
-			Asset:                trade.Coin,
-			Side:                 trade.Side,
-			OriginalSize:         trade.Sz,
-			CopiedSize:           fmt.Sprintf("%.6f", size),
-			OriginalPrice:        trade.Px,
-			ExecutedAt:           time.Now(),
-			Status:               "executed",
-		}
-
-		if err := pce.db.CreateCopyTrade(ctx, copyTrade); err != nil {
-			log.Error().Err(err).Msg("Failed to store copy trade record")
-		}
-	}
+	return copySize
 }
 
-// updateTraderActivity updates trader statistics
+// updateTraderActivity updates trader statistics and registers traderAddress
+// as a live discovery candidate -- discoverActiveTraders only ever scores
+// addresses that have shown up here, since a leader's own trade stream is
+// the only activity signal this engine observes it through.
 func (pce *PermissionlessCopyEngine) updateTraderActivity(traderAddress string, trade models.TradeEvent) {
+	pce.candidates.touch(traderAddress, time.Unix(trade.Time/1000, 0))
+
 	pce.tradersMutex.Lock()
 	defer pce.tradersMutex.Unlock()
 
@@ -496,17 +658,300 @@ func (pce *PermissionlessCopyEngine) runAutoDiscovery(ctx context.Context) {
 	}
 }
 
-// discoverActiveTraders finds new traders from recent market activity
+// discoverActiveTraders scores every address handleTraderTrade has seen
+// recently (the public trade feed carries no per-fill PnL, so it isn't
+// enough on its own -- this pulls each candidate's own closed-PnL fills
+// the same way ProfitFixer does), ranks them by compositeScore, and
+// promotes the top DiscoveryTopK into both discoveredTraders and the
+// persisted trader_discovery leaderboard.
 func (pce *PermissionlessCopyEngine) discoverActiveTraders(ctx context.Context) {
-	// This would analyze recent trades from WebSocket feeds
-	// and identify addresses with significant trading activity
-	log.Info().Msg("Running auto-discovery for active traders")
-	
-	// Implementation would involve:
-	// 1. Monitoring high-volume trades from WebSocket feeds
-	// 2. Tracking addresses with consistent profitability
-	// 3. Analyzing trading patterns for quality metrics
-	// 4. Building a recommendation system
+	candidates := pce.candidates.snapshot()
+	log.Info().Int("candidates", len(candidates)).Msg("Running auto-discovery for active traders")
+
+	now := pce.clock.Now()
+	scored := make([]*scoredDiscovery, 0, len(candidates))
+
+	for _, cand := range candidates {
+		if err := pce.refreshCandidateFills(ctx, cand, now); err != nil {
+			log.Error().Err(err).Str("address", cand.address).Msg("Failed to refresh discovery candidate fills")
+			continue
+		}
+
+		longMetrics := cand.long.metrics()
+		if longMetrics.TradeCount < pce.config.DiscoveryMinTradeCount {
+			continue
+		}
+		shortMetrics := cand.short.metrics()
+		score := compositeScore(longMetrics, pce.config.DiscoveryScoreWeights)
+
+		pce.tradersMutex.RLock()
+		followerCount := len(pce.traderFollowers[cand.address])
+		pce.tradersMutex.RUnlock()
+
+		discovery := &models.TraderDiscovery{
+			Address:          cand.address,
+			FirstDiscovered:  cand.firstSeen,
+			TotalVolume:      longMetrics.TotalNotional,
+			TradeCount:       longMetrics.TradeCount,
+			WinRate:          winRate(longMetrics),
+			MaxDrawdown:      longMetrics.MaxDrawdown,
+			SharpeRatio:      longMetrics.Sharpe,
+			LastActivity:     cand.lastSeenAt(),
+			IsActive:         shortMetrics.TradeCount > 0,
+			FollowerCount:    followerCount,
+			AssetBreakdown:   longMetrics.AssetBreakdown,
+			PerformanceGrade: performanceGrade(score),
+			RiskLevel:        riskLevel(longMetrics),
+			TradingStyle:     tradingStyle(longMetrics.AvgHoldTime),
+		}
+
+		if err := pce.db.CreateTraderDiscovery(ctx, discovery); err != nil {
+			log.Error().Err(err).Str("address", cand.address).Msg("Failed to persist trader discovery score")
+		}
+
+		scored = append(scored, &scoredDiscovery{discovery: discovery, score: score})
+	}
+
+	sort.Slice(scored, func(i, j int) bool { return scored[i].score > scored[j].score })
+	if len(scored) > pce.config.DiscoveryTopK {
+		scored = scored[:pce.config.DiscoveryTopK]
+	}
+
+	pce.promoteTopCandidates(scored)
+}
+
+// refreshCandidateFills folds every fill cand hasn't seen yet into its
+// short and long windows.
+func (pce *PermissionlessCopyEngine) refreshCandidateFills(ctx context.Context, cand *traderCandidate, now time.Time) error {
+	since := cand.fetchWatermark()
+	if since.IsZero() {
+		since = now.Add(-pce.config.DiscoveryLongWindow)
+	}
+
+	fills, err := pce.hyperliquidAPI.GetUserFillsByTime(ctx, cand.address, since.UnixMilli(), now.UnixMilli())
+	if err != nil {
+		return err
+	}
+
+	for _, fill := range fills {
+		if fill.ClosedPnl == "" {
+			continue
+		}
+		pnl, err := utils.ParseFloat(fill.ClosedPnl)
+		if err != nil {
+			continue
+		}
+		price, _ := utils.ParseFloat(fill.Px)
+		size, _ := utils.ParseFloat(fill.Sz)
+
+		f := &candidateFill{
+			timestamp: time.Unix(fill.Time/1000, 0),
+			pnl:       pnl,
+			notional:  price * size,
+			asset:     fill.Coin,
+		}
+		cand.short.push(f)
+		cand.long.push(f)
+	}
+
+	cand.setFetchWatermark(now)
+	return nil
+}
+
+// promoteTopCandidates publishes scored as the new recommendations
+// leaderboard, notifying RecommendationChanged for any address whose score
+// moved enough to matter, and ensures each one has a discoveredTraders
+// entry so it surfaces alongside explicitly-added traders.
+func (pce *PermissionlessCopyEngine) promoteTopCandidates(scored []*scoredDiscovery) {
+	const scoreChangeEpsilon = 1e-6
+
+	pce.recommendationsMu.Lock()
+	previous := pce.recommendations
+	next := make(map[string]*scoredDiscovery, len(scored))
+	for _, sd := range scored {
+		next[sd.discovery.Address] = sd
+	}
+	pce.recommendations = next
+	pce.recommendationsMu.Unlock()
+
+	for address, sd := range next {
+		if prior, ok := previous[address]; !ok || math.Abs(prior.score-sd.score) > scoreChangeEpsilon {
+			select {
+			case pce.RecommendationChanged <- address:
+			default:
+			}
+		}
+	}
+
+	pce.tradersMutex.Lock()
+	defer pce.tradersMutex.Unlock()
+	for _, sd := range scored {
+		if _, exists := pce.discoveredTraders[sd.discovery.Address]; exists {
+			continue
+		}
+		pce.discoveredTraders[sd.discovery.Address] = &TraderInfo{
+			Address:         sd.discovery.Address,
+			FirstDiscovered: sd.discovery.FirstDiscovered,
+			TotalVolume:     sd.discovery.TotalVolume,
+			TradeCount:      sd.discovery.TradeCount,
+			LastActivity:    sd.discovery.LastActivity,
+			IsActive:        sd.discovery.IsActive,
+			AssetBreakdown:  sd.discovery.AssetBreakdown,
+		}
+	}
+}
+
+// GetTraderRecommendations returns the highest-scoring discovery candidates
+// at or above minScore, best first, capped at limit.
+func (pce *PermissionlessCopyEngine) GetTraderRecommendations(minScore float64, limit int) []*models.TraderDiscovery {
+	pce.recommendationsMu.RLock()
+	candidates := make([]*scoredDiscovery, 0, len(pce.recommendations))
+	for _, sd := range pce.recommendations {
+		if sd.score >= minScore {
+			candidates = append(candidates, sd)
+		}
+	}
+	pce.recommendationsMu.RUnlock()
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	out := make([]*models.TraderDiscovery, len(candidates))
+	for i, sd := range candidates {
+		out[i] = sd.discovery
+	}
+	return out
+}
+
+// winRate is the share of an in-window's fills that closed profitably.
+func winRate(m candidateMetrics) float64 {
+	if m.TradeCount == 0 {
+		return 0
+	}
+	return float64(m.WinCount) / float64(m.TradeCount)
+}
+
+// performanceGrade buckets a composite score into the same A-F scale
+// trader_discovery.performance_grade documents.
+func performanceGrade(score float64) string {
+	switch {
+	case score >= 2:
+		return "A"
+	case score >= 1:
+		return "B"
+	case score >= 0:
+		return "C"
+	case score >= -1:
+		return "D"
+	default:
+		return "F"
+	}
+}
+
+// riskLevel buckets a candidate's drawdown and asset concentration into the
+// Low/Medium/High scale trader_discovery.risk_level documents.
+func riskLevel(m candidateMetrics) string {
+	switch {
+	case m.MaxDrawdown > 0.5*m.TotalNotional || m.Herfindahl > 0.8:
+		return "High"
+	case m.MaxDrawdown > 0.2*m.TotalNotional || m.Herfindahl > 0.5:
+		return "Medium"
+	default:
+		return "Low"
+	}
+}
+
+// tradingStyle buckets average holding time into the
+// Scalper/Swing/Position labels trader_discovery.trading_style documents.
+func tradingStyle(avgHold time.Duration) string {
+	switch {
+	case avgHold <= 0:
+		return "Unknown"
+	case avgHold < time.Hour:
+		return "Scalper"
+	case avgHold < 7*24*time.Hour:
+		return "Swing"
+	default:
+		return "Position"
+	}
+}
+
+// FixFollowerProfit reconstructs followerID's realized PnL and per-asset
+// stats by replaying its own fills between since and until through
+// ProfitFixer, overwriting whatever copy_trades-derived stats it had before.
+// Useful to recover accurate numbers after downtime, a crash, or a leader
+// added mid-history.
+func (pce *PermissionlessCopyEngine) FixFollowerProfit(ctx context.Context, followerID int, since, until time.Time) (*models.PnLAnalytics, error) {
+	follower, err := pce.db.GetPermissionlessFollowerByID(ctx, followerID)
+	if err != nil {
+		return nil, fmt.Errorf("loading follower %d: %w", followerID, err)
+	}
+	return pce.profitFixer.FixFollowerProfit(ctx, follower, since, until)
+}
+
+// RunProfitFixerStartupScan is a startup hook that fixes profit for every
+// permissionless follower whose copy_trades table has gaps relative to its
+// target trader's recent fills -- a leader fill with no corresponding
+// copy_trades row in the same window, which a missed WebSocket message,
+// crash, or a follower added mid-history can all produce. window bounds how
+// far back it looks for leader fills to check.
+func (pce *PermissionlessCopyEngine) RunProfitFixerStartupScan(ctx context.Context, window time.Duration) {
+	followers, err := pce.db.GetAllPermissionlessFollowers(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load permissionless followers for profit-fixer startup scan")
+		return
+	}
+
+	since := time.Now().Add(-window)
+	until := time.Now()
+
+	for _, follower := range followers {
+		hasGap, err := pce.hasTradeGap(ctx, follower, since)
+		if err != nil {
+			log.Warn().Err(err).Int("follower_id", follower.ID).Msg("Failed to check for copy-trade gaps")
+			continue
+		}
+		if !hasGap {
+			continue
+		}
+
+		log.Info().Int("follower_id", follower.ID).Msg("Detected copy-trade gap, running profit fixer")
+		if _, err := pce.profitFixer.FixFollowerProfit(ctx, follower, since, until); err != nil {
+			log.Error().Err(err).Int("follower_id", follower.ID).Msg("Profit fixer run failed")
+		}
+	}
+}
+
+// hasTradeGap reports whether any of follower's target trader's fills since
+// since are missing a corresponding copy_trades row (matched by
+// OriginalTradeHash), which is what a missed or crashed copy leaves behind.
+func (pce *PermissionlessCopyEngine) hasTradeGap(ctx context.Context, follower *models.PermissionlessFollower, since time.Time) (bool, error) {
+	leaderFills, err := pce.hyperliquidAPI.GetUserFillsByTime(ctx, follower.TargetTraderAddress, since.UnixMilli(), time.Now().UnixMilli())
+	if err != nil {
+		return false, fmt.Errorf("fetching leader fills: %w", err)
+	}
+	if len(leaderFills) == 0 {
+		return false, nil
+	}
+
+	copyTrades, err := pce.db.GetCopyTradesByFollower(ctx, follower.ID)
+	if err != nil {
+		return false, fmt.Errorf("loading copy trades: %w", err)
+	}
+
+	copied := make(map[string]struct{}, len(copyTrades))
+	for _, ct := range copyTrades {
+		copied[ct.OriginalTradeHash] = struct{}{}
+	}
+
+	for _, fill := range leaderFills {
+		if _, ok := copied[fill.Hash]; !ok {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 func (pce *PermissionlessCopyEngine) handleTraderUserEvent(traderAddress string, userEvent models.UserEvent) {
@@ -520,4 +965,4 @@ func (pce *PermissionlessCopyEngine) handleTraderUserEvent(traderAddress string,
 func (pce *PermissionlessCopyEngine) Stop() {
 	close(pce.shutdown)
 	pce.wg.Wait()
-}
\ No newline at end of file
+}
@@ -0,0 +1,81 @@
+package engine
+
+import (
+	"sync"
+
+	"hyperliquid-copy-trading/internal/models"
+)
+
+// PositionTracker maintains each follower's current open positions, keyed by
+// API wallet address and then asset, so RiskManager can answer concentration
+// and margin-level questions without a DB round trip on the hot path.
+// updateUserPositions pushes fresh state into it on the same cadence it
+// upserts positions to Postgres.
+type PositionTracker struct {
+	mu        sync.RWMutex
+	positions map[string]map[string]models.Position // address -> asset -> position
+}
+
+// NewPositionTracker returns an empty tracker.
+func NewPositionTracker() *PositionTracker {
+	return &PositionTracker{
+		positions: make(map[string]map[string]models.Position),
+	}
+}
+
+// Update records the latest known state of one position. A zero-size
+// position clears the asset entry, since a closed position has nothing left
+// to concentrate risk in.
+func (pt *PositionTracker) Update(address string, position models.Position) {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
+	byAsset, ok := pt.positions[address]
+	if !ok {
+		byAsset = make(map[string]models.Position)
+		pt.positions[address] = byAsset
+	}
+
+	if position.Size == 0 {
+		delete(byAsset, position.Asset)
+		return
+	}
+	byAsset[position.Asset] = position
+}
+
+// Positions returns a snapshot of address's open positions.
+func (pt *PositionTracker) Positions(address string) []models.Position {
+	pt.mu.RLock()
+	defer pt.mu.RUnlock()
+
+	byAsset := pt.positions[address]
+	out := make([]models.Position, 0, len(byAsset))
+	for _, p := range byAsset {
+		out = append(out, p)
+	}
+	return out
+}
+
+// AssetNotional returns address's existing notional exposure to asset.
+func (pt *PositionTracker) AssetNotional(address, asset string) float64 {
+	pt.mu.RLock()
+	defer pt.mu.RUnlock()
+
+	if p, ok := pt.positions[address][asset]; ok {
+		return p.Size * p.CurrentPrice
+	}
+	return 0
+}
+
+// TotalNotional returns address's total open notional exposure across every
+// asset.
+func (pt *PositionTracker) TotalNotional(address string) float64 {
+	pt.mu.RLock()
+	defer pt.mu.RUnlock()
+
+	var total float64
+	for _, p := range pt.positions[address] {
+		total += p.Size * p.CurrentPrice
+	}
+	return total
+}
@@ -0,0 +1,184 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"hyperliquid-copy-trading/internal/api"
+	"hyperliquid-copy-trading/internal/database"
+	"hyperliquid-copy-trading/internal/models"
+	"hyperliquid-copy-trading/internal/pnl"
+	"hyperliquid-copy-trading/internal/utils"
+
+	"github.com/rs/zerolog/log"
+)
+
+// tradeBatchWindow is the fixed slice TradeBatchQuery pages GetUserFillsByTime
+// in. Hyperliquid's userFillsByTime endpoint is unbounded in principle but
+// returns a capped number of fills per call, so a long since/until range is
+// walked in bounded-size windows rather than one unbounded request.
+const tradeBatchWindow = 7 * 24 * time.Hour
+
+// TradeBatchQuery pages a user's fill history between since and until in
+// fixed-size windows, deduplicating by Hash across window boundaries (a fill
+// landing exactly on a window edge can otherwise be returned by both the
+// window that ends there and the one that begins there).
+type TradeBatchQuery struct {
+	hyperliquidAPI *api.HyperliquidAPI
+}
+
+// NewTradeBatchQuery builds a TradeBatchQuery against hyperliquidAPI.
+func NewTradeBatchQuery(hyperliquidAPI *api.HyperliquidAPI) *TradeBatchQuery {
+	return &TradeBatchQuery{hyperliquidAPI: hyperliquidAPI}
+}
+
+// Fetch walks [since, until) in tradeBatchWindow-sized slices, returning
+// every distinct fill (by Hash) in chronological order.
+func (q *TradeBatchQuery) Fetch(ctx context.Context, userAddress string, since, until time.Time) ([]models.EnhancedTradeEvent, error) {
+	seen := make(map[string]struct{})
+	var fills []models.EnhancedTradeEvent
+
+	cursor := since
+	for cursor.Before(until) {
+		windowEnd := cursor.Add(tradeBatchWindow)
+		if windowEnd.After(until) {
+			windowEnd = until
+		}
+
+		batch, err := q.hyperliquidAPI.GetUserFillsByTime(ctx, userAddress, cursor.UnixMilli(), windowEnd.UnixMilli())
+		if err != nil {
+			return nil, fmt.Errorf("fetching fills [%s, %s): %w", cursor, windowEnd, err)
+		}
+
+		for _, fill := range batch {
+			if _, ok := seen[fill.Hash]; ok {
+				continue
+			}
+			seen[fill.Hash] = struct{}{}
+			fills = append(fills, fill)
+		}
+
+		cursor = windowEnd
+	}
+
+	sort.Slice(fills, func(i, j int) bool { return fills[i].Time < fills[j].Time })
+	return fills, nil
+}
+
+// ProfitFixer reconstructs a PermissionlessFollower's realized PnL and
+// per-asset position stats by replaying its own fill history from
+// HyperliquidAPI.GetUserFillsByTime through a pnl.Matcher, instead of
+// trusting only what dispatchCopyTrades recorded live into copy_trades --
+// which can have gaps after downtime, a crash, or a leader added
+// mid-history.
+type ProfitFixer struct {
+	db     *database.PostgresDB
+	query  *TradeBatchQuery
+	method pnl.Method
+}
+
+// NewProfitFixer builds a ProfitFixer that replays fills via hyperliquidAPI
+// and persists reconstructed stats through db, lot-matching with method.
+func NewProfitFixer(hyperliquidAPI *api.HyperliquidAPI, db *database.PostgresDB, method pnl.Method) *ProfitFixer {
+	return &ProfitFixer{
+		db:     db,
+		query:  NewTradeBatchQuery(hyperliquidAPI),
+		method: method,
+	}
+}
+
+// FixFollowerProfit replays follower's own fills between since and until,
+// runs them through a fresh pnl.Matcher per coin, and upserts the resulting
+// models.PnLAnalytics and per-asset realized PnL breakdown into the
+// permissionless_follower_pnl table.
+func (pf *ProfitFixer) FixFollowerProfit(ctx context.Context, follower *models.PermissionlessFollower, since, until time.Time) (*models.PnLAnalytics, error) {
+	fills, err := pf.query.Fetch(ctx, follower.APIWalletAddress, since, until)
+	if err != nil {
+		return nil, fmt.Errorf("fetching follower fills: %w", err)
+	}
+
+	matchers := make(map[string]*pnl.Matcher)
+	assetBreakdown := make(map[string]float64)
+
+	analytics := &models.PnLAnalytics{}
+	for _, fill := range fills {
+		trade, err := followerFillToTrade(follower, fill)
+		if err != nil {
+			log.Warn().Err(err).Str("hash", fill.Hash).Int("follower_id", follower.ID).Msg("Skipping unparseable fill in profit reconstruction")
+			continue
+		}
+
+		m, ok := matchers[trade.Asset]
+		if !ok {
+			m = pnl.NewMatcher(pf.method)
+			matchers[trade.Asset] = m
+		}
+
+		realized, err := m.Process(trade)
+		if err != nil {
+			return nil, fmt.Errorf("matching fill %s: %w", fill.Hash, err)
+		}
+
+		analytics.TotalTrades++
+		for _, r := range realized {
+			analytics.TotalPnL += r.RealizedPnL
+			assetBreakdown[r.Coin] += r.RealizedPnL
+			if r.RealizedPnL > 0 {
+				analytics.ProfitableTrades++
+			}
+		}
+	}
+
+	if analytics.TotalTrades > 0 {
+		analytics.WinRate = float64(analytics.ProfitableTrades) / float64(analytics.TotalTrades)
+	}
+
+	if err := pf.db.UpsertPermissionlessFollowerPnL(ctx, follower.ID, analytics, assetBreakdown, since, until); err != nil {
+		return nil, fmt.Errorf("persisting reconstructed PnL: %w", err)
+	}
+
+	log.Info().
+		Int("follower_id", follower.ID).
+		Int("trades", analytics.TotalTrades).
+		Float64("total_pnl", analytics.TotalPnL).
+		Msg("Reconstructed follower profit from fill history")
+
+	return analytics, nil
+}
+
+// followerFillToTrade converts one of follower's own fills into the
+// models.Trade shape pnl.Matcher expects, treating follower's copied
+// position as owned by (TargetTraderAddress, FollowerID) the same way
+// database.RecomputeFollowerRealizedPnL's trades-table replay does.
+func followerFillToTrade(follower *models.PermissionlessFollower, fill models.EnhancedTradeEvent) (models.Trade, error) {
+	price, err := utils.ParseFloat(fill.Px)
+	if err != nil {
+		return models.Trade{}, fmt.Errorf("parsing price: %w", err)
+	}
+	size, err := utils.ParseFloat(fill.Sz)
+	if err != nil {
+		return models.Trade{}, fmt.Errorf("parsing size: %w", err)
+	}
+	fee, _ := utils.ParseFloat(fill.Fee)
+
+	side := "sell"
+	if fill.Side == "B" {
+		side = "buy"
+	}
+
+	followerID := follower.ID
+	return models.Trade{
+		ID:            int(fill.Tid),
+		LeaderAddress: follower.TargetTraderAddress,
+		FollowerID:    &followerID,
+		Asset:         fill.Coin,
+		Side:          side,
+		Size:          size,
+		Price:         price,
+		Fee:           fee,
+		Status:        "filled",
+		ExecutedAt:    time.UnixMilli(fill.Time),
+	}, nil
+}
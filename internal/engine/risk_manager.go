@@ -1,8 +1,12 @@
 package engine
 
 import (
+	"context"
 	"hyperliquid-copy-trading/config"
+	"hyperliquid-copy-trading/internal/api"
 	"hyperliquid-copy-trading/internal/models"
+	"hyperliquid-copy-trading/internal/utils"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -10,6 +14,14 @@ import (
 
 type RiskManager struct {
 	config *config.Config
+
+	marginMutex  sync.RWMutex
+	marginStates map[string]models.LiveMarginState // keyed by follower API wallet address
+
+	history    TradeHistoryStore
+	positions  *PositionTracker
+	volatility *AssetVolatilityProvider
+	assets     *api.AssetRegistry
 }
 
 type RiskAssessment struct {
@@ -19,13 +31,125 @@ type RiskAssessment struct {
 	RiskScore    float64 `json:"risk_score"`
 }
 
-func NewRiskManager(cfg *config.Config) *RiskManager {
+func NewRiskManager(cfg *config.Config, history TradeHistoryStore, positions *PositionTracker, volatility *AssetVolatilityProvider, assets *api.AssetRegistry) *RiskManager {
 	return &RiskManager{
-		config: cfg,
+		config:       cfg,
+		marginStates: make(map[string]models.LiveMarginState),
+		history:      history,
+		positions:    positions,
+		volatility:   volatility,
+		assets:       assets,
+	}
+}
+
+// RecordTrade records an approved, executed trade in the follower's history,
+// which PerformanceReport, the overtrading window and the cooldown-after-loss
+// check all draw on.
+func (rm *RiskManager) RecordTrade(follower *models.Follower, trade models.Trade) {
+	rm.history.Record(follower.ID, trade)
+}
+
+func (rm *RiskManager) recentTrades(follower *models.Follower) []models.Trade {
+	return rm.history.Since(follower.ID, time.Time{})
+}
+
+// LoadHistory seeds the trade history store for followerID from persistent
+// storage, so overtrading/cooldown windows are correct immediately after a
+// restart rather than warming up from empty.
+func (rm *RiskManager) LoadHistory(ctx context.Context, followerID int) error {
+	return rm.history.Load(ctx, followerID)
+}
+
+// UpdateMarginState records the latest LiveMarginState pushed over a
+// follower's "margin" subscription, consumed by AssessRisk as a pre-trade
+// liquidation guard.
+func (rm *RiskManager) UpdateMarginState(address string, state models.LiveMarginState) {
+	rm.marginMutex.Lock()
+	defer rm.marginMutex.Unlock()
+	rm.marginStates[address] = state
+}
+
+// assessMarginRisk short-circuits a trade when the follower's account is
+// already over the configured RiskRate threshold, or when the trade's mark
+// price sits within RiskSettings["max_drawdown_stop"] percent of the
+// projected liquidation price.
+func (rm *RiskManager) assessMarginRisk(follower *models.Follower, trade *models.Trade) (bool, string) {
+	rm.marginMutex.RLock()
+	state, exists := rm.marginStates[follower.APIWalletAddress]
+	rm.marginMutex.RUnlock()
+
+	if !exists {
+		return true, ""
+	}
+
+	threshold := rm.config.MaxMarginRiskRate
+	if threshold > 0 && state.RiskRate >= threshold {
+		return false, "Margin risk rate breached threshold"
+	}
+
+	if state.LiquidationPrice > 0 && trade.Price > 0 {
+		distance := (trade.Price - state.LiquidationPrice) / trade.Price
+		if distance < 0 {
+			distance = -distance
+		}
+
+		if maxDrawdownStop, ok := follower.RiskSettings["max_drawdown_stop"]; ok {
+			if stop, ok := maxDrawdownStop.(float64); ok && stop > 0 && distance <= stop/100.0 {
+				return false, "Projected liquidation price too close to mark"
+			}
+		}
+	}
+
+	if approved, reason := rm.assessMarginLevel(follower, trade, state); !approved {
+		return false, reason
+	}
+
+	return true, ""
+}
+
+// assessMarginLevel rejects a trade that would leave the follower's account
+// below the configured MinMarginLevel, computed as account equity over the
+// margin required to hold the proposed position at the follower's
+// configured leverage (analogous to a spot-margin borrow's margin ratio).
+func (rm *RiskManager) assessMarginLevel(follower *models.Follower, trade *models.Trade, state models.LiveMarginState) (bool, string) {
+	if rm.config.MinMarginLevel <= 0 || state.AccountValue <= 0 {
+		return true, ""
 	}
+
+	leverage := riskSettingFloat(follower, "leverage", 1)
+	if leverage <= 0 {
+		leverage = 1
+	}
+
+	proposedSize := trade.Size * (follower.CopyPercentage / 100.0)
+	requiredMargin := utils.CalculateMarginRequired(trade.Price, proposedSize, leverage)
+	if requiredMargin <= 0 {
+		return true, ""
+	}
+
+	marginLevel := state.AccountValue / requiredMargin
+	if marginLevel < rm.config.MinMarginLevel {
+		log.Warn().Int("follower_id", follower.ID).Float64("margin_level", marginLevel).
+			Float64("min_required", rm.config.MinMarginLevel).Msg("Rejecting trade: margin level too low")
+		return false, "Margin level would fall below the configured minimum"
+	}
+
+	return true, ""
 }
 
+// AssessRisk assesses trade against follower's live configuration and
+// history, evaluated as of now. It delegates to AssessRiskAt(..., time.Now())
+// so every call site gets a consistent wall-clock reading across the
+// overtrading window and time-of-day checks.
 func (rm *RiskManager) AssessRisk(follower *models.Follower, trade *models.Trade) *RiskAssessment {
+	return rm.AssessRiskAt(follower, trade, time.Now().UTC())
+}
+
+// AssessRiskAt is AssessRisk with the "now" used by the overtrading window
+// and time-of-day risk check taken as an explicit parameter instead of
+// time.Now(), so the decision is a pure function of its inputs — the seam
+// conformance's Decide vectors pin down.
+func (rm *RiskManager) AssessRiskAt(follower *models.Follower, trade *models.Trade, now time.Time) *RiskAssessment {
 	assessment := &RiskAssessment{
 		Approved:     true,
 		AdjustedSize: trade.Size,
@@ -52,29 +176,66 @@ func (rm *RiskManager) AssessRisk(follower *models.Follower, trade *models.Trade
 	}
 
 	// Check for recent trading activity (prevent overtrading)
-	if rm.isOvertrading(follower) {
+	if overtrading, reason := rm.isOvertrading(follower, trade, now); overtrading {
+		assessment.Approved = false
+		assessment.Reason = reason
+		return assessment
+	}
+
+	// Pre-trade liquidation guard driven by the live margin stream
+	if approved, reason := rm.assessMarginRisk(follower, trade); !approved {
 		assessment.Approved = false
-		assessment.Reason = "Overtrading detected - too many trades in short period"
+		assessment.Reason = reason
 		return assessment
 	}
 
+	// Reject trades on an asset the registry has flagged as delisted before
+	// spending any more risk budget on it.
+	if rm.assets != nil {
+		if meta, ok := rm.assets.Get(trade.Asset); ok && meta.IsDelisted {
+			assessment.Approved = false
+			assessment.Reason = "Asset is delisted"
+			return assessment
+		}
+	}
+
 	// Asset-specific risk checks
 	assetRisk := rm.assessAssetRisk(trade.Asset)
 	assessment.RiskScore += assetRisk
 
+	// Volatility-adaptive sizing: shrink the position on a currently-volatile
+	// asset and let it run closer to full size on a quiet one, instead of
+	// relying on the static per-asset table alone.
+	assessment.AdjustedSize *= rm.volatilitySizeMultiplier(trade.Asset)
+
 	// Time-based risk (avoid trading during high volatility periods)
-	timeRisk := rm.assessTimeRisk()
+	timeRisk := rm.assessTimeRisk(now)
 	assessment.RiskScore += timeRisk
 
 	// Position concentration risk
 	concentrationRisk := rm.assessConcentrationRisk(follower, trade)
 	assessment.RiskScore += concentrationRisk
 
+	// A follower whose historical profit factor has slipped below 1, or
+	// whose Sortino ratio has turned negative, is bleeding money even if
+	// today's trade looks clean in isolation — penalize it here.
+	if history := rm.recentTrades(follower); len(history) > 0 {
+		perf := rm.PerformanceReport(follower, history)
+		if perf.TotalTrades > 0 {
+			if perf.ProfitFactor < 1.0 {
+				assessment.RiskScore += 0.2
+			}
+			if perf.SortinoRatio < 0 {
+				assessment.RiskScore += 0.2
+			}
+		}
+	}
+
 	// Apply risk-based position sizing
 	if assessment.RiskScore > 0.5 {
 		riskAdjustment := 1.0 - (assessment.RiskScore * 0.5)
 		assessment.AdjustedSize *= riskAdjustment
-		
+
 		log.Info().
 			Int("follower_id", follower.ID).
 			Float64("risk_score", assessment.RiskScore).
@@ -100,49 +261,223 @@ func (rm *RiskManager) AssessRisk(follower *models.Follower, trade *models.Trade
 	return assessment
 }
 
-func (rm *RiskManager) isOvertrading(follower *models.Follower) bool {
-	// Check for maximum trades per hour/day from risk settings
-	if follower.RiskSettings != nil {
-		if maxTradesPerHour, exists := follower.RiskSettings["max_trades_per_hour"]; exists {
-			if maxTrades, ok := maxTradesPerHour.(float64); ok {
-				// In a real implementation, query recent trades from database
-				// For now, return false as we don't have trade history accessible here
-				_ = maxTrades
-			}
+// riskSettingFloat reads a numeric value out of follower.RiskSettings,
+// returning def if the key is absent or not a float64 (risk settings are
+// decoded from JSON, so every number arrives as float64).
+func riskSettingFloat(follower *models.Follower, key string, def float64) float64 {
+	if follower.RiskSettings == nil {
+		return def
+	}
+	if v, exists := follower.RiskSettings[key]; exists {
+		if f, ok := v.(float64); ok {
+			return f
 		}
 	}
-	return false
+	return def
 }
 
+// isOvertrading enforces per-follower trade-frequency and notional limits
+// over sliding 1h/24h windows, plus a cooldown-after-loss rule: if the last
+// N trades on trade.Asset realized a cumulative loss beyond a configurable
+// threshold, same-direction trades on that asset are rejected for a
+// configurable cooldown period.
+func (rm *RiskManager) isOvertrading(follower *models.Follower, trade *models.Trade, now time.Time) (bool, string) {
+	if maxPerHour := riskSettingFloat(follower, "max_trades_per_hour", 0); maxPerHour > 0 {
+		if count := len(rm.history.Since(follower.ID, now.Add(-time.Hour))); float64(count) >= maxPerHour {
+			log.Warn().Int("follower_id", follower.ID).Int("count", count).Float64("limit", maxPerHour).
+				Msg("Rejecting trade: max_trades_per_hour exceeded")
+			return true, "Too many trades in the last hour"
+		}
+	}
+
+	if maxPerDay := riskSettingFloat(follower, "max_trades_per_day", 0); maxPerDay > 0 {
+		if count := len(rm.history.Since(follower.ID, now.Add(-24*time.Hour))); float64(count) >= maxPerDay {
+			log.Warn().Int("follower_id", follower.ID).Int("count", count).Float64("limit", maxPerDay).
+				Msg("Rejecting trade: max_trades_per_day exceeded")
+			return true, "Too many trades in the last 24 hours"
+		}
+	}
+
+	if maxNotionalPerHour := riskSettingFloat(follower, "max_notional_per_hour", 0); maxNotionalPerHour > 0 {
+		var notional float64
+		for _, t := range rm.history.Since(follower.ID, now.Add(-time.Hour)) {
+			notional += t.Size * t.Price
+		}
+		if notional+trade.Size*trade.Price > maxNotionalPerHour {
+			log.Warn().Int("follower_id", follower.ID).Float64("notional", notional).Float64("limit", maxNotionalPerHour).
+				Msg("Rejecting trade: max_notional_per_hour exceeded")
+			return true, "Hourly notional limit exceeded"
+		}
+	}
+
+	if rejected, reason := rm.isInLossCooldown(follower, trade, now); rejected {
+		return true, reason
+	}
+
+	return false, ""
+}
+
+// isInLossCooldown looks at the last cooldown_after_loss_trades trades on
+// trade.Asset; if their cumulative PnL is a loss beyond
+// cooldown_after_loss_threshold, same-direction trades on that asset are
+// rejected until cooldown_after_loss_minutes has elapsed since the last of
+// those losing trades.
+func (rm *RiskManager) isInLossCooldown(follower *models.Follower, trade *models.Trade, now time.Time) (bool, string) {
+	lookback := int(riskSettingFloat(follower, "cooldown_after_loss_trades", 0))
+	threshold := riskSettingFloat(follower, "cooldown_after_loss_threshold", 0)
+	if lookback <= 0 || threshold <= 0 {
+		return false, ""
+	}
+
+	var assetTrades []models.Trade
+	for _, t := range rm.history.Since(follower.ID, time.Time{}) {
+		if t.Asset == trade.Asset {
+			assetTrades = append(assetTrades, t)
+		}
+	}
+	if len(assetTrades) < lookback {
+		return false, ""
+	}
+
+	recent := assetTrades[len(assetTrades)-lookback:]
+	var cumulativePnL float64
+	for _, t := range recent {
+		cumulativePnL += rm.calculateTradePnL(t)
+	}
+	if cumulativePnL > -threshold {
+		return false, ""
+	}
+
+	cooldownMinutes := riskSettingFloat(follower, "cooldown_after_loss_minutes", 60)
+	lastLoss := recent[len(recent)-1].ExecutedAt
+	if trade.Side == recent[len(recent)-1].Side && now.Sub(lastLoss) < time.Duration(cooldownMinutes)*time.Minute {
+		log.Warn().Int("follower_id", follower.ID).Str("asset", trade.Asset).Float64("pnl", cumulativePnL).
+			Msg("Rejecting trade: cooldown after losses")
+		return true, "In cooldown after recent losses on this asset"
+	}
+
+	return false, ""
+}
+
+// staticAssetRisk is the fallback risk table used only until
+// AssetVolatilityProvider has collected enough candle history for an asset —
+// it is no longer the source of truth once real ATR/stddev data exists.
+var staticAssetRisk = map[string]float64{
+	"BTC":  0.1,
+	"ETH":  0.15,
+	"SOL":  0.25,
+	"AVAX": 0.3,
+	"DOGE": 0.4,
+	"PEPE": 0.6,
+}
+
+// targetVolatilityPct is the return-stddev (in percent) AssessRisk treats as
+// "normal" risk for volatility-adaptive sizing; assets trading above it get
+// sized down, assets trading below it get sized up (within sizeMultiplierMin/Max).
+const targetVolatilityPct = 2.0
+
+const (
+	sizeMultiplierMin = 0.25
+	sizeMultiplierMax = 1.5
+)
+
+// maxLeverageRiskReference normalizes AssetMeta.MaxLeverage into a 0-1 risk
+// component: Hyperliquid caps its most liquid majors (BTC, ETH) around this
+// leverage, so an asset permitted far less leverage is signaling a thinner,
+// riskier market even before any realized-volatility data exists for it.
+const maxLeverageRiskReference = 50.0
+
+// volatilityWeight is how much assessAssetRisk trusts realized volatility
+// over the exchange's max-leverage signal when both are available.
+const volatilityWeight = 0.7
+
+// assessAssetRisk scores an asset from its rolling realized volatility
+// (stddev of returns) and the exchange's own max-leverage signal, blending
+// the two when both are available. It falls back to the static table, and
+// finally a conservative default, for an asset neither source has data for
+// yet.
 func (rm *RiskManager) assessAssetRisk(asset string) float64 {
-	// Different assets have different risk profiles
-	riskScores := map[string]float64{
-		"BTC":  0.1,
-		"ETH":  0.15,
-		"SOL":  0.25,
-		"AVAX": 0.3,
-		"DOGE": 0.4,
-		"PEPE": 0.6,
+	volScore, hasVol := rm.volatilityScore(asset)
+	leverageScore, hasLeverage := rm.leverageScore(asset)
+
+	switch {
+	case hasVol && hasLeverage:
+		return volScore*volatilityWeight + leverageScore*(1-volatilityWeight)
+	case hasVol:
+		return volScore
+	case hasLeverage:
+		return leverageScore
 	}
 
-	if score, exists := riskScores[asset]; exists {
+	if score, exists := staticAssetRisk[asset]; exists {
 		return score
 	}
 
-	// Unknown assets get higher risk score
+	// Unknown, un-cached assets get a higher risk score.
 	return 0.5
 }
 
-func (rm *RiskManager) assessTimeRisk() float64 {
-	now := time.Now().UTC()
+// volatilityScore is the realized-volatility component of assessAssetRisk,
+// 3x target vol saturating the score at 1.0. ok is false until
+// AssetVolatilityProvider has collected enough candle history for asset.
+func (rm *RiskManager) volatilityScore(asset string) (score float64, ok bool) {
+	if rm.volatility == nil {
+		return 0, false
+	}
+	v, cached := rm.volatility.Get(asset)
+	if !cached || v.stdDev <= 0 {
+		return 0, false
+	}
+	return utils.Min(v.stdDev/(targetVolatilityPct*3), 1.0), true
+}
+
+// leverageScore is the max-leverage component of assessAssetRisk. ok is
+// false until the AssetRegistry has cached metadata for asset.
+func (rm *RiskManager) leverageScore(asset string) (score float64, ok bool) {
+	if rm.assets == nil {
+		return 0, false
+	}
+	meta, cached := rm.assets.Get(asset)
+	if !cached || meta.MaxLeverage <= 0 {
+		return 0, false
+	}
+	return 1 - utils.Min(float64(meta.MaxLeverage)/maxLeverageRiskReference, 1.0), true
+}
+
+// volatilitySizeMultiplier scales a position inversely to an asset's current
+// realized volatility: adjustedSize = base * targetVol / currentVol, clamped
+// to [sizeMultiplierMin, sizeMultiplierMax] so a very quiet asset doesn't
+// blow the position up, and a very volatile one doesn't shrink it to
+// nothing.
+func (rm *RiskManager) volatilitySizeMultiplier(asset string) float64 {
+	if rm.volatility == nil {
+		return 1.0
+	}
+
+	v, ok := rm.volatility.Get(asset)
+	if !ok || v.stdDev <= 0 {
+		return 1.0
+	}
+
+	multiplier := targetVolatilityPct / v.stdDev
+	if multiplier < sizeMultiplierMin {
+		return sizeMultiplierMin
+	}
+	if multiplier > sizeMultiplierMax {
+		return sizeMultiplierMax
+	}
+	return multiplier
+}
+
+func (rm *RiskManager) assessTimeRisk(now time.Time) float64 {
 	hour := now.Hour()
 
 	// Higher risk during certain hours (market opens, etc.)
 	highRiskHours := map[int]float64{
-		0:  0.3, // Midnight UTC
-		8:  0.2, // European market open
+		0:  0.3,  // Midnight UTC
+		8:  0.2,  // European market open
 		13: 0.25, // US market open
-		21: 0.2, // Asian market open
+		21: 0.2,  // Asian market open
 	}
 
 	if risk, exists := highRiskHours[hour]; exists {
@@ -152,12 +487,43 @@ func (rm *RiskManager) assessTimeRisk() float64 {
 	return 0.1 // Base time risk
 }
 
+// concentrationFloor/concentrationCeiling bound the smooth risk curve: a
+// proposed trade contributes 0 risk below concentrationFloor of portfolio
+// notional and the full 1.0 at or above concentrationCeiling, scaling
+// linearly in between.
+const (
+	concentrationFloor   = 0.20
+	concentrationCeiling = 0.60
+)
+
+// assessConcentrationRisk scores how much a trade would concentrate the
+// follower's portfolio in a single asset: (proposed + existing asset
+// notional) / total portfolio notional, mapped onto a 0-1 curve that is
+// flat below concentrationFloor and saturates at concentrationCeiling.
 func (rm *RiskManager) assessConcentrationRisk(follower *models.Follower, trade *models.Trade) float64 {
-	// In a real implementation, this would check existing positions
-	// to prevent over-concentration in a single asset
-	
-	// For now, return base concentration risk
-	return 0.1
+	if rm.positions == nil {
+		return 0.1
+	}
+
+	proposedSize := trade.Size * (follower.CopyPercentage / 100.0)
+	proposedNotional := proposedSize * trade.Price
+
+	existingAssetNotional := rm.positions.AssetNotional(follower.APIWalletAddress, trade.Asset)
+	totalNotional := rm.positions.TotalNotional(follower.APIWalletAddress)
+
+	portfolioNotional := totalNotional + proposedNotional
+	if portfolioNotional <= 0 {
+		return 0
+	}
+
+	ratio := (existingAssetNotional + proposedNotional) / portfolioNotional
+	if ratio <= concentrationFloor {
+		return 0
+	}
+	if ratio >= concentrationCeiling {
+		return 1.0
+	}
+	return (ratio - concentrationFloor) / (concentrationCeiling - concentrationFloor)
 }
 
 func (rm *RiskManager) ValidateFollowerSettings(follower *models.Follower) []string {
@@ -204,24 +570,60 @@ func (rm *RiskManager) ValidateFollowerSettings(follower *models.Follower) []str
 	return errors
 }
 
-func (rm *RiskManager) ShouldTriggerStopLoss(follower *models.Follower, currentPnL float64, entryValue float64) bool {
-	if follower.StopLossPercentage == nil {
+// stopLossThresholdPct returns the stop-loss trigger distance as a
+// percentage of entry value: an ATR-multiplier-based threshold
+// (follower.RiskSettings["atr_sl_mult"] times the asset's cached return
+// stddev) once volatility data exists for asset, otherwise the follower's
+// fixed StopLossPercentage.
+func (rm *RiskManager) stopLossThresholdPct(follower *models.Follower, asset string) float64 {
+	if mult := riskSettingFloat(follower, "atr_sl_mult", 0); mult > 0 && rm.volatility != nil {
+		if v, ok := rm.volatility.Get(asset); ok && v.stdDev > 0 {
+			return mult * v.stdDev
+		}
+	}
+	if follower.StopLossPercentage != nil {
+		return *follower.StopLossPercentage
+	}
+	return 0
+}
+
+// takeProfitThresholdPct is stopLossThresholdPct's take-profit counterpart,
+// using atr_tp_mult.
+func (rm *RiskManager) takeProfitThresholdPct(follower *models.Follower, asset string) float64 {
+	if mult := riskSettingFloat(follower, "atr_tp_mult", 0); mult > 0 && rm.volatility != nil {
+		if v, ok := rm.volatility.Get(asset); ok && v.stdDev > 0 {
+			return mult * v.stdDev
+		}
+	}
+	if follower.TakeProfitPercentage != nil {
+		return *follower.TakeProfitPercentage
+	}
+	return 0
+}
+
+// ShouldTriggerStopLoss reports whether a position's PnL has breached
+// asset's volatility-adjusted stop-loss threshold (see
+// stopLossThresholdPct), falling back to the follower's fixed
+// StopLossPercentage when no ATR data is available.
+func (rm *RiskManager) ShouldTriggerStopLoss(follower *models.Follower, asset string, currentPnL float64, entryValue float64) bool {
+	threshold := rm.stopLossThresholdPct(follower, asset)
+	if threshold <= 0 {
 		return false
 	}
 
 	pnlPercentage := (currentPnL / entryValue) * 100
-	
-	return pnlPercentage <= -*follower.StopLossPercentage
+	return pnlPercentage <= -threshold
 }
 
-func (rm *RiskManager) ShouldTriggerTakeProfit(follower *models.Follower, currentPnL float64, entryValue float64) bool {
-	if follower.TakeProfitPercentage == nil {
+// ShouldTriggerTakeProfit is ShouldTriggerStopLoss's take-profit counterpart.
+func (rm *RiskManager) ShouldTriggerTakeProfit(follower *models.Follower, asset string, currentPnL float64, entryValue float64) bool {
+	threshold := rm.takeProfitThresholdPct(follower, asset)
+	if threshold <= 0 {
 		return false
 	}
 
 	pnlPercentage := (currentPnL / entryValue) * 100
-	
-	return pnlPercentage >= *follower.TakeProfitPercentage
+	return pnlPercentage >= threshold
 }
 
 func (rm *RiskManager) CalculateMaxDrawdown(trades []models.Trade) float64 {
@@ -236,11 +638,11 @@ func (rm *RiskManager) CalculateMaxDrawdown(trades []models.Trade) float64 {
 	for _, trade := range trades {
 		tradePnL := rm.calculateTradePnL(trade)
 		runningPnL += tradePnL
-		
+
 		if runningPnL > maxPnL {
 			maxPnL = runningPnL
 		}
-		
+
 		drawdown := maxPnL - runningPnL
 		if drawdown > maxDrawdown {
 			maxDrawdown = drawdown
@@ -259,49 +661,44 @@ func (rm *RiskManager) calculateTradePnL(trade models.Trade) float64 {
 	}
 }
 
+// GetRiskMetrics is kept for existing JSON consumers that expect a loose
+// map; PerformanceReport is the strongly-typed equivalent and should be
+// preferred by new callers.
 func (rm *RiskManager) GetRiskMetrics(follower *models.Follower, trades []models.Trade) map[string]interface{} {
-	totalTrades := len(trades)
-	if totalTrades == 0 {
-		return map[string]interface{}{
-			"total_trades":     0,
-			"win_rate":        0.0,
-			"max_drawdown":    0.0,
-			"risk_score":      0.0,
-		}
-	}
-
-	profitableTrades := 0
-	var totalPnL float64
+	report := rm.PerformanceReport(follower, trades)
 
-	for _, trade := range trades {
-		tradePnL := rm.calculateTradePnL(trade)
-		totalPnL += tradePnL
-		if tradePnL > 0 {
-			profitableTrades++
-		}
-	}
-
-	winRate := float64(profitableTrades) / float64(totalTrades)
-	maxDrawdown := rm.CalculateMaxDrawdown(trades)
-	
-	// Calculate overall risk score
 	riskScore := 0.0
-	if winRate < 0.3 {
+	if report.TotalTrades > 0 && report.WinRate < 30 {
 		riskScore += 0.3
 	}
-	if maxDrawdown > 1000 { // Assuming USD
+	if report.MaxDrawdown > 1000 { // Assuming USD
 		riskScore += 0.4
 	}
-	if totalTrades > 100 { // High frequency trading
+	if report.TotalTrades > 100 { // High frequency trading
 		riskScore += 0.2
 	}
 
-	return map[string]interface{}{
-		"total_trades":     totalTrades,
-		"profitable_trades": profitableTrades,
-		"win_rate":        winRate,
-		"total_pnl":       totalPnL,
-		"max_drawdown":    maxDrawdown,
-		"risk_score":      riskScore,
+	metrics := map[string]interface{}{
+		"total_trades":       report.TotalTrades,
+		"profitable_trades":  report.ProfitableTrades,
+		"win_rate":           report.WinRate,
+		"total_pnl":          report.TotalPnL,
+		"max_drawdown":       report.MaxDrawdown,
+		"risk_score":         riskScore,
+		"performance_report": report,
+	}
+
+	if rm.positions != nil {
+		metrics["open_positions"] = rm.positions.Positions(follower.APIWalletAddress)
+		metrics["total_notional"] = rm.positions.TotalNotional(follower.APIWalletAddress)
+	}
+
+	rm.marginMutex.RLock()
+	if state, exists := rm.marginStates[follower.APIWalletAddress]; exists {
+		metrics["margin_risk_rate"] = state.RiskRate
+		metrics["margin_liquidation_price"] = state.LiquidationPrice
 	}
+	rm.marginMutex.RUnlock()
+
+	return metrics
 }
@@ -0,0 +1,249 @@
+package engine
+
+import (
+	"math"
+	"time"
+
+	"hyperliquid-copy-trading/internal/models"
+	"hyperliquid-copy-trading/internal/utils"
+)
+
+// RiskReport is a strongly-typed, trader-grade statistics summary for one
+// follower's trade history, suitable for persistence or serving over the
+// API without the ambiguity of a map[string]interface{}.
+type RiskReport struct {
+	TotalTrades         int           `json:"total_trades"`
+	ProfitableTrades    int           `json:"profitable_trades"`
+	WinRate             float64       `json:"win_rate"`
+	TotalPnL            float64       `json:"total_pnl"`
+	AvgWin              float64       `json:"avg_win"`
+	AvgLoss             float64       `json:"avg_loss"`
+	ProfitFactor        float64       `json:"profit_factor"`
+	Expectancy          float64       `json:"expectancy"`
+	SharpeRatio         float64       `json:"sharpe_ratio"`
+	SortinoRatio        float64       `json:"sortino_ratio"`
+	CalmarRatio         float64       `json:"calmar_ratio"`
+	MaxDrawdown         float64       `json:"max_drawdown"`
+	MaxDrawdownDuration int           `json:"max_drawdown_duration_trades"`
+	MaxDrawdownWallTime time.Duration `json:"max_drawdown_wall_time"`
+	LongestWinStreak    int           `json:"longest_win_streak"`
+	LongestLossStreak   int           `json:"longest_loss_streak"`
+}
+
+// ClosedTrade is one FIFO-matched entry/exit pair with its realized PnL.
+type ClosedTrade struct {
+	PnL      float64
+	Notional float64
+	ClosedAt time.Time
+}
+
+// MatchTradesFIFO pairs opposite-side trades on the same asset in FIFO
+// order, treating "buy" as opening a long lot and "sell" as closing the
+// oldest open long lot (and vice versa for short lots opened by "sell").
+// Unmatched remainder lots (no opposing trade yet) are dropped — they
+// haven't realized any PnL.
+func MatchTradesFIFO(trades []models.Trade) []ClosedTrade {
+	type lot struct {
+		size  float64
+		price float64
+	}
+
+	longLots := make(map[string][]lot)
+	shortLots := make(map[string][]lot)
+	var closed []ClosedTrade
+
+	for _, t := range trades {
+		remaining := t.Size
+
+		if t.Side == "buy" {
+			// Close out existing short lots first, FIFO.
+			shorts := shortLots[t.Asset]
+			for remaining > 0 && len(shorts) > 0 {
+				l := shorts[0]
+				matched := utils.Min(remaining, l.size)
+				closed = append(closed, ClosedTrade{
+					PnL:      (l.price - t.Price) * matched,
+					Notional: matched * t.Price,
+					ClosedAt: t.ExecutedAt,
+				})
+				remaining -= matched
+				l.size -= matched
+				if l.size <= 0 {
+					shorts = shorts[1:]
+				} else {
+					shorts[0] = l
+				}
+			}
+			shortLots[t.Asset] = shorts
+			if remaining > 0 {
+				longLots[t.Asset] = append(longLots[t.Asset], lot{size: remaining, price: t.Price})
+			}
+		} else {
+			longs := longLots[t.Asset]
+			for remaining > 0 && len(longs) > 0 {
+				l := longs[0]
+				matched := utils.Min(remaining, l.size)
+				closed = append(closed, ClosedTrade{
+					PnL:      (t.Price - l.price) * matched,
+					Notional: matched * t.Price,
+					ClosedAt: t.ExecutedAt,
+				})
+				remaining -= matched
+				l.size -= matched
+				if l.size <= 0 {
+					longs = longs[1:]
+				} else {
+					longs[0] = l
+				}
+			}
+			longLots[t.Asset] = longs
+			if remaining > 0 {
+				shortLots[t.Asset] = append(shortLots[t.Asset], lot{size: remaining, price: t.Price})
+			}
+		}
+	}
+
+	return closed
+}
+
+// PerformanceReport computes a full set of trader-grade statistics for
+// follower from its matched trade history, replacing the crude win-rate/PnL
+// pair previously returned by GetRiskMetrics.
+func (rm *RiskManager) PerformanceReport(follower *models.Follower, trades []models.Trade) *RiskReport {
+	closed := MatchTradesFIFO(trades)
+
+	report := &RiskReport{}
+	if len(closed) == 0 {
+		return report
+	}
+
+	report.TotalTrades = len(closed)
+
+	var grossWin, grossLoss, sumWin, sumLoss float64
+	var returns []float64
+	var downsideReturns []float64
+	var equity []float64
+	runningEquity := 0.0
+
+	winStreak, lossStreak := 0, 0
+	for _, ct := range closed {
+		report.TotalPnL += ct.PnL
+		runningEquity += ct.PnL
+		equity = append(equity, runningEquity)
+
+		ret := utils.SafeDivide(ct.PnL, ct.Notional)
+		returns = append(returns, ret)
+		if ret < 0 {
+			downsideReturns = append(downsideReturns, ret)
+		}
+
+		if ct.PnL > 0 {
+			report.ProfitableTrades++
+			grossWin += ct.PnL
+			sumWin++
+			winStreak++
+			lossStreak = 0
+		} else if ct.PnL < 0 {
+			grossLoss += -ct.PnL
+			sumLoss++
+			lossStreak++
+			winStreak = 0
+		}
+
+		if winStreak > report.LongestWinStreak {
+			report.LongestWinStreak = winStreak
+		}
+		if lossStreak > report.LongestLossStreak {
+			report.LongestLossStreak = lossStreak
+		}
+	}
+
+	report.WinRate = utils.CalculateWinRate(report.ProfitableTrades, report.TotalTrades)
+	report.AvgWin = utils.SafeDivide(grossWin, sumWin)
+	report.AvgLoss = utils.SafeDivide(grossLoss, sumLoss)
+	report.ProfitFactor = utils.SafeDivide(grossWin, grossLoss)
+	report.Expectancy = utils.SafeDivide(report.TotalPnL, float64(report.TotalTrades))
+
+	report.SharpeRatio = utils.CalculateSharpeRatio(returns, 0)
+	report.SortinoRatio = sortinoRatio(returns, downsideReturns)
+	report.MaxDrawdown = utils.CalculateMaxDrawdown(equity)
+
+	duration, wallTime := maxDrawdownDuration(closed)
+	report.MaxDrawdownDuration = duration
+	report.MaxDrawdownWallTime = wallTime
+
+	if report.MaxDrawdown > 0 {
+		annualizedReturn := utils.CalculateCompoundAnnualGrowthRate(1, 1+utils.SafeDivide(report.TotalPnL, 1), 1)
+		report.CalmarRatio = utils.SafeDivide(annualizedReturn, report.MaxDrawdown)
+	}
+
+	return report
+}
+
+// sortinoRatio is CalculateSharpeRatio's denominator swapped for downside
+// deviation: only negative returns contribute to the risk measure, so a
+// strategy with volatile upside but no downside scores well.
+func sortinoRatio(returns, downsideReturns []float64) float64 {
+	if len(returns) == 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, r := range returns {
+		sum += r
+	}
+	meanReturn := sum / float64(len(returns))
+
+	if len(downsideReturns) == 0 {
+		return 0
+	}
+
+	var downsideVariance float64
+	for _, r := range downsideReturns {
+		downsideVariance += r * r
+	}
+	downsideDeviation := math.Sqrt(utils.SafeDivide(downsideVariance, float64(len(downsideReturns))))
+	if downsideDeviation == 0 {
+		return 0
+	}
+
+	return meanReturn / downsideDeviation
+}
+
+// maxDrawdownDuration walks the closed-trade PnL series and returns the
+// longest stretch (in trade count and wall-clock time) spent below a prior
+// equity peak before a new high was made.
+func maxDrawdownDuration(closed []ClosedTrade) (int, time.Duration) {
+	if len(closed) == 0 {
+		return 0, 0
+	}
+
+	var equity float64
+	peak := 0.0
+	peakTime := closed[0].ClosedAt
+
+	var maxTrades, curTrades int
+	var maxWall, curWall time.Duration
+
+	for _, ct := range closed {
+		equity += ct.PnL
+		if equity >= peak {
+			peak = equity
+			peakTime = ct.ClosedAt
+			curTrades = 0
+			curWall = 0
+			continue
+		}
+
+		curTrades++
+		curWall = ct.ClosedAt.Sub(peakTime)
+		if curTrades > maxTrades {
+			maxTrades = curTrades
+		}
+		if curWall > maxWall {
+			maxWall = curWall
+		}
+	}
+
+	return maxTrades, maxWall
+}
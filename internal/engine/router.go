@@ -0,0 +1,105 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"hyperliquid-copy-trading/internal/models"
+)
+
+// OrderIntent is one order a MirrorStrategy wants submitted for a specific
+// follower -- the unit Router dispatches to that follower's Session. It
+// carries no venue or rate-limit concerns; those live on the Session
+// FollowerID resolves to. OnResult, if set, is called by Dispatch with this
+// intent's submission error (nil on success) once known -- DeltaMirror uses
+// it to only advance its believed position after a dispatch actually
+// succeeds, instead of assuming success the moment the intent is built.
+type OrderIntent struct {
+	FollowerID int
+	Order      *models.EnhancedOrderRequest
+	OnResult   func(err error)
+}
+
+// MirrorStrategy decides what orders, if any, one leader trade should
+// produce for each of its followers, decoupling that decision from Router's
+// job of executing it. ProportionalMirror reproduces today's
+// CopyFilters-then-percentage behavior; DeltaMirror instead reconciles
+// position deltas so a follower self-heals after a missed trade.
+type MirrorStrategy interface {
+	OnLeaderTrade(ctx context.Context, leader string, trade models.TradeEvent, followers []*models.PermissionlessFollower) []OrderIntent
+}
+
+// Router maps a follower ID to its Session and serializes order dispatch
+// per session, so any one follower's exchange account receives orders one
+// at a time even when a MirrorStrategy fans a single leader event out to
+// many followers concurrently. Orders dispatched concurrently to the same
+// session are not kept in the order the leader's trades happened.
+type Router struct {
+	mu       sync.RWMutex
+	sessions map[int]*Session
+}
+
+// NewRouter builds an empty Router; sessions are added with AddSession as
+// followers are onboarded.
+func NewRouter() *Router {
+	return &Router{sessions: make(map[int]*Session)}
+}
+
+// AddSession registers (or replaces) the Session handling followerID's
+// orders.
+func (r *Router) AddSession(session *Session) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessions[session.FollowerID] = session
+}
+
+// RemoveSession stops routing orders to followerID. Intents already
+// in-flight to it are unaffected.
+func (r *Router) RemoveSession(followerID int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.sessions, followerID)
+}
+
+// SessionFor looks up followerID's Session.
+func (r *Router) SessionFor(followerID int) (*Session, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s, ok := r.sessions[followerID]
+	return s, ok
+}
+
+// Dispatch submits every intent to its follower's Session concurrently --
+// one slow or rate-limited follower doesn't hold up the rest -- and returns
+// one error per intent (nil on success), in the same order as intents.
+// Orders to the same FollowerID within one Dispatch call still serialize
+// through that Session's own lock.
+func (r *Router) Dispatch(ctx context.Context, intents []OrderIntent) []error {
+	errs := make([]error, len(intents))
+
+	var wg sync.WaitGroup
+	for i, intent := range intents {
+		session, ok := r.SessionFor(intent.FollowerID)
+		if !ok {
+			errs[i] = fmt.Errorf("router: no session for follower %d", intent.FollowerID)
+			if intent.OnResult != nil {
+				intent.OnResult(errs[i])
+			}
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, session *Session, intent OrderIntent) {
+			defer wg.Done()
+			_, err := session.SubmitOrder(ctx, intent.Order)
+			errs[i] = err
+			if intent.OnResult != nil {
+				intent.OnResult(err)
+			}
+		}(i, session, intent)
+	}
+	wg.Wait()
+
+	return errs
+}
@@ -0,0 +1,100 @@
+package engine
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"hyperliquid-copy-trading/internal/models"
+)
+
+// sessionBucket is a count-based rate limiter: tokens refill continuously
+// at refillPerSec up to capacity, and take blocks until one is available or
+// ctx is done. It's the same shape as api's weight-based tokenBucket,
+// narrowed to "one order" per take since Session has no per-request weight
+// to charge.
+type sessionBucket struct {
+	mu           sync.Mutex
+	capacity     float64
+	tokens       float64
+	refillPerSec float64
+	lastRefill   time.Time
+}
+
+func newSessionBucket(capacityPerMinute int) *sessionBucket {
+	return &sessionBucket{
+		capacity:     float64(capacityPerMinute),
+		tokens:       float64(capacityPerMinute),
+		refillPerSec: float64(capacityPerMinute) / 60,
+		lastRefill:   time.Now(),
+	}
+}
+
+func (b *sessionBucket) take(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.lastRefill).Seconds()*b.refillPerSec)
+		b.lastRefill = now
+
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+
+		wait := time.Duration((1 - b.tokens) / b.refillPerSec * float64(time.Second))
+		b.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// Session wraps one follower's execution context: the API wallet orders are
+// signed against, a per-session order-rate budget independent of the shared
+// HyperliquidAPI limiter, and the client orders are actually placed
+// through. Router holds one per follower so a MirrorStrategy's OrderIntents
+// can be dispatched without knowing anything about rate limits or which
+// venue account a follower trades from.
+type Session struct {
+	FollowerID       int
+	APIWalletAddress string
+
+	client PermissionlessOrderClient
+	bucket *sessionBucket
+
+	// mu serializes SubmitOrder so one follower's orders reach the exchange
+	// one at a time. It does not order them: concurrent calls go through in
+	// whichever order they acquire mu, which need not be the order Router
+	// dispatched them in.
+	mu sync.Mutex
+}
+
+// NewSession builds a Session that rate-limits its own PlaceOrder calls to
+// ordersPerMinute, independent of client's own limiting.
+func NewSession(followerID int, apiWalletAddress string, client PermissionlessOrderClient, ordersPerMinute int) *Session {
+	return &Session{
+		FollowerID:       followerID,
+		APIWalletAddress: apiWalletAddress,
+		client:           client,
+		bucket:           newSessionBucket(ordersPerMinute),
+	}
+}
+
+// SubmitOrder rate-limits and places order against this session's API
+// wallet, serialized against any other concurrent SubmitOrder call on the
+// same Session.
+func (s *Session) SubmitOrder(ctx context.Context, order *models.EnhancedOrderRequest) (*models.OrderResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.bucket.take(ctx); err != nil {
+		return nil, err
+	}
+	return s.client.PlaceOrder(ctx, order, models.TradingAccount{APIWallet: s.APIWalletAddress})
+}
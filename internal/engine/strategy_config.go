@@ -0,0 +1,355 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"hyperliquid-copy-trading/internal/models"
+	"hyperliquid-copy-trading/internal/utils"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+// StrategyConfig is the declarative, file-based counterpart to the
+// imperative AddPermissionlessFollower/UpdatePermissionlessFollower/
+// RemovePermissionlessFollower calls -- the same shape bbgo's
+// crossExchangeStrategies section serves for its own strategies, scoped
+// here to one engine's followers.
+type StrategyConfig struct {
+	Followers []FollowerConfig `yaml:"followers" json:"followers"`
+}
+
+// FollowerConfig declares one PermissionlessFollower. UserID plus
+// TargetTraderAddress is this follower's identity within the config --
+// changing either is treated as removing the old follower and adding a
+// new one, since both are immutable on the underlying model.
+type FollowerConfig struct {
+	UserID               string              `yaml:"user_id" json:"user_id"`
+	TargetTraderAddress  string              `yaml:"target_trader_address" json:"target_trader_address"`
+	APIWalletAddress     string              `yaml:"api_wallet_address" json:"api_wallet_address"`
+	CopyPercentage       float64             `yaml:"copy_percentage" json:"copy_percentage"`
+	MaxPositionSize      float64             `yaml:"max_position_size" json:"max_position_size"`
+	MinTradeSize         float64             `yaml:"min_trade_size" json:"min_trade_size"`
+	AssetWhitelist       []string            `yaml:"asset_whitelist,omitempty" json:"asset_whitelist,omitempty"`
+	AssetBlacklist       []string            `yaml:"asset_blacklist,omitempty" json:"asset_blacklist,omitempty"`
+	AutoDiscoveryEnabled bool                `yaml:"auto_discovery_enabled" json:"auto_discovery_enabled"`
+	CopyFilters          *models.CopyFilters `yaml:"copy_filters,omitempty" json:"copy_filters,omitempty"`
+	IsActive             bool                `yaml:"is_active" json:"is_active"`
+}
+
+// key identifies this follower within a StrategyConfig, independent of any
+// database id.
+func (f FollowerConfig) key() string {
+	return followerKey(f.UserID, f.TargetTraderAddress)
+}
+
+func followerKey(userID, targetTraderAddress string) string {
+	return userID + "|" + targetTraderAddress
+}
+
+// toModel converts f into the models.PermissionlessFollower shape the
+// engine's Add/Update calls take. id is 0 for a not-yet-created follower.
+func (f FollowerConfig) toModel(id int) *models.PermissionlessFollower {
+	return &models.PermissionlessFollower{
+		ID:                   id,
+		UserID:               f.UserID,
+		TargetTraderAddress:  f.TargetTraderAddress,
+		APIWalletAddress:     f.APIWalletAddress,
+		CopyPercentage:       f.CopyPercentage,
+		MaxPositionSize:      f.MaxPositionSize,
+		MinTradeSize:         f.MinTradeSize,
+		AssetWhitelist:       f.AssetWhitelist,
+		AssetBlacklist:       f.AssetBlacklist,
+		AutoDiscoveryEnabled: f.AutoDiscoveryEnabled,
+		CopyFilters:          f.CopyFilters,
+		IsActive:             f.IsActive,
+	}
+}
+
+// mutableFieldsEqual reports whether f and existing would produce the same
+// UpdatePermissionlessFollower call -- i.e. nothing actually changed.
+func (f FollowerConfig) mutableFieldsEqual(existing *models.PermissionlessFollower) bool {
+	return f.CopyPercentage == existing.CopyPercentage &&
+		f.MaxPositionSize == existing.MaxPositionSize &&
+		f.MinTradeSize == existing.MinTradeSize &&
+		f.AutoDiscoveryEnabled == existing.AutoDiscoveryEnabled &&
+		f.IsActive == existing.IsActive &&
+		stringSliceEqual(f.AssetWhitelist, existing.AssetWhitelist) &&
+		stringSliceEqual(f.AssetBlacklist, existing.AssetBlacklist) &&
+		copyFiltersEqual(f.CopyFilters, existing.CopyFilters)
+}
+
+func stringSliceEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func copyFiltersEqual(a, b *models.CopyFilters) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+// LoadStrategyConfig parses a YAML or JSON strategy config from path,
+// chosen by its extension (.yaml/.yml vs everything else), and validates it.
+func LoadStrategyConfig(path string) (*StrategyConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading strategy config: %w", err)
+	}
+
+	var cfg StrategyConfig
+	ext := strings.ToLower(filepath.Ext(path))
+	switch ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &cfg)
+	default:
+		err = json.Unmarshal(data, &cfg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing strategy config: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid strategy config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// Validate rejects a config before it's ever handed to the engine, so a bad
+// edit fails the reload instead of taking down the running follower set.
+func (c *StrategyConfig) Validate() error {
+	seen := make(map[string]struct{}, len(c.Followers))
+	for i, f := range c.Followers {
+		if f.UserID == "" {
+			return fmt.Errorf("follower[%d]: user_id is required", i)
+		}
+		if !utils.ValidateAddress(f.TargetTraderAddress) {
+			return fmt.Errorf("follower[%d]: invalid target_trader_address %q", i, f.TargetTraderAddress)
+		}
+		if !utils.ValidateAddress(f.APIWalletAddress) {
+			return fmt.Errorf("follower[%d]: invalid api_wallet_address %q", i, f.APIWalletAddress)
+		}
+		if f.MaxPositionSize < 0 || f.MinTradeSize < 0 || f.CopyPercentage < 0 {
+			return fmt.Errorf("follower[%d]: position/trade sizes and copy_percentage must be non-negative", i)
+		}
+		if f.AssetWhitelist != nil && len(f.AssetWhitelist) == 0 {
+			return fmt.Errorf("follower[%d]: asset_whitelist, if present, must not be empty", i)
+		}
+		if f.AssetBlacklist != nil && len(f.AssetBlacklist) == 0 {
+			return fmt.Errorf("follower[%d]: asset_blacklist, if present, must not be empty", i)
+		}
+
+		key := f.key()
+		if _, dup := seen[key]; dup {
+			return fmt.Errorf("follower[%d]: duplicate user_id/target_trader_address pair %q", i, key)
+		}
+		seen[key] = struct{}{}
+	}
+	return nil
+}
+
+// strategyDiff is the set of changes reconcile would make to bring the
+// engine's tracked followers in line with a reloaded StrategyConfig.
+type strategyDiff struct {
+	additions []FollowerConfig
+	updates   []*models.PermissionlessFollower
+	removals  []*models.PermissionlessFollower
+}
+
+func (d strategyDiff) empty() bool {
+	return len(d.additions) == 0 && len(d.updates) == 0 && len(d.removals) == 0
+}
+
+// diffStrategyConfig compares cfg against the engine's currently tracked
+// followers and returns what it would take to match.
+func diffStrategyConfig(cfg *StrategyConfig, existing []*models.PermissionlessFollower) strategyDiff {
+	existingByKey := make(map[string]*models.PermissionlessFollower, len(existing))
+	for _, f := range existing {
+		existingByKey[followerKey(f.UserID, f.TargetTraderAddress)] = f
+	}
+
+	var diff strategyDiff
+	desiredKeys := make(map[string]struct{}, len(cfg.Followers))
+	for _, fc := range cfg.Followers {
+		key := fc.key()
+		desiredKeys[key] = struct{}{}
+
+		current, ok := existingByKey[key]
+		if !ok {
+			diff.additions = append(diff.additions, fc)
+			continue
+		}
+		if !fc.mutableFieldsEqual(current) {
+			diff.updates = append(diff.updates, fc.toModel(current.ID))
+		}
+	}
+
+	for key, current := range existingByKey {
+		if _, wanted := desiredKeys[key]; !wanted {
+			diff.removals = append(diff.removals, current)
+		}
+	}
+	return diff
+}
+
+// StrategyConfigLoader boots a PermissionlessCopyEngine's followers from a
+// YAML/JSON file instead of only imperative AddPermissionlessFollower
+// calls, and keeps them in sync as the file changes. Leaders that still
+// have at least one follower after a reload never lose their WebSocket
+// subscription, even if every one of their followers was updated in place.
+type StrategyConfigLoader struct {
+	engine *PermissionlessCopyEngine
+	path   string
+	dryRun bool
+
+	watcher  *fsnotify.Watcher
+	shutdown chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewStrategyConfigLoader returns a loader for path against engine. When
+// dryRun is true, Start and every subsequent reload only log the diff they
+// would apply.
+func NewStrategyConfigLoader(engine *PermissionlessCopyEngine, path string, dryRun bool) *StrategyConfigLoader {
+	return &StrategyConfigLoader{
+		engine:   engine,
+		path:     path,
+		dryRun:   dryRun,
+		shutdown: make(chan struct{}),
+	}
+}
+
+// Start loads and applies path once, synchronously, then watches its parent
+// directory for changes (fsnotify on the directory, not the file, because
+// most editors replace a file via rename rather than an in-place write) and
+// reconciles on every change until Stop is called.
+func (l *StrategyConfigLoader) Start(ctx context.Context) error {
+	if err := l.reload(ctx); err != nil {
+		return err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("starting strategy config watcher: %w", err)
+	}
+	if err := watcher.Add(filepath.Dir(l.path)); err != nil {
+		watcher.Close()
+		return fmt.Errorf("watching strategy config directory: %w", err)
+	}
+	l.watcher = watcher
+
+	l.wg.Add(1)
+	go l.watchLoop(ctx)
+	return nil
+}
+
+// Stop closes the watcher and waits for watchLoop to exit.
+func (l *StrategyConfigLoader) Stop() {
+	close(l.shutdown)
+	if l.watcher != nil {
+		l.watcher.Close()
+	}
+	l.wg.Wait()
+}
+
+func (l *StrategyConfigLoader) watchLoop(ctx context.Context) {
+	defer l.wg.Done()
+
+	// Debounce bursts of events a single save can produce (e.g. a
+	// truncate-then-write pair) into one reload.
+	const debounce = 200 * time.Millisecond
+	var debounceTimer *time.Timer
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-l.shutdown:
+			return
+		case event, ok := <-l.watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != filepath.Clean(l.path) {
+				continue
+			}
+			if debounceTimer != nil {
+				debounceTimer.Stop()
+			}
+			debounceTimer = time.AfterFunc(debounce, func() {
+				if err := l.reload(ctx); err != nil {
+					log.Error().Err(err).Str("path", l.path).Msg("Failed to reload strategy config")
+				}
+			})
+		case err, ok := <-l.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Error().Err(err).Str("path", l.path).Msg("Strategy config watcher error")
+		}
+	}
+}
+
+// reload parses l.path and reconciles the engine's tracked followers
+// against it, or -- in dry-run mode -- just logs what it would have done.
+func (l *StrategyConfigLoader) reload(ctx context.Context) error {
+	cfg, err := LoadStrategyConfig(l.path)
+	if err != nil {
+		return err
+	}
+
+	diff := diffStrategyConfig(cfg, l.engine.ListPermissionlessFollowers())
+	if diff.empty() {
+		return nil
+	}
+
+	if l.dryRun {
+		log.Info().
+			Int("additions", len(diff.additions)).
+			Int("updates", len(diff.updates)).
+			Int("removals", len(diff.removals)).
+			Str("path", l.path).
+			Msg("Strategy config dry-run: diff computed, not applied")
+		return nil
+	}
+
+	for _, fc := range diff.additions {
+		if err := l.engine.AddPermissionlessFollower(ctx, fc.toModel(0)); err != nil {
+			log.Error().Err(err).Str("user_id", fc.UserID).Str("trader", fc.TargetTraderAddress).
+				Msg("Strategy config reload: failed to add follower")
+		}
+	}
+	for _, follower := range diff.updates {
+		if err := l.engine.UpdatePermissionlessFollower(ctx, follower); err != nil {
+			log.Error().Err(err).Int("follower_id", follower.ID).Msg("Strategy config reload: failed to update follower")
+		}
+	}
+	for _, follower := range diff.removals {
+		if err := l.engine.RemovePermissionlessFollower(ctx, follower.ID, follower.TargetTraderAddress); err != nil {
+			log.Error().Err(err).Int("follower_id", follower.ID).Msg("Strategy config reload: failed to remove follower")
+		}
+	}
+
+	log.Info().
+		Int("additions", len(diff.additions)).
+		Int("updates", len(diff.updates)).
+		Int("removals", len(diff.removals)).
+		Str("path", l.path).
+		Msg("Strategy config reloaded")
+	return nil
+}
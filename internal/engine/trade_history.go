@@ -0,0 +1,91 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"hyperliquid-copy-trading/internal/database"
+	"hyperliquid-copy-trading/internal/models"
+)
+
+// tradeHistoryRingSize bounds how many recent trades are kept per follower
+// in memory; overtrading windows only ever look back 24h, so this is sized
+// generously above any realistic trade rate rather than tuned tightly.
+const tradeHistoryRingSize = 2000
+
+// TradeHistoryStore records approved copy trades and answers sliding-window
+// queries for the overtrading and cooldown-after-loss checks in AssessRisk.
+// The default implementation keeps a bounded in-memory ring buffer per
+// follower, seeded from Postgres on Load, so AssessRisk never blocks on a DB
+// round trip on the hot path.
+type TradeHistoryStore interface {
+	Record(followerID int, trade models.Trade)
+	Since(followerID int, since time.Time) []models.Trade
+	Load(ctx context.Context, followerID int) error
+}
+
+type ringTradeHistoryStore struct {
+	db *database.PostgresDB
+
+	mu      sync.RWMutex
+	buffers map[int][]models.Trade // oldest first, capped at tradeHistoryRingSize
+}
+
+// NewTradeHistoryStore returns the Postgres-backed ring buffer store. db may
+// be nil, in which case Load is a no-op and the store behaves as a pure
+// in-memory buffer (useful for the backtest/optimizer replay harnesses).
+func NewTradeHistoryStore(db *database.PostgresDB) TradeHistoryStore {
+	return &ringTradeHistoryStore{
+		db:      db,
+		buffers: make(map[int][]models.Trade),
+	}
+}
+
+func (s *ringTradeHistoryStore) Record(followerID int, trade models.Trade) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	buf := append(s.buffers[followerID], trade)
+	if len(buf) > tradeHistoryRingSize {
+		buf = buf[len(buf)-tradeHistoryRingSize:]
+	}
+	s.buffers[followerID] = buf
+}
+
+func (s *ringTradeHistoryStore) Since(followerID int, since time.Time) []models.Trade {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var out []models.Trade
+	for _, t := range s.buffers[followerID] {
+		if !t.ExecutedAt.Before(since) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// Load seeds the ring buffer for followerID from Postgres so sliding-window
+// checks are correct immediately after a restart instead of warming up from
+// empty.
+func (s *ringTradeHistoryStore) Load(ctx context.Context, followerID int) error {
+	if s.db == nil {
+		return nil
+	}
+
+	trades, err := s.db.GetTradesByFollower(ctx, followerID)
+	if err != nil {
+		return fmt.Errorf("loading trade history for follower %d: %w", followerID, err)
+	}
+
+	if len(trades) > tradeHistoryRingSize {
+		trades = trades[len(trades)-tradeHistoryRingSize:]
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buffers[followerID] = trades
+	return nil
+}
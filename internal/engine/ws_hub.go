@@ -0,0 +1,164 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"hyperliquid-copy-trading/internal/models"
+	"hyperliquid-copy-trading/internal/websocket"
+)
+
+// ServeWebSocket upgrades an incoming HTTP request into an outbound
+// WebSocket connection (a follower dashboard or similar subscriber),
+// registers it with the hub, and blocks until the client disconnects.
+func (ce *CopyEngine) ServeWebSocket(w http.ResponseWriter, r *http.Request) error {
+	return ce.hub.Serve(w, r, ce.dispatchRPC)
+}
+
+// GetOutboundConnections reports how many dashboards/subscribers currently
+// hold an open outbound WebSocket connection.
+func (ce *CopyEngine) GetOutboundConnections() int {
+	return ce.hub.Connections()
+}
+
+type followerParams struct {
+	FollowerID int `json:"follower_id"`
+}
+
+type leaderTradesParams struct {
+	LeaderAddress string `json:"leader_address"`
+	Coin          string `json:"coin,omitempty"`
+}
+
+type pnlParams struct {
+	FollowerID int `json:"follower_id"`
+}
+
+type unsubscribeParams struct {
+	SubscriptionID string `json:"subscription_id"`
+}
+
+// dispatchRPC handles one decoded RPCRequest from an outbound connection:
+// subscribeFollower/subscribeLeaderTrades/subscribeLeaderEvents/
+// subscribePnL/subscribeHealth register a filtered subscription and
+// return its id; unsubscribe removes one by id.
+func (ce *CopyEngine) dispatchRPC(conn *websocket.Connection, req websocket.RPCRequest) websocket.RPCResponse {
+	switch req.Method {
+	case "subscribeFollower":
+		var params followerParams
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.FollowerID == 0 {
+			return errorResponse(req.ID, "follower_id is required")
+		}
+		id := conn.Subscribe("trade_fill", websocket.SubscriptionFilter{FollowerID: params.FollowerID})
+		return dataResponse(req.ID, map[string]string{"subscription_id": id})
+
+	case "subscribeLeaderTrades":
+		var params leaderTradesParams
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.LeaderAddress == "" {
+			return errorResponse(req.ID, "leader_address is required")
+		}
+		id := conn.Subscribe("leader_trade", websocket.SubscriptionFilter{
+			LeaderAddress: params.LeaderAddress,
+			Coin:          params.Coin,
+		})
+		return dataResponse(req.ID, map[string]string{"subscription_id": id})
+
+	case "subscribeLeaderEvents":
+		var params leaderTradesParams
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.LeaderAddress == "" {
+			return errorResponse(req.ID, "leader_address is required")
+		}
+		id := conn.Subscribe("leader_event", websocket.SubscriptionFilter{
+			LeaderAddress: params.LeaderAddress,
+			Coin:          params.Coin,
+		})
+		return dataResponse(req.ID, map[string]string{"subscription_id": id})
+
+	case "subscribePnL":
+		var params pnlParams
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.FollowerID == 0 {
+			return errorResponse(req.ID, "follower_id is required")
+		}
+		id := conn.Subscribe("pnl_update", websocket.SubscriptionFilter{FollowerID: params.FollowerID})
+		return dataResponse(req.ID, map[string]string{"subscription_id": id})
+
+	case "subscribeHealth":
+		id := conn.Subscribe("health", websocket.SubscriptionFilter{})
+		return dataResponse(req.ID, map[string]string{"subscription_id": id})
+
+	case "unsubscribe":
+		var params unsubscribeParams
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.SubscriptionID == "" {
+			return errorResponse(req.ID, "subscription_id is required")
+		}
+		if !conn.Unsubscribe(params.SubscriptionID) {
+			return errorResponse(req.ID, "unknown subscription_id")
+		}
+		return dataResponse(req.ID, map[string]bool{"unsubscribed": true})
+
+	default:
+		return errorResponse(req.ID, fmt.Sprintf("unknown method %q", req.Method))
+	}
+}
+
+func dataResponse(id interface{}, data interface{}) websocket.RPCResponse {
+	return websocket.RPCResponse{ID: id, Data: data}
+}
+
+func errorResponse(id interface{}, message string) websocket.RPCResponse {
+	return websocket.RPCResponse{ID: id, Error: message}
+}
+
+// relayOrderEvents forwards terminal order fills from the order engine's
+// lifecycle FSM to subscribeFollower subscribers, so a dashboard sees fills
+// as they happen instead of polling GetTrades.
+func (ce *CopyEngine) relayOrderEvents() {
+	events := ce.orderEngine.SubscribeOrderEvents()
+
+	for {
+		select {
+		case <-ce.shutdown:
+			return
+		case state, ok := <-events:
+			if !ok {
+				return
+			}
+			if state.Status != models.OrderStatusFilled {
+				continue
+			}
+			ce.hub.Publish(websocket.Event{
+				Kind:       "trade_fill",
+				FollowerID: state.FollowerID,
+				Coin:       state.Asset,
+				Data:       state,
+			})
+		}
+	}
+}
+
+// broadcastHealth periodically pushes a WebSocket/order-queue health
+// snapshot to subscribeHealth subscribers.
+func (ce *CopyEngine) broadcastHealth(ctx context.Context) {
+	ticker := time.NewTicker(15 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ce.shutdown:
+			return
+		case <-ticker.C:
+			ce.hub.Publish(websocket.Event{
+				Kind: "health",
+				Data: map[string]interface{}{
+					"websocket":   ce.GetWSHealth(),
+					"order_queue": ce.orderEngine.GetQueueStatus(),
+				},
+			})
+		}
+	}
+}
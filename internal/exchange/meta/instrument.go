@@ -0,0 +1,62 @@
+// Package meta caches per-coin Hyperliquid instrument limits (tick/lot
+// sizes, minimum notional, max leverage) derived from api.AssetRegistry, so
+// order sizing and validation can round to a valid tick and reject
+// sub-minimum copies without re-deriving szDecimals math at every call
+// site.
+package meta
+
+import (
+	"fmt"
+	"math"
+)
+
+// Instrument is one coin's exchange-enforced trading limits: the smallest
+// valid price and size increments, the minimum order notional, and the
+// maximum leverage Hyperliquid allows on that asset.
+type Instrument struct {
+	Coin           string  `json:"coin"`
+	SzDecimals     int     `json:"sz_decimals"`
+	PriceTickSize  float64 `json:"price_tick_size"`
+	AmountTickSize float64 `json:"amount_tick_size"`
+	MinNotional    float64 `json:"min_notional"`
+	MaxLeverage    int     `json:"max_leverage"`
+}
+
+// RoundPrice rounds price down to the instrument's PriceTickSize whatever
+// the order's side: a buy's price never moves up, while a sell's price can
+// move down by up to one tick.
+func (i Instrument) RoundPrice(price float64) float64 {
+	return roundDownToTick(price, i.PriceTickSize)
+}
+
+// RoundSize rounds size down to the instrument's AmountTickSize.
+func (i Instrument) RoundSize(size float64) float64 {
+	return roundDownToTick(size, i.AmountTickSize)
+}
+
+// MeetsMinNotional reports whether size at price clears the instrument's
+// minimum order value.
+func (i Instrument) MeetsMinNotional(size, price float64) bool {
+	return size*price >= i.MinNotional
+}
+
+func roundDownToTick(value, tick float64) float64 {
+	if tick <= 0 {
+		return value
+	}
+	// +1e-9 guards against a value that's a tick multiple landing just
+	// under it due to float64 rounding (e.g. 0.30000000000000004).
+	return math.Floor(value/tick+1e-9) * tick
+}
+
+// ValidationError is a structured rejection reason for a copied order that
+// fails an Instrument's minimum-notional constraint, so callers can log or
+// surface the failure without parsing an error string.
+type ValidationError struct {
+	Coin   string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("instrument validation failed for %s: %s", e.Coin, e.Reason)
+}
@@ -0,0 +1,172 @@
+package meta
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"hyperliquid-copy-trading/internal/api"
+
+	"github.com/rs/zerolog/log"
+)
+
+// maxPriceDecimals is Hyperliquid's cap on decimal places for a perpetual's
+// limit price: a coin's PriceTickSize is 10^-(maxPriceDecimals-szDecimals).
+const maxPriceDecimals = 6
+
+// defaultMinNotionalUSD mirrors api.AssetRegistry's exchange-wide minimum
+// order value until Hyperliquid's meta endpoint publishes a per-coin
+// minimum.
+const defaultMinNotionalUSD = 10.0
+
+// refreshInterval is the nominal period between background refreshes;
+// refreshJitter spreads the actual interval so every deployed instance
+// doesn't hit the meta endpoint in lockstep.
+const (
+	refreshInterval = 5 * time.Minute
+	refreshJitter   = 30 * time.Second
+)
+
+// Registry is an RWMutex-guarded cache of per-coin Instrument limits,
+// rebuilt from api.AssetRegistry's szDecimals/maxLeverage/delisted cache so
+// tick-size and leverage enforcement always reflects the same metadata the
+// rest of the system trades against instead of fetching it a second time.
+type Registry struct {
+	assets *api.AssetRegistry
+
+	mu          sync.RWMutex
+	instruments map[string]Instrument
+}
+
+// NewRegistry builds an instrument cache backed by assets, populated
+// immediately from whatever assets has already cached.
+func NewRegistry(assets *api.AssetRegistry) *Registry {
+	r := &Registry{
+		assets:      assets,
+		instruments: make(map[string]Instrument),
+	}
+	r.Refresh()
+	return r
+}
+
+// Refresh re-derives every cached instrument's limits from the current
+// AssetRegistry snapshot. It does not itself re-fetch from Hyperliquid;
+// Run does that on a timer.
+func (r *Registry) Refresh() {
+	snapshot := r.assets.All()
+	instruments := make(map[string]Instrument, len(snapshot))
+	for coin, meta := range snapshot {
+		priceDecimals := maxPriceDecimals - meta.SzDecimals
+		if priceDecimals < 0 {
+			priceDecimals = 0
+		}
+		instruments[coin] = Instrument{
+			Coin:           coin,
+			SzDecimals:     meta.SzDecimals,
+			PriceTickSize:  1 / math.Pow(10, float64(priceDecimals)),
+			AmountTickSize: 1 / math.Pow(10, float64(meta.SzDecimals)),
+			MinNotional:    defaultMinNotionalUSD,
+			MaxLeverage:    meta.MaxLeverage,
+		}
+	}
+
+	r.mu.Lock()
+	r.instruments = instruments
+	r.mu.Unlock()
+}
+
+// Run refreshes the backing AssetRegistry from Hyperliquid and rebuilds the
+// instrument cache on a jittered interval, until stop is closed. It blocks,
+// so callers run it in their own goroutine.
+func (r *Registry) Run(ctx context.Context, stop <-chan struct{}) {
+	for {
+		interval := refreshInterval + time.Duration(rand.Int63n(int64(refreshJitter)))
+		select {
+		case <-stop:
+			return
+		case <-time.After(interval):
+		}
+
+		if err := r.assets.Refresh(ctx); err != nil {
+			log.Warn().Err(err).Msg("Failed to refresh asset registry for instrument cache")
+			continue
+		}
+		r.Refresh()
+	}
+}
+
+// Get returns coin's cached Instrument, and whether it is known.
+func (r *Registry) Get(coin string) (Instrument, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	inst, ok := r.instruments[coin]
+	return inst, ok
+}
+
+// All returns every cached Instrument, keyed by coin.
+func (r *Registry) All() map[string]Instrument {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make(map[string]Instrument, len(r.instruments))
+	for k, v := range r.instruments {
+		out[k] = v
+	}
+	return out
+}
+
+// RoundPrice rounds price to coin's PriceTickSize, passing it through
+// unchanged if coin isn't cached yet.
+func (r *Registry) RoundPrice(coin string, price float64) float64 {
+	inst, ok := r.Get(coin)
+	if !ok {
+		return price
+	}
+	return inst.RoundPrice(price)
+}
+
+// RoundSize rounds size to coin's AmountTickSize, passing it through
+// unchanged if coin isn't cached yet.
+func (r *Registry) RoundSize(coin string, size float64) float64 {
+	inst, ok := r.Get(coin)
+	if !ok {
+		return size
+	}
+	return inst.RoundSize(size)
+}
+
+// Validate checks size at price against coin's minimum order notional,
+// returning a *ValidationError if it falls short. A coin with no cached
+// Instrument yet is let through uncontested rather than blocking copies on
+// a cold cache.
+func (r *Registry) Validate(coin string, size, price float64) error {
+	inst, ok := r.Get(coin)
+	if !ok {
+		return nil
+	}
+	if !inst.MeetsMinNotional(size, price) {
+		return &ValidationError{
+			Coin:   coin,
+			Reason: fmt.Sprintf("notional %.4f below minimum %.2f", size*price, inst.MinNotional),
+		}
+	}
+	return nil
+}
+
+// MinNotional returns the lowest minimum order notional across every cached
+// instrument, for callers (e.g. follower validation) that need a floor on
+// a position-size limit without pinning it to one coin.
+func (r *Registry) MinNotional() float64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	min := defaultMinNotionalUSD
+	for _, inst := range r.instruments {
+		if inst.MinNotional < min {
+			min = inst.MinNotional
+		}
+	}
+	return min
+}
@@ -0,0 +1,67 @@
+package execution
+
+import (
+	"context"
+	"hyperliquid-copy-trading/internal/models"
+	"time"
+)
+
+// adaptiveTimeThreshold is the remaining-time fraction below which Adaptive
+// abandons its base algorithm for aggressive IOC execution.
+const adaptiveTimeThreshold = 0.10
+
+// Adaptive delegates to a base participation/volume algorithm until time is
+// running out or realized slippage is eating into budget, then switches to
+// an aggressive immediate-or-cancel fill of whatever remains.
+type Adaptive struct {
+	base Algorithm
+}
+
+// NewAdaptive wraps base (typically POV) with the escalation rule.
+func NewAdaptive(base Algorithm) *Adaptive {
+	return &Adaptive{base: base}
+}
+
+func (a *Adaptive) Name() string { return "adaptive" }
+
+func (a *Adaptive) Slice(ctx context.Context, order models.SmartCopyOrder, book models.L2Book) ([]models.OrderRequest, time.Duration) {
+	remaining := remainingSize(order)
+	if remaining <= 0 {
+		return nil, 0
+	}
+
+	if a.shouldEscalate(order) {
+		// "market" orders resolve to an Ioc time-in-force downstream (see
+		// HyperliquidAPI.getOrderTypeCode), which is the aggressive
+		// immediate-or-cancel fill this escalation is meant to produce.
+		return []models.OrderRequest{sliceOrder(order, remaining, order.Side == "buy", "market", nil)}, 0
+	}
+
+	return a.base.Slice(ctx, order, book)
+}
+
+// shouldEscalate switches to aggressive IOC execution once remaining time
+// drops under adaptiveTimeThreshold of the order's TimeLimit, or once
+// realized slippage already exceeds half the order's MaxSlippage budget —
+// waiting longer in either case only risks missing the fill entirely.
+func (a *Adaptive) shouldEscalate(order models.SmartCopyOrder) bool {
+	if order.TimeLimit > 0 {
+		elapsed := time.Since(order.CreatedAt)
+		remainingFrac := 1 - elapsed.Seconds()/float64(order.TimeLimit)
+		if remainingFrac <= adaptiveTimeThreshold {
+			return true
+		}
+	}
+
+	// TotalSlippage is (average - reference) / reference, so an adverse
+	// move on a sell shows up as a negative value.
+	slippage := order.TotalSlippage
+	if order.Side != "buy" {
+		slippage = -slippage
+	}
+	if order.MaxSlippage > 0 && slippage >= order.MaxSlippage/2 {
+		return true
+	}
+
+	return false
+}
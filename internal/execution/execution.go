@@ -0,0 +1,113 @@
+// Package execution implements the slicing algorithms behind
+// SmartCopyOrder.ExecutionStrategy: TWAP, VWAP, participation-rate (POV),
+// and an adaptive mode that escalates to aggressive IOC execution late in
+// the order's life. Strategy authors can add their own via Register.
+package execution
+
+import (
+	"context"
+	"fmt"
+	"hyperliquid-copy-trading/internal/models"
+	"hyperliquid-copy-trading/internal/utils"
+	"sync"
+	"time"
+)
+
+// Algorithm slices a SmartCopyOrder's remaining size into child orders given
+// the current L2 book, and reports how long the caller should wait before
+// calling Slice again.
+type Algorithm interface {
+	Name() string
+	Slice(ctx context.Context, order models.SmartCopyOrder, book models.L2Book) ([]models.OrderRequest, time.Duration)
+}
+
+var (
+	registryMutex sync.RWMutex
+	registry      = map[string]Algorithm{}
+)
+
+func init() {
+	Register(NewTWAP())
+	Register(NewVWAP(nil))
+	Register(NewPOV(0.1))
+	Register(NewAdaptive(NewPOV(0.1)))
+}
+
+// Register makes an algorithm available by name (case-sensitive, matches
+// SmartCopyOrder.ExecutionStrategy). Registering a name that already exists
+// replaces it, so strategy authors can override the built-ins.
+func Register(algo Algorithm) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	registry[algo.Name()] = algo
+}
+
+// Get resolves an algorithm by name.
+func Get(name string) (Algorithm, bool) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	algo, ok := registry[name]
+	return algo, ok
+}
+
+// remainingSize is the portion of a SmartCopyOrder not yet executed.
+func remainingSize(order models.SmartCopyOrder) float64 {
+	remaining := order.TargetSize - order.TotalExecuted
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// bestBidAsk returns the top of book, or (0, 0) if the book is empty.
+func bestBidAsk(book models.L2Book) (bid, ask float64) {
+	if bids, ok := book.Levels["bids"]; ok && len(bids) > 0 {
+		bid, _ = utils.ParseFloat(bids[0].Px)
+	}
+	if asks, ok := book.Levels["asks"]; ok && len(asks) > 0 {
+		ask, _ = utils.ParseFloat(asks[0].Px)
+	}
+	return bid, ask
+}
+
+// depthWithinLevels sums the size quoted on one side of the book across up
+// to levels price levels.
+func depthWithinLevels(book models.L2Book, side string, levels int) float64 {
+	quotes, ok := book.Levels[side]
+	if !ok {
+		return 0
+	}
+
+	var total float64
+	for i, level := range quotes {
+		if i >= levels {
+			break
+		}
+		sz, _ := utils.ParseFloat(level.Sz)
+		total += sz
+	}
+	return total
+}
+
+// describeMarketState renders the spread/depth snapshot recorded on each
+// PartialExecution.MarketState.
+func describeMarketState(book models.L2Book) string {
+	bid, ask := bestBidAsk(book)
+	spread := ask - bid
+	bidDepth := depthWithinLevels(book, "bids", 5)
+	askDepth := depthWithinLevels(book, "asks", 5)
+
+	return fmt.Sprintf("bid=%.6f ask=%.6f spread=%.6f depth5(bid=%.4f,ask=%.4f)", bid, ask, spread, bidDepth, askDepth)
+}
+
+// sliceOrder builds the OrderRequest for one child slice.
+func sliceOrder(order models.SmartCopyOrder, size float64, isBuy bool, orderType string, price *float64) models.OrderRequest {
+	return models.OrderRequest{
+		Asset:     order.Asset,
+		IsBuy:     isBuy,
+		Size:      size,
+		Price:     price,
+		OrderType: orderType,
+		Nonce:     time.Now().UnixMilli(),
+	}
+}
@@ -0,0 +1,56 @@
+package execution
+
+import (
+	"context"
+	"hyperliquid-copy-trading/internal/models"
+	"time"
+)
+
+// povCheckInterval is how often a POV order re-sizes against trailing
+// volume.
+const povCheckInterval = 10 * time.Second
+
+// POV (participation-rate) sizes each slice so it stays under
+// ParticipationRate of the size resting on the top 10 levels of each side of
+// the L2 book, which trailing1MinVolume uses as its volume estimate. Neither
+// SpotAssetContext.DayNtlVlm nor trade prints are read.
+type POV struct {
+	ParticipationRate float64 // e.g. 0.1 = each slice at most 10% of that book depth
+}
+
+func NewPOV(participationRate float64) *POV {
+	return &POV{ParticipationRate: participationRate}
+}
+
+func (p *POV) Name() string { return "pov" }
+
+func (p *POV) Slice(ctx context.Context, order models.SmartCopyOrder, book models.L2Book) ([]models.OrderRequest, time.Duration) {
+	remaining := remainingSize(order)
+	if remaining <= 0 {
+		return nil, 0
+	}
+
+	trailingVolume := p.trailing1MinVolume(book)
+	maxSlice := trailingVolume * p.ParticipationRate
+	if maxSlice <= 0 {
+		// No volume signal available — fall back to a conservative fixed
+		// fraction of what's left rather than stalling indefinitely.
+		maxSlice = remaining * 0.1
+	}
+
+	sliceSize := maxSlice
+	if sliceSize > remaining {
+		sliceSize = remaining
+	}
+
+	return []models.OrderRequest{sliceOrder(order, sliceSize, order.Side == "buy", "market", nil)}, povCheckInterval
+}
+
+// trailing1MinVolume estimates the asset's trailing 1-minute traded volume
+// as the summed size on the top 10 bid and ask levels of book. No trade
+// history is read.
+func (p *POV) trailing1MinVolume(book models.L2Book) float64 {
+	bidDepth := depthWithinLevels(book, "bids", 10)
+	askDepth := depthWithinLevels(book, "asks", 10)
+	return bidDepth + askDepth
+}
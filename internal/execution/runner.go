@@ -0,0 +1,93 @@
+package execution
+
+import (
+	"context"
+	"hyperliquid-copy-trading/internal/models"
+	"time"
+)
+
+// Placer executes one child OrderRequest and reports the filled size and
+// average fill price, letting Run stay decoupled from the order engine's
+// wallet/nonce/batching concerns.
+type Placer interface {
+	PlaceOrder(ctx context.Context, order models.OrderRequest) (filledSize, avgPrice float64, err error)
+}
+
+// BookSource supplies the current L2 book for an asset.
+type BookSource func(ctx context.Context, asset string) (models.L2Book, error)
+
+// Run drives a SmartCopyOrder to completion (or context cancellation) using
+// algo to decide slice sizing and Adaptive-style escalation. Each placed
+// slice is appended to order.PartialExecutions with MarketState captured
+// from the book at slice time, and order.TotalExecuted/AveragePrice/
+// TotalSlippage are updated as fills land.
+func Run(ctx context.Context, algo Algorithm, order *models.SmartCopyOrder, books BookSource, placer Placer) error {
+	for remainingSize(*order) > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		book, err := books(ctx, order.Asset)
+		if err != nil {
+			return err
+		}
+
+		slices, wait := algo.Slice(ctx, *order, book)
+		marketState := describeMarketState(book)
+
+		for _, req := range slices {
+			filled, avgPrice, err := placer.PlaceOrder(ctx, req)
+			if err != nil || filled <= 0 {
+				continue
+			}
+
+			applyFill(order, filled, avgPrice, marketState)
+		}
+
+		if remainingSize(*order) <= 0 {
+			break
+		}
+
+		if wait <= 0 {
+			wait = time.Second
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+
+	now := time.Now()
+	order.CompletedAt = &now
+	order.Status = "completed"
+	return nil
+}
+
+// applyFill records a slice fill and recomputes the order's running average
+// price and realized slippage against its first fill's reference price.
+func applyFill(order *models.SmartCopyOrder, filled, avgPrice float64, marketState string) {
+	order.PartialExecutions = append(order.PartialExecutions, models.PartialExecution{
+		Size:        filled,
+		Price:       avgPrice,
+		Timestamp:   time.Now(),
+		MarketState: marketState,
+	})
+
+	prevTotal := order.TotalExecuted
+	newTotal := prevTotal + filled
+	if newTotal > 0 {
+		order.AveragePrice = (order.AveragePrice*prevTotal + avgPrice*filled) / newTotal
+	}
+	order.TotalExecuted = newTotal
+
+	if len(order.PartialExecutions) > 0 {
+		reference := order.PartialExecutions[0].Price
+		if reference > 0 {
+			order.TotalSlippage = (order.AveragePrice - reference) / reference
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package execution
+
+import (
+	"context"
+	"hyperliquid-copy-trading/internal/models"
+	"time"
+)
+
+// twapSlices is the number of equal-size child orders a TWAP order is cut
+// into over its TimeLimit.
+const twapSlices = 10
+
+// TWAP splits the remaining size into equal slices spread evenly across the
+// order's TimeLimit.
+type TWAP struct{}
+
+func NewTWAP() *TWAP {
+	return &TWAP{}
+}
+
+func (t *TWAP) Name() string { return "twap" }
+
+func (t *TWAP) Slice(ctx context.Context, order models.SmartCopyOrder, book models.L2Book) ([]models.OrderRequest, time.Duration) {
+	remaining := remainingSize(order)
+	if remaining <= 0 {
+		return nil, 0
+	}
+
+	interval := time.Duration(order.TimeLimit) * time.Second / twapSlices
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	slicesDone := len(order.PartialExecutions)
+	slicesLeft := twapSlices - slicesDone
+	if slicesLeft <= 1 {
+		// Last slice (or an order cut short of its full schedule) takes
+		// whatever remains instead of leaving a dangling fraction.
+		return []models.OrderRequest{sliceOrder(order, remaining, order.Side == "buy", "market", nil)}, interval
+	}
+
+	sliceSize := remaining / float64(slicesLeft)
+	return []models.OrderRequest{sliceOrder(order, sliceSize, order.Side == "buy", "market", nil)}, interval
+}
@@ -0,0 +1,76 @@
+package execution
+
+import (
+	"context"
+	"hyperliquid-copy-trading/internal/models"
+	"time"
+)
+
+// VolumeCurve maps an hour-of-day (UTC, 0-23) to its share of typical daily
+// volume. Values should sum to 1.0 across all 24 hours.
+type VolumeCurve map[int]float64
+
+// DefaultVolumeCurve is a rough intraday U-shape: heavier at the US/EU
+// session opens and overlap, lighter during the Asia-only overnight hours.
+func DefaultVolumeCurve() VolumeCurve {
+	curve := VolumeCurve{}
+	base := 1.0 / 24.0
+	for h := 0; h < 24; h++ {
+		curve[h] = base
+	}
+	// Boost European open (7-9 UTC), US open (13-15 UTC), and the
+	// US/EU overlap (14-16 UTC); trim the quiet overnight hours (2-5 UTC).
+	for _, h := range []int{7, 8, 13, 14, 15} {
+		curve[h] *= 1.6
+	}
+	for _, h := range []int{2, 3, 4, 5} {
+		curve[h] *= 0.5
+	}
+	// Rescale so the 24 shares sum to 1.0 again after the boosts and trims.
+	var total float64
+	for _, share := range curve {
+		total += share
+	}
+	for h := range curve {
+		curve[h] /= total
+	}
+	return curve
+}
+
+// VWAP sizes each slice by that hour's share of the configured intraday
+// volume curve, so heavier-volume hours get bigger slices.
+type VWAP struct {
+	curve VolumeCurve
+}
+
+// NewVWAP builds a VWAP algorithm with curve, or DefaultVolumeCurve if nil.
+func NewVWAP(curve VolumeCurve) *VWAP {
+	if curve == nil {
+		curve = DefaultVolumeCurve()
+	}
+	return &VWAP{curve: curve}
+}
+
+func (v *VWAP) Name() string { return "vwap" }
+
+func (v *VWAP) Slice(ctx context.Context, order models.SmartCopyOrder, book models.L2Book) ([]models.OrderRequest, time.Duration) {
+	remaining := remainingSize(order)
+	if remaining <= 0 {
+		return nil, 0
+	}
+
+	hour := time.Now().UTC().Hour()
+	weight := v.curve[hour]
+	if weight <= 0 {
+		weight = 1.0 / 24.0
+	}
+
+	// Size this slice as if it covers the next hour's worth of the volume
+	// curve, capped at the remaining size.
+	sliceSize := remaining * weight
+	if sliceSize > remaining || sliceSize <= 0 {
+		sliceSize = remaining
+	}
+
+	return []models.OrderRequest{sliceOrder(order, sliceSize, order.Side == "buy", "market", nil)}, time.Hour
+}
@@ -0,0 +1,103 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// CSVStreamWriter writes one CSV header followed by rows of a fixed struct
+// type, handing each record to csv.Writer as it arrives rather than
+// buffering the full dataset; csv.Writer's buffer reaches the underlying
+// writer as it fills and on Close.
+type CSVStreamWriter struct {
+	w       *csv.Writer
+	typ     reflect.Type
+	columns []column
+}
+
+// NewCSVStreamWriter derives the column set from sample's type (a struct, or
+// a pointer to one) and writes the header row immediately.
+func NewCSVStreamWriter(w io.Writer, sample any) (*CSVStreamWriter, error) {
+	typ := reflect.TypeOf(sample)
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	columns, err := columnsOf(typ)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("export: %s has no csv-tagged fields", typ)
+	}
+
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = c.name
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return nil, fmt.Errorf("export: writing csv header: %w", err)
+	}
+
+	return &CSVStreamWriter{w: cw, typ: typ, columns: columns}, nil
+}
+
+// Write appends one row. row must be the same type (or a pointer to it)
+// NewCSVStreamWriter was built with.
+func (s *CSVStreamWriter) Write(row any) error {
+	v := reflect.ValueOf(row)
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Type() != s.typ {
+		return fmt.Errorf("export: expected row of type %s, got %s", s.typ, v.Type())
+	}
+
+	record := make([]string, len(s.columns))
+	for i, c := range s.columns {
+		record[i] = cellString(v.FieldByIndex(c.index))
+	}
+
+	if err := s.w.Write(record); err != nil {
+		return fmt.Errorf("export: writing csv row: %w", err)
+	}
+	return nil
+}
+
+// Close flushes any buffered records and returns the first write error, if
+// any.
+func (s *CSVStreamWriter) Close() error {
+	s.w.Flush()
+	return s.w.Error()
+}
+
+// ToCSV writes rows (a slice of structs, or pointers to structs) to w as CSV,
+// streaming one record at a time so multi-million-row slices — a full
+// CopyTrade export, for example — never get buffered in memory beyond the
+// input slice itself.
+func ToCSV(w io.Writer, rows any) error {
+	v, err := rowsValue(rows)
+	if err != nil {
+		return err
+	}
+	if v.Len() == 0 {
+		return fmt.Errorf("export: rows is empty, cannot infer columns")
+	}
+
+	sw, err := NewCSVStreamWriter(w, v.Index(0).Interface())
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		if err := sw.Write(v.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+
+	return sw.Close()
+}
@@ -0,0 +1,79 @@
+// Package export flattens the analytics models (TraderAnalytics,
+// LeaderPerformanceAnalysis, CopyTrade, TimeSeriesPoint, PerformanceMetrics,
+// RiskMetrics, ...) into CSV/Parquet rows using their `csv` struct tags, so
+// users can pull hyperlit data straight into pandas/DuckDB/spreadsheets
+// instead of scraping JSON. Every writer streams row-by-row and never holds
+// the whole dataset: CSV output passes through csv.Writer's small buffer and
+// Parquet output through one row group at a time.
+package export
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// column describes one exported field: its header name and the reflect path
+// to reach it (len > 1 only for embedded structs, which this package does
+// not currently flatten — embedding isn't used by the tagged models).
+type column struct {
+	name  string
+	index []int
+}
+
+// columnsOf reflects the `csv` struct tags off t in field order. A field
+// tagged `csv:"-"` or with no `csv` tag at all is skipped — that's how
+// TraderAnalytics/LeaderPerformanceAnalysis's nested maps/slices/structs are
+// excluded from the flat export.
+func columnsOf(t reflect.Type) ([]column, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("export: %s is not a struct", t)
+	}
+
+	var columns []column
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup("csv")
+		if !ok || tag == "-" {
+			continue
+		}
+		columns = append(columns, column{name: tag, index: []int{i}})
+	}
+	return columns, nil
+}
+
+// rowsValue validates that rows is a slice and returns its reflect.Value.
+func rowsValue(rows any) (reflect.Value, error) {
+	v := reflect.ValueOf(rows)
+	if v.Kind() != reflect.Slice {
+		return reflect.Value{}, fmt.Errorf("export: rows must be a slice, got %T", rows)
+	}
+	return v, nil
+}
+
+// cellString renders one field value as its exported string form. Pointers
+// are dereferenced (empty string for nil), time.Time uses RFC3339.
+func cellString(v reflect.Value) string {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	if t, ok := v.Interface().(time.Time); ok {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(time.RFC3339)
+	}
+
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return fmt.Sprintf("%g", v.Float())
+	case reflect.Bool:
+		return fmt.Sprintf("%t", v.Bool())
+	default:
+		return fmt.Sprintf("%v", v.Interface())
+	}
+}
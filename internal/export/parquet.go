@@ -0,0 +1,118 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// ParquetStreamWriter writes rows of a fixed struct type to a single Parquet
+// file, row group by row group, without holding the full dataset in memory.
+// parquet-go infers the file schema from a Go struct's exported fields and
+// `parquet:"..."` tags, so each row is first copied into a struct type built
+// at runtime (via buildParquetRowType) whose fields carry the same names and
+// Go types as the csv-tagged source fields, tagged with the export column
+// name instead.
+type ParquetStreamWriter struct {
+	w       *parquet.Writer
+	typ     reflect.Type // source struct type
+	rowType reflect.Type // runtime-built parquet row type
+	columns []column
+}
+
+// NewParquetStreamWriter derives the column set and Parquet schema from
+// sample's type (a struct, or a pointer to one).
+func NewParquetStreamWriter(w io.Writer, sample any) (*ParquetStreamWriter, error) {
+	typ := reflect.TypeOf(sample)
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	columns, err := columnsOf(typ)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("export: %s has no csv-tagged fields", typ)
+	}
+
+	rowType := buildParquetRowType(typ, columns)
+	pw := parquet.NewWriter(w, parquet.SchemaOf(reflect.New(rowType).Interface()))
+
+	return &ParquetStreamWriter{w: pw, typ: typ, rowType: rowType, columns: columns}, nil
+}
+
+// buildParquetRowType builds a struct type with one field per column,
+// reusing the source field's name and Go type but swapping its struct tag
+// for the export column name, so parquet-go's reflection-based schema
+// inference names the Parquet column after the csv tag rather than the Go
+// field name.
+func buildParquetRowType(src reflect.Type, columns []column) reflect.Type {
+	fields := make([]reflect.StructField, len(columns))
+	for i, c := range columns {
+		srcField := src.FieldByIndex(c.index)
+		fields[i] = reflect.StructField{
+			Name: srcField.Name,
+			Type: srcField.Type,
+			Tag:  reflect.StructTag(fmt.Sprintf(`parquet:"%s,optional"`, c.name)),
+		}
+	}
+	return reflect.StructOf(fields)
+}
+
+// Write appends one row. row must be the same type (or a pointer to it)
+// NewParquetStreamWriter was built with.
+func (s *ParquetStreamWriter) Write(row any) error {
+	v := reflect.ValueOf(row)
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Type() != s.typ {
+		return fmt.Errorf("export: expected row of type %s, got %s", s.typ, v.Type())
+	}
+
+	out := reflect.New(s.rowType).Elem()
+	for i, c := range s.columns {
+		out.Field(i).Set(v.FieldByIndex(c.index))
+	}
+
+	if err := s.w.Write(out.Addr().Interface()); err != nil {
+		return fmt.Errorf("export: writing parquet row: %w", err)
+	}
+	return nil
+}
+
+// Close flushes the final row group and writes the Parquet footer. It must
+// be called (and its error checked) or the output file is not valid Parquet.
+func (s *ParquetStreamWriter) Close() error {
+	return s.w.Close()
+}
+
+// ToParquet writes rows (a slice of structs, or pointers to structs) to w as
+// a single-file Parquet dataset, streaming one row at a time so a
+// multi-million-row CopyTrade export stays bounded by parquet-go's row group
+// buffer rather than the full input slice.
+func ToParquet(w io.Writer, rows any) error {
+	v, err := rowsValue(rows)
+	if err != nil {
+		return err
+	}
+	if v.Len() == 0 {
+		return fmt.Errorf("export: rows is empty, cannot infer schema")
+	}
+
+	sw, err := NewParquetStreamWriter(w, v.Index(0).Interface())
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		if err := sw.Write(v.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+
+	return sw.Close()
+}
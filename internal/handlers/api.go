@@ -3,6 +3,8 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"hyperliquid-copy-trading/internal/conformance"
 	"hyperliquid-copy-trading/internal/database"
 	"hyperliquid-copy-trading/internal/engine"
 	"hyperliquid-copy-trading/internal/models"
@@ -57,13 +59,72 @@ func (h *APIHandler) writeSuccess(w http.ResponseWriter, data interface{}, messa
 	h.writeJSON(w, http.StatusOK, response)
 }
 
+// agentChallengeRequest is the payload for CreateAgentChallenge: a client
+// asks for a fresh agent wallet on behalf of masterAddress before a
+// follower exists to reference it.
+type agentChallengeRequest struct {
+	MasterAddress string `json:"master_address"`
+	AgentName     string `json:"agent_name"`
+}
+
+// CreateAgentChallenge generates an agent wallet for the request's
+// MasterAddress and returns the EIP-712 typed data it must sign
+// out-of-band (e.g. via the client's own wallet extension) before
+// CreateFollower can be called with the resulting approval.
+func (h *APIHandler) CreateAgentChallenge(w http.ResponseWriter, r *http.Request) {
+	var req agentChallengeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+	if req.MasterAddress == "" {
+		h.writeError(w, http.StatusBadRequest, "master_address is required")
+		return
+	}
+	if req.AgentName == "" {
+		req.AgentName = "hyperlit-copy-engine"
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	agentAddress, nonce, typedData, err := h.copyEngine.BeginAgentEnrollment(ctx, req.MasterAddress, req.AgentName)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to generate agent wallet")
+		h.writeError(w, http.StatusInternalServerError, "Failed to generate agent wallet")
+		return
+	}
+
+	h.writeSuccess(w, map[string]interface{}{
+		"agent_address": agentAddress,
+		"agent_name":    req.AgentName,
+		"nonce":         nonce,
+		"typed_data":    typedData,
+	}, "Sign typed_data with your master wallet and submit it with CreateFollower")
+}
+
+// createFollowerRequest is CreateFollower's payload: the follower settings
+// plus the agent wallet CreateAgentChallenge generated and the
+// master-signed approval authorizing it.
+type createFollowerRequest struct {
+	models.Follower
+	AgentAddress string               `json:"agent_address"`
+	Approval     models.AgentApproval `json:"approval"`
+}
+
 // Follower endpoints
 func (h *APIHandler) CreateFollower(w http.ResponseWriter, r *http.Request) {
-	var follower models.Follower
-	if err := json.NewDecoder(r.Body).Decode(&follower); err != nil {
+	var req createFollowerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.writeError(w, http.StatusBadRequest, "Invalid JSON payload")
 		return
 	}
+	follower := req.Follower
+
+	if req.AgentAddress == "" {
+		h.writeError(w, http.StatusBadRequest, "agent_address is required")
+		return
+	}
 
 	// Validate follower settings
 	if errors := h.validateFollower(&follower); len(errors) > 0 {
@@ -88,7 +149,7 @@ func (h *APIHandler) CreateFollower(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
 
-	if err := h.copyEngine.AddFollower(ctx, &follower); err != nil {
+	if err := h.copyEngine.AddFollower(ctx, &follower, req.AgentAddress, req.Approval); err != nil {
 		log.Error().Err(err).Msg("Failed to create follower")
 		h.writeError(w, http.StatusInternalServerError, "Failed to create follower")
 		return
@@ -310,6 +371,65 @@ func (h *APIHandler) GetPnLAnalytics(w http.ResponseWriter, r *http.Request) {
 	h.writeSuccess(w, analytics, "")
 }
 
+// HandleWebSocket upgrades GET /ws into an outbound JSON-RPC style
+// subscription connection (subscribeFollower/subscribeLeaderTrades/
+// subscribeLeaderEvents/subscribePnL/subscribeHealth/unsubscribe), letting dashboards receive
+// trade fills, PnL updates, and health changes as push notifications
+// instead of polling the REST endpoints above.
+func (h *APIHandler) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
+	if err := h.copyEngine.ServeWebSocket(w, r); err != nil {
+		log.Error().Err(err).Msg("Failed to upgrade outbound WebSocket connection")
+	}
+}
+
+// GetInstruments returns every cached instrument's tick/lot-size, minimum
+// notional, and max leverage.
+func (h *APIHandler) GetInstruments(w http.ResponseWriter, r *http.Request) {
+	h.writeSuccess(w, h.copyEngine.GetInstruments(), "")
+}
+
+// GetInstrument returns a single coin's cached instrument limits.
+func (h *APIHandler) GetInstrument(w http.ResponseWriter, r *http.Request) {
+	coin := mux.Vars(r)["coin"]
+
+	instrument, ok := h.copyEngine.GetInstrument(coin)
+	if !ok {
+		h.writeError(w, http.StatusNotFound, fmt.Sprintf("unknown instrument %s", coin))
+		return
+	}
+
+	h.writeSuccess(w, instrument, "")
+}
+
+// GetOrderBook returns the live reconstructed order book for a coin.
+func (h *APIHandler) GetOrderBook(w http.ResponseWriter, r *http.Request) {
+	coin := mux.Vars(r)["coin"]
+
+	book, err := h.copyEngine.GetOrderBook(coin)
+	if err != nil {
+		h.writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+
+	h.writeSuccess(w, book, "")
+}
+
+// GetConformanceStatus runs the embedded conformance vector corpora and
+// reports pass/fail counts, so CI or an operator can check the engine's
+// wallet-grouping, position-sizing and copy-trade decision behavior hasn't
+// regressed without shelling out to cmd/conformance. Honors SKIP_CONFORMANCE
+// the same way the CLI does.
+func (h *APIHandler) GetConformanceStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := conformance.RunStatus()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to run conformance vectors")
+		h.writeError(w, http.StatusInternalServerError, "Failed to run conformance vectors")
+		return
+	}
+
+	h.writeSuccess(w, status, "")
+}
+
 // Health check endpoint
 func (h *APIHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	// Check WebSocket connections health
@@ -350,15 +470,115 @@ func (h *APIHandler) GetSystemStatus(w http.ResponseWriter, r *http.Request) {
 	orderQueueStatus := h.copyEngine.GetOrderQueueStatus()
 
 	status := map[string]interface{}{
-		"timestamp":           time.Now().Unix(),
-		"websocket_connections": wsConnections,
-		"order_queue":         orderQueueStatus,
-		"uptime_seconds":      time.Since(time.Now()).Seconds(), // Would track actual uptime
+		"timestamp":              time.Now().Unix(),
+		"websocket_connections":  wsConnections,
+		"outbound_ws_connections": h.copyEngine.GetOutboundConnections(),
+		"order_queue":            orderQueueStatus,
+		"uptime_seconds":         time.Since(time.Now()).Seconds(), // Would track actual uptime
 	}
 
 	h.writeSuccess(w, status, "")
 }
 
+// GetLeaderEvents replays the persisted leader_events log, eth_getLogs-style:
+// leader/asset/event_type narrow the topic, and from/to (Unix milliseconds,
+// defaulting to the last 24h) bound the height range. Use the
+// subscribeLeaderEvents WebSocket method on /ws for live events instead of polling
+// this for fresh activity.
+func (h *APIHandler) GetLeaderEvents(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	filter := models.EventFilter{
+		Leader:    q.Get("leader"),
+		Asset:     q.Get("asset"),
+		EventType: q.Get("event_type"),
+	}
+
+	to := time.Now()
+	if toStr := q.Get("to"); toStr != "" {
+		if ms, err := strconv.ParseInt(toStr, 10, 64); err == nil {
+			to = time.UnixMilli(ms)
+		}
+	}
+
+	from := to.Add(-24 * time.Hour)
+	if fromStr := q.Get("from"); fromStr != "" {
+		if ms, err := strconv.ParseInt(fromStr, 10, 64); err == nil {
+			from = time.UnixMilli(ms)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	events, err := h.copyEngine.GetEvents(ctx, filter, from, to)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get leader events")
+		h.writeError(w, http.StatusInternalServerError, "Failed to retrieve leader events")
+		return
+	}
+
+	h.writeSuccess(w, events, "")
+}
+
+// Blacklist admin endpoints
+func (h *APIHandler) ListBlockedAddresses(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	addresses, err := h.db.ListBlockedAddresses(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list blocked addresses")
+		h.writeError(w, http.StatusInternalServerError, "Failed to retrieve blocked addresses")
+		return
+	}
+
+	h.writeSuccess(w, addresses, "")
+}
+
+func (h *APIHandler) AddBlockedAddress(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Address string `json:"address"`
+		Reason  string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+	if req.Address == "" {
+		h.writeError(w, http.StatusBadRequest, "address is required")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := h.db.AddBlockedAddress(ctx, req.Address, req.Reason); err != nil {
+		log.Error().Err(err).Str("address", req.Address).Msg("Failed to add blocked address")
+		h.writeError(w, http.StatusInternalServerError, "Failed to add blocked address")
+		return
+	}
+
+	log.Info().Str("address", req.Address).Msg("Address blocked")
+	h.writeSuccess(w, nil, "Address blocked successfully")
+}
+
+func (h *APIHandler) RemoveBlockedAddress(w http.ResponseWriter, r *http.Request) {
+	address := mux.Vars(r)["address"]
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := h.db.RemoveBlockedAddress(ctx, address); err != nil {
+		log.Error().Err(err).Str("address", address).Msg("Failed to remove blocked address")
+		h.writeError(w, http.StatusInternalServerError, "Failed to remove blocked address")
+		return
+	}
+
+	log.Info().Str("address", address).Msg("Address unblocked")
+	h.writeSuccess(w, nil, "Address unblocked successfully")
+}
+
 // Validation helpers
 func (h *APIHandler) validateFollower(follower *models.Follower) []string {
 	var errors []string
@@ -371,8 +591,8 @@ func (h *APIHandler) validateFollower(follower *models.Follower) []string {
 		errors = append(errors, "leader_address is required")
 	}
 
-	if follower.APIWalletAddress == "" {
-		errors = append(errors, "api_wallet_address is required")
+	if follower.MasterAddress == "" {
+		errors = append(errors, "master_address is required")
 	}
 
 	if follower.CopyPercentage <= 0 || follower.CopyPercentage > 100 {
@@ -381,6 +601,8 @@ func (h *APIHandler) validateFollower(follower *models.Follower) []string {
 
 	if follower.MaxPositionSize <= 0 {
 		errors = append(errors, "max_position_size must be positive")
+	} else if minNotional := h.copyEngine.MinInstrumentNotional(); follower.MaxPositionSize < minNotional {
+		errors = append(errors, fmt.Sprintf("max_position_size must be at least %.2f to clear the exchange minimum order notional", minNotional))
 	}
 
 	if follower.StopLossPercentage != nil && (*follower.StopLossPercentage <= 0 || *follower.StopLossPercentage >= 100) {
@@ -410,6 +632,16 @@ func (h *APIHandler) EnableCORS(next http.Handler) http.Handler {
 	})
 }
 
+// ReadYourWritesMiddleware attaches database.WithReadStickiness to every
+// request, so that if a handler writes and then reads within the same
+// request, the read is pinned to the primary for Config.ReplicaLagWindow
+// instead of risking a stale result from a lagging read replica.
+func (h *APIHandler) ReadYourWritesMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(database.WithReadStickiness(r.Context())))
+	})
+}
+
 // Logging middleware
 func (h *APIHandler) LoggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
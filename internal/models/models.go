@@ -19,21 +19,67 @@ type Leader struct {
 	UpdatedAt      time.Time `json:"updated_at"`
 }
 
+// Follower's APIWalletAddress is never supplied by the client directly: it's
+// the address of an ephemeral AgentWallet CopyEngine generates on the
+// follower's behalf and that MasterAddress approves via an approveAgent
+// signature, so the server never has to be handed -- or store -- the
+// master wallet's own key.
 type Follower struct {
 	ID                   int                    `json:"id"`
 	UserID               string                 `json:"user_id"`
 	LeaderAddress        string                 `json:"leader_address"`
 	APIWalletAddress     string                 `json:"api_wallet_address"`
+	MasterAddress        string                 `json:"master_address"`
 	CopyPercentage       float64                `json:"copy_percentage"`
 	MaxPositionSize      float64                `json:"max_position_size"`
 	StopLossPercentage   *float64               `json:"stop_loss_percentage,omitempty"`
 	TakeProfitPercentage *float64               `json:"take_profit_percentage,omitempty"`
 	IsActive             bool                   `json:"is_active"`
 	RiskSettings         map[string]interface{} `json:"risk_settings"`
+	CopyMode             CopyMode               `json:"copy_mode,omitempty"`
+	Venue                string                 `json:"venue,omitempty"`
+	Tier                 FollowerTier           `json:"tier,omitempty"`
 	CreatedAt            time.Time              `json:"created_at"`
 	UpdatedAt            time.Time              `json:"updated_at"`
 }
 
+// FollowerTier ranks a follower for OrderEngine's dispatch scheduler: the
+// zero value (FollowerTierStandard) is dispatched after any higher tier
+// queued behind it, so a premium follower's orders leave first when the
+// scheduler is under backpressure. Like CopyMode and Venue, it isn't yet
+// persisted by CreateFollower/GetFollowers -- it only takes effect for the
+// lifetime of the in-memory Follower a caller builds and passes to
+// AddFollower/ExecuteBatch directly.
+type FollowerTier int
+
+const (
+	FollowerTierStandard FollowerTier = iota
+	FollowerTierPlus
+	FollowerTierPremium
+)
+
+// DefaultVenue is the ExchangeClient/websocket.Manager key a Follower or
+// OrderRequest with an empty Venue resolves to, so existing rows and
+// requests created before multi-venue support keep trading on Hyperliquid.
+const DefaultVenue = "hyperliquid"
+
+// CopyMode selects how OrderEngine handles a follower's copied orders. The
+// zero value behaves as CopyModeTracked, so existing followers persisted
+// before this field existed keep the fully-monitored flow.
+type CopyMode string
+
+const (
+	// CopyModeTracked persists a crash-safe OrderState FSM for every order
+	// and polls GetOrderStatus until each one reaches a terminal status.
+	CopyModeTracked CopyMode = "tracked"
+	// CopyModeStateless submits orders via ExecuteBatchStateless and
+	// returns as soon as BatchOrders acknowledges, with no OrderState
+	// persistence or status polling. For high-frequency followers or
+	// external systems that already track fills through their own
+	// subscription.
+	CopyModeStateless CopyMode = "stateless"
+)
+
 type Trade struct {
 	ID              int       `json:"id"`
 	LeaderAddress   string    `json:"leader_address"`
@@ -47,7 +93,124 @@ type Trade struct {
 	ExecutedAt      time.Time `json:"executed_at"`
 	HyperliquidTxID string    `json:"hyperliquid_tx_id"`
 	Status          string    `json:"status"`
-	CreatedAt       time.Time `json:"created_at"`
+	// Fee is the exchange-reported trading fee for this fill, carried over
+	// from EnhancedTradeEvent.Fee so lot-matching PnL can net it out.
+	Fee float64 `json:"fee"`
+	// Funding is any funding payment attributed to this fill. Zero unless a
+	// caller that tracks funding events sets it explicitly.
+	Funding   float64   `json:"funding"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RealizedPnL is one closing match produced by pnl.Matcher: some quantity of
+// an earlier opening lot closed out against trade TradeID at ExitPrice.
+// GetLeaderPerformance, GetFollowerPnL and the max-drawdown calculations
+// read from the realized_pnl table these rows are persisted to instead of
+// treating every sell as profit and every buy as loss.
+type RealizedPnL struct {
+	ID            int       `json:"id"`
+	TradeID       int       `json:"trade_id"`
+	LeaderAddress string    `json:"leader_address"`
+	FollowerID    *int      `json:"follower_id,omitempty"`
+	Coin          string    `json:"coin"`
+	MatchedQty    float64   `json:"matched_qty"`
+	EntryPrice    float64   `json:"entry_price"`
+	ExitPrice     float64   `json:"exit_price"`
+	RealizedPnL   float64   `json:"realized_pnl"`
+	Fees          float64   `json:"fees"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// UnrealizedPnL marks an owner's still-open lots in one coin to MarkPrice.
+type UnrealizedPnL struct {
+	LeaderAddress string  `json:"leader_address"`
+	FollowerID    *int    `json:"follower_id,omitempty"`
+	Coin          string  `json:"coin"`
+	Qty           float64 `json:"qty"` // signed: positive long, negative short
+	EntryPrice    float64 `json:"entry_price"`
+	MarkPrice     float64 `json:"mark_price"`
+	UnrealizedPnL float64 `json:"unrealized_pnl"`
+}
+
+// BridgeStatus is one step of a BridgeJob's progress from quote to
+// deposited-on-Hyperliquid, persisted so a restart can resume a job instead
+// of losing track of in-flight funds.
+type BridgeStatus string
+
+const (
+	BridgeStatusQuoted            BridgeStatus = "quoted"
+	BridgeStatusSent              BridgeStatus = "sent"
+	BridgeStatusArrivedOnArbitrum BridgeStatus = "arrived_on_arbitrum"
+	BridgeStatusDepositedToHL     BridgeStatus = "deposited_to_hl"
+	BridgeStatusFailed            BridgeStatus = "failed"
+)
+
+// DepositSource identifies where collateral should be bridged from:
+// chainID is the source EVM chain, wallet the address funds are pulled
+// from, token its address on that chain (empty for native), and amount the
+// quantity to bridge in the token's native decimals.
+type DepositSource struct {
+	ChainID int64   `json:"chain_id"`
+	Wallet  string  `json:"wallet"`
+	Token   string  `json:"token"`
+	Amount  float64 `json:"amount"`
+}
+
+// BridgeQuote is one provider's estimate for moving a DepositSource to
+// Arbitrum USDC, used by Router to compare providers before committing to
+// one.
+type BridgeQuote struct {
+	Provider      string        `json:"provider"`
+	AmountOut     float64       `json:"amount_out"` // USDC received on Arbitrum, net of fees
+	Fee           float64       `json:"fee"`
+	EstimatedTime time.Duration `json:"estimated_time"`
+}
+
+// BridgeJob is one bridge-then-deposit attempt's persisted state, advancing
+// through BridgeStatus as BridgeManager drives it and Quote/Send/
+// WaitForConfirmation return.
+type BridgeJob struct {
+	ID           int           `json:"id"`
+	Source       DepositSource `json:"source"`
+	Provider     string        `json:"provider"`
+	Quote        BridgeQuote   `json:"quote"`
+	Status       BridgeStatus  `json:"status"`
+	SourceTxHash string        `json:"source_tx_hash,omitempty"`
+	ArbitrumTxHash string      `json:"arbitrum_tx_hash,omitempty"`
+	ErrorMessage string        `json:"error_message,omitempty"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+}
+
+// AgentWallet is an ephemeral Hyperliquid API wallet CopyEngine generates
+// for one follower, in place of the follower ever handing over a
+// long-lived private key. FollowerID is nil until CopyEngine.AddFollower
+// binds it -- generation and approval both happen before a follower row
+// exists to reference. It only becomes usable once MasterAddress has
+// signed an approveAgent action for Address, recorded in ApprovedAt;
+// RevokedAt marks it unusable even if ExpiresAt hasn't passed yet.
+// EncryptedKey is ciphertext produced by an api.AgentKeyCipher -- the
+// server never persists the plaintext key.
+type AgentWallet struct {
+	ID            int        `json:"id"`
+	FollowerID    *int       `json:"follower_id,omitempty"`
+	MasterAddress string     `json:"master_address"`
+	Address       string     `json:"address"`
+	EncryptedKey  []byte     `json:"-"`
+	ExpiresAt     time.Time  `json:"expires_at"`
+	ApprovedAt    *time.Time `json:"approved_at,omitempty"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// AgentApproval is the approveAgent signature a follower's master wallet
+// produced out-of-band against the typed data api.BuildApproveAgentTypedData
+// returned for an AgentWallet, submitted back to CopyEngine.AddFollower
+// alongside the rest of the follower's settings.
+type AgentApproval struct {
+	AgentName string                 `json:"agent_name"`
+	Nonce     int64                  `json:"nonce"`
+	Signature map[string]interface{} `json:"signature"`
 }
 
 type Position struct {
@@ -64,18 +227,78 @@ type Position struct {
 }
 
 type OrderRequest struct {
-	Asset     string   `json:"asset"`
-	IsBuy     bool     `json:"is_buy"`
-	Size      float64  `json:"size"`
-	Price     *float64 `json:"price,omitempty"`
-	OrderType string   `json:"order_type"`
-	Nonce     int64    `json:"nonce"`
+	Asset      string   `json:"asset"`
+	IsBuy      bool     `json:"is_buy"`
+	Size       float64  `json:"size"`
+	Price      *float64 `json:"price,omitempty"`
+	OrderType  string   `json:"order_type"`
+	ReduceOnly bool     `json:"reduce_only,omitempty"`
+	Tif        string   `json:"tif,omitempty"` // Time in force: "Gtc", "Ioc", "Alo"; defaults to "Gtc" if empty
+	Nonce      int64    `json:"nonce"`
+	Venue      string   `json:"venue,omitempty"`
+
+	// RiskScore is RiskAssessment.RiskScore at the time this order was
+	// built, carried along purely for OrderEngine's dispatch scheduler to
+	// rank against other queued orders -- it's never sent to the exchange.
+	RiskScore float64 `json:"-"`
+}
+
+// OrderStatus is one step in an order's lifecycle as tracked by OrderEngine,
+// from being queued locally through to a terminal exchange outcome.
+type OrderStatus string
+
+const (
+	OrderStatusQueued    OrderStatus = "queued"
+	OrderStatusSigned    OrderStatus = "signed"
+	OrderStatusSubmitted OrderStatus = "submitted"
+	OrderStatusResting   OrderStatus = "resting"
+	OrderStatusFilled    OrderStatus = "filled"
+	OrderStatusCancelled OrderStatus = "cancelled"
+	OrderStatusRejected  OrderStatus = "rejected"
+	OrderStatusFailed    OrderStatus = "failed"
+)
+
+// IsTerminal reports whether status ends an order's lifecycle, meaning no
+// further transitions or monitoring are expected once it's reached.
+func (s OrderStatus) IsTerminal() bool {
+	switch s {
+	case OrderStatusFilled, OrderStatusCancelled, OrderStatusRejected, OrderStatusFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// OrderState is one order's crash-safe lifecycle record. OrderEngine writes
+// a row through every transition so a restart can find any order still in
+// flight and resume monitoring it instead of losing track of it.
+type OrderState struct {
+	ID               int64       `json:"id"`
+	FollowerID       int         `json:"follower_id"`
+	APIWalletAddress string      `json:"api_wallet_address"`
+	Venue            string      `json:"venue,omitempty"`
+	Asset            string      `json:"asset"`
+	Nonce            int64       `json:"nonce"`
+	OID              *int64      `json:"oid,omitempty"`
+	Status           OrderStatus `json:"status"`
+	ErrorMessage     string      `json:"error_message,omitempty"`
+	CreatedAt        time.Time   `json:"created_at"`
+	UpdatedAt        time.Time   `json:"updated_at"`
 }
 
 type WebSocketMessage struct {
 	Method       string      `json:"method"`
 	Subscription interface{} `json:"subscription,omitempty"`
 	Data         interface{} `json:"data,omitempty"`
+	// ID correlates a subscribe/unsubscribe/post request with its
+	// acknowledgement or error response; unset (nil) for the ongoing data
+	// stream a subscription produces once acked.
+	ID *uint64 `json:"id,omitempty"`
+	// Request carries a "post" method's info/action payload.
+	Request interface{} `json:"request,omitempty"`
+	// Error carries the server's rejection reason for an id-carrying
+	// request; unset on a successful acknowledgement.
+	Error *string `json:"error,omitempty"`
 }
 
 type UserEvent struct {
@@ -83,6 +306,16 @@ type UserEvent struct {
 	Data interface{} `json:"data"`
 }
 
+// OrderUpdate is a push-based order lifecycle update extracted from a
+// userEvents message, keyed by the exchange-assigned order id so
+// OrderEngine's per-order waiters can resolve without polling
+// GetOrderStatus.
+type OrderUpdate struct {
+	Oid    int64                  `json:"oid"`
+	Status string                 `json:"status"` // "filled", "cancelled", "rejected"
+	Raw    map[string]interface{} `json:"raw"`
+}
+
 type TradeEvent struct {
 	Coin     string `json:"coin"`
 	Side     string `json:"side"`
@@ -129,6 +362,50 @@ type MarginSummary struct {
 	TotalRawUsd  string `json:"totalRawUsd"`
 }
 
+// LiveMarginState is pushed over the "margin" WebSocket subscription whenever
+// a UserState/Position delta changes the account's margin or liquidation
+// picture, modeled after Huobi's sub_accounts push topic. It gives followers
+// a pre-trade liquidation guard that realized Slippage/UnrealizedPnL alone
+// cannot express.
+type LiveMarginState struct {
+	User              string                     `json:"user"`
+	AccountValue      float64                    `json:"account_value"`
+	MarginBalance     float64                    `json:"margin_balance"`
+	MarginPosition    float64                    `json:"margin_position"`
+	MarginFrozen      float64                    `json:"margin_frozen"`
+	MarginAvailable   float64                    `json:"margin_available"`
+	WithdrawAvailable float64                    `json:"withdraw_available"`
+	RiskRate          float64                    `json:"risk_rate"`
+	LiquidationPrice  float64                    `json:"liquidation_price"`
+	LeverRate         float64                    `json:"lever_rate"`
+	AdjustFactor      float64                    `json:"adjust_factor"`
+	Assets            []AssetMarginState         `json:"assets"`
+	UpdatedAt         time.Time                  `json:"updated_at"`
+}
+
+// AssetMarginState is the per-asset breakdown attached to LiveMarginState.
+type AssetMarginState struct {
+	Asset            string  `json:"asset"`
+	MarginUsed       float64 `json:"margin_used"`
+	LiquidationPrice float64 `json:"liquidation_price"`
+	LeverRate        float64 `json:"lever_rate"`
+}
+
+// LeaderPerformanceSnapshot is a point-in-time materialization of a
+// leader's incrementally maintained performance metrics, persisted
+// periodically so a read becomes an O(1) row lookup instead of a full
+// re-scan over every historical fill.
+type LeaderPerformanceSnapshot struct {
+	LeaderAddress    string    `json:"leader_address"`
+	TotalPnL         float64   `json:"total_pnl"`
+	TotalTrades      int       `json:"total_trades"`
+	ProfitableTrades int       `json:"profitable_trades"`
+	WinRate          float64   `json:"win_rate"`
+	SharpeRatio      float64   `json:"sharpe_ratio"`
+	MaxDrawdown      float64   `json:"max_drawdown"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
 type PnLAnalytics struct {
 	TotalPnL         float64   `json:"total_pnl"`
 	DailyPnL         []float64 `json:"daily_pnl"`
@@ -141,13 +418,90 @@ type PnLAnalytics struct {
 
 // Enhanced order request with TIF and client order ID support
 type EnhancedOrderRequest struct {
-	Asset     string   `json:"asset"`
-	IsBuy     bool     `json:"is_buy"`
-	Size      float64  `json:"size"`
-	Price     *float64 `json:"price,omitempty"`
-	OrderType string   `json:"order_type"`
-	Tif       string   `json:"tif,omitempty"`       // Time in force: "Gtc", "Ioc", "Alo"
-	ClOid     *string  `json:"clOid,omitempty"`    // Client order ID
+	Asset      string   `json:"asset"`
+	IsBuy      bool     `json:"is_buy"`
+	Size       float64  `json:"size"`
+	Price      *float64 `json:"price,omitempty"`
+	OrderType  string   `json:"order_type"`
+	ReduceOnly bool     `json:"reduce_only,omitempty"`
+	Tif        string   `json:"tif,omitempty"`    // Time in force: "Gtc", "Ioc", "Alo"
+	ClOid      *string  `json:"clOid,omitempty"`  // Client order ID
+
+	// Trigger turns this order into a Hyperliquid trigger (TP/SL) order: it
+	// rests invisibly until the oracle/mark price crosses Trigger.TriggerPx,
+	// then fires as a market or limit order. Mutually exclusive with Tif
+	// being used for a plain resting order.
+	Trigger *TriggerParams `json:"trigger,omitempty"`
+
+	// Contingent/OCO/bracket support: LinkID groups this order with its
+	// Children under a Contingency relationship, mirroring the
+	// ClOrdLinkID/ContingencyType pattern used by BitMEX.
+	LinkID      string                  `json:"link_id,omitempty"`
+	Contingency ContingencyType         `json:"contingency,omitempty"`
+	Children    []EnhancedOrderRequest  `json:"children,omitempty"`
+}
+
+// TriggerParams is the "trigger" variant of Hyperliquid's order-type field:
+// {"trigger": {"triggerPx": ..., "isMarket": ..., "tpsl": "tp"|"sl"}}.
+type TriggerParams struct {
+	TriggerPx string `json:"triggerPx"`
+	IsMarket  bool   `json:"isMarket"`
+	Tpsl      string `json:"tpsl"` // "tp" or "sl"
+}
+
+// TwapParams configures a TWAP order: Size is sliced into SliceCount equal
+// child orders spread evenly across Duration, so the API client can
+// decompose one logical order into scheduled child orders rather than
+// submitting the whole size at once.
+type TwapParams struct {
+	Duration   time.Duration
+	SliceCount int
+}
+
+// ScaleParams configures a scale/ladder order: Size is split across
+// LevelCount child orders with limit prices spanning [StartPrice,
+// EndPrice], spaced linearly or, if Geometric is set, geometrically.
+type ScaleParams struct {
+	StartPrice float64
+	EndPrice   float64
+	LevelCount int
+	Geometric  bool
+}
+
+// ContingencyType describes how a parent order relates to its Children.
+type ContingencyType string
+
+const (
+	// ContingencyOCO cancels all other children once one fills (One Cancels Other).
+	ContingencyOCO ContingencyType = "OneCancelsOther"
+	// ContingencyOTO submits the children only once the parent fills (One Triggers Other).
+	ContingencyOTO ContingencyType = "OneTriggersOther"
+	// ContingencyOTOCO triggers the children on parent fill, then OCOs between them
+	// (One Triggers One Cancels Other) — the standard entry + TP/SL bracket shape.
+	ContingencyOTOCO ContingencyType = "OneTriggersOneCancelsOther"
+)
+
+// NewBracketOrder builds an entry order with attached take-profit/stop-loss
+// children linked via OneTriggersOneCancelsOther, the shape a copy trade uses
+// to enforce a follower's StopLossPercentage/TakeProfitPercentage atomically.
+func NewBracketOrder(entry EnhancedOrderRequest, linkID string, takeProfit, stopLoss *EnhancedOrderRequest) EnhancedOrderRequest {
+	entry.LinkID = linkID
+	entry.Contingency = ContingencyOTOCO
+
+	var children []EnhancedOrderRequest
+	if takeProfit != nil {
+		tp := *takeProfit
+		tp.LinkID = linkID
+		children = append(children, tp)
+	}
+	if stopLoss != nil {
+		sl := *stopLoss
+		sl.LinkID = linkID
+		children = append(children, sl)
+	}
+	entry.Children = children
+
+	return entry
 }
 
 // Order response models
@@ -156,14 +510,65 @@ type OrderResponse struct {
 	Data   OrderResponseData `json:"data"`
 }
 
+// TradingAccount identifies who an action is signed by and which book it
+// trades against: APIWallet is always the signer, while VaultOrSubaccount
+// is nil for the API wallet's own account or set to a vault/subaccount
+// address when that wallet is trading on its behalf. Hyperliquid tracks
+// nonce windows per (signer, account) pair, so this is also the unit
+// NonceManager keys its state on.
+type TradingAccount struct {
+	APIWallet         string
+	VaultOrSubaccount *string
+}
+
+// EffectiveAddress is the address whose margin/balances/positions an
+// order against this account actually affects: VaultOrSubaccount if set,
+// else the API wallet itself.
+func (a TradingAccount) EffectiveAddress() string {
+	if a.VaultOrSubaccount != nil && *a.VaultOrSubaccount != "" {
+		return *a.VaultOrSubaccount
+	}
+	return a.APIWallet
+}
+
+// Subaccount is one entry in GetSubaccounts' response: an account the
+// queried user controls in addition to their main account.
+type Subaccount struct {
+	Name           string `json:"name"`
+	SubaccountUser string `json:"subaccountUser"`
+	Master         string `json:"master"`
+}
+
+// SignedOrderEnvelope is the fully-signed, JSON-serializable output of
+// HyperliquidAPI.SignOrder: everything SubmitSigned needs to dispatch the
+// order over the wire without re-deriving or re-signing anything. This lets
+// signing happen on a machine, or at a time, completely separate from
+// submission -- an air-gapped signer produces the envelope, a hot node (or
+// a third-party relayer) submits it later.
+type SignedOrderEnvelope struct {
+	Action           map[string]interface{} `json:"action"`
+	Nonce            int64                  `json:"nonce"`
+	Signature        map[string]interface{} `json:"signature"`
+	VaultAddress     *string                `json:"vaultAddress"`
+	APIWalletAddress string                 `json:"apiWalletAddress"`
+	// ActionHash is a canonical sha256 of Action+Nonce, for a relayer or
+	// submission log to dedupe envelopes without re-signing them.
+	ActionHash string `json:"actionHash"`
+}
+
 type OrderResponseData struct {
-	Statuses []OrderStatus `json:"statuses"`
+	Statuses []OrderResponseStatus `json:"statuses"`
 }
 
-type OrderStatus struct {
-	Error   string            `json:"error,omitempty"`
-	Resting *OrderRestingInfo `json:"resting,omitempty"`
-	Filled  *OrderFillInfo    `json:"filled,omitempty"`
+// OrderResponseStatus is one entry in an order/batch-order response's
+// "statuses" array, reporting how the exchange handled that particular
+// order (resting, filled immediately, triggered, or rejected with Error).
+type OrderResponseStatus struct {
+	LinkID    string            `json:"link_id,omitempty"`
+	Error     string            `json:"error,omitempty"`
+	Resting   *OrderRestingInfo `json:"resting,omitempty"`
+	Filled    *OrderFillInfo    `json:"filled,omitempty"`
+	Triggered *OrderTriggerInfo `json:"triggered,omitempty"`
 }
 
 type OrderRestingInfo struct {
@@ -175,6 +580,14 @@ type OrderFillInfo struct {
 	AvgPx   string `json:"avgPx"`
 }
 
+// OrderTriggerInfo reports a trigger (TP/SL) child that has fired and been
+// placed as a live order, as opposed to one still waiting on its trigger price.
+type OrderTriggerInfo struct {
+	Oid        int64  `json:"oid"`
+	TriggerPx  string `json:"triggerPx"`
+	IsMarket   bool   `json:"isMarket"`
+}
+
 // Enhanced trade event with more fields
 type EnhancedTradeEvent struct {
 	Coin      string `json:"coin"`
@@ -189,6 +602,9 @@ type EnhancedTradeEvent struct {
 	User      string `json:"user"`
 	ClosedPnl string `json:"closedPnl,omitempty"`
 	Dir       string `json:"dir,omitempty"`
+	// Funding is populated only for funding-payment user events; empty for
+	// ordinary fills.
+	Funding string `json:"funding,omitempty"`
 }
 
 // Asset data for risk management
@@ -276,6 +692,31 @@ type PriceLevel struct {
 	N  int    `json:"n"`
 }
 
+// Kline is one candle from Hyperliquid's candleSnapshot endpoint, used to
+// drive the ATR/stddev-based volatility model in engine.AssetVolatilityProvider.
+type Kline struct {
+	Coin      string  `json:"s"`
+	Interval  string  `json:"i"`
+	OpenTime  int64   `json:"t"`
+	CloseTime int64   `json:"T"`
+	Open      float64 `json:"o,string"`
+	High      float64 `json:"h,string"`
+	Low       float64 `json:"l,string"`
+	Close     float64 `json:"c,string"`
+	Volume    float64 `json:"v,string"`
+	Trades    int     `json:"n"`
+}
+
+// BBO is one coin's best bid/offer, pushed by Hyperliquid's "bbo"
+// subscription channel for quote-level strategies that don't need the full
+// L2Book depth.
+type BBO struct {
+	Coin string      `json:"coin"`
+	Time int64       `json:"time"`
+	Bid  *PriceLevel `json:"bid,omitempty"`
+	Ask  *PriceLevel `json:"ask,omitempty"`
+}
+
 type Leverage struct {
 	Type  string `json:"type"`
 	Value int    `json:"value"`
@@ -306,24 +747,24 @@ const (
 )
 
 type RiskMetrics struct {
-	Volatility       float64   `json:"volatility_pct"`
-	MaxDrawdown      float64   `json:"max_drawdown_pct"`
-	VaR95            float64   `json:"var_95_pct"`
-	RiskLevel        RiskLevel `json:"risk_level"`
-	RiskScore        float64   `json:"risk_score"`
-	AvgTimeBetween   float64   `json:"avg_time_between_trades_minutes"`
-	TradingIntensity float64   `json:"trading_intensity"`
+	Volatility       float64   `json:"volatility_pct" csv:"volatility_pct"`
+	MaxDrawdown      float64   `json:"max_drawdown_pct" csv:"max_drawdown_pct"`
+	VaR95            float64   `json:"var_95_pct" csv:"var_95_pct"`
+	RiskLevel        RiskLevel `json:"risk_level" csv:"risk_level"`
+	RiskScore        float64   `json:"risk_score" csv:"risk_score"`
+	AvgTimeBetween   float64   `json:"avg_time_between_trades_minutes" csv:"avg_time_between_trades_minutes"`
+	TradingIntensity float64   `json:"trading_intensity" csv:"trading_intensity"`
 }
 
 type PerformanceMetrics struct {
-	TotalReturn      float64 `json:"total_return_pct"`
-	AnnualizedReturn float64 `json:"annualized_return_pct"`
-	SharpeRatio      float64 `json:"sharpe_ratio"`
-	WinRate          float64 `json:"win_rate"`
-	AvgWin           float64 `json:"avg_win"`
-	AvgLoss          float64 `json:"avg_loss"`
-	ProfitFactor     float64 `json:"profit_factor"`
-	TotalTrades      int     `json:"total_trades"`
+	TotalReturn      float64 `json:"total_return_pct" csv:"total_return_pct"`
+	AnnualizedReturn float64 `json:"annualized_return_pct" csv:"annualized_return_pct"`
+	SharpeRatio      float64 `json:"sharpe_ratio" csv:"sharpe_ratio"`
+	WinRate          float64 `json:"win_rate" csv:"win_rate"`
+	AvgWin           float64 `json:"avg_win" csv:"avg_win"`
+	AvgLoss          float64 `json:"avg_loss" csv:"avg_loss"`
+	ProfitFactor     float64 `json:"profit_factor" csv:"profit_factor"`
+	TotalTrades      int     `json:"total_trades" csv:"total_trades"`
 }
 
 type MarketMetrics struct {
@@ -334,22 +775,22 @@ type MarketMetrics struct {
 
 // Leader performance analysis
 type LeaderPerformanceAnalysis struct {
-	LeaderAddress      string             `json:"leader_address"`
-	AnalysisPeriodDays int                `json:"analysis_period_days"`
-	PerformanceMetrics PerformanceMetrics `json:"performance_metrics"`
-	RiskMetrics        RiskMetrics        `json:"risk_metrics"`
-	MarketMetrics      MarketMetrics      `json:"market_metrics"`
-	TradingFrequency   map[string]float64 `json:"trading_frequency"`
-	AssetAllocation    map[string]float64 `json:"asset_allocation"`
-	TimeSeriesData     []TimeSeriesPoint  `json:"time_series_data"`
-	Predictions        interface{}        `json:"predictions,omitempty"`
-	AnalysisTimestamp  time.Time          `json:"analysis_timestamp"`
+	LeaderAddress      string             `json:"leader_address" csv:"leader_address"`
+	AnalysisPeriodDays int                `json:"analysis_period_days" csv:"analysis_period_days"`
+	PerformanceMetrics PerformanceMetrics `json:"performance_metrics" csv:"-"`
+	RiskMetrics        RiskMetrics        `json:"risk_metrics" csv:"-"`
+	MarketMetrics      MarketMetrics      `json:"market_metrics" csv:"-"`
+	TradingFrequency   map[string]float64 `json:"trading_frequency" csv:"-"`
+	AssetAllocation    map[string]float64 `json:"asset_allocation" csv:"-"`
+	TimeSeriesData     []TimeSeriesPoint  `json:"time_series_data" csv:"-"`
+	Predictions        interface{}        `json:"predictions,omitempty" csv:"-"`
+	AnalysisTimestamp  time.Time          `json:"analysis_timestamp" csv:"analysis_timestamp"`
 }
 
 type TimeSeriesPoint struct {
-	Timestamp time.Time `json:"timestamp"`
-	Value     float64   `json:"value"`
-	Type      string    `json:"type"` // "pnl", "equity", "drawdown"
+	Timestamp time.Time `json:"timestamp" csv:"timestamp"`
+	Value     float64   `json:"value" csv:"value"`
+	Type      string    `json:"type" csv:"type"` // "pnl", "equity", "drawdown"
 }
 
 // Follower optimization
@@ -417,22 +858,22 @@ type TimeRange struct {
 
 // CopyTrade records each copy trading execution
 type CopyTrade struct {
-	ID                    int       `json:"id"`
-	OriginalTraderAddress string    `json:"original_trader_address"`
-	FollowerID            int       `json:"follower_id"`
-	OriginalTradeHash     string    `json:"original_trade_hash"`
-	Asset                 string    `json:"asset"`
-	Side                  string    `json:"side"`
-	OriginalSize          string    `json:"original_size"`
-	CopiedSize            string    `json:"copied_size"`
-	OriginalPrice         string    `json:"original_price"`
-	ExecutedPrice         string    `json:"executed_price,omitempty"`
-	Slippage              float64   `json:"slippage"`
-	DelayMs               int64     `json:"delay_ms"` // Execution delay
-	Status                string    `json:"status"`
-	ErrorMessage          string    `json:"error_message,omitempty"`
-	ExecutedAt            time.Time `json:"executed_at"`
-	CreatedAt             time.Time `json:"created_at"`
+	ID                    int       `json:"id" csv:"id"`
+	OriginalTraderAddress string    `json:"original_trader_address" csv:"original_trader_address"`
+	FollowerID            int       `json:"follower_id" csv:"follower_id"`
+	OriginalTradeHash     string    `json:"original_trade_hash" csv:"original_trade_hash"`
+	Asset                 string    `json:"asset" csv:"asset"`
+	Side                  string    `json:"side" csv:"side"`
+	OriginalSize          string    `json:"original_size" csv:"original_size"`
+	CopiedSize            string    `json:"copied_size" csv:"copied_size"`
+	OriginalPrice         string    `json:"original_price" csv:"original_price"`
+	ExecutedPrice         string    `json:"executed_price,omitempty" csv:"executed_price"`
+	Slippage              float64   `json:"slippage" csv:"slippage"`
+	DelayMs               int64     `json:"delay_ms" csv:"delay_ms"` // Execution delay
+	Status                string    `json:"status" csv:"status"`
+	ErrorMessage          string    `json:"error_message,omitempty" csv:"error_message"`
+	ExecutedAt            time.Time `json:"executed_at" csv:"executed_at"`
+	CreatedAt             time.Time `json:"created_at" csv:"created_at"`
 }
 
 // TraderDiscovery tracks discovered traders
@@ -502,29 +943,29 @@ type StrategyPerformance struct {
 
 // TraderAnalytics provides deep analytics for any trader
 type TraderAnalytics struct {
-	Address              string                 `json:"address"`
-	AnalysisPeriod       string                 `json:"analysis_period"`
-	TotalTrades          int                    `json:"total_trades"`
-	TotalVolume          float64                `json:"total_volume"`
-	WinRate              float64                `json:"win_rate"`
-	ProfitFactor         float64                `json:"profit_factor"`
-	SharpeRatio          float64                `json:"sharpe_ratio"`
-	MaxDrawdown          float64                `json:"max_drawdown"`
-	AvgWin               float64                `json:"avg_win"`
-	AvgLoss              float64                `json:"avg_loss"`
-	LargestWin           float64                `json:"largest_win"`
-	LargestLoss          float64                `json:"largest_loss"`
-	ConsecutiveWins      int                    `json:"consecutive_wins"`
-	ConsecutiveLosses    int                    `json:"consecutive_losses"`
-	AssetPreferences     map[string]float64     `json:"asset_preferences"`
-	TradingHours         map[int]int            `json:"trading_hours"`        // Hour -> Trade count
-	TradingDays          map[string]int         `json:"trading_days"`         // Day -> Trade count
-	PositionSizes        []float64              `json:"position_sizes"`
-	HoldingTimes         []int                  `json:"holding_times"`        // Minutes
-	RiskMetrics          *RiskMetrics           `json:"risk_metrics"`
-	SeasonalPerformance  map[string]float64     `json:"seasonal_performance"` // Month -> Performance
-	MarketConditions     map[string]float64     `json:"market_conditions"`    // Bull/Bear/Sideways performance
-	AnalyzedAt           time.Time              `json:"analyzed_at"`
+	Address              string                 `json:"address" csv:"address"`
+	AnalysisPeriod       string                 `json:"analysis_period" csv:"analysis_period"`
+	TotalTrades          int                    `json:"total_trades" csv:"total_trades"`
+	TotalVolume          float64                `json:"total_volume" csv:"total_volume"`
+	WinRate              float64                `json:"win_rate" csv:"win_rate"`
+	ProfitFactor         float64                `json:"profit_factor" csv:"profit_factor"`
+	SharpeRatio          float64                `json:"sharpe_ratio" csv:"sharpe_ratio"`
+	MaxDrawdown          float64                `json:"max_drawdown" csv:"max_drawdown"`
+	AvgWin               float64                `json:"avg_win" csv:"avg_win"`
+	AvgLoss              float64                `json:"avg_loss" csv:"avg_loss"`
+	LargestWin           float64                `json:"largest_win" csv:"largest_win"`
+	LargestLoss          float64                `json:"largest_loss" csv:"largest_loss"`
+	ConsecutiveWins      int                    `json:"consecutive_wins" csv:"consecutive_wins"`
+	ConsecutiveLosses    int                    `json:"consecutive_losses" csv:"consecutive_losses"`
+	AssetPreferences     map[string]float64     `json:"asset_preferences" csv:"-"`
+	TradingHours         map[int]int            `json:"trading_hours" csv:"-"`        // Hour -> Trade count
+	TradingDays          map[string]int         `json:"trading_days" csv:"-"`         // Day -> Trade count
+	PositionSizes        []float64              `json:"position_sizes" csv:"-"`
+	HoldingTimes         []int                  `json:"holding_times" csv:"-"`        // Minutes
+	RiskMetrics          *RiskMetrics           `json:"risk_metrics" csv:"-"`
+	SeasonalPerformance  map[string]float64     `json:"seasonal_performance" csv:"-"` // Month -> Performance
+	MarketConditions     map[string]float64     `json:"market_conditions" csv:"-"`    // Bull/Bear/Sideways performance
+	AnalyzedAt           time.Time              `json:"analyzed_at" csv:"analyzed_at"`
 }
 
 // SmartCopyOrder represents an order with intelligent execution
@@ -556,6 +997,16 @@ type PartialExecution struct {
 	MarketState string    `json:"market_state"` // Market conditions at execution
 }
 
+// BlockedAddress is one entry in the address blacklist BlacklistStore loads
+// and enforces, whether added manually via the admin API or synced from an
+// external OFAC-style/JSON source.
+type BlockedAddress struct {
+	Address   string    `json:"address"`
+	Reason    string    `json:"reason,omitempty"`
+	Source    string    `json:"source"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 // CopyTradingInsights provides AI-driven insights
 type CopyTradingInsights struct {
 	UserID              string                 `json:"user_id"`
@@ -570,3 +1021,42 @@ type CopyTradingInsights struct {
 	RecommendedActions  []string               `json:"recommended_actions"`
 	GeneratedAt         time.Time              `json:"generated_at"`
 }
+
+// LeaderEvent is one immutable entry in the leader activity log: a trade,
+// fill, liquidation, or funding event, topic-addressed by
+// (Leader, Asset, EventType) the same way an eth_getLogs entry is
+// addressed by (address, topics). Height orders events within a topic --
+// it's the leader trade/fill's Unix millisecond timestamp, not a block
+// number, since Hyperliquid has no block height a follower can anchor to.
+type LeaderEvent struct {
+	ID        int64                  `json:"id"`
+	Leader    string                 `json:"leader"`
+	Asset     string                 `json:"asset"`
+	EventType string                 `json:"event_type"` // "trade", "fill", "liquidation", "funding"
+	Height    int64                  `json:"height"`
+	Payload   map[string]interface{} `json:"payload"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// EventFilter narrows a LeaderEvent query or subscription the same way an
+// eth_getLogs filter narrows by address/topics: a zero field means "don't
+// filter on this dimension".
+type EventFilter struct {
+	Leader    string
+	Asset     string
+	EventType string
+}
+
+// Matches reports whether event satisfies every non-zero field of f.
+func (f EventFilter) Matches(event LeaderEvent) bool {
+	if f.Leader != "" && f.Leader != event.Leader {
+		return false
+	}
+	if f.Asset != "" && f.Asset != event.Asset {
+		return false
+	}
+	if f.EventType != "" && f.EventType != event.EventType {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,88 @@
+package optimize
+
+import (
+	"context"
+	"hyperliquid-copy-trading/internal/api"
+)
+
+// searchGrid evaluates every combination of cfg.SearchSpace, discretized to
+// cfg.GridSteps points per continuous dimension, and returns the candidate
+// with the best mean out-of-sample score across all folds.
+func searchGrid(ctx context.Context, hl *api.HyperliquidAPI, cfg Config, folds []fold) (candidate, error) {
+	keys, grids := buildGrids(cfg.SearchSpace, cfg.GridSteps)
+
+	var best candidate
+	haveBest := false
+
+	for _, combo := range cartesianProduct(grids) {
+		params := make(map[string]float64, len(keys))
+		for i, key := range keys {
+			params[key] = combo[i]
+		}
+
+		score, err := evaluateAcrossFolds(ctx, hl, cfg, folds, params)
+		if err != nil {
+			return candidate{}, err
+		}
+
+		if !haveBest || score > best.score {
+			best = candidate{params: params, score: score}
+			haveBest = true
+		}
+	}
+
+	return best, nil
+}
+
+// buildGrids discretizes each ParamRange into its candidate values: Values
+// verbatim for discrete ranges, or `steps` evenly spaced points for
+// continuous ones.
+func buildGrids(space SearchSpace, steps int) ([]string, [][]float64) {
+	keys := make([]string, 0, len(space))
+	for key := range space {
+		keys = append(keys, key)
+	}
+
+	grids := make([][]float64, len(keys))
+	for i, key := range keys {
+		r := space[key]
+		if r.Discrete {
+			grids[i] = r.Values
+			continue
+		}
+
+		if steps < 2 {
+			steps = 2
+		}
+		values := make([]float64, steps)
+		span := r.Max - r.Min
+		for s := 0; s < steps; s++ {
+			values[s] = r.Min + span*float64(s)/float64(steps-1)
+		}
+		grids[i] = values
+	}
+
+	return keys, grids
+}
+
+// cartesianProduct enumerates every combination across the given dimensions.
+func cartesianProduct(grids [][]float64) [][]float64 {
+	if len(grids) == 0 {
+		return nil
+	}
+
+	combos := [][]float64{{}}
+	for _, values := range grids {
+		var next [][]float64
+		for _, combo := range combos {
+			for _, v := range values {
+				extended := make([]float64, len(combo)+1)
+				copy(extended, combo)
+				extended[len(combo)] = v
+				next = append(next, extended)
+			}
+		}
+		combos = next
+	}
+	return combos
+}
@@ -0,0 +1,327 @@
+// Package optimize walk-forward tunes CopyFilters and sizing settings for a
+// PermissionlessFollower against a target trader's historical fills, using
+// the backtest package as its evaluation function.
+package optimize
+
+import (
+	"context"
+	"fmt"
+	"hyperliquid-copy-trading/internal/api"
+	"hyperliquid-copy-trading/internal/backtest"
+	"hyperliquid-copy-trading/internal/models"
+	"hyperliquid-copy-trading/internal/utils"
+	"math"
+	"sort"
+	"time"
+)
+
+// Objective selects the metric maximized during search. All three are
+// computed out-of-sample only.
+type Objective string
+
+const (
+	ObjectiveSharpe      Objective = "sharpe"
+	ObjectiveCalmar      Objective = "calmar"
+	ObjectiveTotalReturn Objective = "total_return"
+)
+
+// Mode selects the search strategy.
+type Mode string
+
+const (
+	ModeGrid Mode = "grid"
+	ModeTPE  Mode = "tpe"
+)
+
+// ParamRange describes the search bounds for one tunable setting. Discrete
+// ranges (e.g. ExcludeLeverageAbove) enumerate Values as a categorical
+// distribution instead of sampling continuously between Min/Max.
+type ParamRange struct {
+	Min      float64
+	Max      float64
+	Discrete bool
+	Values   []float64
+}
+
+// SearchSpace maps a CopyFilters/follower field name to its tunable range.
+// Recognized keys: min_position_value, max_position_value,
+// exclude_leverage_above, time_delay_seconds, slippage_tolerance,
+// max_drawdown_stop, copy_percentage, max_position_size.
+type SearchSpace map[string]ParamRange
+
+// DefaultSearchSpace returns sensible bounds for every tunable field.
+func DefaultSearchSpace() SearchSpace {
+	return SearchSpace{
+		"min_position_value":     {Min: 0, Max: 1000},
+		"max_position_value":     {Min: 1000, Max: 100000},
+		"exclude_leverage_above": {Discrete: true, Values: []float64{5, 10, 20, 50}},
+		"time_delay_seconds":     {Discrete: true, Values: []float64{0, 1, 5, 15, 30, 60}},
+		"slippage_tolerance":     {Min: 0.001, Max: 0.05},
+		"max_drawdown_stop":      {Min: 1, Max: 30},
+		"copy_percentage":        {Min: 1, Max: 100},
+		"max_position_size":      {Min: 100, Max: 50000},
+	}
+}
+
+// Config describes one optimization run.
+type Config struct {
+	TargetTrader   string
+	BaseFollower   *models.PermissionlessFollower
+	StartTime      time.Time
+	EndTime        time.Time
+	Folds          int // number of walk-forward folds; minimum 2
+	Objective      Objective
+	Mode           Mode
+	Trials         int // candidates evaluated per fold in TPE mode; ignored in grid mode
+	GridSteps      int // grid points per continuous dimension in grid mode
+	SearchSpace    SearchSpace
+	StartingEquity float64
+	SlippageBps    float64
+}
+
+// fold is one walk-forward split: an in-sample window and the out-of-sample
+// window that follows it. Only the out-of-sample window is backtested.
+type fold struct {
+	inStart, inEnd   time.Time
+	outStart, outEnd time.Time
+}
+
+// candidate is one sampled point in the search space plus its OOS score.
+type candidate struct {
+	params map[string]float64
+	score  float64
+}
+
+// Run walk-forward tunes cfg.BaseFollower's settings against cfg.TargetTrader
+// and returns a FollowerOptimization populated with the winning params and
+// the OOS expected improvement over the current settings. The search and
+// ExpectedImprovement both use the mean score over the folds' out-of-sample
+// windows, so the winner is scored on the same windows it was selected on;
+// the in-sample windows are not backtested.
+func Run(ctx context.Context, hl *api.HyperliquidAPI, cfg Config) (*models.FollowerOptimization, error) {
+	if cfg.BaseFollower == nil {
+		return nil, fmt.Errorf("optimize: base follower settings are required")
+	}
+	if cfg.Folds < 2 {
+		cfg.Folds = 2
+	}
+	if cfg.SearchSpace == nil {
+		cfg.SearchSpace = DefaultSearchSpace()
+	}
+	if cfg.Trials <= 0 {
+		cfg.Trials = 30
+	}
+	if cfg.GridSteps <= 0 {
+		cfg.GridSteps = 4
+	}
+
+	folds := buildFolds(cfg.StartTime, cfg.EndTime, cfg.Folds)
+
+	currentParams := extractParams(cfg.BaseFollower)
+	currentOOS, err := evaluateAcrossFolds(ctx, hl, cfg, folds, currentParams)
+	if err != nil {
+		return nil, fmt.Errorf("optimize: failed to evaluate current settings: %w", err)
+	}
+
+	var best candidate
+	switch cfg.Mode {
+	case ModeTPE:
+		best, err = searchTPE(ctx, hl, cfg, folds)
+	default:
+		best, err = searchGrid(ctx, hl, cfg, folds)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("optimize: search failed: %w", err)
+	}
+
+	result := &models.FollowerOptimization{
+		FollowerID:            cfg.BaseFollower.ID,
+		CurrentSettings:       paramsToSettings(currentParams),
+		OptimizedSettings:     paramsToSettings(best.params),
+		ExpectedImprovement:   best.score - currentOOS,
+		OptimizationTimestamp: time.Now(),
+	}
+
+	return result, nil
+}
+
+// buildFolds splits [start, end) into n+1 equal windows and pairs them into n
+// folds: fold i's in-sample window is window i and its out-of-sample window
+// is window i+1.
+func buildFolds(start, end time.Time, n int) []fold {
+	total := end.Sub(start)
+	step := total / time.Duration(n+1)
+
+	folds := make([]fold, 0, n)
+	for i := 0; i < n; i++ {
+		inStart := start.Add(time.Duration(i) * step)
+		inEnd := inStart.Add(step)
+		outStart := inEnd
+		outEnd := outStart.Add(step)
+		folds = append(folds, fold{inStart: inStart, inEnd: inEnd, outStart: outStart, outEnd: outEnd})
+	}
+	return folds
+}
+
+// evaluateAcrossFolds runs params out-of-sample on every fold and returns the
+// mean objective score. A fold's in-sample window is never read; searchGrid,
+// searchTPE and Run all score through this function.
+func evaluateAcrossFolds(ctx context.Context, hl *api.HyperliquidAPI, cfg Config, folds []fold, params map[string]float64) (float64, error) {
+	var scores []float64
+	for _, f := range folds {
+		perf, series, err := runBacktest(ctx, hl, cfg, params, f.outStart, f.outEnd)
+		if err != nil {
+			return 0, err
+		}
+		scores = append(scores, objectiveScore(cfg.Objective, perf, series))
+	}
+	return mean(scores), nil
+}
+
+func runBacktest(ctx context.Context, hl *api.HyperliquidAPI, cfg Config, params map[string]float64, start, end time.Time) (*models.StrategyPerformance, []models.TimeSeriesPoint, error) {
+	follower := applyParams(cfg.BaseFollower, params)
+
+	btCfg := backtest.Config{
+		Follower:       follower,
+		TargetTraders:  []string{cfg.TargetTrader},
+		StartTime:      start,
+		EndTime:        end,
+		StartingEquity: cfg.StartingEquity,
+		SlippageBps:    cfg.SlippageBps,
+	}
+
+	return backtest.Run(ctx, hl, btCfg)
+}
+
+func objectiveScore(objective Objective, perf *models.StrategyPerformance, series []models.TimeSeriesPoint) float64 {
+	if perf == nil {
+		return math.Inf(-1)
+	}
+
+	switch objective {
+	case ObjectiveSharpe:
+		return utils.CalculateSharpeRatio(equityReturns(series), 0)
+	case ObjectiveCalmar:
+		if perf.MaxDrawdown == 0 {
+			return perf.TotalReturn
+		}
+		return perf.TotalReturn / perf.MaxDrawdown
+	default:
+		return perf.TotalReturn
+	}
+}
+
+// equityReturns converts an equity time series into period-over-period
+// returns for Sharpe calculation.
+func equityReturns(series []models.TimeSeriesPoint) []float64 {
+	var equity []float64
+	for _, point := range series {
+		if point.Type == "equity" {
+			equity = append(equity, point.Value)
+		}
+	}
+
+	if len(equity) < 2 {
+		return nil
+	}
+
+	returns := make([]float64, 0, len(equity)-1)
+	for i := 1; i < len(equity); i++ {
+		if equity[i-1] == 0 {
+			continue
+		}
+		returns = append(returns, (equity[i]-equity[i-1])/equity[i-1])
+	}
+	return returns
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// extractParams reads the tunable fields off the follower's current
+// settings so the optimizer has a baseline to beat.
+func extractParams(follower *models.PermissionlessFollower) map[string]float64 {
+	params := map[string]float64{
+		"copy_percentage":   follower.CopyPercentage,
+		"max_position_size": follower.MaxPositionSize,
+	}
+
+	if follower.CopyFilters != nil {
+		params["min_position_value"] = follower.CopyFilters.MinPositionValue
+		params["max_position_value"] = follower.CopyFilters.MaxPositionValue
+		params["exclude_leverage_above"] = float64(follower.CopyFilters.ExcludeLeverageAbove)
+		params["time_delay_seconds"] = float64(follower.CopyFilters.TimeDelaySeconds)
+		params["slippage_tolerance"] = follower.CopyFilters.SlippageTolerance
+		params["max_drawdown_stop"] = follower.CopyFilters.MaxDrawdownStop
+	}
+
+	return params
+}
+
+// applyParams clones the base follower and overlays the candidate params,
+// never mutating the caller's follower.
+func applyParams(base *models.PermissionlessFollower, params map[string]float64) *models.PermissionlessFollower {
+	follower := *base
+
+	filters := copyFiltersOrDefault(base.CopyFilters)
+	clonedFilters := *filters
+	follower.CopyFilters = &clonedFilters
+
+	if v, ok := params["copy_percentage"]; ok {
+		follower.CopyPercentage = v
+	}
+	if v, ok := params["max_position_size"]; ok {
+		follower.MaxPositionSize = v
+	}
+	if v, ok := params["min_position_value"]; ok {
+		follower.CopyFilters.MinPositionValue = v
+	}
+	if v, ok := params["max_position_value"]; ok {
+		follower.CopyFilters.MaxPositionValue = v
+	}
+	if v, ok := params["exclude_leverage_above"]; ok {
+		follower.CopyFilters.ExcludeLeverageAbove = int(v)
+	}
+	if v, ok := params["time_delay_seconds"]; ok {
+		follower.CopyFilters.TimeDelaySeconds = int(v)
+	}
+	if v, ok := params["slippage_tolerance"]; ok {
+		follower.CopyFilters.SlippageTolerance = v
+	}
+	if v, ok := params["max_drawdown_stop"]; ok {
+		follower.CopyFilters.MaxDrawdownStop = v
+	}
+
+	return &follower
+}
+
+// copyFiltersOrDefault returns filters, or a zero-value CopyFilters if nil.
+func copyFiltersOrDefault(filters *models.CopyFilters) *models.CopyFilters {
+	if filters != nil {
+		return filters
+	}
+	return &models.CopyFilters{}
+}
+
+func paramsToSettings(params map[string]float64) map[string]interface{} {
+	settings := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		settings[k] = v
+	}
+	return settings
+}
+
+// sortCandidates orders candidates best-score-first.
+func sortCandidates(candidates []candidate) {
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+}
@@ -0,0 +1,252 @@
+package optimize
+
+import (
+	"context"
+	"hyperliquid-copy-trading/internal/api"
+	"math"
+	"sort"
+)
+
+// tpeGamma is the top-quantile split between "good" trials (fitted by l(x))
+// and "bad" trials (fitted by g(x)).
+const tpeGamma = 0.25
+
+// tpeRandomTrials is how many candidates are sampled uniformly before
+// enough trials exist to fit l(x)/g(x) KDEs.
+const tpeRandomTrials = 10
+
+// searchTPE implements a single-fold-aggregated Tree-structured Parzen
+// Estimator: trials are scored by their mean OOS objective across all folds,
+// split into an above-gamma group and a below-gamma group per dimension,
+// modeled as 1-D Gaussian KDEs l(x)/g(x), and new candidates are sampled from
+// l(x) and ranked by l(x)/g(x) before being evaluated for real.
+func searchTPE(ctx context.Context, hl *api.HyperliquidAPI, cfg Config, folds []fold) (candidate, error) {
+	keys := make([]string, 0, len(cfg.SearchSpace))
+	for key := range cfg.SearchSpace {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys) // fixed order, so the PRNG draws land on the same keys every run
+
+	var trials []candidate
+	rngState := uint64(0x9E3779B97F4A7C15)
+
+	var best candidate
+	haveBest := false
+
+	for t := 0; t < cfg.Trials; t++ {
+		var params map[string]float64
+
+		if len(trials) < tpeRandomTrials {
+			params, rngState = sampleUniform(cfg.SearchSpace, keys, rngState)
+		} else {
+			params, rngState = sampleTPE(cfg.SearchSpace, keys, trials, rngState)
+		}
+
+		score, err := evaluateAcrossFolds(ctx, hl, cfg, folds, params)
+		if err != nil {
+			return candidate{}, err
+		}
+
+		c := candidate{params: params, score: score}
+		trials = append(trials, c)
+		sortCandidates(trials)
+
+		if !haveBest || score > best.score {
+			best = c
+			haveBest = true
+		}
+	}
+
+	return best, nil
+}
+
+// sampleUniform draws one candidate uniformly at random from the search
+// space, used to seed the TPE history before l(x)/g(x) can be fit.
+func sampleUniform(space SearchSpace, keys []string, state uint64) (map[string]float64, uint64) {
+	params := make(map[string]float64, len(keys))
+	for _, key := range keys {
+		r := space[key]
+		var u float64
+		u, state = nextUniform(state)
+
+		if r.Discrete {
+			idx := int(u * float64(len(r.Values)))
+			if idx >= len(r.Values) {
+				idx = len(r.Values) - 1
+			}
+			params[key] = r.Values[idx]
+		} else {
+			params[key] = r.Min + u*(r.Max-r.Min)
+		}
+	}
+	return params, state
+}
+
+// sampleTPE splits trials into the top tpeGamma fraction (l) and the rest
+// (g), fits a 1-D Gaussian per dimension for each group, draws candidates
+// from l, and keeps the one maximizing l(x)/g(x).
+func sampleTPE(space SearchSpace, keys []string, trials []candidate, state uint64) (map[string]float64, uint64) {
+	splitIdx := int(math.Ceil(float64(len(trials)) * tpeGamma))
+	if splitIdx < 1 {
+		splitIdx = 1
+	}
+	good := trials[:splitIdx]
+	bad := trials[splitIdx:]
+	if len(bad) == 0 {
+		bad = trials
+	}
+
+	const candidatePool = 24
+	var bestParams map[string]float64
+	bestRatio := math.Inf(-1)
+
+	for i := 0; i < candidatePool; i++ {
+		params := make(map[string]float64, len(keys))
+		logRatio := 0.0
+
+		for _, key := range keys {
+			r := space[key]
+			goodValues := valuesFor(good, key)
+			badValues := valuesFor(bad, key)
+
+			var sample float64
+			if r.Discrete {
+				idx, nextState := categoricalSample(r.Values, goodValues, state)
+				state = nextState
+				sample = r.Values[idx]
+			} else {
+				mean, std := gaussianParams(goodValues, r.Min, r.Max)
+				var g float64
+				g, state = nextGaussian(state)
+				sample = clamp(mean+g*std, r.Min, r.Max)
+			}
+			params[key] = sample
+
+			lDensity := density(sample, goodValues, r)
+			gDensity := density(sample, badValues, r)
+			logRatio += math.Log(lDensity+1e-9) - math.Log(gDensity+1e-9)
+		}
+
+		if logRatio > bestRatio {
+			bestRatio = logRatio
+			bestParams = params
+		}
+	}
+
+	return bestParams, state
+}
+
+func valuesFor(trials []candidate, key string) []float64 {
+	values := make([]float64, 0, len(trials))
+	for _, t := range trials {
+		values = append(values, t.params[key])
+	}
+	return values
+}
+
+// gaussianParams fits a mean/std to values, falling back to the midpoint and
+// a quarter of the range when there isn't enough data to estimate spread.
+func gaussianParams(values []float64, min, max float64) (float64, float64) {
+	if len(values) == 0 {
+		return (min + max) / 2, (max - min) / 4
+	}
+
+	mean := mean(values)
+	if len(values) == 1 {
+		return mean, (max - min) / 4
+	}
+
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	std := math.Sqrt(variance / float64(len(values)))
+	if std < (max-min)*0.01 {
+		std = (max - min) * 0.05
+	}
+	return mean, std
+}
+
+// density evaluates a Gaussian-kernel density estimate of x against the
+// observed sample values, used to score l(x)/g(x) for a continuous range or
+// as a simple empirical frequency for a discrete one.
+func density(x float64, values []float64, r ParamRange) float64 {
+	if len(values) == 0 {
+		return 1e-6
+	}
+
+	if r.Discrete {
+		var count float64
+		for _, v := range values {
+			if v == x {
+				count++
+			}
+		}
+		return (count + 0.5) / float64(len(values)+1)
+	}
+
+	mean, std := gaussianParams(values, r.Min, r.Max)
+	if std == 0 {
+		std = 1e-6
+	}
+	z := (x - mean) / std
+	return math.Exp(-0.5*z*z) / (std * math.Sqrt(2*math.Pi))
+}
+
+func categoricalSample(all, good []float64, state uint64) (int, uint64) {
+	counts := make(map[float64]int)
+	for _, v := range good {
+		counts[v]++
+	}
+
+	best := 0
+	bestCount := -1
+	for i, v := range all {
+		if counts[v] > bestCount {
+			bestCount = counts[v]
+			best = i
+		}
+	}
+
+	// Occasionally explore a non-favored category to avoid collapsing the
+	// search onto the first good trial's value.
+	u, next := nextUniform(state)
+	if u < 0.2 {
+		idx := int(u / 0.2 * float64(len(all)))
+		if idx >= len(all) {
+			idx = len(all) - 1
+		}
+		return idx, next
+	}
+	return best, next
+}
+
+func clamp(v, min, max float64) float64 {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+// nextUniform and nextGaussian are a tiny deterministic splitmix64-based PRNG
+// so optimization runs are reproducible without a global rand dependency.
+func nextUniform(state uint64) (float64, uint64) {
+	state += 0x9E3779B97F4A7C15
+	z := state
+	z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+	z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+	z = z ^ (z >> 31)
+	return float64(z>>11) / float64(1<<53), state
+}
+
+func nextGaussian(state uint64) (float64, uint64) {
+	u1, state := nextUniform(state)
+	u2, state := nextUniform(state)
+	if u1 < 1e-12 {
+		u1 = 1e-12
+	}
+	return math.Sqrt(-2*math.Log(u1)) * math.Cos(2*math.Pi*u2), state
+}
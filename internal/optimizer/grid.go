@@ -0,0 +1,57 @@
+package optimizer
+
+import "sort"
+
+// gridSearch lays out every combination of SearchSpace's discrete points
+// (Min, Min+Step, ... up to Max) as the Cartesian product across all keys.
+func gridSearch(space SearchSpace) []map[string]float64 {
+	keys := make([]string, 0, len(space))
+	for key := range space {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys) // deterministic iteration order
+
+	values := make([][]float64, len(keys))
+	for i, key := range keys {
+		values[i] = gridPoints(space[key])
+	}
+
+	return cartesianProduct(keys, values)
+}
+
+// gridPoints enumerates r's discrete sweep points, always including Max even
+// if it doesn't land on an exact Step boundary.
+func gridPoints(r ParamRange) []float64 {
+	if r.Step <= 0 || r.Min >= r.Max {
+		return []float64{r.Min}
+	}
+
+	var points []float64
+	for v := r.Min; v < r.Max; v += r.Step {
+		points = append(points, v)
+	}
+	return append(points, r.Max)
+}
+
+// cartesianProduct builds every combination of keys[i] -> values[i][*] as a
+// slice of parameter maps.
+func cartesianProduct(keys []string, values [][]float64) []map[string]float64 {
+	combos := []map[string]float64{{}}
+
+	for i, key := range keys {
+		var next []map[string]float64
+		for _, combo := range combos {
+			for _, v := range values[i] {
+				extended := make(map[string]float64, len(combo)+1)
+				for k, existing := range combo {
+					extended[k] = existing
+				}
+				extended[key] = v
+				next = append(next, extended)
+			}
+		}
+		combos = next
+	}
+
+	return combos
+}
@@ -0,0 +1,38 @@
+package optimizer
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// defaultIterations is used when Config.Iterations is left unset (<= 0).
+const defaultIterations = 50
+
+// randomSearch draws `iterations` uniform samples from space, seeded for
+// reproducibility. This is the "hpoptimizer" mode: cheaper per-candidate
+// than an exhaustive grid, and better suited to wide search spaces where a
+// full Cartesian product would be too large to evaluate.
+func randomSearch(space SearchSpace, iterations int, seed int64) []map[string]float64 {
+	if iterations <= 0 {
+		iterations = defaultIterations
+	}
+
+	keys := make([]string, 0, len(space))
+	for key := range space {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys) // fixed order, so a seed always draws the same value for each key
+
+	rng := rand.New(rand.NewSource(seed))
+	samples := make([]map[string]float64, iterations)
+	for i := 0; i < iterations; i++ {
+		sample := make(map[string]float64, len(keys))
+		for _, key := range keys {
+			r := space[key]
+			sample[key] = r.Min + rng.Float64()*(r.Max-r.Min)
+		}
+		samples[i] = sample
+	}
+
+	return samples
+}
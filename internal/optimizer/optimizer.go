@@ -0,0 +1,229 @@
+// Package optimizer sweeps a follower's risk parameters against a leader's
+// historical trades, replaying them through engine.RiskManager.AssessRiskAt
+// and a lightweight fill simulator to score each candidate configuration.
+// Its grid/hpoptimizer (random search) split parallels internal/optimize's
+// grid/TPE modes, but targets
+// per-follower risk settings rather than CopyFilters/PermissionlessFollower
+// hyperparameters.
+package optimizer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"hyperliquid-copy-trading/config"
+	"hyperliquid-copy-trading/internal/engine"
+	"hyperliquid-copy-trading/internal/models"
+)
+
+// Objective selects which statistic from the replayed RiskReport a
+// candidate is ranked by.
+type Objective string
+
+const (
+	ObjectiveProfitFactor Objective = "profit_factor"
+	ObjectiveSharpe       Objective = "sharpe"
+	ObjectiveSortino      Objective = "sortino"
+	ObjectiveNegDrawdown  Objective = "neg_max_drawdown" // smaller drawdown scores higher
+)
+
+// Mode selects the search strategy.
+type Mode string
+
+const (
+	ModeGrid     Mode = "grid"
+	ModeHyperopt Mode = "hpoptimizer"
+)
+
+// ParamRange declares one tunable risk parameter's sweep bounds. Step is
+// used by grid search to lay out discrete points; hpoptimizer samples
+// continuously between Min and Max and ignores it.
+type ParamRange struct {
+	Min  float64
+	Max  float64
+	Step float64
+}
+
+// SearchSpace maps a follower risk-setting key to its sweep range.
+// Recognized keys: "copy_percentage", "max_position_size",
+// "stop_loss_percentage", "take_profit_percentage", "max_trades_per_hour",
+// "atr_tp_mult", "atr_sl_mult".
+type SearchSpace map[string]ParamRange
+
+// DefaultSearchSpace returns a reasonable sweep range for every supported
+// follower risk parameter.
+func DefaultSearchSpace() SearchSpace {
+	return SearchSpace{
+		"copy_percentage":        {Min: 10, Max: 100, Step: 10},
+		"max_position_size":      {Min: 1000, Max: 50000, Step: 5000},
+		"stop_loss_percentage":   {Min: 2, Max: 20, Step: 2},
+		"take_profit_percentage": {Min: 5, Max: 50, Step: 5},
+		"max_trades_per_hour":    {Min: 1, Max: 20, Step: 1},
+		"atr_tp_mult":            {Min: 1, Max: 5, Step: 0.5},
+		"atr_sl_mult":            {Min: 1, Max: 5, Step: 0.5},
+	}
+}
+
+// Config describes one optimization run.
+type Config struct {
+	Follower     *models.Follower
+	LeaderTrades []models.Trade // chronological order, IsLeaderTrade == true
+	Space        SearchSpace
+	Mode         Mode
+	Objective    Objective
+	Iterations   int   // sample count for ModeHyperopt; ignored for ModeGrid
+	Seed         int64 // ModeHyperopt PRNG seed, for reproducible sweeps
+}
+
+// Candidate is one evaluated parameter set.
+type Candidate struct {
+	Params map[string]float64
+	Score  float64
+	Report *engine.RiskReport
+}
+
+// Result is a completed sweep, ranked best-first.
+type Result struct {
+	Candidates []Candidate
+	Best       *Candidate
+}
+
+// Run evaluates every candidate parameter set in cfg.Space (via grid or
+// random sampling, per cfg.Mode) against cfg.LeaderTrades and returns them
+// ranked by cfg.Objective, best first.
+func Run(ctx context.Context, cfg Config) (*Result, error) {
+	if cfg.Follower == nil {
+		return nil, fmt.Errorf("optimizer: Config.Follower is required")
+	}
+	if len(cfg.Space) == 0 {
+		cfg.Space = DefaultSearchSpace()
+	}
+
+	var paramSets []map[string]float64
+	switch cfg.Mode {
+	case ModeHyperopt:
+		paramSets = randomSearch(cfg.Space, cfg.Iterations, cfg.Seed)
+	default:
+		paramSets = gridSearch(cfg.Space)
+	}
+
+	candidates := make([]Candidate, 0, len(paramSets))
+	for _, params := range paramSets {
+		follower := applyParams(cfg.Follower, params)
+
+		report, err := replay(follower, cfg.LeaderTrades)
+		if err != nil {
+			return nil, fmt.Errorf("optimizer: replaying candidate: %w", err)
+		}
+
+		candidates = append(candidates, Candidate{
+			Params: params,
+			Score:  objectiveScore(report, cfg.Objective),
+			Report: report,
+		})
+	}
+
+	sortCandidates(candidates)
+
+	result := &Result{Candidates: candidates}
+	if len(candidates) > 0 {
+		result.Best = &candidates[0]
+	}
+	return result, nil
+}
+
+// applyParams clones follower and overlays params onto its risk settings,
+// leaving the original untouched so the same base follower can be replayed
+// many times concurrently.
+func applyParams(follower *models.Follower, params map[string]float64) *models.Follower {
+	clone := *follower
+	clone.RiskSettings = make(map[string]interface{}, len(follower.RiskSettings))
+	for k, v := range follower.RiskSettings {
+		clone.RiskSettings[k] = v
+	}
+
+	for key, value := range params {
+		switch key {
+		case "copy_percentage":
+			clone.CopyPercentage = value
+		case "max_position_size":
+			clone.MaxPositionSize = value
+		case "stop_loss_percentage":
+			v := value
+			clone.StopLossPercentage = &v
+		case "take_profit_percentage":
+			v := value
+			clone.TakeProfitPercentage = &v
+		default:
+			clone.RiskSettings[key] = value
+		}
+	}
+
+	return &clone
+}
+
+// replay drives follower's simulated copy trades through a fresh
+// engine.RiskManager (isolated history/position state per candidate) and
+// returns the resulting performance report.
+func replay(follower *models.Follower, leaderTrades []models.Trade) (*engine.RiskReport, error) {
+	cfg := &config.Config{MaxPositionSize: follower.MaxPositionSize * 10}
+	riskManager := engine.NewRiskManager(cfg, engine.NewTradeHistoryStore(nil), engine.NewPositionTracker(), nil, nil)
+
+	var simulated []models.Trade
+	for _, leaderTrade := range leaderTrades {
+		trade := leaderTrade
+		assessment := riskManager.AssessRiskAt(follower, &trade, trade.ExecutedAt)
+		if !assessment.Approved || assessment.AdjustedSize <= 0 {
+			continue
+		}
+
+		fill := simulateFill(follower, trade, assessment.AdjustedSize)
+		riskManager.RecordTrade(follower, fill)
+		simulated = append(simulated, fill)
+	}
+
+	return riskManager.PerformanceReport(follower, simulated), nil
+}
+
+// simulateFill is the lightweight fill model: every approved trade fills
+// fully and instantly at the leader's executed price, with no slippage or
+// partial fills. Good enough to rank risk-setting candidates relative to
+// each other; internal/backtest's richer slippage model is for absolute
+// P&L estimates.
+func simulateFill(follower *models.Follower, leaderTrade models.Trade, adjustedSize float64) models.Trade {
+	return models.Trade{
+		LeaderAddress: leaderTrade.LeaderAddress,
+		FollowerID:    &follower.ID,
+		Asset:         leaderTrade.Asset,
+		Side:          leaderTrade.Side,
+		Size:          adjustedSize,
+		Price:         leaderTrade.Price,
+		OrderType:     "market",
+		IsLeaderTrade: false,
+		ExecutedAt:    leaderTrade.ExecutedAt,
+		Status:        "filled",
+	}
+}
+
+// objectiveScore extracts the statistic cfg.Objective ranks candidates by.
+// Unrecognized objectives fall back to profit factor.
+func objectiveScore(report *engine.RiskReport, objective Objective) float64 {
+	switch objective {
+	case ObjectiveSharpe:
+		return report.SharpeRatio
+	case ObjectiveSortino:
+		return report.SortinoRatio
+	case ObjectiveNegDrawdown:
+		return -report.MaxDrawdown
+	default:
+		return report.ProfitFactor
+	}
+}
+
+// sortCandidates orders candidates by descending score in place.
+func sortCandidates(candidates []Candidate) {
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+}
@@ -0,0 +1,81 @@
+package optimizer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"hyperliquid-copy-trading/internal/database"
+	"hyperliquid-copy-trading/internal/export"
+	"hyperliquid-copy-trading/internal/models"
+)
+
+// CandidateReport flattens one Candidate into CSV/JSON-friendly columns for
+// the ranked sweep report, reusing the internal/export CSV writer.
+type CandidateReport struct {
+	Rank         int     `json:"rank" csv:"rank"`
+	Score        float64 `json:"score" csv:"score"`
+	TotalTrades  int     `json:"total_trades" csv:"total_trades"`
+	WinRate      float64 `json:"win_rate" csv:"win_rate"`
+	ProfitFactor float64 `json:"profit_factor" csv:"profit_factor"`
+	SharpeRatio  float64 `json:"sharpe_ratio" csv:"sharpe_ratio"`
+	SortinoRatio float64 `json:"sortino_ratio" csv:"sortino_ratio"`
+	MaxDrawdown  float64 `json:"max_drawdown" csv:"max_drawdown"`
+	ParamsJSON   string  `json:"params" csv:"params"`
+}
+
+// RankedReport converts a Result into flat rows suitable for CSV/JSON
+// export, in the same best-first order Run returned them.
+func RankedReport(result *Result) ([]CandidateReport, error) {
+	rows := make([]CandidateReport, 0, len(result.Candidates))
+	for i, c := range result.Candidates {
+		paramsJSON, err := json.Marshal(c.Params)
+		if err != nil {
+			return nil, fmt.Errorf("optimizer: marshaling candidate params: %w", err)
+		}
+
+		rows = append(rows, CandidateReport{
+			Rank:         i + 1,
+			Score:        c.Score,
+			TotalTrades:  c.Report.TotalTrades,
+			WinRate:      c.Report.WinRate,
+			ProfitFactor: c.Report.ProfitFactor,
+			SharpeRatio:  c.Report.SharpeRatio,
+			SortinoRatio: c.Report.SortinoRatio,
+			MaxDrawdown:  c.Report.MaxDrawdown,
+			ParamsJSON:   string(paramsJSON),
+		})
+	}
+	return rows, nil
+}
+
+// WriteCSV writes the ranked report as CSV via internal/export.
+func WriteCSV(w io.Writer, result *Result) error {
+	rows, err := RankedReport(result)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return fmt.Errorf("optimizer: no candidates to report")
+	}
+	return export.ToCSV(w, rows)
+}
+
+// WriteJSON writes the ranked report as a JSON array.
+func WriteJSON(w io.Writer, result *Result) error {
+	rows, err := RankedReport(result)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(rows)
+}
+
+// ApplyBest overlays the winning candidate's parameters onto follower and
+// persists it, so an operator can promote a sweep's winner without manually
+// copying numbers out of the report.
+func ApplyBest(ctx context.Context, db *database.PostgresDB, follower *models.Follower, best Candidate) error {
+	updated := applyParams(follower, best.Params)
+	*follower = *updated
+	return db.UpdateFollower(ctx, follower)
+}
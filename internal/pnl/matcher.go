@@ -0,0 +1,215 @@
+// Package pnl computes realized and unrealized PnL from an ordered trade
+// stream via lot matching, replacing the side-based cashflow heuristic
+// (every sell is +size*price, every buy is -size*price) the SQL in
+// database.GetLeaderPerformance/GetFollowerPnL used to rely on, which gives
+// nonsensical numbers for anyone net-long or holding positions across the
+// window.
+package pnl
+
+import (
+	"fmt"
+	"math"
+
+	"hyperliquid-copy-trading/internal/models"
+)
+
+// Method selects how Matcher pairs a closing fill against the open lots it
+// is reducing.
+type Method string
+
+const (
+	FIFO            Method = "fifo"
+	LIFO            Method = "lifo"
+	WeightedAverage Method = "weighted_average"
+)
+
+// lot is one still-open slice of a position. Qty is signed: positive for a
+// long lot opened by a buy, negative for a short lot opened by a sell.
+type lot struct {
+	tradeID int
+	qty     float64
+	price   float64
+	fee     float64 // fee attributable to the remaining qty
+}
+
+// owner identifies whose position a trade belongs to: either the leader's
+// own book (FollowerID nil) or one follower's copied book, mirroring how
+// the trades table itself distinguishes leader vs. follower rows.
+type owner struct {
+	leaderAddress string
+	followerID    int
+	isFollower    bool
+	coin          string
+}
+
+func ownerOf(t models.Trade) owner {
+	o := owner{leaderAddress: t.LeaderAddress, coin: t.Asset}
+	if t.FollowerID != nil {
+		o.isFollower = true
+		o.followerID = *t.FollowerID
+	}
+	return o
+}
+
+// Matcher runs lot matching over an ordered trade stream, independently per
+// (owner, coin) group. Trades must be fed to Process in execution order
+// within each group; Matcher does no sorting of its own.
+type Matcher struct {
+	method Method
+	open   map[owner][]lot
+}
+
+// NewMatcher returns a Matcher using method, defaulting to FIFO for an
+// unrecognized value so a bad config.LotMatchingMethod degrades safely
+// instead of panicking.
+func NewMatcher(method Method) *Matcher {
+	switch method {
+	case LIFO, WeightedAverage:
+	default:
+		method = FIFO
+	}
+	return &Matcher{method: method, open: make(map[owner][]lot)}
+}
+
+// Process folds trade into its (owner, coin) group's open lots, returning
+// one RealizedPnL row per closing match trade produced against earlier
+// opening lots. It returns nil if trade only opened or extended a position.
+func (m *Matcher) Process(trade models.Trade) ([]models.RealizedPnL, error) {
+	if trade.Size <= 0 {
+		return nil, fmt.Errorf("pnl: trade %d has non-positive size %g", trade.ID, trade.Size)
+	}
+
+	sign := 1.0
+	if trade.Side == "sell" {
+		sign = -1.0
+	} else if trade.Side != "buy" {
+		return nil, fmt.Errorf("pnl: trade %d has unknown side %q", trade.ID, trade.Side)
+	}
+
+	remaining := trade.Size * sign
+	feePerUnit := trade.Fee / trade.Size
+	o := ownerOf(trade)
+	lots := m.open[o]
+
+	var realized []models.RealizedPnL
+	for len(lots) > 0 && remaining != 0 && !sameSign(lots[0].qty, remaining) {
+		idx := 0
+		if m.method == LIFO {
+			idx = len(lots) - 1
+		}
+		l := &lots[idx]
+
+		matched := math.Min(math.Abs(remaining), math.Abs(l.qty))
+		entryPrice, exitPrice := l.price, trade.Price
+
+		var pnlPerUnit float64
+		if l.qty > 0 {
+			pnlPerUnit = exitPrice - entryPrice // closing a long: sell above entry
+		} else {
+			pnlPerUnit = entryPrice - exitPrice // closing a short: buy back below entry
+		}
+
+		lotFeeShare := l.fee * (matched / math.Abs(l.qty))
+		tradeFeeShare := feePerUnit * matched
+		fees := lotFeeShare + tradeFeeShare
+
+		realized = append(realized, models.RealizedPnL{
+			TradeID:       trade.ID,
+			LeaderAddress: o.leaderAddress,
+			Coin:          o.coin,
+			MatchedQty:    matched,
+			EntryPrice:    entryPrice,
+			ExitPrice:     exitPrice,
+			RealizedPnL:   matched*pnlPerUnit - fees,
+			Fees:          fees,
+		})
+		if o.isFollower {
+			followerID := o.followerID
+			realized[len(realized)-1].FollowerID = &followerID
+		}
+
+		l.fee -= lotFeeShare
+		if l.qty > 0 {
+			l.qty -= matched
+			remaining += matched // remaining is negative here; matching moves it toward zero
+		} else {
+			l.qty += matched
+			remaining -= matched // remaining is positive here; matching moves it toward zero
+		}
+
+		if l.qty == 0 {
+			if m.method == LIFO {
+				lots = lots[:idx]
+			} else {
+				lots = lots[1:]
+			}
+		}
+	}
+
+	if remaining != 0 {
+		newLot := lot{tradeID: trade.ID, qty: remaining, price: trade.Price, fee: feePerUnit * math.Abs(remaining)}
+		if m.method == WeightedAverage && len(lots) == 1 && sameSign(lots[0].qty, remaining) {
+			existing := lots[0]
+			totalQty := math.Abs(existing.qty) + math.Abs(remaining)
+			blendedPrice := (existing.price*math.Abs(existing.qty) + trade.Price*math.Abs(remaining)) / totalQty
+			existing.qty += remaining
+			existing.price = blendedPrice
+			existing.fee += newLot.fee
+			existing.tradeID = trade.ID
+			lots[0] = existing
+		} else {
+			lots = append(lots, newLot)
+		}
+	}
+
+	if len(lots) == 0 {
+		delete(m.open, o)
+	} else {
+		m.open[o] = lots
+	}
+
+	return realized, nil
+}
+
+func sameSign(a, b float64) bool {
+	return (a > 0 && b > 0) || (a < 0 && b < 0)
+}
+
+// MarkToMarket returns one UnrealizedPnL row per (owner, coin) group with an
+// open position and a known mark price in markPrices (coin -> price string,
+// the shape HyperliquidAPI.GetAllMids/allMids already use). Groups whose
+// coin has no entry in markPrices are skipped.
+func (m *Matcher) MarkToMarket(markPrices map[string]float64) []models.UnrealizedPnL {
+	var out []models.UnrealizedPnL
+	for o, lots := range m.open {
+		mark, ok := markPrices[o.coin]
+		if !ok {
+			continue
+		}
+
+		var qty, costBasis float64
+		for _, l := range lots {
+			qty += l.qty
+			costBasis += l.qty * l.price
+		}
+		if qty == 0 {
+			continue
+		}
+		avgEntry := costBasis / qty
+
+		row := models.UnrealizedPnL{
+			LeaderAddress: o.leaderAddress,
+			Coin:          o.coin,
+			Qty:           qty,
+			EntryPrice:    avgEntry,
+			MarkPrice:     mark,
+			UnrealizedPnL: qty * (mark - avgEntry),
+		}
+		if o.isFollower {
+			followerID := o.followerID
+			row.FollowerID = &followerID
+		}
+		out = append(out, row)
+	}
+	return out
+}
@@ -257,29 +257,10 @@ func CalculateVolatility(returns []float64) float64 {
 	return math.Sqrt(variance) * 100 // Convert to percentage
 }
 
-// GetAssetIDFromName converts asset name to ID for Hyperliquid
-func GetAssetIDFromName(assetName string) (int, error) {
-	assetMap := map[string]int{
-		"BTC":  0,
-		"ETH":  1,
-		"SOL":  2,
-		"AVAX": 3,
-		"DOGE": 4,
-		"ATOM": 5,
-		"NEAR": 6,
-		"FTM":  7,
-		"GMX":  8,
-		"ARB":  9,
-	}
-
-	if id, exists := assetMap[strings.ToUpper(assetName)]; exists {
-		return id, nil
-	}
-
-	return 0, fmt.Errorf("unknown asset: %s", assetName)
-}
-
-// ValidateOrderSize checks if order size meets minimum requirements
+// ValidateOrderSize checks if order size meets minimum requirements. Callers
+// with access to live exchange metadata (szDecimals, minimum notional)
+// should prefer api.AssetRegistry.ValidateOrderSize, which derives minSize
+// from the asset's actual minimum notional instead of a caller-supplied one.
 func ValidateOrderSize(size float64, minSize float64) bool {
 	return size >= minSize
 }
@@ -289,16 +270,3 @@ func CalculateMarginRequired(price, size, leverage float64) float64 {
 	notionalValue := price * size
 	return notionalValue / leverage
 }
-
-// FormatTradeSize formats trade size with appropriate precision
-func FormatTradeSize(size float64, asset string) string {
-	// Different assets have different precision requirements
-	switch strings.ToUpper(asset) {
-	case "BTC":
-		return strconv.FormatFloat(size, 'f', 6, 64)
-	case "ETH":
-		return strconv.FormatFloat(size, 'f', 5, 64)
-	default:
-		return strconv.FormatFloat(size, 'f', 4, 64)
-	}
-}
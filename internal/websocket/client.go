@@ -1,9 +1,13 @@
 package websocket
 
 import (
+	"context"
 	"hyperliquid-copy-trading/config"
+	"hyperliquid-copy-trading/internal/exchange/meta"
 	"hyperliquid-copy-trading/internal/models"
+	"net"
 	"net/url"
+	"strconv"
 	"sync"
 	"time"
 
@@ -14,22 +18,59 @@ import (
 type Client struct {
 	config        *config.Config
 	leaderAddress string
+	instruments   *meta.Registry
 	conn          *websocket.Conn
 	connected     bool
 	connMutex     sync.RWMutex
 	shutdown      chan struct{}
 	wg            sync.WaitGroup
+	readDeadline  *deadlineTimer
+	writeDeadline *deadlineTimer
 }
 
-func NewClient(cfg *config.Config, leaderAddress string) *Client {
+// NewClient builds a leader trade/user-event client. instruments may be nil
+// (e.g. in offline/test construction), in which case parseTradeEvent skips
+// tick-size rounding and passes prices/sizes through as received.
+func NewClient(cfg *config.Config, leaderAddress string, instruments *meta.Registry) *Client {
 	return &Client{
 		config:        cfg,
 		leaderAddress: leaderAddress,
+		instruments:   instruments,
 		shutdown:      make(chan struct{}),
+		readDeadline:  newDeadlineTimer(),
+		writeDeadline: newDeadlineTimer(),
 	}
 }
 
-func (c *Client) Start(tradeChannel chan models.TradeEvent, userChannel chan models.UserEvent) error {
+// SetReadDeadline arms both the underlying connection's read deadline and
+// this client's deadlineTimer, so a goroutine blocked in readMessages can
+// either observe the ReadJSON timeout directly or select on readDeadline.
+// Done() if it's waiting elsewhere.
+func (c *Client) SetReadDeadline(t time.Time) {
+	c.connMutex.RLock()
+	conn := c.conn
+	c.connMutex.RUnlock()
+
+	if conn != nil {
+		conn.SetReadDeadline(t)
+	}
+	c.readDeadline.SetDeadline(t)
+}
+
+// SetWriteDeadline arms both the underlying connection's write deadline and
+// this client's deadlineTimer, mirroring SetReadDeadline for writers.
+func (c *Client) SetWriteDeadline(t time.Time) {
+	c.connMutex.RLock()
+	conn := c.conn
+	c.connMutex.RUnlock()
+
+	if conn != nil {
+		conn.SetWriteDeadline(t)
+	}
+	c.writeDeadline.SetDeadline(t)
+}
+
+func (c *Client) Start(ctx context.Context, tradeChannel chan models.TradeEvent, userChannel chan models.UserEvent) error {
 	// Determine WebSocket URL based on environment
 	wsURL := c.config.HyperliquidWSURL
 	if c.config.Environment == "testnet" {
@@ -42,7 +83,7 @@ func (c *Client) Start(tradeChannel chan models.TradeEvent, userChannel chan mod
 		return err
 	}
 
-	conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, u.String(), nil)
 	if err != nil {
 		return err
 	}
@@ -85,6 +126,12 @@ func (c *Client) Stop() {
 	}
 	c.connMutex.Unlock()
 
+	// Arm an already-expired deadline so a goroutine parked in ReadJSON or
+	// WriteJSON unblocks deterministically on the next I/O attempt rather
+	// than waiting out its full 60s timeout, matching conn.Close() above.
+	c.SetReadDeadline(time.Now())
+	c.SetWriteDeadline(time.Now())
+
 	c.wg.Wait()
 }
 
@@ -127,6 +174,7 @@ func (c *Client) sendMessage(msg interface{}) error {
 		return websocket.ErrCloseSent
 	}
 
+	c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
 	return c.conn.WriteJSON(msg)
 }
 
@@ -147,11 +195,24 @@ func (c *Client) readMessages(tradeChannel chan models.TradeEvent, userChannel c
 				return
 			}
 
-			// Set read deadline
-			conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+			// Arm the read deadline so this call can't block past shutdown
+			// or a reconnect-triggered SetReadDeadline indefinitely.
+			c.SetReadDeadline(time.Now().Add(60 * time.Second))
 
 			var message map[string]interface{}
 			if err := conn.ReadJSON(&message); err != nil {
+				select {
+				case <-c.shutdown:
+					return
+				default:
+				}
+
+				if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+					// Deadline expired with no message; loop around to
+					// recheck shutdown and re-arm the deadline.
+					continue
+				}
+
 				if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 					log.Error().Err(err).Str("leader", c.leaderAddress).Msg("WebSocket read error")
 				}
@@ -266,6 +327,28 @@ func (c *Client) processUserEvents(data map[string]interface{}, userChannel chan
 	}
 }
 
+// roundToInstrumentTick rounds event's price and size down to its coin's
+// cached tick/lot size, so a copy order derived from this trade is never
+// rejected for excess precision. A no-op if no instrument cache is wired or
+// the coin isn't cached yet.
+func (c *Client) roundToInstrumentTick(event *models.TradeEvent) {
+	if c.instruments == nil {
+		return
+	}
+
+	inst, ok := c.instruments.Get(event.Coin)
+	if !ok {
+		return
+	}
+
+	if px, err := strconv.ParseFloat(event.Px, 64); err == nil {
+		event.Px = strconv.FormatFloat(inst.RoundPrice(px), 'f', -1, 64)
+	}
+	if sz, err := strconv.ParseFloat(event.Sz, 64); err == nil {
+		event.Sz = strconv.FormatFloat(inst.RoundSize(sz), 'f', -1, 64)
+	}
+}
+
 func (c *Client) parseTradeEvent(trade map[string]interface{}) *models.TradeEvent {
 	event := &models.TradeEvent{}
 
@@ -309,6 +392,8 @@ func (c *Client) parseTradeEvent(trade map[string]interface{}) *models.TradeEven
 		event.User = user
 	}
 
+	c.roundToInstrumentTick(event)
+
 	return event
 }
 
@@ -332,6 +417,7 @@ func (c *Client) pingLoop() {
 				return
 			}
 
+			conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
 			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
 				log.Error().Err(err).Str("leader", c.leaderAddress).Msg("Failed to send ping")
 				return
@@ -0,0 +1,54 @@
+package websocket
+
+import (
+	"sync"
+	"time"
+)
+
+// deadlineTimer implements the net.Conn-style deadline pattern: a SetDeadline
+// call arms (or disarms) a timer that closes a "done" channel when it fires,
+// so a goroutine blocked on a channel select can unblock deterministically
+// instead of relying on the peer closing the connection. Each SetDeadline
+// call stops any previously-armed timer and swaps in a fresh channel, so a
+// caller that re-reads Done() after rearming the deadline never observes a
+// channel left closed by an earlier, already-expired deadline.
+type deadlineTimer struct {
+	mu     sync.Mutex
+	timer  *time.Timer
+	cancel chan struct{}
+}
+
+func newDeadlineTimer() *deadlineTimer {
+	return &deadlineTimer{cancel: make(chan struct{})}
+}
+
+// SetDeadline arms the timer to close Done() at t. A zero t disarms it, in
+// which case Done() only closes once a later SetDeadline expires.
+func (d *deadlineTimer) SetDeadline(t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	d.cancel = make(chan struct{})
+
+	if t.IsZero() {
+		d.timer = nil
+		return
+	}
+
+	cancel := d.cancel
+	d.timer = time.AfterFunc(time.Until(t), func() {
+		close(cancel)
+	})
+}
+
+// Done returns the channel for the deadline currently in effect. It closes
+// when that deadline expires and is replaced, not reused, by the next
+// SetDeadline call.
+func (d *deadlineTimer) Done() <-chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.cancel
+}
@@ -0,0 +1,329 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+)
+
+// outboundBufferSize bounds each outbound connection's pending-message
+// queue; a client that can't keep up gets messages dropped rather than
+// stalling the publisher (mirrors the tradeChannel/userChannel
+// drop-on-slow-consumer semantics in client.go).
+const outboundBufferSize = 32
+
+const (
+	writeWait    = 10 * time.Second
+	pongWait     = 60 * time.Second
+	pingInterval = (pongWait * 9) / 10
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     func(r *http.Request) bool { return true },
+}
+
+// RPCRequest is an inbound JSON-RPC style request from an outbound client,
+// e.g. {"id": 1, "method": "subscribeFollower", "params": {"follower_id": 7}}.
+type RPCRequest struct {
+	ID     interface{}     `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// RPCResponse answers one RPCRequest by echoing its ID.
+type RPCResponse struct {
+	ID    interface{} `json:"id"`
+	Data  interface{} `json:"data,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
+
+// RPCNotification is an unsolicited push delivered to a subscription once
+// registered, independent of any request ID.
+type RPCNotification struct {
+	Type           string      `json:"type"`
+	Event          string      `json:"event"`
+	SubscriptionID string      `json:"subscription_id"`
+	Data           interface{} `json:"data"`
+}
+
+// SubscriptionFilter narrows which published Events reach a subscription.
+// A zero field means "don't filter on this dimension".
+type SubscriptionFilter struct {
+	FollowerID    int
+	LeaderAddress string
+	Coin          string
+}
+
+func (f SubscriptionFilter) matches(e Event) bool {
+	if f.FollowerID != 0 && f.FollowerID != e.FollowerID {
+		return false
+	}
+	if f.LeaderAddress != "" && f.LeaderAddress != e.LeaderAddress {
+		return false
+	}
+	if f.Coin != "" && f.Coin != e.Coin {
+		return false
+	}
+	return true
+}
+
+// Event is one fact a Hub publisher (CopyEngine) fans out to matching
+// subscriptions: a trade fill, a PnL update, a leader trade, or a health
+// change.
+type Event struct {
+	Kind          string
+	FollowerID    int
+	LeaderAddress string
+	Coin          string
+	Data          interface{}
+}
+
+type subscription struct {
+	id     string
+	kind   string
+	filter SubscriptionFilter
+}
+
+// Connection is one outbound WebSocket client, e.g. a follower dashboard.
+// out is bounded and drop-on-slow-consumer so one stuck client can't stall
+// event fan-out for everyone else.
+type Connection struct {
+	id   string
+	conn *websocket.Conn
+
+	out       chan []byte
+	shutdown  chan struct{}
+	closeOnce sync.Once
+
+	aliveMu sync.Mutex
+	alive   bool
+
+	subsMu    sync.RWMutex
+	subs      map[string]subscription
+	nextSubID int
+}
+
+// IsAlive reports whether the connection's read/write pumps are still
+// running, guarded by aliveMu so Hub.Publish can check it from another
+// goroutine without racing close().
+func (c *Connection) IsAlive() bool {
+	c.aliveMu.Lock()
+	defer c.aliveMu.Unlock()
+	return c.alive
+}
+
+func (c *Connection) close() {
+	c.closeOnce.Do(func() {
+		c.aliveMu.Lock()
+		c.alive = false
+		c.aliveMu.Unlock()
+		close(c.shutdown)
+		c.conn.Close()
+	})
+}
+
+// Subscribe registers filter under kind (e.g. "trade_fill", "leader_trade",
+// "pnl_update", "health") and returns a subscription id a later unsubscribe
+// call can reference.
+func (c *Connection) Subscribe(kind string, filter SubscriptionFilter) string {
+	c.subsMu.Lock()
+	defer c.subsMu.Unlock()
+
+	c.nextSubID++
+	id := fmt.Sprintf("%s-%d", kind, c.nextSubID)
+	c.subs[id] = subscription{id: id, kind: kind, filter: filter}
+	return id
+}
+
+// Unsubscribe removes a previously returned subscription id, reporting
+// whether it existed.
+func (c *Connection) Unsubscribe(id string) bool {
+	c.subsMu.Lock()
+	defer c.subsMu.Unlock()
+
+	if _, ok := c.subs[id]; !ok {
+		return false
+	}
+	delete(c.subs, id)
+	return true
+}
+
+func (c *Connection) deliver(event Event) {
+	c.subsMu.RLock()
+	var matched []subscription
+	for _, s := range c.subs {
+		if s.kind == event.Kind && s.filter.matches(event) {
+			matched = append(matched, s)
+		}
+	}
+	c.subsMu.RUnlock()
+
+	for _, s := range matched {
+		c.send(RPCNotification{
+			Type:           "notification",
+			Event:          event.Kind,
+			SubscriptionID: s.id,
+			Data:           event.Data,
+		})
+	}
+}
+
+func (c *Connection) send(msg interface{}) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to marshal outbound WebSocket message")
+		return
+	}
+
+	select {
+	case c.out <- data:
+	default:
+		log.Warn().Str("connection", c.id).Msg("Outbound WebSocket buffer full, dropping message")
+	}
+}
+
+func (c *Connection) readPump(dispatch func(*Connection, RPCRequest) RPCResponse) {
+	defer c.close()
+
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		c.conn.SetReadDeadline(time.Now().Add(pongWait))
+		return nil
+	})
+
+	for {
+		var req RPCRequest
+		if err := c.conn.ReadJSON(&req); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+				log.Error().Err(err).Str("connection", c.id).Msg("Outbound WebSocket read error")
+			}
+			return
+		}
+
+		c.send(dispatch(c, req))
+	}
+}
+
+func (c *Connection) writePump() {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+	defer c.close()
+
+	for {
+		select {
+		case <-c.shutdown:
+			return
+		case data, ok := <-c.out:
+			if !ok {
+				return
+			}
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Hub is the registry of outbound WebSocket connections a publisher (e.g.
+// CopyEngine) fans trade fills, PnL updates, leader trades, and health
+// changes out to, so dashboards get pushed updates instead of polling the
+// REST API.
+type Hub struct {
+	mu          sync.RWMutex
+	connections map[*Connection]struct{}
+	nextConnID  int
+}
+
+// NewHub returns an empty connection registry.
+func NewHub() *Hub {
+	return &Hub{connections: make(map[*Connection]struct{})}
+}
+
+// Connections reports the number of currently registered outbound
+// connections, for health/status endpoints.
+func (h *Hub) Connections() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.connections)
+}
+
+// Publish fans event out to every subscription, across every registered
+// connection, whose kind and filter match. A connection with a full out
+// buffer drops the notification rather than stalling the publisher.
+func (h *Hub) Publish(event Event) {
+	h.mu.RLock()
+	conns := make([]*Connection, 0, len(h.connections))
+	for c := range h.connections {
+		conns = append(conns, c)
+	}
+	h.mu.RUnlock()
+
+	for _, c := range conns {
+		c.deliver(event)
+	}
+}
+
+// Serve upgrades r into a WebSocket connection, registers it with the hub,
+// and runs its read/write pumps until the client disconnects or the
+// connection is closed. dispatch handles one decoded RPCRequest and
+// returns the RPCResponse to send back; it's supplied by the caller since
+// validating subscribeFollower/subscribeLeaderTrades/subscribePnL params
+// against live engine state is the caller's concern, not the transport's.
+func (h *Hub) Serve(w http.ResponseWriter, r *http.Request, dispatch func(*Connection, RPCRequest) RPCResponse) error {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	h.nextConnID++
+	id := fmt.Sprintf("ws-%d", h.nextConnID)
+	h.mu.Unlock()
+
+	c := &Connection{
+		id:       id,
+		conn:     conn,
+		out:      make(chan []byte, outboundBufferSize),
+		shutdown: make(chan struct{}),
+		alive:    true,
+		subs:     make(map[string]subscription),
+	}
+
+	h.mu.Lock()
+	h.connections[c] = struct{}{}
+	h.mu.Unlock()
+
+	log.Info().Str("connection", c.id).Str("remote_addr", r.RemoteAddr).Msg("Outbound WebSocket connection opened")
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		c.writePump()
+	}()
+	go func() {
+		defer wg.Done()
+		c.readPump(dispatch)
+	}()
+	wg.Wait()
+
+	h.mu.Lock()
+	delete(h.connections, c)
+	h.mu.Unlock()
+
+	log.Info().Str("connection", c.id).Msg("Outbound WebSocket connection closed")
+	return nil
+}
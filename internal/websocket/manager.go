@@ -3,6 +3,7 @@ package websocket
 import (
 	"context"
 	"hyperliquid-copy-trading/config"
+	"hyperliquid-copy-trading/internal/exchange/meta"
 	"hyperliquid-copy-trading/internal/models"
 	"sync"
 	"time"
@@ -11,94 +12,189 @@ import (
 )
 
 type Manager struct {
-	config        *config.Config
-	clients       map[string]*Client
-	clientsMutex  sync.RWMutex
-	tradeChannels map[string]chan models.TradeEvent
-	userChannels  map[string]chan models.UserEvent
-	shutdown      chan struct{}
-	wg            sync.WaitGroup
+	config         *config.Config
+	instruments    *meta.Registry
+	clients        map[string]*Client
+	clientsMutex   sync.RWMutex
+	tradeChannels  map[string]chan models.TradeEvent
+	userChannels   map[string]chan models.UserEvent
+	marginClients  map[string]*MarginClient
+	marginChannels map[string]chan models.LiveMarginState
+	marginMutex    sync.RWMutex
+	shutdown       chan struct{}
+	wg             sync.WaitGroup
+}
+
+// SetInstruments wires an instrument tick/lot-size cache into the manager,
+// so leader Clients created afterward round parsed trade prices/sizes to a
+// valid tick. Expected to be called once, before any leader is subscribed
+// to.
+func (m *Manager) SetInstruments(instruments *meta.Registry) {
+	m.instruments = instruments
 }
 
 func NewManager(cfg *config.Config) *Manager {
 	return &Manager{
-		config:        cfg,
-		clients:       make(map[string]*Client),
-		tradeChannels: make(map[string]chan models.TradeEvent),
-		userChannels:  make(map[string]chan models.UserEvent),
-		shutdown:      make(chan struct{}),
+		config:         cfg,
+		clients:        make(map[string]*Client),
+		tradeChannels:  make(map[string]chan models.TradeEvent),
+		userChannels:   make(map[string]chan models.UserEvent),
+		marginClients:  make(map[string]*MarginClient),
+		marginChannels: make(map[string]chan models.LiveMarginState),
+		shutdown:       make(chan struct{}),
 	}
 }
 
-func (m *Manager) SubscribeToLeader(leaderAddress string) (chan models.TradeEvent, chan models.UserEvent, error) {
+// SubscribeMargin opens a dedicated "margin" subscription for address
+// (typically a follower's API wallet) and returns a channel that receives a
+// LiveMarginState every time the account's margin/liquidation picture moves.
+func (m *Manager) SubscribeMargin(address string) (chan models.LiveMarginState, error) {
+	m.marginMutex.Lock()
+	defer m.marginMutex.Unlock()
+
+	if _, exists := m.marginClients[address]; exists {
+		return m.marginChannels[address], nil
+	}
+
+	client := NewMarginClient(m.config, address)
+	marginChannel := make(chan models.LiveMarginState, 100)
+
+	m.marginClients[address] = client
+	m.marginChannels[address] = marginChannel
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		if err := client.Start(marginChannel); err != nil {
+			log.Error().Err(err).Str("address", address).Msg("Margin WebSocket client failed")
+		}
+	}()
+
+	log.Info().Str("address", address).Msg("Subscribed to margin stream")
+	return marginChannel, nil
+}
+
+// UnsubscribeMargin tears down the margin stream for address.
+func (m *Manager) UnsubscribeMargin(address string) {
+	m.marginMutex.Lock()
+	defer m.marginMutex.Unlock()
+
+	if client, exists := m.marginClients[address]; exists {
+		client.Stop()
+		delete(m.marginClients, address)
+
+		if marginChannel, exists := m.marginChannels[address]; exists {
+			close(marginChannel)
+			delete(m.marginChannels, address)
+		}
+
+		log.Info().Str("address", address).Msg("Unsubscribed from margin stream")
+	}
+}
+
+// GetMarginStream returns the existing margin channel for address, if any.
+func (m *Manager) GetMarginStream(address string) (chan models.LiveMarginState, bool) {
+	m.marginMutex.RLock()
+	defer m.marginMutex.RUnlock()
+
+	channel, exists := m.marginChannels[address]
+	return channel, exists
+}
+
+// venueKey composites venue and address into the map key every client/channel
+// table below is keyed by, so the same address on two different venues gets
+// independent connections.
+func venueKey(venue, address string) string {
+	if venue == "" {
+		venue = models.DefaultVenue
+	}
+	return venue + "|" + address
+}
+
+// SubscribeToLeader opens (or returns the existing) trade/user event streams
+// for address on venue. Only venue == models.DefaultVenue (Hyperliquid) is
+// actually wired to a live Client today; the venue-keyed maps are the
+// dispatch point a future venue's Client implementation plugs into.
+func (m *Manager) SubscribeToLeader(ctx context.Context, venue, leaderAddress string) (chan models.TradeEvent, chan models.UserEvent, error) {
 	m.clientsMutex.Lock()
 	defer m.clientsMutex.Unlock()
 
+	key := venueKey(venue, leaderAddress)
+
 	// Check if we already have a client for this leader
-	if _, exists := m.clients[leaderAddress]; exists {
-		return m.tradeChannels[leaderAddress], m.userChannels[leaderAddress], nil
+	if _, exists := m.clients[key]; exists {
+		return m.tradeChannels[key], m.userChannels[key], nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
 	}
 
 	// Create new client
-	client := NewClient(m.config, leaderAddress)
-	
+	client := NewClient(m.config, leaderAddress, m.instruments)
+
 	// Create channels for this leader
 	tradeChannel := make(chan models.TradeEvent, 1000)
 	userChannel := make(chan models.UserEvent, 1000)
-	
-	m.clients[leaderAddress] = client
-	m.tradeChannels[leaderAddress] = tradeChannel
-	m.userChannels[leaderAddress] = userChannel
 
-	// Start the client
+	m.clients[key] = client
+	m.tradeChannels[key] = tradeChannel
+	m.userChannels[key] = userChannel
+
+	// The dial itself observes ctx so a cancelled caller (e.g. the HTTP
+	// request that triggered this subscription disconnecting) aborts
+	// in-flight connection setup; once connected, the client's own
+	// lifecycle is governed by Stop()/shutdown, not ctx.
 	m.wg.Add(1)
 	go func() {
 		defer m.wg.Done()
-		if err := client.Start(tradeChannel, userChannel); err != nil {
-			log.Error().Err(err).Str("leader", leaderAddress).Msg("WebSocket client failed")
+		if err := client.Start(ctx, tradeChannel, userChannel); err != nil {
+			log.Error().Err(err).Str("leader", leaderAddress).Str("venue", venue).Msg("WebSocket client failed")
 		}
 	}()
 
-	log.Info().Str("leader", leaderAddress).Msg("Subscribed to leader")
+	log.Info().Str("leader", leaderAddress).Str("venue", venue).Msg("Subscribed to leader")
 	return tradeChannel, userChannel, nil
 }
 
-func (m *Manager) UnsubscribeFromLeader(leaderAddress string) {
+func (m *Manager) UnsubscribeFromLeader(venue, leaderAddress string) {
 	m.clientsMutex.Lock()
 	defer m.clientsMutex.Unlock()
 
-	if client, exists := m.clients[leaderAddress]; exists {
+	key := venueKey(venue, leaderAddress)
+
+	if client, exists := m.clients[key]; exists {
 		client.Stop()
-		delete(m.clients, leaderAddress)
-		
+		delete(m.clients, key)
+
 		// Close channels
-		if tradeChannel, exists := m.tradeChannels[leaderAddress]; exists {
+		if tradeChannel, exists := m.tradeChannels[key]; exists {
 			close(tradeChannel)
-			delete(m.tradeChannels, leaderAddress)
+			delete(m.tradeChannels, key)
 		}
-		
-		if userChannel, exists := m.userChannels[leaderAddress]; exists {
+
+		if userChannel, exists := m.userChannels[key]; exists {
 			close(userChannel)
-			delete(m.userChannels, leaderAddress)
+			delete(m.userChannels, key)
 		}
 
-		log.Info().Str("leader", leaderAddress).Msg("Unsubscribed from leader")
+		log.Info().Str("leader", leaderAddress).Str("venue", venue).Msg("Unsubscribed from leader")
 	}
 }
 
-func (m *Manager) GetTradeStream(leaderAddress string) (chan models.TradeEvent, bool) {
+func (m *Manager) GetTradeStream(venue, leaderAddress string) (chan models.TradeEvent, bool) {
 	m.clientsMutex.RLock()
 	defer m.clientsMutex.RUnlock()
 
-	channel, exists := m.tradeChannels[leaderAddress]
+	channel, exists := m.tradeChannels[venueKey(venue, leaderAddress)]
 	return channel, exists
 }
 
-func (m *Manager) GetUserStream(leaderAddress string) (chan models.UserEvent, bool) {
+func (m *Manager) GetUserStream(venue, leaderAddress string) (chan models.UserEvent, bool) {
 	m.clientsMutex.RLock()
 	defer m.clientsMutex.RUnlock()
 
-	channel, exists := m.userChannels[leaderAddress]
+	channel, exists := m.userChannels[venueKey(venue, leaderAddress)]
 	return channel, exists
 }
 
@@ -118,6 +214,13 @@ func (m *Manager) Close() {
 	}
 	m.clientsMutex.Unlock()
 
+	m.marginMutex.Lock()
+	for address, client := range m.marginClients {
+		client.Stop()
+		log.Info().Str("address", address).Msg("Stopping margin WebSocket client")
+	}
+	m.marginMutex.Unlock()
+
 	// Wait for all goroutines to finish
 	done := make(chan struct{})
 	go func() {
@@ -146,30 +249,32 @@ func (m *Manager) HealthCheck() map[string]bool {
 }
 
 // RestartClient restarts a specific client connection
-func (m *Manager) RestartClient(leaderAddress string) error {
+func (m *Manager) RestartClient(venue, leaderAddress string) error {
 	m.clientsMutex.Lock()
 	defer m.clientsMutex.Unlock()
 
-	if client, exists := m.clients[leaderAddress]; exists {
+	key := venueKey(venue, leaderAddress)
+
+	if client, exists := m.clients[key]; exists {
 		client.Stop()
-		
+
 		// Wait a bit before reconnecting
 		time.Sleep(2 * time.Second)
-		
+
 		// Get existing channels
-		tradeChannel := m.tradeChannels[leaderAddress]
-		userChannel := m.userChannels[leaderAddress]
-		
+		tradeChannel := m.tradeChannels[key]
+		userChannel := m.userChannels[key]
+
 		// Restart the client
 		m.wg.Add(1)
 		go func() {
 			defer m.wg.Done()
-			if err := client.Start(tradeChannel, userChannel); err != nil {
-				log.Error().Err(err).Str("leader", leaderAddress).Msg("Failed to restart WebSocket client")
+			if err := client.Start(context.Background(), tradeChannel, userChannel); err != nil {
+				log.Error().Err(err).Str("leader", leaderAddress).Str("venue", venue).Msg("Failed to restart WebSocket client")
 			}
 		}()
 
-		log.Info().Str("leader", leaderAddress).Msg("Restarted WebSocket client")
+		log.Info().Str("leader", leaderAddress).Str("venue", venue).Msg("Restarted WebSocket client")
 	}
 
 	return nil
@@ -192,13 +297,27 @@ func (m *Manager) MonitorConnections(ctx context.Context) {
 
 func (m *Manager) checkAndReconnect() {
 	health := m.HealthCheck()
-	
-	for leaderAddress, isHealthy := range health {
-		if !isHealthy {
-			log.Warn().Str("leader", leaderAddress).Msg("Unhealthy connection detected, restarting")
-			if err := m.RestartClient(leaderAddress); err != nil {
-				log.Error().Err(err).Str("leader", leaderAddress).Msg("Failed to restart unhealthy connection")
-			}
+
+	for key, isHealthy := range health {
+		if isHealthy {
+			continue
+		}
+
+		venue, leaderAddress := splitVenueKey(key)
+		log.Warn().Str("leader", leaderAddress).Str("venue", venue).Msg("Unhealthy connection detected, restarting")
+		if err := m.RestartClient(venue, leaderAddress); err != nil {
+			log.Error().Err(err).Str("leader", leaderAddress).Str("venue", venue).Msg("Failed to restart unhealthy connection")
+		}
+	}
+}
+
+// splitVenueKey reverses venueKey for the rare caller (checkAndReconnect)
+// that only has the composite map key and needs the venue/address pair back.
+func splitVenueKey(key string) (venue, address string) {
+	for i := 0; i < len(key); i++ {
+		if key[i] == '|' {
+			return key[:i], key[i+1:]
 		}
 	}
+	return models.DefaultVenue, key
 }
@@ -0,0 +1,200 @@
+package websocket
+
+import (
+	"hyperliquid-copy-trading/config"
+	"hyperliquid-copy-trading/internal/models"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+)
+
+// MarginClient maintains a dedicated connection subscribed to a single
+// account's "margin" topic, pushing a LiveMarginState every time the
+// account's UserState/Position deltas change its margin or liquidation
+// picture.
+type MarginClient struct {
+	config    *config.Config
+	address   string
+	conn      *websocket.Conn
+	connected bool
+	connMutex sync.RWMutex
+	shutdown  chan struct{}
+	wg        sync.WaitGroup
+}
+
+func NewMarginClient(cfg *config.Config, address string) *MarginClient {
+	return &MarginClient{
+		config:   cfg,
+		address:  address,
+		shutdown: make(chan struct{}),
+	}
+}
+
+func (mc *MarginClient) Start(marginChannel chan models.LiveMarginState) error {
+	wsURL := mc.config.HyperliquidWSURL
+	if mc.config.Environment == "testnet" {
+		wsURL = mc.config.HyperliquidTestnetWSURL
+	}
+
+	u, err := url.Parse(wsURL)
+	if err != nil {
+		return err
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	mc.connMutex.Lock()
+	mc.conn = conn
+	mc.connected = true
+	mc.connMutex.Unlock()
+
+	log.Info().Str("address", mc.address).Str("url", wsURL).Msg("Margin WebSocket connected")
+
+	if err := mc.subscribeToMargin(); err != nil {
+		log.Error().Err(err).Str("address", mc.address).Msg("Failed to subscribe to margin")
+		return err
+	}
+
+	mc.wg.Add(1)
+	go mc.readMessages(marginChannel)
+
+	return nil
+}
+
+func (mc *MarginClient) Stop() {
+	mc.connMutex.Lock()
+	if mc.connected {
+		mc.connected = false
+		close(mc.shutdown)
+		if mc.conn != nil {
+			mc.conn.Close()
+		}
+	}
+	mc.connMutex.Unlock()
+
+	mc.wg.Wait()
+}
+
+func (mc *MarginClient) subscribeToMargin() error {
+	subscription := map[string]interface{}{
+		"method": "subscribe",
+		"subscription": map[string]interface{}{
+			"type": "margin",
+			"user": mc.address,
+		},
+	}
+
+	mc.connMutex.RLock()
+	defer mc.connMutex.RUnlock()
+
+	if !mc.connected || mc.conn == nil {
+		return websocket.ErrCloseSent
+	}
+
+	return mc.conn.WriteJSON(subscription)
+}
+
+func (mc *MarginClient) readMessages(marginChannel chan models.LiveMarginState) {
+	defer mc.wg.Done()
+
+	for {
+		select {
+		case <-mc.shutdown:
+			return
+		default:
+			mc.connMutex.RLock()
+			conn := mc.conn
+			connected := mc.connected
+			mc.connMutex.RUnlock()
+
+			if !connected || conn == nil {
+				return
+			}
+
+			conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+
+			var message map[string]interface{}
+			if err := conn.ReadJSON(&message); err != nil {
+				if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+					log.Error().Err(err).Str("address", mc.address).Msg("Margin WebSocket read error")
+				}
+				return
+			}
+
+			channel, ok := message["channel"].(string)
+			if !ok || channel != "margin" {
+				continue
+			}
+
+			data, ok := message["data"].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			state := mc.parseMarginState(data)
+			select {
+			case marginChannel <- state:
+			case <-mc.shutdown:
+				return
+			default:
+				log.Warn().Str("address", mc.address).Msg("Margin channel full, dropping update")
+			}
+		}
+	}
+}
+
+func (mc *MarginClient) parseMarginState(data map[string]interface{}) models.LiveMarginState {
+	state := models.LiveMarginState{
+		User:      mc.address,
+		UpdatedAt: time.Now(),
+	}
+
+	floatField := func(key string) float64 {
+		if v, ok := data[key].(float64); ok {
+			return v
+		}
+		return 0
+	}
+
+	state.AccountValue = floatField("accountValue")
+	state.MarginBalance = floatField("marginBalance")
+	state.MarginPosition = floatField("marginPosition")
+	state.MarginFrozen = floatField("marginFrozen")
+	state.MarginAvailable = floatField("marginAvailable")
+	state.WithdrawAvailable = floatField("withdrawAvailable")
+	state.RiskRate = floatField("riskRate")
+	state.LiquidationPrice = floatField("liquidationPrice")
+	state.LeverRate = floatField("leverRate")
+	state.AdjustFactor = floatField("adjustFactor")
+
+	if assets, ok := data["assets"].([]interface{}); ok {
+		for _, a := range assets {
+			assetMap, ok := a.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			asset := models.AssetMarginState{}
+			if name, ok := assetMap["asset"].(string); ok {
+				asset.Asset = name
+			}
+			if v, ok := assetMap["marginUsed"].(float64); ok {
+				asset.MarginUsed = v
+			}
+			if v, ok := assetMap["liquidationPrice"].(float64); ok {
+				asset.LiquidationPrice = v
+			}
+			if v, ok := assetMap["leverRate"].(float64); ok {
+				asset.LeverRate = v
+			}
+			state.Assets = append(state.Assets, asset)
+		}
+	}
+
+	return state
+}
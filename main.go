@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"flag"
 	"hyperliquid-copy-trading/config"
+	"hyperliquid-copy-trading/internal/blacklist"
 	"hyperliquid-copy-trading/internal/database"
 	"hyperliquid-copy-trading/internal/engine"
 	"hyperliquid-copy-trading/internal/handlers"
@@ -19,6 +21,10 @@ import (
 )
 
 func main() {
+	migrateOnly := flag.Bool("migrate-only", false, "run pending database migrations, then exit")
+	rollback := flag.Int("rollback", 0, "roll back the N most recently applied database migrations, then exit")
+	flag.Parse()
+
 	// Initialize logger
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
 	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
@@ -27,13 +33,39 @@ func main() {
 	cfg := config.Load()
 	log.Info().Msg("Starting Hyperliquid Copy Trading Backend")
 
-	// Initialize database
-	db, err := database.NewPostgresDB(cfg.DatabaseURL)
+	// Initialize database. NewPostgresDB always applies pending migrations
+	// before returning, so by the time we get here the schema is current.
+	db, err := database.NewPostgresDB(cfg.DatabaseURL, cfg.DatabaseReplicaURLs, cfg.DBRetryMaxElapsed, cfg.LotMatchingMethod, cfg.ReplicaLagWindow)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to connect to database")
 	}
 	defer db.Close()
 
+	if *rollback > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := database.Rollback(ctx, db.Pool(), *rollback); err != nil {
+			log.Fatal().Err(err).Int("count", *rollback).Msg("Failed to roll back database migrations")
+		}
+		log.Info().Int("count", *rollback).Msg("Rolled back database migrations")
+		return
+	}
+
+	if *migrateOnly {
+		log.Info().Msg("Migrations applied, exiting (--migrate-only)")
+		return
+	}
+
+	// Address blacklist: loaded synchronously once so the first copy trade
+	// already sees it, then refreshed in the background on BlacklistRefreshInterval.
+	blacklistStore := blacklist.NewStore(db.Pool(), cfg.BlacklistSourceURLs, cfg.BlacklistSourceFiles)
+	blacklistCtx, stopBlacklist := context.WithCancel(context.Background())
+	defer stopBlacklist()
+	if err := blacklistStore.Start(blacklistCtx, cfg.BlacklistRefreshInterval); err != nil {
+		log.Fatal().Err(err).Msg("Failed to load address blacklist")
+	}
+	db.SetBlacklist(blacklistStore)
+
 	// Initialize WebSocket manager
 	wsManager := websocket.NewManager(cfg)
 
@@ -45,9 +77,11 @@ func main() {
 
 	// Setup routes
 	router := mux.NewRouter()
-	
+	router.Use(apiHandler.ReadYourWritesMiddleware)
+
 	// API routes
 	api := router.PathPrefix("/api/v1").Subrouter()
+	api.HandleFunc("/followers/agent-challenge", apiHandler.CreateAgentChallenge).Methods("POST")
 	api.HandleFunc("/followers", apiHandler.CreateFollower).Methods("POST")
 	api.HandleFunc("/followers", apiHandler.GetFollowers).Methods("GET")
 	api.HandleFunc("/followers/{id}", apiHandler.UpdateFollower).Methods("PUT")
@@ -57,7 +91,20 @@ func main() {
 	api.HandleFunc("/trades", apiHandler.GetTrades).Methods("GET")
 	api.HandleFunc("/positions", apiHandler.GetPositions).Methods("GET")
 	api.HandleFunc("/analytics/{follower_id}/pnl", apiHandler.GetPnLAnalytics).Methods("GET")
-	
+	api.HandleFunc("/instruments", apiHandler.GetInstruments).Methods("GET")
+	api.HandleFunc("/instruments/{coin}", apiHandler.GetInstrument).Methods("GET")
+	api.HandleFunc("/orderbook/{coin}", apiHandler.GetOrderBook).Methods("GET")
+	api.HandleFunc("/events", apiHandler.GetLeaderEvents).Methods("GET")
+	api.HandleFunc("/blocked-addresses", apiHandler.ListBlockedAddresses).Methods("GET")
+	api.HandleFunc("/blocked-addresses", apiHandler.AddBlockedAddress).Methods("POST")
+	api.HandleFunc("/blocked-addresses/{address}", apiHandler.RemoveBlockedAddress).Methods("DELETE")
+
+	// Conformance self-check (see internal/conformance)
+	router.HandleFunc("/conformance/status", apiHandler.GetConformanceStatus).Methods("GET")
+
+	// Outbound WebSocket subscription endpoint for dashboards
+	router.HandleFunc("/ws", apiHandler.HandleWebSocket)
+
 	// Serve static files
 	router.PathPrefix("/").Handler(http.FileServer(http.Dir("./frontend/")))
 